@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -36,6 +37,8 @@ import (
 	"github.com/garnizeh/luckyfive/internal/middleware"
 	"github.com/garnizeh/luckyfive/internal/services"
 	"github.com/garnizeh/luckyfive/internal/store"
+	"github.com/garnizeh/luckyfive/internal/worker"
+	"github.com/google/uuid"
 )
 
 func main() {
@@ -81,9 +84,65 @@ func main() {
 	comparisonSvc := services.NewComparisonService(db.Comparisons, db.Simulations, db.SimulationsDB, logger)
 	leaderboardSvc := services.NewLeaderboardService(db.Simulations, logger)
 	sweepExecutionSvc := services.NewSweepService(db.SweepExecution, db.SimulationsDB, simSvc, logger)
+	predictionSvc := services.NewPredictionService(db.Predictions, db.Results, configSvc, logger)
+	ticketSvc := services.NewTicketService(db.Finances, db.FinancesDB, db.Results, logger)
+	checkerSvc := services.NewCheckerService(db.Results, db.Finances, logger)
+	workerSvc := services.NewWorkerService(db.Simulations, logger)
+	jobSvc := services.NewJobService(db.Jobs, logger)
+
+	// Check stored predictions and placed tickets as soon as their contests are imported
+	resultsSvc.AddDrawObserver(predictionSvc)
+	resultsSvc.AddDrawObserver(ticketSvc)
+
+	// Bound simulation runs; synchronous runs are checked against the same limits
+	limitPolicy := services.LimitPolicy{
+		Defaults: services.ExecutionLimits(cfg.Limits.Default),
+		Ceiling:  services.ExecutionLimits(cfg.Limits.Ceiling),
+	}
+	if err := simSvc.SetLimitPolicy(limitPolicy); err != nil {
+		logger.Error("Invalid execution limits", "error", err)
+		os.Exit(1)
+	}
+
+	// Advance sweeps as their simulations start and finish
+	if err := sweepExecutionSvc.SetBestMetric(cfg.Sweep.BestMetric); err != nil {
+		logger.Error("Invalid sweep configuration", "error", err)
+		os.Exit(1)
+	}
+	simSvc.AddSimulationObserver(sweepExecutionSvc)
+
+	// Comparisons, sweep creation, optimizations and reports are queued for workers
+	jobSvc.RegisterJobType(comparisonSvc.ComparisonJobType())
+	jobSvc.RegisterJobType(sweepExecutionSvc.SweepJobType())
+	jobSvc.RegisterJobType(sweepExecutionSvc.OptimizationJobType())
+	jobSvc.RegisterJobType(simSvc.ContestReportJobType())
+
+	// Retries only apply to claimed simulations, not to synchronous requests
+	workerSimSvc := services.NewSimulationService(db.Simulations, db.SimulationsDB, engineSvc, logger)
+	workerSimSvc.SetRetryPolicy(services.RetryPolicy{
+		MaxAttempts:    cfg.Worker.MaxAttempts,
+		InitialBackoff: cfg.Worker.RetryBackoff,
+		MaxBackoff:     cfg.Worker.RetryMaxBackoff,
+	})
+	// Validated with the API's simulation service already
+	_ = workerSimSvc.SetLimitPolicy(limitPolicy)
+	workerSimSvc.AddSimulationObserver(sweepExecutionSvc)
+
+	// Remote workers claim and report simulations over HTTP
+	leaseSvc := services.NewLeaseService(workerSimSvc, db.Simulations, db.Results, logger)
+
+	// Run simulations in this process when no separate worker is deployed
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	workerDone := make(chan struct{})
+	if cfg.Worker.Embedded {
+		startEmbeddedWorker(workerCtx, workerDone, cfg, db, workerSimSvc, simSvc, jobSvc, logger)
+	} else {
+		close(workerDone)
+	}
 
 	// Setup router
-	router := setupRouter(logger, systemSvc, uploadSvc, resultsSvc, configSvc, sweepSvc, simSvc, metricsSvc, comparisonSvc, leaderboardSvc, sweepExecutionSvc)
+	router := setupRouter(logger, systemSvc, uploadSvc, resultsSvc, configSvc, sweepSvc, simSvc, metricsSvc, comparisonSvc, leaderboardSvc, sweepExecutionSvc, predictionSvc, ticketSvc, checkerSvc, workerSvc, jobSvc, leaseSvc)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -115,10 +174,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Let the embedded worker drain its jobs before the databases are closed
+	stopWorker()
+	<-workerDone
+
 	logger.Info("Server exited")
 }
 
-func setupRouter(logger *slog.Logger, systemSvc *services.SystemService, uploadSvc *services.UploadService, resultsSvc *services.ResultsService, configSvc *services.ConfigService, sweepSvc *services.SweepConfigService, simSvc *services.SimulationService, metricsSvc *services.MetricsService, comparisonSvc *services.ComparisonService, leaderboardSvc *services.LeaderboardService, sweepExecutionSvc *services.SweepService) *chi.Mux {
+// startEmbeddedWorker runs a JobWorker with workerSimSvc until ctx is
+// cancelled and closes done once it drained its jobs. Simulations queued
+// through simSvc wake it right away; it still polls for simulations queued by
+// other processes.
+func startEmbeddedWorker(ctx context.Context, done chan<- struct{}, cfg *config.Config, db *store.DB, workerSimSvc, simSvc *services.SimulationService, jobSvc *services.JobService, logger *slog.Logger) {
+	notifier := worker.NewNotifier()
+	simSvc.SetJobNotifier(notifier)
+	jobSvc.SetJobNotifier(notifier)
+
+	workerID := "embedded-" + uuid.New().String()
+	jobWorker := worker.NewJobWorker(
+		db.Simulations,
+		workerSimSvc,
+		workerID,
+		cfg.Worker.PollInterval,
+		cfg.Worker.Concurrency,
+		logger,
+	)
+	jobWorker.SetLease(cfg.Worker.LeaseDuration, cfg.Worker.MaxAttempts)
+	jobWorker.SetDrainTimeout(cfg.Worker.DrainTimeout)
+	jobWorker.SetNotifier(notifier)
+	jobWorker.SetJobService(db.Jobs, jobSvc)
+
+	logger.Info("Starting embedded worker", "worker_id", workerID, "concurrency", cfg.Worker.Concurrency)
+	go func() {
+		defer close(done)
+		if err := jobWorker.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("Embedded worker error", "error", err)
+		}
+	}()
+}
+
+func setupRouter(logger *slog.Logger, systemSvc *services.SystemService, uploadSvc *services.UploadService, resultsSvc *services.ResultsService, configSvc *services.ConfigService, sweepSvc *services.SweepConfigService, simSvc *services.SimulationService, metricsSvc *services.MetricsService, comparisonSvc *services.ComparisonService, leaderboardSvc *services.LeaderboardService, sweepExecutionSvc *services.SweepService, predictionSvc *services.PredictionService, ticketSvc *services.TicketService, checkerSvc *services.CheckerService, workerSvc *services.WorkerService, jobSvc *services.JobService, leaseSvc *services.LeaseService) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware stack
@@ -146,9 +241,22 @@ func setupRouter(logger *slog.Logger, systemSvc *services.SystemService, uploadS
 
 	// Simulation endpoints
 	r.Post("/api/v1/simulations/simple", handlers.SimpleSimulation(configSvc, simSvc))
+	r.Post("/api/v1/simulations/fixed", handlers.FixedTicketSimulation(simSvc))
+	r.Get("/api/v1/simulations/search", handlers.SearchSimulations(simSvc))
 	r.Get("/api/v1/simulations/{id}", handlers.GetSimulation(simSvc))
 	r.Get("/api/v1/simulations", handlers.ListSimulations(simSvc))
 	r.Post("/api/v1/simulations/{id}/cancel", handlers.CancelSimulation(simSvc))
+	r.Get("/api/v1/simulations/{id}/logs", handlers.GetSimulationLogs(simSvc))
+	r.Get("/api/v1/simulations/{id}/attempts", handlers.ListSimulationAttempts(simSvc))
+	r.Get("/api/v1/simulations/{id}/results", handlers.GetContestResults(simSvc))
+	r.Get("/api/v1/simulations/{id}/contests", handlers.ListSimulationContests(simSvc))
+	r.Get("/api/v1/simulations/{id}/contests/export", handlers.ExportSimulationContests(simSvc))
+	r.Post("/api/v1/simulations/{id}/contests/report", handlers.CreateContestReport(jobSvc))
+	r.Get("/api/v1/simulations/{id}/tags", handlers.GetSimulationTags(simSvc))
+	r.Put("/api/v1/simulations/{id}/tags", handlers.SetSimulationTags(simSvc))
+
+	// Admin endpoints
+	r.Patch("/api/v1/admin/simulations/{id}", handlers.UpdateQueuedSimulation(simSvc))
 
 	// Config endpoints
 	r.Get("/api/v1/configs", handlers.ListConfigs(configSvc))
@@ -166,7 +274,7 @@ func setupRouter(logger *slog.Logger, systemSvc *services.SystemService, uploadS
 	r.Delete("/api/v1/sweep-configs/{id}", handlers.DeleteSweepConfig(sweepSvc))
 
 	// Comparison endpoints
-	r.Post("/api/v1/comparisons", handlers.CreateComparison(comparisonSvc))
+	r.Post("/api/v1/comparisons", handlers.CreateComparison(jobSvc))
 	r.Get("/api/v1/comparisons/{id}", handlers.GetComparison(comparisonSvc))
 	r.Get("/api/v1/comparisons", handlers.ListComparisons(comparisonSvc))
 
@@ -174,13 +282,48 @@ func setupRouter(logger *slog.Logger, systemSvc *services.SystemService, uploadS
 	r.Get("/api/v1/leaderboards/{metric}", handlers.GetLeaderboard(leaderboardSvc))
 
 	// Sweep execution endpoints
-	r.Post("/api/v1/sweeps", handlers.CreateSweep(sweepExecutionSvc))
+	r.Post("/api/v1/sweeps", handlers.CreateSweep(jobSvc))
 	r.Get("/api/v1/sweeps/{id}", handlers.GetSweep(sweepExecutionSvc))
 	r.Get("/api/v1/sweeps/{id}/status", handlers.GetSweepStatus(sweepExecutionSvc))
 	r.Get("/api/v1/sweeps/{id}/results", handlers.GetSweepResults(sweepExecutionSvc))
 	r.Post("/api/v1/sweeps/{id}/cancel", handlers.CancelSweep(sweepExecutionSvc))
 	r.Get("/api/v1/sweeps/{id}/best", handlers.GetSweepBest(sweepExecutionSvc))
 	r.Get("/api/v1/sweeps/{id}/visualization", handlers.GetSweepVisualization(sweepExecutionSvc))
+	r.Post("/api/v1/optimizations", handlers.CreateOptimization(jobSvc))
+
+	// Prediction endpoints
+	r.Post("/api/v1/predictions", handlers.CreatePrediction(predictionSvc))
+	r.Get("/api/v1/predictions", handlers.ListPredictions(predictionSvc))
+	r.Get("/api/v1/predictions/{id}", handlers.GetPrediction(predictionSvc))
+
+	// Ticket endpoints
+	r.Post("/api/v1/tickets", handlers.CreateTicket(ticketSvc))
+	r.Get("/api/v1/tickets", handlers.ListTickets(ticketSvc))
+	r.Get("/api/v1/tickets/{id}", handlers.GetTicket(ticketSvc))
+
+	// Checker endpoints
+	r.Post("/api/v1/checker", handlers.CheckTickets(checkerSvc))
+
+	// Worker fleet endpoints
+	r.Get("/api/v1/workers", handlers.ListWorkers(workerSvc))
+
+	// Job-lease API of remote workers (cmd/worker --remote)
+	r.Put("/api/v1/workers/{worker}", handlers.RegisterWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/heartbeat", handlers.HeartbeatWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/stop", handlers.StopWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/reap", handlers.ReapExpiredSimulations(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/claim", handlers.ClaimSimulation(leaseSvc))
+	r.Get("/api/v1/workers/{worker}/draws", handlers.ListLeaseDraws(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/heartbeat", handlers.HeartbeatSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/release", handlers.ReleaseSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/complete", handlers.CompleteSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/fail", handlers.FailSimulation(leaseSvc))
+
+	// Analysis job endpoints
+	r.Get("/api/v1/jobs", handlers.ListJobs(jobSvc))
+	r.Get("/api/v1/jobs/{id}", handlers.GetJob(jobSvc))
+	r.Get("/api/v1/jobs/{id}/report", handlers.GetJobReport(jobSvc))
+	r.Post("/api/v1/jobs/{id}/cancel", handlers.CancelJob(jobSvc))
 
 	// Swagger UI — serves UI and expects swagger JSON at /swagger/doc.json
 	// If you generate docs with `swag init -g cmd/api/main.go -o api`,
@@ -24,12 +24,13 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Open database (use path from .env)
+	// Open databases (use paths from .env). Predictions and tickets are
+	// checked against the imported draws, so their databases are needed too.
 	db, err := store.Open(store.Config{
 		ResultsPath:     cfg.Database.ResultsPath,
-		SimulationsPath: ":memory:", // Not needed for import
-		ConfigsPath:     ":memory:",
-		FinancesPath:    ":memory:",
+		SimulationsPath: cfg.Database.SimulationsPath,
+		ConfigsPath:     cfg.Database.ConfigsPath,
+		FinancesPath:    cfg.Database.FinancesPath,
 	})
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
@@ -41,6 +42,13 @@ func main() {
 
 	// Create import service
 	importSvc := services.NewImportService(db, logger)
+	configSvc := services.NewConfigService(db.Configs, db.ConfigsDB, logger)
+	predictionSvc := services.NewPredictionService(db.Predictions, db.Results, configSvc, logger)
+	ticketSvc := services.NewTicketService(db.Finances, db.FinancesDB, db.Results, logger)
+
+	// Check stored predictions and placed tickets as soon as their contests are imported
+	importSvc.AddDrawObserver(predictionSvc)
+	importSvc.AddDrawObserver(ticketSvc)
 
 	// Open XLSX file
 	file, err := os.Open(*xlsxPath)
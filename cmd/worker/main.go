@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -16,10 +18,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// version is reported in the worker registry, set it at build time with
+// -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
 	// Parse command line flags
 	envFile := flag.String("env-file", ".env", "Path to .env configuration file")
 	workerID := flag.String("worker-id", "", "Unique identifier for this worker instance (auto-generated if not provided)")
+	remote := flag.String("remote", "", "Base URL of the API to claim simulations from instead of opening the databases, e.g. http://api:8080")
 	flag.Parse()
 
 	// Generate worker ID if not provided
@@ -36,6 +43,15 @@ func main() {
 	// Initialize logger
 	logger := logger.New(cfg.LogLevel)
 
+	// Handle shutdown signals
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if *remote != "" {
+		runRemote(ctx, *remote, *workerID, cfg, logger)
+		return
+	}
+
 	// Initialize database connections
 	db, err := store.Open(store.Config{
 		ResultsPath:     cfg.Database.ResultsPath,
@@ -52,6 +68,34 @@ func main() {
 	// Initialize services
 	engineSvc := services.NewEngineService(db.Results, logger)
 	simSvc := services.NewSimulationService(db.Simulations, db.SimulationsDB, engineSvc, logger)
+	simSvc.SetRetryPolicy(services.RetryPolicy{
+		MaxAttempts:    cfg.Worker.MaxAttempts,
+		InitialBackoff: cfg.Worker.RetryBackoff,
+		MaxBackoff:     cfg.Worker.RetryMaxBackoff,
+	})
+	if err := simSvc.SetLimitPolicy(services.LimitPolicy{
+		Defaults: services.ExecutionLimits(cfg.Limits.Default),
+		Ceiling:  services.ExecutionLimits(cfg.Limits.Ceiling),
+	}); err != nil {
+		logger.Error("Invalid execution limits", "error", err)
+		os.Exit(1)
+	}
+
+	// Advance sweeps as their simulations start and finish
+	sweepSvc := services.NewSweepService(db.SweepExecution, db.SimulationsDB, simSvc, logger)
+	if err := sweepSvc.SetBestMetric(cfg.Sweep.BestMetric); err != nil {
+		logger.Error("Invalid sweep configuration", "error", err)
+		os.Exit(1)
+	}
+	simSvc.AddSimulationObserver(sweepSvc)
+
+	// Analysis jobs queued by the API
+	comparisonSvc := services.NewComparisonService(db.Comparisons, db.Simulations, db.SimulationsDB, logger)
+	jobSvc := services.NewJobService(db.Jobs, logger)
+	jobSvc.RegisterJobType(comparisonSvc.ComparisonJobType())
+	jobSvc.RegisterJobType(sweepSvc.SweepJobType())
+	jobSvc.RegisterJobType(sweepSvc.OptimizationJobType())
+	jobSvc.RegisterJobType(simSvc.ContestReportJobType())
 
 	// Create worker
 	jobWorker := worker.NewJobWorker(
@@ -62,17 +106,38 @@ func main() {
 		cfg.Worker.Concurrency,
 		logger,
 	)
+	jobWorker.SetLease(cfg.Worker.LeaseDuration, cfg.Worker.MaxAttempts)
+	jobWorker.SetDrainTimeout(cfg.Worker.DrainTimeout)
+	jobWorker.SetVersion(version)
+	jobWorker.SetJobService(db.Jobs, jobSvc)
 
-	// Handle shutdown signals
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+	logger.Info("Starting worker", "worker_id", *workerID, "version", version, "concurrency", cfg.Worker.Concurrency)
 
-	logger.Info("Starting worker", "worker_id", *workerID, "concurrency", cfg.Worker.Concurrency)
-
-	if err := jobWorker.Start(ctx); err != nil {
+	// A signal stops claiming and drains in-flight jobs before Start returns
+	if err := jobWorker.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		logger.Error("Worker error", "error", err)
 		os.Exit(1)
 	}
 
 	logger.Info("Worker stopped", "worker_id", *workerID)
 }
+
+// runRemote runs simulations leased from the API at baseURL until ctx is
+// cancelled. No database is opened: the API applies the retry and limit
+// policies and stores the results, and only simulations are run.
+func runRemote(ctx context.Context, baseURL, workerID string, cfg *config.Config, logger *slog.Logger) {
+	client := worker.NewRemoteClient(baseURL, workerID, logger)
+	jobWorker := worker.NewRemoteJobWorker(client, cfg.Worker.PollInterval, cfg.Worker.Concurrency, logger)
+	jobWorker.SetLease(cfg.Worker.LeaseDuration, cfg.Worker.MaxAttempts)
+	jobWorker.SetDrainTimeout(cfg.Worker.DrainTimeout)
+	jobWorker.SetVersion(version)
+
+	logger.Info("Starting remote worker", "worker_id", workerID, "api", baseURL, "version", version, "concurrency", cfg.Worker.Concurrency)
+
+	if err := jobWorker.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		logger.Error("Worker error", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Worker stopped", "worker_id", workerID)
+}
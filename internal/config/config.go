@@ -14,6 +14,8 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	Worker   WorkerConfig
+	Sweep    SweepConfig
+	Limits   LimitsConfig
 	LogLevel string
 }
 
@@ -31,8 +33,31 @@ type DatabaseConfig struct {
 }
 
 type WorkerConfig struct {
-	Concurrency  int
-	PollInterval time.Duration
+	Concurrency     int
+	PollInterval    time.Duration
+	LeaseDuration   time.Duration
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	DrainTimeout    time.Duration
+	Embedded        bool // Run a worker inside the API server
+}
+
+type SweepConfig struct {
+	BestMetric string // Metric cached as the best configuration of finished sweeps
+}
+
+// ExecutionLimits bound one simulation run. Zero fields are unlimited.
+type ExecutionLimits struct {
+	Timeout                 time.Duration
+	MaxContests             int
+	MaxPredictions          int // Per contest
+	MaxCandidateEvaluations int64
+}
+
+type LimitsConfig struct {
+	Default ExecutionLimits // Applied unless the recipe overrides it
+	Ceiling ExecutionLimits // Highest limits a recipe may ask for
 }
 
 // getEnv returns the value for key or defaultVal if not present.
@@ -68,6 +93,50 @@ func Load(envFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	leaseStr := getEnv("WORKER_LEASE_SECONDS", "120")
+	leaseSec, err := strconv.Atoi(leaseStr)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttemptsStr := getEnv("WORKER_MAX_ATTEMPTS", "3")
+	maxAttempts, err := strconv.Atoi(maxAttemptsStr)
+	if err != nil {
+		return nil, err
+	}
+
+	retryBackoffStr := getEnv("WORKER_RETRY_BACKOFF_SECONDS", "5")
+	retryBackoffSec, err := strconv.Atoi(retryBackoffStr)
+	if err != nil {
+		return nil, err
+	}
+
+	retryMaxBackoffStr := getEnv("WORKER_RETRY_MAX_BACKOFF_SECONDS", "300")
+	retryMaxBackoffSec, err := strconv.Atoi(retryMaxBackoffStr)
+	if err != nil {
+		return nil, err
+	}
+
+	drainTimeoutStr := getEnv("WORKER_DRAIN_TIMEOUT_SECONDS", "30")
+	drainTimeoutSec, err := strconv.Atoi(drainTimeoutStr)
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, err := strconv.ParseBool(getEnv("WORKER_EMBEDDED", "false"))
+	if err != nil {
+		return nil, err
+	}
+
+	defaultLimits, err := loadLimits("LIMIT_", "1800", "5000", "100", "50000000")
+	if err != nil {
+		return nil, err
+	}
+	ceilingLimits, err := loadLimits("LIMIT_CEILING_", "14400", "20000", "1000", "500000000")
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "localhost"),
@@ -81,11 +150,46 @@ func Load(envFilePath string) (*Config, error) {
 			SweepsPath:      getEnv("DB_SWEEPS_PATH", "data/sweeps.db"),
 		},
 		Worker: WorkerConfig{
-			Concurrency:  conc,
-			PollInterval: time.Duration(pollIntervalSec) * time.Second,
+			Concurrency:     conc,
+			PollInterval:    time.Duration(pollIntervalSec) * time.Second,
+			LeaseDuration:   time.Duration(leaseSec) * time.Second,
+			MaxAttempts:     maxAttempts,
+			RetryBackoff:    time.Duration(retryBackoffSec) * time.Second,
+			RetryMaxBackoff: time.Duration(retryMaxBackoffSec) * time.Second,
+			DrainTimeout:    time.Duration(drainTimeoutSec) * time.Second,
+			Embedded:        embedded,
+		},
+		Sweep: SweepConfig{
+			BestMetric: getEnv("SWEEP_BEST_METRIC", ""),
+		},
+		Limits: LimitsConfig{
+			Default: defaultLimits,
+			Ceiling: ceilingLimits,
 		},
 		LogLevel: strings.ToUpper(getEnv("LOG_LEVEL", "INFO")),
 	}
 
 	return cfg, nil
 }
+
+// loadLimits reads execution limits from the variables with the given prefix.
+func loadLimits(prefix, timeoutSec, contests, predictions, evaluations string) (ExecutionLimits, error) {
+	var limits ExecutionLimits
+
+	timeout, err := strconv.Atoi(getEnv(prefix+"TIMEOUT_SECONDS", timeoutSec))
+	if err != nil {
+		return limits, err
+	}
+	limits.Timeout = time.Duration(timeout) * time.Second
+
+	if limits.MaxContests, err = strconv.Atoi(getEnv(prefix+"MAX_CONTESTS", contests)); err != nil {
+		return limits, err
+	}
+	if limits.MaxPredictions, err = strconv.Atoi(getEnv(prefix+"MAX_PREDICTIONS", predictions)); err != nil {
+		return limits, err
+	}
+	if limits.MaxCandidateEvaluations, err = strconv.ParseInt(getEnv(prefix+"MAX_CANDIDATE_EVALUATIONS", evaluations), 10, 64); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
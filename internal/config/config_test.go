@@ -3,13 +3,14 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/garnizeh/luckyfive/internal/config"
 )
 
 func TestLoadDefaults(t *testing.T) {
 	// Clear relevant env vars
-	keys := []string{"SERVER_HOST", "SERVER_PORT", "DB_RESULTS_PATH", "DB_SIMULATIONS_PATH", "DB_CONFIGS_PATH", "DB_FINANCES_PATH", "LOG_LEVEL", "WORKER_CONCURRENCY"}
+	keys := []string{"SERVER_HOST", "SERVER_PORT", "DB_RESULTS_PATH", "DB_SIMULATIONS_PATH", "DB_CONFIGS_PATH", "DB_FINANCES_PATH", "LOG_LEVEL", "WORKER_CONCURRENCY", "WORKER_LEASE_SECONDS", "WORKER_MAX_ATTEMPTS", "WORKER_RETRY_BACKOFF_SECONDS", "WORKER_RETRY_MAX_BACKOFF_SECONDS"}
 	saved := map[string]string{}
 	for _, k := range keys {
 		if v, ok := os.LookupEnv(k); ok {
@@ -44,6 +45,21 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.Worker.Concurrency != 4 {
 		t.Errorf("expected default worker concurrency 4, got %d", cfg.Worker.Concurrency)
 	}
+	if cfg.Worker.LeaseDuration != 2*time.Minute {
+		t.Errorf("expected default worker lease 2m, got %s", cfg.Worker.LeaseDuration)
+	}
+	if cfg.Worker.MaxAttempts != 3 {
+		t.Errorf("expected default worker max attempts 3, got %d", cfg.Worker.MaxAttempts)
+	}
+	if cfg.Worker.RetryBackoff != 5*time.Second || cfg.Worker.RetryMaxBackoff != 5*time.Minute {
+		t.Errorf("expected default retry backoff 5s up to 5m, got %s up to %s", cfg.Worker.RetryBackoff, cfg.Worker.RetryMaxBackoff)
+	}
+	if cfg.Worker.DrainTimeout != 30*time.Second {
+		t.Errorf("expected default drain timeout 30s, got %s", cfg.Worker.DrainTimeout)
+	}
+	if cfg.Worker.Embedded {
+		t.Errorf("expected embedded worker to be disabled by default")
+	}
 }
 
 func TestLoadEnvOverrides(t *testing.T) {
@@ -95,6 +111,107 @@ func TestLoadInvalidPort(t *testing.T) {
 	}
 }
 
+func TestLoadInvalidLease(t *testing.T) {
+	os.Setenv("WORKER_LEASE_SECONDS", "2m")
+	defer os.Unsetenv("WORKER_LEASE_SECONDS")
+
+	_, err := config.Load("")
+	if err == nil {
+		t.Fatalf("expected error when WORKER_LEASE_SECONDS is invalid, got nil")
+	}
+}
+
+func TestLoadInvalidRetryBackoff(t *testing.T) {
+	os.Setenv("WORKER_RETRY_BACKOFF_SECONDS", "5s")
+	defer os.Unsetenv("WORKER_RETRY_BACKOFF_SECONDS")
+
+	_, err := config.Load("")
+	if err == nil {
+		t.Fatalf("expected error when WORKER_RETRY_BACKOFF_SECONDS is invalid, got nil")
+	}
+}
+
+func TestLoadInvalidDrainTimeout(t *testing.T) {
+	os.Setenv("WORKER_DRAIN_TIMEOUT_SECONDS", "soon")
+	defer os.Unsetenv("WORKER_DRAIN_TIMEOUT_SECONDS")
+
+	_, err := config.Load("")
+	if err == nil {
+		t.Fatalf("expected error when WORKER_DRAIN_TIMEOUT_SECONDS is invalid, got nil")
+	}
+}
+
+func TestLoadEmbeddedWorker(t *testing.T) {
+	os.Setenv("WORKER_EMBEDDED", "true")
+	defer os.Unsetenv("WORKER_EMBEDDED")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if !cfg.Worker.Embedded {
+		t.Errorf("expected embedded worker to be enabled")
+	}
+
+	os.Setenv("WORKER_EMBEDDED", "sometimes")
+	if _, err := config.Load(""); err == nil {
+		t.Fatalf("expected error when WORKER_EMBEDDED is invalid, got nil")
+	}
+}
+
+func TestLoadSweepBestMetric(t *testing.T) {
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Sweep.BestMetric != "" {
+		t.Errorf("expected no best metric by default, got %q", cfg.Sweep.BestMetric)
+	}
+
+	os.Setenv("SWEEP_BEST_METRIC", "quina_rate")
+	defer os.Unsetenv("SWEEP_BEST_METRIC")
+
+	cfg, err = config.Load("")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Sweep.BestMetric != "quina_rate" {
+		t.Errorf("expected best metric quina_rate, got %q", cfg.Sweep.BestMetric)
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Limits.Default.Timeout != 30*time.Minute || cfg.Limits.Default.MaxPredictions != 100 {
+		t.Errorf("unexpected default limits %+v", cfg.Limits.Default)
+	}
+	if cfg.Limits.Ceiling.Timeout != 4*time.Hour || cfg.Limits.Ceiling.MaxCandidateEvaluations != 500000000 {
+		t.Errorf("unexpected ceiling %+v", cfg.Limits.Ceiling)
+	}
+
+	os.Setenv("LIMIT_MAX_CONTESTS", "0")
+	os.Setenv("LIMIT_CEILING_MAX_PREDICTIONS", "250")
+	defer os.Unsetenv("LIMIT_MAX_CONTESTS")
+	defer os.Unsetenv("LIMIT_CEILING_MAX_PREDICTIONS")
+
+	cfg, err = config.Load("")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Limits.Default.MaxContests != 0 || cfg.Limits.Ceiling.MaxPredictions != 250 {
+		t.Errorf("unexpected limits %+v", cfg.Limits)
+	}
+
+	os.Setenv("LIMIT_CEILING_TIMEOUT_SECONDS", "soon")
+	defer os.Unsetenv("LIMIT_CEILING_TIMEOUT_SECONDS")
+	if _, err := config.Load(""); err == nil {
+		t.Fatalf("expected error when LIMIT_CEILING_TIMEOUT_SECONDS is invalid, got nil")
+	}
+}
+
 func TestLoadInvalidConcurrency(t *testing.T) {
 	os.Setenv("WORKER_CONCURRENCY", "notanint")
 	defer os.Unsetenv("WORKER_CONCURRENCY")
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+// maxCheckerBody limits the size of uploaded ticket lists.
+const maxCheckerBody = 1 << 20
+
+// CheckTickets godoc
+// @Summary Check tickets against drawn contests
+// @Description Check one or more tickets (5 to 15 numbers) against a contest or contest range and return hits, matched numbers and prize tier per contest. Tickets are sent as JSON, CSV (one ticket per row) or the legacy "Jogo #NN: [..]" text format, selected by Content-Type or the format query parameter. For CSV and text the contests are given as query parameters.
+// @Tags checker
+// @Accept json,text/csv,text/plain
+// @Produce json
+// @Param request body object{tickets=[][]integer,contest=integer,first_contest=integer,last_contest=integer} false "JSON check request"
+// @Param format query string false "Ticket format" Enums(json,csv,text)
+// @Param contest query integer false "Contest to check (CSV and text)"
+// @Param first_contest query integer false "First contest of the range (CSV and text)"
+// @Param last_contest query integer false "Last contest of the range (CSV and text)"
+// @Success 200 {object} services.CheckTicketsResult "Check results"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 404 {object} models.APIError "Contest not drawn"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/checker [post]
+func CheckTickets(checkerSvc services.CheckerServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCheckerBody))
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_request", "Failed to read request body"))
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = checkerFormat(r.Header.Get("Content-Type"))
+		}
+
+		var req services.CheckTicketsRequest
+		if format == "json" {
+			var jsonReq struct {
+				Tickets      [][]int `json:"tickets"`
+				Contest      int     `json:"contest"`
+				FirstContest int     `json:"first_contest"`
+				LastContest  int     `json:"last_contest"`
+			}
+			if err := json.Unmarshal(body, &jsonReq); err != nil {
+				WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid request format"))
+				return
+			}
+			req = services.CheckTicketsRequest{
+				Tickets:      jsonReq.Tickets,
+				FirstContest: jsonReq.FirstContest,
+				LastContest:  jsonReq.LastContest,
+			}
+			if jsonReq.Contest > 0 {
+				req.FirstContest, req.LastContest = jsonReq.Contest, jsonReq.Contest
+			}
+		} else {
+			tickets, err := checkerSvc.ParseTickets(format, body)
+			if err != nil {
+				WriteError(w, r, *models.NewAPIError("invalid_tickets", err.Error()))
+				return
+			}
+			req.Tickets = tickets
+
+			q := r.URL.Query()
+			contest, errContest := queryInt(q.Get("contest"))
+			first, errFirst := queryInt(q.Get("first_contest"))
+			last, errLast := queryInt(q.Get("last_contest"))
+			if errContest != nil || errFirst != nil || errLast != nil {
+				WriteError(w, r, *models.NewAPIError("invalid_contest", "Contests must be integers"))
+				return
+			}
+			req.FirstContest, req.LastContest = first, last
+			if contest > 0 {
+				req.FirstContest, req.LastContest = contest, contest
+			}
+		}
+
+		result, err := checkerSvc.CheckTickets(r.Context(), req)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidCheck):
+				WriteError(w, r, *models.NewAPIError("invalid_tickets", err.Error()))
+			case errors.Is(err, services.ErrContestNotDrawn):
+				WriteError(w, r, *models.NewAPIError("draw_not_found", "None of the requested contests has been drawn"))
+			default:
+				WriteError(w, r, *models.NewAPIError("check_failed", err.Error()))
+			}
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, result)
+	}
+}
+
+// checkerFormat maps a request Content-Type to a ticket format, defaulting to JSON.
+func checkerFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return "csv"
+	case "text/plain":
+		return "text"
+	default:
+		return "json"
+	}
+}
+
+func queryInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
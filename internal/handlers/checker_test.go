@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+// Mock implementation of CheckerServicer for testing
+type mockCheckerService struct {
+	parseTicketsFunc func(format string, data []byte) ([][]int, error)
+	checkTicketsFunc func(ctx context.Context, req services.CheckTicketsRequest) (*services.CheckTicketsResult, error)
+}
+
+func (m *mockCheckerService) ParseTickets(format string, data []byte) ([][]int, error) {
+	if m.parseTicketsFunc != nil {
+		return m.parseTicketsFunc(format, data)
+	}
+	return [][]int{{1, 2, 3, 4, 5}}, nil
+}
+
+func (m *mockCheckerService) CheckTickets(ctx context.Context, req services.CheckTicketsRequest) (*services.CheckTicketsResult, error) {
+	if m.checkTicketsFunc != nil {
+		return m.checkTicketsFunc(ctx, req)
+	}
+	return &services.CheckTicketsResult{FirstContest: req.FirstContest, LastContest: req.LastContest}, nil
+}
+
+func TestCheckTickets_JSON(t *testing.T) {
+	mockSvc := &mockCheckerService{
+		checkTicketsFunc: func(ctx context.Context, req services.CheckTicketsRequest) (*services.CheckTicketsResult, error) {
+			if len(req.Tickets) != 2 || req.FirstContest != 6000 || req.LastContest != 6000 {
+				t.Errorf("unexpected request: %+v", req)
+			}
+			return &services.CheckTicketsResult{}, nil
+		},
+	}
+
+	body := `{"tickets": [[1,2,3,4,5],[6,7,8,9,10]], "contest": 6000}`
+	req := httptest.NewRequest("POST", "/api/v1/checker", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	CheckTickets(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestCheckTickets_CSVWithRange(t *testing.T) {
+	mockSvc := &mockCheckerService{
+		parseTicketsFunc: func(format string, data []byte) ([][]int, error) {
+			if format != "csv" {
+				t.Errorf("expected csv format, got %q", format)
+			}
+			return [][]int{{1, 2, 3, 4, 5}}, nil
+		},
+		checkTicketsFunc: func(ctx context.Context, req services.CheckTicketsRequest) (*services.CheckTicketsResult, error) {
+			if req.FirstContest != 10 || req.LastContest != 20 {
+				t.Errorf("expected range 10-20, got %d-%d", req.FirstContest, req.LastContest)
+			}
+			return &services.CheckTicketsResult{}, nil
+		},
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/checker?first_contest=10&last_contest=20", bytes.NewBufferString("1,2,3,4,5\n"))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	CheckTickets(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestCheckTickets_LegacyTextFormatParam(t *testing.T) {
+	mockSvc := &mockCheckerService{
+		parseTicketsFunc: func(format string, data []byte) ([][]int, error) {
+			if format != "text" {
+				t.Errorf("expected text format, got %q", format)
+			}
+			return [][]int{{18, 26, 44, 57, 61}}, nil
+		},
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/checker?format=text&contest=6882", bytes.NewBufferString("Jogo #01: [18 26 44 57 61]\n"))
+	w := httptest.NewRecorder()
+
+	CheckTickets(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestCheckTickets_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"invalid", fmt.Errorf("%w: ticket 1: duplicate number 4", services.ErrInvalidCheck), http.StatusBadRequest},
+		{"not drawn", services.ErrContestNotDrawn, http.StatusNotFound},
+		{"internal", fmt.Errorf("list draws: boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mockCheckerService{
+				checkTicketsFunc: func(ctx context.Context, req services.CheckTicketsRequest) (*services.CheckTicketsResult, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest("POST", "/api/v1/checker", bytes.NewBufferString(`{"tickets": [[1,2,3,4,4]], "contest": 1}`))
+			w := httptest.NewRecorder()
+
+			CheckTickets(mockSvc).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCheckTickets_InvalidContestQuery(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v1/checker?format=csv&contest=abc", bytes.NewBufferString("1,2,3,4,5\n"))
+	w := httptest.NewRecorder()
+
+	CheckTickets(&mockCheckerService{}).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
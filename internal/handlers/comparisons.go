@@ -20,18 +20,18 @@ func NewComparisonHandler(comparisonService services.ComparisonServicer) *Compar
 	}
 }
 
-// CreateComparison creates a new comparison
+// CreateComparison queues a new comparison
 // @Summary Create a new simulation comparison
-// @Description Compare multiple simulations across various metrics
+// @Description Queue a comparison of multiple simulations across various metrics. A worker runs it; poll the returned job for the ComparisonResult.
 // @Tags comparisons
 // @Accept json
 // @Produce json
 // @Param request body services.CompareRequest true "Comparison request"
-// @Success 201 {object} services.ComparisonResult "Comparison created successfully"
+// @Success 202 {object} services.Job "Comparison job queued"
 // @Failure 400 {object} models.APIError "Invalid request"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /api/v1/comparisons [post]
-func CreateComparison(comparisonSvc services.ComparisonServicer) http.HandlerFunc {
+func CreateComparison(jobSvc services.JobServicer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req services.CompareRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -39,13 +39,8 @@ func CreateComparison(comparisonSvc services.ComparisonServicer) http.HandlerFun
 			return
 		}
 
-		result, err := comparisonSvc.Compare(r.Context(), req)
-		if err != nil {
-			WriteError(w, r, *models.NewAPIError("comparison_failed", err.Error()))
-			return
-		}
-
-		WriteJSON(w, http.StatusCreated, result)
+		job, err := jobSvc.EnqueueJob(r.Context(), services.JobTypeComparison, req, "")
+		writeJobQueued(w, r, job, err)
 	}
 }
 
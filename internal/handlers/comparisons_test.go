@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -16,15 +17,11 @@ import (
 
 // Mock implementation of ComparisonServicer for testing
 type mockComparisonService struct {
-	compareFunc         func(ctx context.Context, req services.CompareRequest) (*services.ComparisonResult, error)
 	getComparisonFunc   func(ctx context.Context, id int64) (*services.ComparisonResult, error)
 	listComparisonsFunc func(ctx context.Context, limit, offset int) ([]comparisons.Comparison, error)
 }
 
 func (m *mockComparisonService) Compare(ctx context.Context, req services.CompareRequest) (*services.ComparisonResult, error) {
-	if m.compareFunc != nil {
-		return m.compareFunc(ctx, req)
-	}
 	return &services.ComparisonResult{ID: 1, Name: "Test"}, nil
 }
 
@@ -47,12 +44,16 @@ func (m *mockComparisonService) ListComparisons(ctx context.Context, limit, offs
 }
 
 func TestCreateComparison_Success(t *testing.T) {
-	mockSvc := &mockComparisonService{
-		compareFunc: func(ctx context.Context, req services.CompareRequest) (*services.ComparisonResult, error) {
-			return &services.ComparisonResult{
-				ID:   1,
-				Name: req.Name,
-			}, nil
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+			if jobType != services.JobTypeComparison {
+				t.Errorf("expected comparison job, got %s", jobType)
+			}
+			req := payload.(services.CompareRequest)
+			if req.Name != "Test Comparison" || len(req.SimulationIDs) != 2 {
+				t.Errorf("unexpected payload %+v", req)
+			}
+			return &services.Job{ID: 5, Type: jobType, Status: "pending"}, nil
 		},
 	}
 
@@ -71,25 +72,46 @@ func TestCreateComparison_Success(t *testing.T) {
 
 	handler.ServeHTTP(w, req)
 
-	if w.Code != http.StatusCreated {
-		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/api/v1/jobs/5" {
+		t.Errorf("expected job location, got %q", loc)
 	}
 
-	var response services.ComparisonResult
+	var response services.Job
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
 
-	if response.ID != 1 {
-		t.Errorf("expected ID 1, got %d", response.ID)
+	if response.ID != 5 {
+		t.Errorf("expected ID 5, got %d", response.ID)
+	}
+	if response.Status != "pending" {
+		t.Errorf("expected status 'pending', got %s", response.Status)
+	}
+}
+
+func TestCreateComparison_InvalidRequest(t *testing.T) {
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+			return nil, fmt.Errorf("%w: at least 2 simulations required", services.ErrInvalidJobPayload)
+		},
 	}
-	if response.Name != "Test Comparison" {
-		t.Errorf("expected name 'Test Comparison', got %s", response.Name)
+	handler := CreateComparison(mockSvc)
+
+	req := httptest.NewRequest("POST", "/api/v1/comparisons", bytes.NewReader([]byte(`{"name":"x","simulation_ids":[1]}`)))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
 }
 
 func TestCreateComparison_InvalidJSON(t *testing.T) {
-	mockSvc := &mockComparisonService{}
+	mockSvc := &mockJobService{}
 	handler := CreateComparison(mockSvc)
 
 	req := httptest.NewRequest("POST", "/api/v1/comparisons", bytes.NewReader([]byte("invalid json")))
@@ -87,7 +87,11 @@ func ListConfigs(configSvc services.ConfigServicer) http.HandlerFunc {
 // @Router /api/v1/configs [post]
 func CreateConfig(configSvc services.ConfigServicer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req CreateConfigRequest
+		// The recipe is decoded with the schema of its version
+		var req struct {
+			CreateConfigRequest
+			Recipe json.RawMessage `json:"recipe"`
+		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON"))
 			return
@@ -102,8 +106,9 @@ func CreateConfig(configSvc services.ConfigServicer) http.HandlerFunc {
 			WriteError(w, r, *models.NewAPIError("validation_error", "Mode is required"))
 			return
 		}
-		if req.Recipe.Version == "" {
-			WriteError(w, r, *models.NewAPIError("validation_error", "Recipe version is required"))
+		recipe, err := decodeRequestRecipe(req.Recipe)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
 			return
 		}
 
@@ -111,7 +116,7 @@ func CreateConfig(configSvc services.ConfigServicer) http.HandlerFunc {
 		config, err := configSvc.Create(r.Context(), services.CreateConfigRequest{
 			Name:        req.Name,
 			Description: req.Description,
-			Recipe:      req.Recipe,
+			Recipe:      recipe,
 			Tags:        req.Tags,
 			Mode:        req.Mode,
 			CreatedBy:   "api", // TODO: get from auth context
@@ -181,22 +186,27 @@ func UpdateConfig(configSvc services.ConfigServicer) http.HandlerFunc {
 			return
 		}
 
-		var req UpdateConfigRequest
+		// The recipe is decoded with the schema of its version
+		var req struct {
+			UpdateConfigRequest
+			Recipe json.RawMessage `json:"recipe"`
+		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON"))
 			return
 		}
 
 		// Basic validation
-		if req.Recipe.Version == "" {
-			WriteError(w, r, *models.NewAPIError("validation_error", "Recipe version is required"))
+		recipe, err := decodeRequestRecipe(req.Recipe)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
 			return
 		}
 
 		// Update config
 		err = configSvc.Update(r.Context(), id, services.CreateConfigRequest{
 			Description: req.Description,
-			Recipe:      req.Recipe,
+			Recipe:      recipe,
 			Tags:        req.Tags,
 		})
 		if err != nil {
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+var jobStatuses = map[string]bool{
+	"pending":   true,
+	"running":   true,
+	"completed": true,
+	"failed":    true,
+	"cancelled": true,
+}
+
+// ListJobs lists analysis jobs
+// @Summary List analysis jobs
+// @Description List comparison, sweep and report jobs newest first, optionally filtered by type and status. Reports are not included; download them from /api/v1/jobs/{id}/report.
+// @Tags jobs
+// @Produce json
+// @Param type query string false "Job type (comparison, sweep or contest_report)"
+// @Param status query string false "Job status (pending, running, completed, failed or cancelled)"
+// @Param limit query int false "Maximum number of results" default(50)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {object} object{jobs=[]services.Job,limit=integer,offset=integer} "Analysis jobs"
+// @Failure 400 {object} models.APIError "Invalid filter"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/jobs [get]
+func ListJobs(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		filter := services.JobFilter{
+			Type:   q.Get("type"),
+			Status: q.Get("status"),
+			Limit:  50,
+		}
+
+		if filter.Status != "" && !jobStatuses[filter.Status] {
+			WriteError(w, r, *models.NewAPIError("invalid_job_filter", fmt.Sprintf("Unknown status %q", filter.Status)))
+			return
+		}
+		if l, err := strconv.Atoi(q.Get("limit")); err == nil && l > 0 && l <= 1000 {
+			filter.Limit = l
+		}
+		if o, err := strconv.Atoi(q.Get("offset")); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+
+		list, err := jobSvc.ListJobs(r.Context(), filter)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("list_jobs_failed", err.Error()))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"jobs":   list,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		})
+	}
+}
+
+// GetJob retrieves an analysis job
+// @Summary Get analysis job
+// @Description Get the status, progress and result of an analysis job
+// @Tags jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} services.Job "Analysis job"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 404 {object} models.APIError "Job not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/jobs/{id} [get]
+func GetJob(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		job, err := jobSvc.GetJob(r.Context(), id)
+		if err != nil {
+			writeJobError(w, r, err)
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, job)
+	}
+}
+
+// GetJobReport downloads the report of an analysis job
+// @Summary Download job report
+// @Description Download the report an analysis job produced, such as the CSV of a contest report job
+// @Tags jobs
+// @Produce octet-stream
+// @Param id path int true "Job ID"
+// @Success 200 {file} file "Report"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 404 {object} models.APIError "Job or report not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/jobs/{id}/report [get]
+func GetJobReport(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		name, report, err := jobSvc.GetJobReport(r.Context(), id)
+		if err != nil {
+			writeJobError(w, r, err)
+			return
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		w.WriteHeader(http.StatusOK)
+		w.Write(report)
+	}
+}
+
+// CancelJob cancels an analysis job
+// @Summary Cancel analysis job
+// @Description Cancel a pending or running analysis job. Running jobs stop when their worker next extends the lease.
+// @Tags jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} object{message=string} "Job cancelled"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 404 {object} models.APIError "Job not found"
+// @Failure 409 {object} models.APIError "Job already finished"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/jobs/{id}/cancel [post]
+func CancelJob(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := jobSvc.CancelJob(r.Context(), id); err != nil {
+			writeJobError(w, r, err)
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]string{"message": "Job cancelled"})
+	}
+}
+
+func parseJobID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		WriteError(w, r, *models.NewAPIError("invalid_job_id", "Invalid job ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJobQueued answers requests that queued an analysis job.
+func writeJobQueued(w http.ResponseWriter, r *http.Request, job *services.Job, err error) {
+	if err != nil {
+		writeJobError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/jobs/%d", job.ID))
+	WriteJSON(w, http.StatusAccepted, job)
+}
+
+func writeJobError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidJobPayload):
+		WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+	case errors.Is(err, services.ErrJobNotFound):
+		WriteError(w, r, *models.NewAPIError("job_not_found", "Job not found"))
+	case errors.Is(err, services.ErrJobReportNotFound):
+		WriteError(w, r, *models.NewAPIError("job_report_not_found", "Job has no report"))
+	case errors.Is(err, services.ErrJobNotCancellable):
+		WriteError(w, r, *models.NewAPIError("job_not_cancellable", err.Error()))
+	default:
+		WriteError(w, r, *models.NewAPIError("job_failed", err.Error()))
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+)
+
+// Mock implementation of JobServicer for testing
+type mockJobService struct {
+	enqueueJobFunc   func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error)
+	getJobFunc       func(ctx context.Context, id int64) (*services.Job, error)
+	listJobsFunc     func(ctx context.Context, filter services.JobFilter) ([]services.Job, error)
+	getJobReportFunc func(ctx context.Context, id int64) (string, []byte, error)
+	cancelJobFunc    func(ctx context.Context, id int64) error
+}
+
+func (m *mockJobService) EnqueueJob(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+	if m.enqueueJobFunc != nil {
+		return m.enqueueJobFunc(ctx, jobType, payload, createdBy)
+	}
+	return &services.Job{ID: 1, Type: jobType, Status: "pending"}, nil
+}
+
+func (m *mockJobService) GetJob(ctx context.Context, id int64) (*services.Job, error) {
+	if m.getJobFunc != nil {
+		return m.getJobFunc(ctx, id)
+	}
+	return &services.Job{ID: id, Type: services.JobTypeComparison, Status: "pending"}, nil
+}
+
+func (m *mockJobService) ListJobs(ctx context.Context, filter services.JobFilter) ([]services.Job, error) {
+	if m.listJobsFunc != nil {
+		return m.listJobsFunc(ctx, filter)
+	}
+	return []services.Job{}, nil
+}
+
+func (m *mockJobService) GetJobReport(ctx context.Context, id int64) (string, []byte, error) {
+	if m.getJobReportFunc != nil {
+		return m.getJobReportFunc(ctx, id)
+	}
+	return "", nil, services.ErrJobReportNotFound
+}
+
+func (m *mockJobService) CancelJob(ctx context.Context, id int64) error {
+	if m.cancelJobFunc != nil {
+		return m.cancelJobFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockJobService) ExecuteJob(ctx context.Context, job jobs.AnalysisJob) error {
+	return nil
+}
+
+func (m *mockJobService) JobTypes() []string {
+	return []string{services.JobTypeComparison, services.JobTypeContestReport, services.JobTypeSweep}
+}
+
+func withJobID(req *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestListJobs(t *testing.T) {
+	var got services.JobFilter
+	mockSvc := &mockJobService{
+		listJobsFunc: func(ctx context.Context, filter services.JobFilter) ([]services.Job, error) {
+			got = filter
+			return []services.Job{{ID: 3, Type: services.JobTypeSweep, Status: "running"}}, nil
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/jobs?type=sweep&status=running&limit=10&offset=5", nil)
+	w := httptest.NewRecorder()
+	ListJobs(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Type != services.JobTypeSweep || got.Status != "running" || got.Limit != 10 || got.Offset != 5 {
+		t.Errorf("unexpected filter %+v", got)
+	}
+
+	var response struct {
+		Jobs []services.Job `json:"jobs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(response.Jobs) != 1 || response.Jobs[0].ID != 3 {
+		t.Errorf("unexpected jobs %+v", response.Jobs)
+	}
+}
+
+func TestListJobs_InvalidStatus(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/jobs?status=done", nil)
+	w := httptest.NewRecorder()
+	ListJobs(&mockJobService{}).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	mockSvc := &mockJobService{
+		getJobFunc: func(ctx context.Context, id int64) (*services.Job, error) {
+			if id == 404 {
+				return nil, services.ErrJobNotFound
+			}
+			return &services.Job{ID: id, Status: "completed", Result: json.RawMessage(`{"id":9}`)}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	GetJob(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("GET", "/api/v1/jobs/7", nil), "7"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var job services.Job
+	if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if job.ID != 7 || string(job.Result) != `{"id":9}` {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	w = httptest.NewRecorder()
+	GetJob(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("GET", "/api/v1/jobs/404", nil), "404"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	GetJob(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("GET", "/api/v1/jobs/x", nil), "x"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestGetJobReport(t *testing.T) {
+	mockSvc := &mockJobService{
+		getJobReportFunc: func(ctx context.Context, id int64) (string, []byte, error) {
+			if id == 2 {
+				return "", nil, services.ErrJobReportNotFound
+			}
+			return "simulation_1_contests.csv", []byte("contest\n1000\n"), nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	GetJobReport(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("GET", "/api/v1/jobs/1/report", nil), "1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("expected CSV content type, got %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="simulation_1_contests.csv"` {
+		t.Errorf("unexpected content disposition %q", cd)
+	}
+	if w.Body.String() != "contest\n1000\n" {
+		t.Errorf("unexpected report %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	GetJobReport(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("GET", "/api/v1/jobs/2/report", nil), "2"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	mockSvc := &mockJobService{
+		cancelJobFunc: func(ctx context.Context, id int64) error {
+			if id == 2 {
+				return services.ErrJobNotCancellable
+			}
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	CancelJob(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("POST", "/api/v1/jobs/1/cancel", nil), "1"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	CancelJob(mockSvc).ServeHTTP(w, withJobID(httptest.NewRequest("POST", "/api/v1/jobs/2/cancel", nil), "2"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+}
+
+func TestCreateContestReport(t *testing.T) {
+	var payload services.ContestReportRequest
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, p any, createdBy string) (*services.Job, error) {
+			if jobType != services.JobTypeContestReport {
+				t.Errorf("expected contest report job, got %s", jobType)
+			}
+			payload = p.(services.ContestReportRequest)
+			return &services.Job{ID: 4, Type: jobType, Status: "pending"}, nil
+		},
+	}
+
+	req := withJobID(httptest.NewRequest("POST", "/api/v1/simulations/12/contests/report?min_hits=3&sort=hits", nil), "12")
+	w := httptest.NewRecorder()
+	CreateContestReport(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	if loc := w.Header().Get("Location"); loc != "/api/v1/jobs/4" {
+		t.Errorf("expected job location, got %q", loc)
+	}
+	if payload.SimulationID != 12 || payload.MinHits == nil || *payload.MinHits != 3 || !payload.SortByHits {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+}
@@ -19,6 +19,7 @@ import (
 // @Param mode query string false "Filter by simulation mode" Enums(simple,advanced,sweep,all) default(all)
 // @Param date_from query string false "Filter simulations created after this date (RFC3339 format)"
 // @Param date_to query string false "Filter simulations created before this date (RFC3339 format)"
+// @Param use_lower_bound query bool false "Rank multi-seed simulations by the lower confidence bound" default(false)
 // @Param limit query int false "Maximum number of results" default(50) maximum(1000)
 // @Param offset query int false "Number of results to skip" default(0)
 // @Success 200 {object} object{leaderboard=[]models.LeaderboardEntryResponse,total=integer,limit=integer,offset=integer} "Leaderboard results"
@@ -60,14 +61,22 @@ func GetLeaderboard(leaderboardSvc services.LeaderboardServicer) http.HandlerFun
 			}
 		}
 
+		useLowerBound := false
+		if boundStr := r.URL.Query().Get("use_lower_bound"); boundStr != "" {
+			if b, err := strconv.ParseBool(boundStr); err == nil {
+				useLowerBound = b
+			}
+		}
+
 		// Create request
 		req := services.LeaderboardRequest{
-			Metric:   metric,
-			Mode:     mode,
-			DateFrom: dateFrom,
-			DateTo:   dateTo,
-			Limit:    limit,
-			Offset:   offset,
+			Metric:        metric,
+			Mode:          mode,
+			DateFrom:      dateFrom,
+			DateTo:        dateTo,
+			Limit:         limit,
+			Offset:        offset,
+			UseLowerBound: useLowerBound,
 		}
 
 		// Get leaderboard
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+// leaseRequest asks for a simulation lease, or to extend one.
+type leaseRequest struct {
+	LeaseSeconds int `json:"lease_seconds"`
+}
+
+// RegisterWorker adds a remote worker to the registry
+// @Summary Register remote worker
+// @Description Register a worker that runs simulations through the job-lease API. Registering again with the same ID resets the entry.
+// @Tags workers
+// @Accept json
+// @Param worker path string true "Worker ID"
+// @Param request body services.WorkerRegistration true "Worker details"
+// @Success 204 "Worker registered"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker} [put]
+func RegisterWorker(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req services.WorkerRegistration
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		if err := leaseSvc.RegisterWorker(r.Context(), chi.URLParam(r, "worker"), req); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HeartbeatWorker refreshes the registry entry of a remote worker
+// @Summary Remote worker heartbeat
+// @Description Refresh the registry entry of a remote worker. A 404 means the worker must register again.
+// @Tags workers
+// @Accept json
+// @Param worker path string true "Worker ID"
+// @Param request body object{active_jobs=integer} true "Jobs the worker is running"
+// @Success 204 "Heartbeat recorded"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 404 {object} models.APIError "Worker not registered"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/heartbeat [post]
+func HeartbeatWorker(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ActiveJobs int `json:"active_jobs"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		if err := leaseSvc.HeartbeatWorker(r.Context(), chi.URLParam(r, "worker"), req.ActiveJobs); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// StopWorker marks a remote worker stopped
+// @Summary Stop remote worker
+// @Description Mark a remote worker stopped once it drained its jobs
+// @Tags workers
+// @Param worker path string true "Worker ID"
+// @Success 204 "Worker stopped"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/stop [post]
+func StopWorker(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := leaseSvc.StopWorker(r.Context(), chi.URLParam(r, "worker")); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ClaimSimulation leases the next pending simulation to a remote worker
+// @Summary Claim simulation
+// @Description Claim the next pending simulation for a remote worker. The lease holds for lease_seconds unless extended with heartbeats.
+// @Tags workers
+// @Accept json
+// @Produce json
+// @Param worker path string true "Worker ID"
+// @Param request body object{lease_seconds=integer} true "Lease duration"
+// @Success 200 {object} services.SimulationLease "Simulation leased"
+// @Success 204 "No pending simulation"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/claim [post]
+func ClaimSimulation(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lease, ok := parseLeaseRequest(w, r)
+		if !ok {
+			return
+		}
+
+		leased, err := leaseSvc.ClaimSimulation(r.Context(), chi.URLParam(r, "worker"), lease)
+		if errors.Is(err, services.ErrNoPendingSimulation) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, leased)
+	}
+}
+
+// HeartbeatSimulation extends the lease of a simulation
+// @Summary Extend simulation lease
+// @Description Extend the lease of a simulation run by a remote worker. A 409 means the worker no longer owns it and must stop the run.
+// @Tags workers
+// @Accept json
+// @Param worker path string true "Worker ID"
+// @Param id path int true "Simulation ID"
+// @Param request body object{lease_seconds=integer} true "Lease duration"
+// @Success 204 "Lease extended"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 409 {object} models.APIError "Lease lost"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/simulations/{id}/heartbeat [post]
+func HeartbeatSimulation(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseSimulationLeaseID(w, r)
+		if !ok {
+			return
+		}
+		lease, ok := parseLeaseRequest(w, r)
+		if !ok {
+			return
+		}
+
+		if err := leaseSvc.HeartbeatSimulation(r.Context(), chi.URLParam(r, "worker"), id, lease); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ReleaseSimulation returns a leased simulation to the queue
+// @Summary Release simulation
+// @Description Return a simulation interrupted by the shutdown of a remote worker to the queue. The run does not count as an attempt.
+// @Tags workers
+// @Param worker path string true "Worker ID"
+// @Param id path int true "Simulation ID"
+// @Success 204 "Simulation released"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 409 {object} models.APIError "Lease lost"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/simulations/{id}/release [post]
+func ReleaseSimulation(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseSimulationLeaseID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := leaseSvc.ReleaseSimulation(r.Context(), chi.URLParam(r, "worker"), id); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// CompleteSimulation stores the result of a leased simulation
+// @Summary Submit simulation result
+// @Description Store the result and log of a simulation run by a remote worker
+// @Tags workers
+// @Accept json
+// @Param worker path string true "Worker ID"
+// @Param id path int true "Simulation ID"
+// @Param request body services.LeaseReport true "Run result"
+// @Success 204 "Simulation completed"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 409 {object} models.APIError "Lease lost"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/simulations/{id}/complete [post]
+func CompleteSimulation(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseSimulationLeaseID(w, r)
+		if !ok {
+			return
+		}
+		var report services.LeaseReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		if err := leaseSvc.CompleteSimulation(r.Context(), chi.URLParam(r, "worker"), id, report); err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// FailSimulation records a failed run of a leased simulation
+// @Summary Report simulation failure
+// @Description Record a failed run of a remote worker. Transient failures are queued again while the retry policy allows it.
+// @Tags workers
+// @Accept json
+// @Produce json
+// @Param worker path string true "Worker ID"
+// @Param id path int true "Simulation ID"
+// @Param request body services.LeaseReport true "Run failure"
+// @Success 200 {object} object{retry_scheduled=boolean} "Failure recorded"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 409 {object} models.APIError "Lease lost"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/simulations/{id}/fail [post]
+func FailSimulation(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseSimulationLeaseID(w, r)
+		if !ok {
+			return
+		}
+		var report services.LeaseReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		retry, err := leaseSvc.FailSimulation(r.Context(), chi.URLParam(r, "worker"), id, report)
+		if err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, map[string]bool{"retry_scheduled": retry})
+	}
+}
+
+// ReapExpiredSimulations recovers simulations of crashed workers
+// @Summary Reap expired leases
+// @Description Fail simulations whose lease expired after the runs the API's retry policy allows and return the others to the queue. Remote workers call it periodically.
+// @Tags workers
+// @Produce json
+// @Param worker path string true "Worker ID"
+// @Success 200 {object} services.ReapResult "Reaped simulations"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/reap [post]
+func ReapExpiredSimulations(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := leaseSvc.ReapExpiredSimulations(r.Context())
+		if err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, result)
+	}
+}
+
+// ListLeaseDraws returns the draws a remote worker needs for a run
+// @Summary List draws for a contest range
+// @Description List the draws of a contest range, oldest first, for remote workers running simulations
+// @Tags workers
+// @Produce json
+// @Param worker path string true "Worker ID"
+// @Param from query int true "First contest"
+// @Param to query int true "Last contest"
+// @Success 200 {object} object{draws=[]results.Draw} "Draws"
+// @Failure 400 {object} models.APIError "Invalid range"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers/{worker}/draws [get]
+func ListLeaseDraws(leaseSvc services.LeaseServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, err := strconv.Atoi(r.URL.Query().Get("from"))
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_contest", "from must be a contest number"))
+			return
+		}
+		to, err := strconv.Atoi(r.URL.Query().Get("to"))
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_contest", "to must be a contest number"))
+			return
+		}
+
+		draws, err := leaseSvc.ListDraws(r.Context(), from, to)
+		if err != nil {
+			writeLeaseError(w, r, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, map[string]any{"draws": draws})
+	}
+}
+
+func parseLeaseRequest(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	var req leaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+		return 0, false
+	}
+	if req.LeaseSeconds <= 0 {
+		WriteError(w, r, *models.NewAPIError("invalid_request", "lease_seconds must be positive"))
+		return 0, false
+	}
+	return time.Duration(req.LeaseSeconds) * time.Second, true
+}
+
+func parseSimulationLeaseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+func writeLeaseError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLeaseRequest):
+		WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+	case errors.Is(err, services.ErrLeaseLost):
+		WriteError(w, r, *models.NewAPIError("lease_lost", "Worker no longer owns the simulation"))
+	case errors.Is(err, services.ErrWorkerNotRegistered):
+		WriteError(w, r, *models.NewAPIError("worker_not_found", "Worker not registered"))
+	default:
+		WriteError(w, r, *models.NewAPIError("lease_failed", err.Error()))
+	}
+}
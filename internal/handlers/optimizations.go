@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+// CreateOptimization queues an adaptive parameter search
+// @Summary Create an optimization job
+// @Description Queue a search of a sweep's parameter space by successive halving, Bayesian optimisation or a genetic algorithm. A worker runs the search until the method ends or the contest budget runs out: successive halving and Bayesian runs one after another, genetic generations as batches of queued simulations; the job result holds the best configuration and the search trajectory.
+// @Tags sweeps
+// @Accept json
+// @Produce json
+// @Param request body services.OptimizationRequest true "Optimization request"
+// @Success 202 {object} services.Job "Optimization job queued"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/optimizations [post]
+func CreateOptimization(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req services.OptimizationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		job, err := jobSvc.EnqueueJob(r.Context(), services.JobTypeOptimization, req, req.CreatedBy)
+		writeJobQueued(w, r, job, err)
+	}
+}
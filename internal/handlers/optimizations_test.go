@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+func TestCreateOptimization_Success(t *testing.T) {
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+			if jobType != services.JobTypeOptimization {
+				t.Errorf("expected optimization job, got %s", jobType)
+			}
+			req := payload.(services.OptimizationRequest)
+			if req.Name != "search" || req.Method != services.OptimizationSuccessiveHalving || req.Budget != 500 {
+				t.Errorf("unexpected payload %+v", req)
+			}
+			if createdBy != "tester" {
+				t.Errorf("expected created_by 'tester', got %s", createdBy)
+			}
+			return &services.Job{ID: 7, Type: jobType, Status: "pending"}, nil
+		},
+	}
+
+	body := `{"name":"search","method":"successive_halving","metric":"avg_hits","start_contest":1,"end_contest":100,"budget":500,"created_by":"tester"}`
+	req := httptest.NewRequest("POST", "/api/v1/optimizations", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+
+	CreateOptimization(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/api/v1/jobs/7" {
+		t.Errorf("expected job location, got %q", loc)
+	}
+}
+
+func TestCreateOptimization_InvalidPayload(t *testing.T) {
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+			return nil, fmt.Errorf("%w: budget must be positive", services.ErrInvalidJobPayload)
+		},
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/optimizations", bytes.NewReader([]byte(`{"name":"search"}`)))
+	w := httptest.NewRecorder()
+
+	CreateOptimization(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
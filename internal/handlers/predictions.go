@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/predictions"
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+// CreatePrediction godoc
+// @Summary Predict the next contest
+// @Description Generate tickets for the contest after the latest imported draw using a saved config or an inline recipe. The prediction is stored and checked automatically once that draw is imported.
+// @Tags predictions
+// @Accept json
+// @Produce json
+// @Param request body object{config_id=integer,recipe=services.Recipe,seed=integer,created_by=string} true "Prediction request"
+// @Success 201 {object} models.PredictionResponse "Prediction created"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 409 {object} models.APIError "No draws imported yet"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/predictions [post]
+func CreatePrediction(predictionSvc services.PredictionServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ConfigID  int64           `json:"config_id"`
+			Recipe    json.RawMessage `json:"recipe"`
+			Seed      int64           `json:"seed"`
+			CreatedBy string          `json:"created_by"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid request format"))
+			return
+		}
+
+		var recipe *services.Recipe
+		if len(req.Recipe) > 0 && string(req.Recipe) != "null" {
+			decoded, err := decodeRequestRecipe(req.Recipe)
+			if err != nil {
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+				return
+			}
+			recipe = &decoded
+		}
+
+		if req.ConfigID <= 0 && recipe == nil {
+			WriteError(w, r, *models.NewAPIError("validation_error", "Either config_id or recipe is required"))
+			return
+		}
+
+		p, err := predictionSvc.CreatePrediction(r.Context(), services.CreatePredictionRequest{
+			ConfigID:  req.ConfigID,
+			Recipe:    recipe,
+			Seed:      req.Seed,
+			CreatedBy: req.CreatedBy,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrNoDraws) {
+				WriteError(w, r, *models.NewAPIError("no_draws", "No draws have been imported yet"))
+				return
+			}
+			if errors.Is(err, services.ErrInvalidPrediction) || errors.Is(err, services.ErrInvalidRecipe) {
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+				return
+			}
+			WriteError(w, r, *models.NewAPIError("prediction_creation_failed", "Prediction creation failed"))
+			return
+		}
+
+		WriteJSON(w, http.StatusCreated, toPredictionResponse(*p))
+	}
+}
+
+// GetPrediction godoc
+// @Summary Get prediction
+// @Description Retrieve a stored prediction, including its check results once the target contest is drawn
+// @Tags predictions
+// @Accept json
+// @Produce json
+// @Param id path int true "Prediction ID"
+// @Success 200 {object} models.PredictionResponse "Prediction details"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 404 {object} models.APIError "Prediction not found"
+// @Router /api/v1/predictions/{id} [get]
+func GetPrediction(predictionSvc services.PredictionServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_prediction_id", "Invalid prediction ID"))
+			return
+		}
+
+		p, err := predictionSvc.GetPrediction(r.Context(), id)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("prediction_not_found", "Prediction not found"))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, toPredictionResponse(*p))
+	}
+}
+
+// ListPredictions godoc
+// @Summary List predictions
+// @Description Retrieve a paginated list of predictions, newest first
+// @Tags predictions
+// @Accept json
+// @Produce json
+// @Param limit query integer false "Maximum number of predictions to return" default(10)
+// @Param offset query integer false "Number of predictions to skip" default(0)
+// @Success 200 {object} object{predictions=[]models.PredictionResponse,limit=integer,offset=integer} "List of predictions"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/predictions [get]
+func ListPredictions(predictionSvc services.PredictionServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 10 // default
+		offset := 0 // default
+
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+				limit = l
+			}
+		}
+
+		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+			if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+				offset = o
+			}
+		}
+
+		preds, err := predictionSvc.ListPredictions(r.Context(), limit, offset)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("list_predictions_failed", err.Error()))
+			return
+		}
+
+		resp := make([]models.PredictionResponse, len(preds))
+		for i, p := range preds {
+			resp[i] = toPredictionResponse(p)
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"predictions": resp,
+			"limit":       limit,
+			"offset":      offset,
+		})
+	}
+}
+
+func toPredictionResponse(p predictions.Prediction) models.PredictionResponse {
+	resp := models.PredictionResponse{
+		ID:             p.ID,
+		RecipeName:     p.RecipeName.String,
+		RecipeHash:     p.RecipeHash,
+		TargetContest:  p.TargetContest,
+		BasedOnContest: p.BasedOnContest,
+		Seed:           p.Seed,
+		Status:         p.Status,
+		CreatedAt:      p.CreatedAt.String,
+		CheckedAt:      p.CheckedAt.String,
+		CreatedBy:      p.CreatedBy.String,
+	}
+	if p.ConfigID.Valid {
+		resp.ConfigID = &p.ConfigID.Int64
+	}
+	if p.BestHits.Valid {
+		resp.BestHits = &p.BestHits.Int64
+	}
+
+	var tickets []predictor.Prediction
+	if err := json.Unmarshal([]byte(p.PredictionsJson), &tickets); err == nil {
+		resp.Tickets = make([][]int, len(tickets))
+		for i, t := range tickets {
+			resp.Tickets[i] = t.Numbers
+		}
+	}
+	if p.ActualNumbers.Valid {
+		_ = json.Unmarshal([]byte(p.ActualNumbers.String), &resp.ActualNumbers)
+	}
+	if p.HitsJson.Valid {
+		_ = json.Unmarshal([]byte(p.HitsJson.String), &resp.Hits)
+	}
+
+	return resp
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/predictions"
+)
+
+// Mock implementation of PredictionServicer for testing
+type mockPredictionService struct {
+	createPredictionFunc func(ctx context.Context, req services.CreatePredictionRequest) (*predictions.Prediction, error)
+	getPredictionFunc    func(ctx context.Context, id int64) (*predictions.Prediction, error)
+	listPredictionsFunc  func(ctx context.Context, limit, offset int) ([]predictions.Prediction, error)
+}
+
+func (m *mockPredictionService) CreatePrediction(ctx context.Context, req services.CreatePredictionRequest) (*predictions.Prediction, error) {
+	if m.createPredictionFunc != nil {
+		return m.createPredictionFunc(ctx, req)
+	}
+	return &predictions.Prediction{ID: 1, PredictionsJson: "[]", Status: "pending"}, nil
+}
+
+func (m *mockPredictionService) GetPrediction(ctx context.Context, id int64) (*predictions.Prediction, error) {
+	if m.getPredictionFunc != nil {
+		return m.getPredictionFunc(ctx, id)
+	}
+	return &predictions.Prediction{ID: id, PredictionsJson: "[]", Status: "pending"}, nil
+}
+
+func (m *mockPredictionService) ListPredictions(ctx context.Context, limit, offset int) ([]predictions.Prediction, error) {
+	if m.listPredictionsFunc != nil {
+		return m.listPredictionsFunc(ctx, limit, offset)
+	}
+	return []predictions.Prediction{}, nil
+}
+
+func (m *mockPredictionService) CheckContest(ctx context.Context, contest int) (int, error) {
+	return 0, nil
+}
+
+func TestCreatePrediction_Success(t *testing.T) {
+	mockSvc := &mockPredictionService{
+		createPredictionFunc: func(ctx context.Context, req services.CreatePredictionRequest) (*predictions.Prediction, error) {
+			if req.ConfigID != 7 {
+				t.Errorf("expected config ID 7, got %d", req.ConfigID)
+			}
+			return &predictions.Prediction{
+				ID:              1,
+				ConfigID:        sql.NullInt64{Int64: 7, Valid: true},
+				RecipeHash:      "abc",
+				TargetContest:   6001,
+				BasedOnContest:  6000,
+				PredictionsJson: `[{"Numbers":[1,2,3,4,5]},{"Numbers":[6,7,8,9,10]}]`,
+				Status:          "pending",
+			}, nil
+		},
+	}
+
+	body, _ := json.Marshal(map[string]any{"config_id": 7})
+	req := httptest.NewRequest("POST", "/api/v1/predictions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	CreatePrediction(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.TargetContest != 6001 {
+		t.Errorf("expected target contest 6001, got %d", resp.TargetContest)
+	}
+	if len(resp.Tickets) != 2 || resp.Tickets[1][0] != 6 {
+		t.Errorf("unexpected tickets: %v", resp.Tickets)
+	}
+}
+
+func TestCreatePrediction_MissingSource(t *testing.T) {
+	body, _ := json.Marshal(map[string]any{"seed": 1})
+	req := httptest.NewRequest("POST", "/api/v1/predictions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	CreatePrediction(&mockPredictionService{}).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestCreatePrediction_NoDraws(t *testing.T) {
+	mockSvc := &mockPredictionService{
+		createPredictionFunc: func(ctx context.Context, req services.CreatePredictionRequest) (*predictions.Prediction, error) {
+			return nil, services.ErrNoDraws
+		},
+	}
+
+	body, _ := json.Marshal(map[string]any{"config_id": 1})
+	req := httptest.NewRequest("POST", "/api/v1/predictions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	CreatePrediction(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestCreatePrediction_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid recipe", fmt.Errorf("%w: sim_preds must be positive", services.ErrInvalidRecipe), http.StatusBadRequest, "validation_error"},
+		{"unknown config", fmt.Errorf("%w: config 9 not found", services.ErrInvalidPrediction), http.StatusBadRequest, "validation_error"},
+		{"store failure", errors.New("fetch draws: disk I/O error"), http.StatusInternalServerError, "prediction_creation_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mockPredictionService{
+				createPredictionFunc: func(ctx context.Context, req services.CreatePredictionRequest) (*predictions.Prediction, error) {
+					return nil, tt.err
+				},
+			}
+
+			body, _ := json.Marshal(map[string]any{"config_id": 9})
+			req := httptest.NewRequest("POST", "/api/v1/predictions", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			CreatePrediction(mockSvc).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var apiErr models.APIError
+			if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, apiErr.Code)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(apiErr.Message, "disk") {
+				t.Errorf("expected the internal error to stay hidden, got %q", apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestGetPrediction_Checked(t *testing.T) {
+	mockSvc := &mockPredictionService{
+		getPredictionFunc: func(ctx context.Context, id int64) (*predictions.Prediction, error) {
+			return &predictions.Prediction{
+				ID:              id,
+				PredictionsJson: `[{"Numbers":[1,2,3,4,5]}]`,
+				Status:          "checked",
+				ActualNumbers:   sql.NullString{String: "[1,2,3,40,50]", Valid: true},
+				BestHits:        sql.NullInt64{Int64: 3, Valid: true},
+				HitsJson:        sql.NullString{String: "[3]", Valid: true},
+			}, nil
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/predictions/{id}", GetPrediction(mockSvc))
+
+	req := httptest.NewRequest("GET", "/api/v1/predictions/5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.BestHits == nil || *resp.BestHits != 3 {
+		t.Errorf("expected best hits 3, got %v", resp.BestHits)
+	}
+	if len(resp.Hits) != 1 || resp.Hits[0] != 3 {
+		t.Errorf("unexpected hits: %v", resp.Hits)
+	}
+}
+
+func TestGetPrediction_NotFound(t *testing.T) {
+	mockSvc := &mockPredictionService{
+		getPredictionFunc: func(ctx context.Context, id int64) (*predictions.Prediction, error) {
+			return nil, fmt.Errorf("get prediction: %w", sql.ErrNoRows)
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/predictions/{id}", GetPrediction(mockSvc))
+
+	req := httptest.NewRequest("GET", "/api/v1/predictions/99", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
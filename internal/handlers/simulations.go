@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
@@ -17,7 +22,7 @@ import (
 // @Tags simulations
 // @Accept json
 // @Produce json
-// @Param request body object{preset=string,start_contest=integer,end_contest=integer,async=boolean} true "Simulation request"
+// @Param request body object{preset=string,start_contest=integer,end_contest=integer,async=boolean,tags=[]string,priority=integer} true "Simulation request"
 // @Success 200 {object} object{id=integer,created_at=string,started_at=string,finished_at=string,status=string,recipe_name=string,recipe_json=string,mode=string,start_contest=integer,end_contest=integer,worker_id=string,run_duration_ms=integer,summary_json=string,output_blob=[]byte,output_name=string,log_blob=[]byte,error_message=string,error_stack=string,created_by=string} "Synchronous simulation completed"
 // @Success 202 {object} object{simulation_id=integer,status=string,message=string} "Asynchronous simulation queued"
 // @Failure 400 {object} models.APIError "Invalid request"
@@ -30,10 +35,12 @@ func SimpleSimulation(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
-			Preset       string `json:"preset"`
-			StartContest int    `json:"start_contest"`
-			EndContest   int    `json:"end_contest"`
-			Async        bool   `json:"async"`
+			Preset       string   `json:"preset"`
+			StartContest int      `json:"start_contest"`
+			EndContest   int      `json:"end_contest"`
+			Async        bool     `json:"async"`
+			Tags         []string `json:"tags,omitempty"`
+			Priority     int      `json:"priority,omitempty"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -73,8 +80,18 @@ func SimpleSimulation(
 			StartContest: req.StartContest,
 			EndContest:   req.EndContest,
 			Async:        req.Async,
+			Tags:         req.Tags,
+			Priority:     req.Priority,
 		})
 		if err != nil {
+			if errors.Is(err, services.ErrInvalidTags) || errors.Is(err, services.ErrInvalidPriority) {
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+				return
+			}
+			if errors.Is(err, services.ErrLimitExceeded) {
+				WriteError(w, r, *models.NewAPIError("limit_exceeded", err.Error()))
+				return
+			}
 			WriteError(w, r, *models.NewAPIError("simulation_creation_failed", "Simulation creation failed"))
 			return
 		}
@@ -92,6 +109,93 @@ func SimpleSimulation(
 	}
 }
 
+// FixedTicketSimulation godoc
+// @Summary Create a fixed-ticket simulation
+// @Description Backtest the same tickets played on every contest of a range ("teimosinha") instead of generating predictions
+// @Tags simulations
+// @Accept json
+// @Produce json
+// @Param request body object{name=string,tickets=[][]integer,start_contest=integer,end_contest=integer,async=boolean,created_by=string,tags=[]string,priority=integer} true "Fixed-ticket simulation request"
+// @Success 200 {object} object{id=integer,created_at=string,started_at=string,finished_at=string,status=string,recipe_name=string,recipe_json=string,mode=string,start_contest=integer,end_contest=integer,worker_id=string,run_duration_ms=integer,summary_json=string,output_blob=[]byte,output_name=string,log_blob=[]byte,error_message=string,error_stack=string,created_by=string} "Synchronous simulation completed"
+// @Success 202 {object} object{simulation_id=integer,status=string,message=string} "Asynchronous simulation queued"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/fixed [post]
+func FixedTicketSimulation(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name         string   `json:"name"`
+			Tickets      [][]int  `json:"tickets"`
+			StartContest int      `json:"start_contest"`
+			EndContest   int      `json:"end_contest"`
+			Async        bool     `json:"async"`
+			CreatedBy    string   `json:"created_by"`
+			Tags         []string `json:"tags,omitempty"`
+			Priority     int      `json:"priority,omitempty"`
+		}
+
+		// The recipe is built from the request, so unknown keys are rejected
+		// here rather than by its schema
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid request format"))
+			return
+		}
+
+		// Validate request
+		if len(req.Tickets) == 0 {
+			WriteError(w, r, *models.NewAPIError("validation_error", "At least one ticket is required"))
+			return
+		}
+		if req.StartContest <= 0 || req.EndContest <= 0 || req.StartContest > req.EndContest {
+			WriteError(w, r, *models.NewAPIError("validation_error", "Invalid contest range"))
+			return
+		}
+		if req.Name == "" {
+			req.Name = "fixed_tickets"
+		}
+
+		sim, err := simSvc.CreateSimulation(r.Context(), services.CreateSimulationRequest{
+			Mode:       "simple",
+			RecipeName: req.Name,
+			Recipe: services.Recipe{
+				Version:    "1.0",
+				Name:       req.Name,
+				Parameters: services.RecipeParameters{FixedTickets: req.Tickets},
+			},
+			StartContest: req.StartContest,
+			EndContest:   req.EndContest,
+			Async:        req.Async,
+			CreatedBy:    req.CreatedBy,
+			Tags:         req.Tags,
+			Priority:     req.Priority,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidRecipe) || errors.Is(err, services.ErrInvalidTags) || errors.Is(err, services.ErrInvalidPriority) {
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+				return
+			}
+			if errors.Is(err, services.ErrLimitExceeded) {
+				WriteError(w, r, *models.NewAPIError("limit_exceeded", err.Error()))
+				return
+			}
+			WriteError(w, r, *models.NewAPIError("simulation_creation_failed", "Simulation creation failed"))
+			return
+		}
+
+		if req.Async {
+			WriteJSON(w, http.StatusAccepted, map[string]any{
+				"simulation_id": sim.ID,
+				"status":        sim.Status,
+				"message":       "Simulation queued for processing",
+			})
+		} else {
+			WriteJSON(w, http.StatusOK, sim)
+		}
+	}
+}
+
 // GetSimulation godoc
 // @Summary Get simulation details
 // @Description Retrieve details of a specific simulation by ID
@@ -171,6 +275,159 @@ func ListSimulations(simSvc services.SimulationServicer) http.HandlerFunc {
 	}
 }
 
+// SearchSimulations godoc
+// @Summary Search simulations
+// @Description Filter, sort and paginate simulations. Returns the total number of matches for pagination.
+// @Tags simulations
+// @Produce json
+// @Param status query string false "Status (pending, running, completed, failed, cancelled)"
+// @Param mode query string false "Mode (simple, advanced)"
+// @Param recipe_name query string false "Recipe name prefix"
+// @Param created_by query string false "Creator"
+// @Param contest_from query integer false "Simulations whose contest range overlaps from this contest"
+// @Param contest_to query integer false "Simulations whose contest range overlaps up to this contest"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param created_to query string false "Created up to (YYYY-MM-DD inclusive, or RFC3339)"
+// @Param metric query string false "Summary metric threshold, e.g. quina_rate>0"
+// @Param tags query string false "Comma-separated tags; all must match"
+// @Param sort query string false "Summary metric to sort by (default: newest first)"
+// @Param order query string false "Sort order (asc, desc)" default(desc)
+// @Param limit query integer false "Maximum number of simulations to return" default(10)
+// @Param offset query integer false "Number of simulations to skip" default(0)
+// @Success 200 {object} services.SimulationSearchResult "Matching simulations"
+// @Failure 400 {object} models.APIError "Invalid search"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/search [get]
+func SearchSimulations(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		search := services.SimulationSearch{
+			Status:      q.Get("status"),
+			Mode:        q.Get("mode"),
+			RecipeName:  q.Get("recipe_name"),
+			CreatedBy:   q.Get("created_by"),
+			CreatedFrom: q.Get("created_from"),
+			CreatedTo:   q.Get("created_to"),
+			Metric:      q.Get("metric"),
+			SortBy:      q.Get("sort"),
+			SortOrder:   q.Get("order"),
+		}
+		if tags := q.Get("tags"); tags != "" {
+			search.Tags = strings.Split(tags, ",")
+		}
+
+		ints := []struct {
+			name string
+			dst  *int
+		}{
+			{"contest_from", &search.ContestFrom},
+			{"contest_to", &search.ContestTo},
+			{"limit", &search.Limit},
+			{"offset", &search.Offset},
+		}
+		for _, p := range ints {
+			v, err := queryInt(q.Get(p.name))
+			if err != nil {
+				WriteError(w, r, *models.NewAPIError("invalid_search", fmt.Sprintf("%s must be an integer", p.name)))
+				return
+			}
+			*p.dst = v
+		}
+
+		result, err := simSvc.SearchSimulations(r.Context(), search)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidSearch) {
+				WriteError(w, r, *models.NewAPIError("invalid_search", err.Error()))
+				return
+			}
+			WriteError(w, r, *models.NewAPIError("list_simulations_failed", "Failed to search simulations"))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, result)
+	}
+}
+
+// GetSimulationTags godoc
+// @Summary Get simulation tags
+// @Description List the tags of a simulation
+// @Tags simulations
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Success 200 {object} object{simulation_id=integer,tags=[]string} "Simulation tags"
+// @Failure 400 {object} models.APIError "Invalid simulation ID"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/tags [get]
+func GetSimulationTags(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		tags, err := simSvc.GetSimulationTags(r.Context(), id)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("get_tags_failed", "Failed to get simulation tags"))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"simulation_id": id,
+			"tags":          tags,
+		})
+	}
+}
+
+// SetSimulationTags godoc
+// @Summary Set simulation tags
+// @Description Replace the tags of a simulation
+// @Tags simulations
+// @Accept json
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Param request body object{tags=[]string} true "New tags"
+// @Success 200 {object} object{simulation_id=integer,tags=[]string} "Simulation tags"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 404 {object} models.APIError "Simulation not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/tags [put]
+func SetSimulationTags(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		tags, err := simSvc.SetSimulationTags(r.Context(), id, req.Tags)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidTags):
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+			case errors.Is(err, sql.ErrNoRows):
+				WriteError(w, r, *models.NewAPIError("not_found", "Simulation not found"))
+			default:
+				WriteError(w, r, *models.NewAPIError("set_tags_failed", "Failed to set simulation tags"))
+			}
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"simulation_id": id,
+			"tags":          tags,
+		})
+	}
+}
+
 // CancelSimulation godoc
 // @Summary Cancel a simulation
 // @Description Cancel a pending or running simulation
@@ -205,6 +462,134 @@ func CancelSimulation(simSvc services.SimulationServicer) http.HandlerFunc {
 	}
 }
 
+// ListSimulationAttempts godoc
+// @Summary List simulation attempts
+// @Description List every run of a simulation with its outcome. Transient failures that were retried have the outcome "retrying" and the time of the next attempt.
+// @Tags simulations
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Success 200 {object} object{simulation_id=integer,attempts=[]object{id=integer,simulation_id=integer,attempt=integer,worker_id=string,started_at=string,finished_at=string,outcome=string,error_message=string,transient=integer,retry_at=string}} "Simulation attempts"
+// @Failure 400 {object} models.APIError "Invalid simulation ID"
+// @Failure 404 {object} models.APIError "Simulation not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/attempts [get]
+func ListSimulationAttempts(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		attempts, err := simSvc.ListSimulationAttempts(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				WriteError(w, r, *models.NewAPIError("not_found", "Simulation not found"))
+				return
+			}
+			WriteError(w, r, *models.NewAPIError("list_attempts_failed", "Failed to list simulation attempts"))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"simulation_id": id,
+			"attempts":      attempts,
+		})
+	}
+}
+
+// UpdateQueuedSimulation godoc
+// @Summary Reprioritise or pause a queued simulation
+// @Description Change the priority of a pending simulation, or pause and resume it. Paused simulations stay pending but are not claimed by workers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Param request body object{priority=integer,paused=boolean} true "Queue update; omitted fields are unchanged"
+// @Success 200 {object} object{id=integer,status=string,priority=integer,paused=integer} "Updated simulation"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 404 {object} models.APIError "Simulation not found"
+// @Failure 409 {object} models.APIError "Simulation is no longer queued"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/admin/simulations/{id} [patch]
+func UpdateQueuedSimulation(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		var update services.QueueUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid JSON in request body"))
+			return
+		}
+
+		sim, err := simSvc.UpdateQueuedSimulation(r.Context(), id, update)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidPriority), errors.Is(err, services.ErrInvalidQueueUpdate):
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+			case errors.Is(err, services.ErrSimulationNotQueued):
+				WriteError(w, r, *models.NewAPIError("simulation_not_queued", err.Error()))
+			case errors.Is(err, sql.ErrNoRows):
+				WriteError(w, r, *models.NewAPIError("not_found", "Simulation not found"))
+			default:
+				WriteError(w, r, *models.NewAPIError("update_queue_failed", "Failed to update queued simulation"))
+			}
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, sim)
+	}
+}
+
+// GetSimulationLogs godoc
+// @Summary Get simulation logs
+// @Description Stream the log records of the last run of a simulation as newline-delimited JSON
+// @Tags simulations
+// @Produce application/x-ndjson
+// @Param id path integer true "Simulation ID"
+// @Success 200 {string} string "Log records, one JSON object per line"
+// @Failure 400 {object} models.APIError "Invalid simulation ID"
+// @Failure 404 {object} models.APIError "Simulation or simulation log not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/logs [get]
+func GetSimulationLogs(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := chi.URLParam(r, "id")
+		if idStr == "" {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Simulation ID is required"))
+			return
+		}
+
+		var id int64
+		if _, err := fmt.Sscanf(idStr, "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		logs, err := simSvc.GetSimulationLog(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrSimulationLogNotFound):
+				WriteError(w, r, *models.NewAPIError("simulation_log_not_found", "Simulation has no captured log"))
+			case errors.Is(err, sql.ErrNoRows):
+				WriteError(w, r, *models.NewAPIError("not_found", "Simulation not found"))
+			default:
+				WriteError(w, r, *models.NewAPIError("get_simulation_log_failed", "Failed to get simulation log"))
+			}
+			return
+		}
+		defer logs.Close()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, logs)
+	}
+}
+
 // GetContestResults godoc
 // @Summary Get simulation contest results
 // @Description Retrieve paginated contest results for a specific simulation
@@ -262,6 +647,220 @@ func GetContestResults(simSvc services.SimulationServicer) http.HandlerFunc {
 	}
 }
 
+// ListSimulationContests godoc
+// @Summary Browse simulation contest results
+// @Description Filter contest results of a simulation by hits and contest range, optionally with every prediction scored against the draw
+// @Tags simulations
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Param min_hits query integer false "Minimum best hits"
+// @Param max_hits query integer false "Maximum best hits"
+// @Param contest_from query integer false "First contest"
+// @Param contest_to query integer false "Last contest"
+// @Param sort query string false "Sort by contest or hits" default(contest)
+// @Param include_predictions query boolean false "Include all predictions with hits and matched numbers"
+// @Param limit query integer false "Maximum number of results to return" default(50)
+// @Param offset query integer false "Number of results to skip" default(0)
+// @Success 200 {object} services.ContestResultPage "Contest results"
+// @Failure 400 {object} models.APIError "Invalid filter"
+// @Failure 404 {object} models.APIError "Simulation not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/contests [get]
+func ListSimulationContests(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		filter, err := parseContestResultFilter(r)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_contest_filter", err.Error()))
+			return
+		}
+
+		page, err := simSvc.ListContestResults(r.Context(), id, filter)
+		if err != nil {
+			writeContestResultsError(w, r, err)
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, page)
+	}
+}
+
+// ExportSimulationContests godoc
+// @Summary Export simulation contest results as CSV
+// @Description Download every contest result matching the filters as CSV. With include_predictions there is one row per prediction.
+// @Tags simulations
+// @Produce text/csv
+// @Param id path integer true "Simulation ID"
+// @Param min_hits query integer false "Minimum best hits"
+// @Param max_hits query integer false "Maximum best hits"
+// @Param contest_from query integer false "First contest"
+// @Param contest_to query integer false "Last contest"
+// @Param sort query string false "Sort by contest or hits" default(contest)
+// @Param include_predictions query boolean false "One row per prediction with hits and matched numbers"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} models.APIError "Invalid filter"
+// @Failure 404 {object} models.APIError "Simulation not found"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/contests/export [get]
+func ExportSimulationContests(simSvc services.SimulationServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		filter, err := parseContestResultFilter(r)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_contest_filter", err.Error()))
+			return
+		}
+
+		// Nothing is written before the first rows are fetched, so filter and
+		// lookup errors still get a JSON response.
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"simulation_%d_contests.csv\"", id))
+
+		if err := simSvc.ExportContestResults(r.Context(), id, filter, w); err != nil {
+			w.Header().Del("Content-Disposition")
+			writeContestResultsError(w, r, err)
+		}
+	}
+}
+
+// CreateContestReport godoc
+// @Summary Queue a contest results report
+// @Description Queue a job exporting the contest results matching the filters as CSV, for exports too large to download directly. Download the CSV from /api/v1/jobs/{id}/report once the job completed.
+// @Tags simulations
+// @Produce json
+// @Param id path integer true "Simulation ID"
+// @Param min_hits query integer false "Minimum best hits"
+// @Param max_hits query integer false "Maximum best hits"
+// @Param contest_from query integer false "First contest"
+// @Param contest_to query integer false "Last contest"
+// @Param sort query string false "Sort by contest or hits" default(contest)
+// @Param include_predictions query boolean false "One row per prediction with hits and matched numbers"
+// @Success 202 {object} services.Job "Report job queued"
+// @Failure 400 {object} models.APIError "Invalid filter"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/simulations/{id}/contests/report [post]
+func CreateContestReport(jobSvc services.JobServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		if _, err := fmt.Sscanf(chi.URLParam(r, "id"), "%d", &id); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_simulation_id", "Invalid simulation ID"))
+			return
+		}
+
+		filter, err := parseContestResultFilter(r)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_contest_filter", err.Error()))
+			return
+		}
+
+		job, err := jobSvc.EnqueueJob(r.Context(), services.JobTypeContestReport, services.ContestReportRequest{
+			SimulationID:       id,
+			MinHits:            filter.MinHits,
+			MaxHits:            filter.MaxHits,
+			ContestFrom:        filter.ContestFrom,
+			ContestTo:          filter.ContestTo,
+			SortByHits:         filter.SortByHits,
+			IncludePredictions: filter.IncludePredictions,
+		}, "")
+		writeJobQueued(w, r, job, err)
+	}
+}
+
+func parseContestResultFilter(r *http.Request) (services.ContestResultFilter, error) {
+	q := r.URL.Query()
+	var filter services.ContestResultFilter
+
+	for _, p := range []struct {
+		name string
+		dst  **int
+	}{
+		{"min_hits", &filter.MinHits},
+		{"max_hits", &filter.MaxHits},
+	} {
+		if v := q.Get(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an integer", p.name)
+			}
+			*p.dst = &n
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{
+		{"contest_from", &filter.ContestFrom},
+		{"contest_to", &filter.ContestTo},
+		{"limit", &filter.Limit},
+		{"offset", &filter.Offset},
+	} {
+		v, err := queryInt(q.Get(p.name))
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an integer", p.name)
+		}
+		*p.dst = v
+	}
+
+	switch q.Get("sort") {
+	case "", "contest":
+	case "hits":
+		filter.SortByHits = true
+	default:
+		return filter, fmt.Errorf("sort must be contest or hits")
+	}
+
+	if v := q.Get("include_predictions"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("include_predictions must be true or false")
+		}
+		filter.IncludePredictions = include
+	}
+
+	return filter, nil
+}
+
+func writeContestResultsError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidContestFilter):
+		WriteError(w, r, *models.NewAPIError("invalid_contest_filter", err.Error()))
+	case errors.Is(err, sql.ErrNoRows):
+		WriteError(w, r, *models.NewAPIError("not_found", "Simulation not found"))
+	default:
+		WriteError(w, r, *models.NewAPIError("get_contest_results_failed", "Failed to get contest results"))
+	}
+}
+
+// decodeRequestRecipe decodes the recipe of a request body with the strict
+// schema of its version, so that unknown keys are rejected rather than
+// dropped.
+func decodeRequestRecipe(raw json.RawMessage) (services.Recipe, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return services.Recipe{}, fmt.Errorf("recipe is required")
+	}
+	var versioned struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(raw, &versioned); err != nil {
+		return services.Recipe{}, fmt.Errorf("invalid recipe: %w", err)
+	}
+	if versioned.Version == "" {
+		return services.Recipe{}, fmt.Errorf("recipe version is required")
+	}
+	return services.DecodeRecipe(raw)
+}
+
 func validateRecipe(recipe services.Recipe) error {
 	if recipe.Version == "" {
 		return fmt.Errorf("recipe version is required")
@@ -269,11 +868,13 @@ func validateRecipe(recipe services.Recipe) error {
 	if recipe.Name == "" {
 		return fmt.Errorf("recipe name is required")
 	}
-	if recipe.Parameters.SimPrevMax <= 0 {
-		return fmt.Errorf("sim_prev_max must be positive")
-	}
-	if recipe.Parameters.SimPreds <= 0 {
-		return fmt.Errorf("sim_preds must be positive")
+	if len(recipe.Parameters.FixedTickets) == 0 {
+		if recipe.Parameters.SimPrevMax <= 0 {
+			return fmt.Errorf("sim_prev_max must be positive")
+		}
+		if recipe.Parameters.SimPreds <= 0 {
+			return fmt.Errorf("sim_preds must be positive")
+		}
 	}
 	if recipe.Parameters.Alpha < 0 || recipe.Parameters.Beta < 0 || recipe.Parameters.Gamma < 0 || recipe.Parameters.Delta < 0 {
 		return fmt.Errorf("weights must be non-negative")
@@ -287,7 +888,7 @@ func validateRecipe(recipe services.Recipe) error {
 // @Tags simulations
 // @Accept json
 // @Produce json
-// @Param request body object{recipe=object{version=string,name=string,algorithm=string,parameters=object{sim_prev_max=integer,sim_preds=integer,alpha=number,beta=number,gamma=number,delta=number}},start_contest=integer,end_contest=integer,async=boolean,save_as_config=boolean,config_name=string,config_description=string} true "Advanced simulation request"
+// @Param request body object{recipe=object{version=string,name=string,parameters=object{sim_prev_max=integer,sim_preds=integer,alpha=number,beta=number,gamma=number,delta=number}},start_contest=integer,end_contest=integer,async=boolean,tags=[]string,priority=integer,save_as_config=boolean,config_name=string,config_description=string} true "Advanced simulation request"
 // @Success 200 {object} object{simulation_id=integer,status=string,simulation=object{id=integer,created_at=string,started_at=string,finished_at=string,status=string,recipe_name=string,recipe_json=string,mode=string,start_contest=integer,end_contest=integer,worker_id=string,run_duration_ms=integer,summary_json=string,output_blob=[]byte,output_name=string,log_blob=[]byte,error_message=string,error_stack=string,created_by=string}} "Synchronous simulation completed"
 // @Success 202 {object} object{simulation_id=integer,status=string,message=string} "Asynchronous simulation queued"
 // @Failure 400 {object} models.APIError "Invalid request"
@@ -299,10 +900,12 @@ func AdvancedSimulation(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
-			Recipe       services.Recipe `json:"recipe"`
+			Recipe       json.RawMessage `json:"recipe"`
 			StartContest int             `json:"start_contest"`
 			EndContest   int             `json:"end_contest"`
 			Async        bool            `json:"async"`
+			Tags         []string        `json:"tags,omitempty"`
+			Priority     int             `json:"priority,omitempty"`
 			SaveAsConfig bool            `json:"save_as_config,omitempty"`
 			ConfigName   string          `json:"config_name,omitempty"`
 			ConfigDesc   string          `json:"config_description,omitempty"`
@@ -320,7 +923,12 @@ func AdvancedSimulation(
 		}
 
 		// Validate recipe
-		if err := validateRecipe(req.Recipe); err != nil {
+		recipe, err := decodeRequestRecipe(req.Recipe)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", err.Error()))
+			return
+		}
+		if err := validateRecipe(recipe); err != nil {
 			WriteError(w, r, *models.NewAPIError("invalid_json", err.Error()))
 			return
 		}
@@ -333,10 +941,10 @@ func AdvancedSimulation(
 			}
 
 			// Create config
-			_, err := configSvc.Create(r.Context(), services.CreateConfigRequest{
+			_, err = configSvc.Create(r.Context(), services.CreateConfigRequest{
 				Name:        req.ConfigName,
 				Description: req.ConfigDesc,
-				Recipe:      req.Recipe,
+				Recipe:      recipe,
 				Mode:        "advanced",
 			})
 			if err != nil {
@@ -348,13 +956,23 @@ func AdvancedSimulation(
 		// Create simulation
 		sim, err := simSvc.CreateSimulation(r.Context(), services.CreateSimulationRequest{
 			Mode:         "advanced",
-			RecipeName:   req.Recipe.Name,
-			Recipe:       req.Recipe,
+			RecipeName:   recipe.Name,
+			Recipe:       recipe,
 			StartContest: req.StartContest,
 			EndContest:   req.EndContest,
 			Async:        req.Async,
+			Tags:         req.Tags,
+			Priority:     req.Priority,
 		})
 		if err != nil {
+			if errors.Is(err, services.ErrInvalidTags) || errors.Is(err, services.ErrInvalidPriority) {
+				WriteError(w, r, *models.NewAPIError("validation_error", err.Error()))
+				return
+			}
+			if errors.Is(err, services.ErrLimitExceeded) {
+				WriteError(w, r, *models.NewAPIError("limit_exceeded", err.Error()))
+				return
+			}
 			WriteError(w, r, *models.NewAPIError("upload_failed", "Simulation creation failed"))
 			return
 		}
@@ -3,13 +3,19 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/garnizeh/luckyfive/internal/models"
 	"github.com/garnizeh/luckyfive/internal/services"
 	"github.com/garnizeh/luckyfive/internal/store/configs"
 	"github.com/garnizeh/luckyfive/internal/store/simulations"
@@ -82,6 +88,16 @@ type MockSimulationService struct {
 	CancelSimulationFunc  func(ctx context.Context, id int64) error
 	GetContestResultsFunc func(ctx context.Context, simulationID int64, limit, offset int) ([]simulations.SimulationContestResult, error)
 	ExecuteSimulationFunc func(ctx context.Context, simID int64) error
+	GetSimulationLogFunc  func(ctx context.Context, id int64) (io.ReadCloser, error)
+	SearchSimulationsFunc func(ctx context.Context, search services.SimulationSearch) (*services.SimulationSearchResult, error)
+	SetSimulationTagsFunc func(ctx context.Context, id int64, tags []string) ([]string, error)
+	GetSimulationTagsFunc func(ctx context.Context, id int64) ([]string, error)
+
+	ListContestResultsFunc   func(ctx context.Context, simulationID int64, filter services.ContestResultFilter) (*services.ContestResultPage, error)
+	ExportContestResultsFunc func(ctx context.Context, simulationID int64, filter services.ContestResultFilter, w io.Writer) error
+
+	UpdateQueuedSimulationFunc func(ctx context.Context, id int64, update services.QueueUpdate) (*simulations.Simulation, error)
+	ListSimulationAttemptsFunc func(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error)
 }
 
 func (m *MockSimulationService) CreateSimulation(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
@@ -126,6 +142,64 @@ func (m *MockSimulationService) ExecuteSimulation(ctx context.Context, simID int
 	return nil
 }
 
+func (m *MockSimulationService) GetSimulationLog(ctx context.Context, id int64) (io.ReadCloser, error) {
+	if m.GetSimulationLogFunc != nil {
+		return m.GetSimulationLogFunc(ctx, id)
+	}
+	return nil, services.ErrSimulationLogNotFound
+}
+
+func (m *MockSimulationService) SearchSimulations(ctx context.Context, search services.SimulationSearch) (*services.SimulationSearchResult, error) {
+	if m.SearchSimulationsFunc != nil {
+		return m.SearchSimulationsFunc(ctx, search)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) SetSimulationTags(ctx context.Context, id int64, tags []string) ([]string, error) {
+	if m.SetSimulationTagsFunc != nil {
+		return m.SetSimulationTagsFunc(ctx, id, tags)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) GetSimulationTags(ctx context.Context, id int64) ([]string, error) {
+	if m.GetSimulationTagsFunc != nil {
+		return m.GetSimulationTagsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) UpdateQueuedSimulation(ctx context.Context, id int64, update services.QueueUpdate) (*simulations.Simulation, error) {
+	if m.UpdateQueuedSimulationFunc != nil {
+		return m.UpdateQueuedSimulationFunc(ctx, id, update)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) ListSimulationAttempts(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error) {
+	if m.ListSimulationAttemptsFunc != nil {
+		return m.ListSimulationAttemptsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) NotifySimulationFinished(ctx context.Context, simID int64) {}
+
+func (m *MockSimulationService) ListContestResults(ctx context.Context, simulationID int64, filter services.ContestResultFilter) (*services.ContestResultPage, error) {
+	if m.ListContestResultsFunc != nil {
+		return m.ListContestResultsFunc(ctx, simulationID, filter)
+	}
+	return nil, nil
+}
+
+func (m *MockSimulationService) ExportContestResults(ctx context.Context, simulationID int64, filter services.ContestResultFilter, w io.Writer) error {
+	if m.ExportContestResultsFunc != nil {
+		return m.ExportContestResultsFunc(ctx, simulationID, filter, w)
+	}
+	return nil
+}
+
 func TestSimpleSimulation_ValidRequest(t *testing.T) {
 	// Mock services
 	mockConfigSvc := &MockConfigService{
@@ -182,6 +256,148 @@ func TestSimpleSimulation_ValidRequest(t *testing.T) {
 	}
 }
 
+func TestFixedTicketSimulation_ValidRequest(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		CreateSimulationFunc: func(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
+			if len(req.Recipe.Parameters.FixedTickets) != 2 {
+				t.Errorf("expected 2 fixed tickets, got %d", len(req.Recipe.Parameters.FixedTickets))
+			}
+			if req.RecipeName != "fixed_tickets" {
+				t.Errorf("expected default recipe name, got %q", req.RecipeName)
+			}
+			return &simulations.Simulation{ID: 2, Status: "pending"}, nil
+		},
+	}
+
+	reqBody := map[string]any{
+		"tickets":       [][]int{{1, 2, 3, 4, 5}, {6, 7, 8, 9, 10, 11}},
+		"start_contest": 1000,
+		"end_contest":   1010,
+		"async":         true,
+	}
+	reqBytes, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/v1/simulations/fixed", bytes.NewReader(reqBytes))
+	w := httptest.NewRecorder()
+
+	FixedTicketSimulation(mockSimSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, got %d", http.StatusAccepted, w.Code)
+	}
+}
+
+func TestRecipeWrites_RejectUnknownKeys(t *testing.T) {
+	// Neither service may be reached with a recipe its schema rejects
+	simSvc := &MockSimulationService{
+		CreateSimulationFunc: func(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
+			t.Error("unexpected CreateSimulation call")
+			return nil, nil
+		},
+	}
+	configSvc := &MockConfigsService{
+		CreateFunc: func(ctx context.Context, req services.CreateConfigRequest) (configs.Config, error) {
+			t.Error("unexpected config Create call")
+			return configs.Config{}, nil
+		},
+	}
+	recipe := `{"version":"1.2","name":"r","parameters":{"sim_prev_max":10,"sim_preds":5,"alphaa":0.5}}`
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+	}{
+		{"advanced simulation", AdvancedSimulation(configSvc, simSvc), `{"recipe":` + recipe + `,"start_contest":1000,"end_contest":1010}`},
+		{"create config", CreateConfig(configSvc), `{"name":"c","mode":"simple","recipe":` + recipe + `}`},
+		{"fixed ticket simulation", FixedTicketSimulation(simSvc), `{"tickets":[[1,2,3,4,5]],"start_contest":1000,"end_contest":1010,"alpha":0.5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdvancedSimulation_UpgradesRecipe(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		CreateSimulationFunc: func(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
+			if req.Recipe.Version != "1.2" || req.Recipe.Parameters.Alpha != 0.5 || req.Recipe.Parameters.SimPreds != 5 {
+				t.Errorf("expected the recipe upgraded to 1.2, got %+v", req.Recipe)
+			}
+			return &simulations.Simulation{ID: 3, Status: "pending"}, nil
+		},
+	}
+
+	body := `{"recipe":{"version":"1.0","name":"r","parameters":{"sim_prev_max":10,"sim_preds":5,"alpha":0.5}},"start_contest":1000,"end_contest":1010,"async":true}`
+	req := httptest.NewRequest("POST", "/api/v1/simulations/advanced", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	AdvancedSimulation(&MockConfigsService{}, mockSimSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+}
+
+func TestFixedTicketSimulation_InvalidTicket(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		CreateSimulationFunc: func(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
+			return nil, fmt.Errorf("%w: fixed ticket 1: duplicate number 1", services.ErrInvalidRecipe)
+		},
+	}
+
+	reqBytes, _ := json.Marshal(map[string]any{
+		"tickets":       [][]int{{1, 1, 2, 3, 4}},
+		"start_contest": 1000,
+		"end_contest":   1010,
+	})
+	req := httptest.NewRequest("POST", "/api/v1/simulations/fixed", bytes.NewReader(reqBytes))
+	w := httptest.NewRecorder()
+
+	FixedTicketSimulation(mockSimSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestFixedTicketSimulation_LimitExceeded(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		CreateSimulationFunc: func(ctx context.Context, req services.CreateSimulationRequest) (*simulations.Simulation, error) {
+			return nil, fmt.Errorf("%w: 11 contests, max_contests is 5", services.ErrLimitExceeded)
+		},
+	}
+
+	reqBytes, _ := json.Marshal(map[string]any{
+		"tickets":       [][]int{{1, 2, 3, 4, 5}},
+		"start_contest": 1000,
+		"end_contest":   1010,
+	})
+	req := httptest.NewRequest("POST", "/api/v1/simulations/fixed", bytes.NewReader(reqBytes))
+	w := httptest.NewRecorder()
+
+	FixedTicketSimulation(mockSimSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var apiErr models.APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if apiErr.Code != "limit_exceeded" {
+		t.Errorf("Expected code limit_exceeded, got %s", apiErr.Code)
+	}
+}
+
 func TestSimpleSimulation_InvalidJSON(t *testing.T) {
 	mockConfigSvc := &MockConfigService{}
 	mockSimSvc := &MockSimulationService{}
@@ -346,6 +562,282 @@ func TestCancelSimulation_ValidID(t *testing.T) {
 	}
 }
 
+func TestGetSimulationLogs_ValidID(t *testing.T) {
+	logLines := `{"level":"INFO","msg":"simulation started","job_id":1}` + "\n"
+	mockSimSvc := &MockSimulationService{
+		GetSimulationLogFunc: func(ctx context.Context, id int64) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(logLines)), nil
+		},
+	}
+
+	handler := GetSimulationLogs(mockSimSvc)
+
+	req := httptest.NewRequest("GET", "/api/v1/simulations/1/logs", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected ndjson content type, got %q", ct)
+	}
+	if w.Body.String() != logLines {
+		t.Errorf("Expected log body %q, got %q", logLines, w.Body.String())
+	}
+}
+
+func TestGetSimulationLogs_NotFound(t *testing.T) {
+	mockSimSvc := &MockSimulationService{}
+
+	handler := GetSimulationLogs(mockSimSvc)
+
+	req := httptest.NewRequest("GET", "/api/v1/simulations/1/logs", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestGetSimulationLogs_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"simulation not found", sql.ErrNoRows, http.StatusNotFound},
+		{"log not captured", services.ErrSimulationLogNotFound, http.StatusNotFound},
+		{"corrupt log", errors.New("decompress log: gzip: invalid header"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSimSvc := &MockSimulationService{
+				GetSimulationLogFunc: func(ctx context.Context, id int64) (io.ReadCloser, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest("GET", "/api/v1/simulations/1/logs", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			GetSimulationLogs(mockSimSvc).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status code %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestSearchSimulations_ParsesFilters(t *testing.T) {
+	var got services.SimulationSearch
+	mockSimSvc := &MockSimulationService{
+		SearchSimulationsFunc: func(ctx context.Context, search services.SimulationSearch) (*services.SimulationSearchResult, error) {
+			got = search
+			return &services.SimulationSearchResult{
+				Simulations: []simulations.Simulation{{ID: 1, Status: "completed"}},
+				Total:       3,
+				Limit:       1,
+				Offset:      0,
+			}, nil
+		},
+	}
+
+	handler := SearchSimulations(mockSimSvc)
+
+	req := httptest.NewRequest("GET", "/api/v1/simulations/search?status=completed&recipe_name=freq&contest_from=100&contest_to=200&metric=quina_rate%3E0&tags=a,b&sort=avg_hits&order=asc&limit=1", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.Status != "completed" || got.RecipeName != "freq" || got.ContestFrom != 100 || got.ContestTo != 200 {
+		t.Errorf("Unexpected filters: %+v", got)
+	}
+	if got.Metric != "quina_rate>0" || got.SortBy != "avg_hits" || got.SortOrder != "asc" || got.Limit != 1 {
+		t.Errorf("Unexpected metric or sorting: %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+		t.Errorf("Expected tags [a b], got %v", got.Tags)
+	}
+
+	var response map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["total"].(float64) != 3 {
+		t.Errorf("Expected total 3, got %v", response["total"])
+	}
+}
+
+func TestSearchSimulations_InvalidSearch(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		SearchSimulationsFunc: func(ctx context.Context, search services.SimulationSearch) (*services.SimulationSearchResult, error) {
+			return nil, fmt.Errorf("%w: unknown metric", services.ErrInvalidSearch)
+		},
+	}
+
+	handler := SearchSimulations(mockSimSvc)
+
+	for _, url := range []string{
+		"/api/v1/simulations/search?metric=nope>1",
+		"/api/v1/simulations/search?contest_from=abc",
+	} {
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status code %d, got %d", url, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestSetSimulationTags(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		SetSimulationTagsFunc: func(ctx context.Context, id int64, tags []string) ([]string, error) {
+			if id != 7 {
+				return nil, sql.ErrNoRows
+			}
+			return tags, nil
+		},
+	}
+
+	handler := SetSimulationTags(mockSimSvc)
+
+	tests := []struct {
+		id     string
+		body   string
+		status int
+	}{
+		{"7", `{"tags":["baseline"]}`, http.StatusOK},
+		{"8", `{"tags":["baseline"]}`, http.StatusNotFound},
+		{"7", `{"tags":`, http.StatusBadRequest},
+		{"abc", `{"tags":[]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("PUT", "/api/v1/simulations/"+tt.id+"/tags", strings.NewReader(tt.body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", tt.id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("id=%s body=%s: expected status code %d, got %d", tt.id, tt.body, tt.status, w.Code)
+		}
+	}
+}
+
+func TestListSimulationAttempts(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		ListSimulationAttemptsFunc: func(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error) {
+			switch id {
+			case 8:
+				return nil, sql.ErrNoRows
+			case 9:
+				return nil, fmt.Errorf("database is locked")
+			}
+			return []simulations.SimulationAttempt{
+				{SimulationID: id, Attempt: 1, Outcome: "retrying", Transient: 1},
+				{SimulationID: id, Attempt: 2, Outcome: "completed"},
+			}, nil
+		},
+	}
+
+	handler := ListSimulationAttempts(mockSimSvc)
+
+	tests := []struct {
+		id     string
+		status int
+	}{
+		{"7", http.StatusOK},
+		{"8", http.StatusNotFound},
+		{"9", http.StatusInternalServerError},
+		{"abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/simulations/"+tt.id+"/attempts", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", tt.id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("id=%s: expected status code %d, got %d", tt.id, tt.status, w.Code)
+		}
+		if tt.status == http.StatusOK && !strings.Contains(w.Body.String(), `"outcome":"retrying"`) {
+			t.Errorf("expected attempts in response, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestUpdateQueuedSimulation(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		UpdateQueuedSimulationFunc: func(ctx context.Context, id int64, update services.QueueUpdate) (*simulations.Simulation, error) {
+			switch {
+			case id == 8:
+				return nil, sql.ErrNoRows
+			case id == 9:
+				return nil, fmt.Errorf("%w: simulation 9 is running", services.ErrSimulationNotQueued)
+			case update.Priority != nil && *update.Priority > services.MaxSimulationPriority:
+				return nil, services.ErrInvalidPriority
+			}
+			return &simulations.Simulation{ID: id, Status: "pending", Priority: int64(*update.Priority)}, nil
+		},
+	}
+
+	handler := UpdateQueuedSimulation(mockSimSvc)
+
+	tests := []struct {
+		id     string
+		body   string
+		status int
+	}{
+		{"7", `{"priority":10}`, http.StatusOK},
+		{"7", `{"priority":1000}`, http.StatusBadRequest},
+		{"8", `{"priority":10}`, http.StatusNotFound},
+		{"9", `{"priority":10}`, http.StatusConflict},
+		{"7", `{"priority":`, http.StatusBadRequest},
+		{"abc", `{"priority":10}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("PATCH", "/api/v1/admin/simulations/"+tt.id, strings.NewReader(tt.body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", tt.id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("id=%s body=%s: expected status code %d, got %d", tt.id, tt.body, tt.status, w.Code)
+		}
+	}
+}
+
 func TestAdvancedSimulation_ValidRequest(t *testing.T) {
 	// Mock services
 	mockConfigSvc := &MockConfigService{}
@@ -577,6 +1069,121 @@ func TestGetContestResults_ValidRequest(t *testing.T) {
 	}
 }
 
+func TestListSimulationContests_ParsesFilter(t *testing.T) {
+	var got services.ContestResultFilter
+	mockSimSvc := &MockSimulationService{
+		ListContestResultsFunc: func(ctx context.Context, simulationID int64, filter services.ContestResultFilter) (*services.ContestResultPage, error) {
+			got = filter
+			return &services.ContestResultPage{SimulationID: simulationID, Results: []services.ContestResultView{}, Total: 0, Limit: 50}, nil
+		},
+	}
+
+	handler := ListSimulationContests(mockSimSvc)
+
+	req := httptest.NewRequest("GET", "/api/v1/simulations/1/contests?min_hits=0&max_hits=3&contest_from=1001&sort=hits&include_predictions=true", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.MinHits == nil || *got.MinHits != 0 || got.MaxHits == nil || *got.MaxHits != 3 {
+		t.Errorf("Unexpected hit bounds: %v %v", got.MinHits, got.MaxHits)
+	}
+	if got.ContestFrom != 1001 || got.ContestTo != 0 || !got.SortByHits || !got.IncludePredictions {
+		t.Errorf("Unexpected filter: %+v", got)
+	}
+}
+
+func TestListSimulationContests_Errors(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		ListContestResultsFunc: func(ctx context.Context, simulationID int64, filter services.ContestResultFilter) (*services.ContestResultPage, error) {
+			if simulationID == 2 {
+				return nil, fmt.Errorf("get simulation: %w", sql.ErrNoRows)
+			}
+			return nil, fmt.Errorf("%w: hits must be between 0 and 5", services.ErrInvalidContestFilter)
+		},
+	}
+
+	handler := ListSimulationContests(mockSimSvc)
+
+	tests := []struct {
+		id     string
+		query  string
+		status int
+	}{
+		{"1", "min_hits=9", http.StatusBadRequest},
+		{"1", "min_hits=x", http.StatusBadRequest},
+		{"1", "sort=score", http.StatusBadRequest},
+		{"1", "include_predictions=maybe", http.StatusBadRequest},
+		{"2", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/simulations/"+tt.id+"/contests?"+tt.query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", tt.id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("id=%s %s: expected status code %d, got %d", tt.id, tt.query, tt.status, w.Code)
+		}
+	}
+}
+
+func TestExportSimulationContests(t *testing.T) {
+	mockSimSvc := &MockSimulationService{
+		ExportContestResultsFunc: func(ctx context.Context, simulationID int64, filter services.ContestResultFilter, w io.Writer) error {
+			if simulationID == 2 {
+				return fmt.Errorf("get simulation: %w", sql.ErrNoRows)
+			}
+			_, err := io.WriteString(w, "contest,actual_numbers\n1001,6 7 8 9 10\n")
+			return err
+		},
+	}
+
+	handler := ExportSimulationContests(mockSimSvc)
+
+	req := httptest.NewRequest("GET", "/api/v1/simulations/1/contests/export", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Expected CSV content type, got %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "1001,6 7 8 9 10") {
+		t.Errorf("Unexpected body: %q", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/simulations/2/contests/export", nil)
+	rctx = chi.NewRouteContext()
+	rctx.URLParams.Add("id", "2")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON error, got %q", ct)
+	}
+}
+
 func TestGetContestResults_InvalidID(t *testing.T) {
 	mockSimSvc := &MockSimulationService{}
 
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,18 +12,18 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// CreateSweep creates a new sweep job
+// CreateSweep queues the creation of a new sweep job
 // @Summary Create a new sweep job
-// @Description Create a parameter sweep job that will generate and execute multiple simulations
+// @Description Queue a parameter sweep that will generate and execute multiple simulations. A worker creates the simulations; the returned job reports their creation as progress and the sweep job as its result.
 // @Tags sweeps
 // @Accept json
 // @Produce json
 // @Param request body services.CreateSweepRequest true "Sweep creation request"
-// @Success 202 {object} object{id=integer,name=string,description=string,sweep_config_json=string,base_contest_range=string,status=string,total_combinations=integer,completed_simulations=integer,failed_simulations=integer,created_at=string,started_at=string,finished_at=string,run_duration_ms=integer,created_by=string} "Sweep job created successfully"
+// @Success 202 {object} services.Job "Sweep creation job queued"
 // @Failure 400 {object} models.APIError "Invalid request"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /api/v1/sweeps [post]
-func CreateSweep(sweepSvc services.SweepServicer) http.HandlerFunc {
+func CreateSweep(jobSvc services.JobServicer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req services.CreateSweepRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -30,13 +31,8 @@ func CreateSweep(sweepSvc services.SweepServicer) http.HandlerFunc {
 			return
 		}
 
-		sweep, err := sweepSvc.CreateSweep(r.Context(), req)
-		if err != nil {
-			WriteError(w, r, *models.NewAPIError("create_sweep_failed", err.Error()))
-			return
-		}
-
-		WriteJSON(w, http.StatusAccepted, sweep)
+		job, err := jobSvc.EnqueueJob(r.Context(), services.JobTypeSweep, req, req.CreatedBy)
+		writeJobQueued(w, r, job, err)
 	}
 }
 
@@ -152,16 +148,17 @@ func GetSweepResults(sweepSvc services.SweepServicer) http.HandlerFunc {
 	}
 }
 
-// CancelSweep cancels a running sweep job
+// CancelSweep cancels a sweep job and its unfinished simulations
 // @Summary Cancel sweep job
-// @Description Cancel a running sweep job and stop all pending simulations
+// @Description Cancel a sweep job. Its pending simulations are cancelled and its running ones stop when their worker next extends the lease. Cancelling a cancelled sweep again affects no simulations.
 // @Tags sweeps
 // @Accept json
 // @Produce json
 // @Param id path int true "Sweep job ID"
-// @Success 200 {object} object{message=string} "Sweep job cancelled successfully"
+// @Success 200 {object} services.SweepCancellation "Sweep job cancelled"
 // @Failure 400 {object} models.APIError "Invalid ID"
 // @Failure 404 {object} models.APIError "Sweep job not found"
+// @Failure 409 {object} models.APIError "Sweep job already finished"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /api/v1/sweeps/{id}/cancel [post]
 func CancelSweep(sweepSvc services.SweepServicer) http.HandlerFunc {
@@ -178,17 +175,20 @@ func CancelSweep(sweepSvc services.SweepServicer) http.HandlerFunc {
 			return
 		}
 
-		// For now, we'll just update the progress to mark as cancelled
-		// In a real implementation, this would cancel running simulations
-		err = sweepSvc.UpdateSweepProgress(r.Context(), id)
-		if err != nil {
+		cancellation, err := sweepSvc.CancelSweep(r.Context(), id)
+		switch {
+		case errors.Is(err, services.ErrSweepNotFound):
+			WriteError(w, r, *models.NewAPIError("sweep_not_found", "Sweep job not found"))
+			return
+		case errors.Is(err, services.ErrSweepNotCancellable):
+			WriteError(w, r, *models.NewAPIError("sweep_not_cancellable", err.Error()))
+			return
+		case err != nil:
 			WriteError(w, r, *models.NewAPIError("cancel_sweep_failed", err.Error()))
 			return
 		}
 
-		WriteJSON(w, http.StatusOK, map[string]string{
-			"message": "Sweep job cancellation requested",
-		})
+		WriteJSON(w, http.StatusOK, cancellation)
 	}
 }
 
@@ -300,6 +300,7 @@ func convertSweepStatusToResponse(status *services.SweepStatus) *models.SweepSta
 		Completed: status.Completed,
 		Running:   status.Running,
 		Failed:    status.Failed,
+		Cancelled: status.Cancelled,
 		Pending:   status.Pending,
 	}
 
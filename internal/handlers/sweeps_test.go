@@ -18,17 +18,14 @@ import (
 
 // Mock implementation of SweepServicer for testing
 type mockSweepService struct {
-	createSweepFunc         func(ctx context.Context, req services.CreateSweepRequest) (*sweep_execution.SweepJob, error)
 	getSweepStatusFunc      func(ctx context.Context, sweepID int64) (*services.SweepStatus, error)
 	updateSweepProgressFunc func(ctx context.Context, sweepID int64) error
+	cancelSweepFunc         func(ctx context.Context, sweepID int64) (*services.SweepCancellation, error)
 	findBestFunc            func(ctx context.Context, sweepID int64, metric string) (*services.BestConfiguration, error)
 	getVisualizationFunc    func(ctx context.Context, sweepID int64, metrics []string) (*services.VisualizationData, error)
 }
 
 func (m *mockSweepService) CreateSweep(ctx context.Context, req services.CreateSweepRequest) (*sweep_execution.SweepJob, error) {
-	if m.createSweepFunc != nil {
-		return m.createSweepFunc(ctx, req)
-	}
 	return &sweep_execution.SweepJob{
 		ID:                1,
 		Name:              "Test Sweep",
@@ -67,6 +64,13 @@ func (m *mockSweepService) UpdateSweepProgress(ctx context.Context, sweepID int6
 	return nil
 }
 
+func (m *mockSweepService) CancelSweep(ctx context.Context, sweepID int64) (*services.SweepCancellation, error) {
+	if m.cancelSweepFunc != nil {
+		return m.cancelSweepFunc(ctx, sweepID)
+	}
+	return &services.SweepCancellation{SweepID: sweepID, Cancelled: 3, Stopped: 1}, nil
+}
+
 func (m *mockSweepService) FindBest(ctx context.Context, sweepID int64, metric string) (*services.BestConfiguration, error) {
 	if m.findBestFunc != nil {
 		return m.findBestFunc(ctx, sweepID, metric)
@@ -111,9 +115,13 @@ func (m *mockSweepService) GetVisualizationData(ctx context.Context, sweepID int
 }
 
 func TestCreateSweep_Success(t *testing.T) {
-	mockSvc := &mockSweepService{
-		createSweepFunc: func(ctx context.Context, req services.CreateSweepRequest) (*sweep_execution.SweepJob, error) {
+	mockSvc := &mockJobService{
+		enqueueJobFunc: func(ctx context.Context, jobType string, payload any, createdBy string) (*services.Job, error) {
+			if jobType != services.JobTypeSweep {
+				t.Errorf("expected sweep job, got %s", jobType)
+			}
 			// Verify request parameters
+			req := payload.(services.CreateSweepRequest)
 			if req.Name != "Test Sweep" {
 				t.Errorf("expected name 'Test Sweep', got %s", req.Name)
 			}
@@ -123,12 +131,10 @@ func TestCreateSweep_Success(t *testing.T) {
 			if req.EndContest != 100 {
 				t.Errorf("expected end_contest 100, got %d", req.EndContest)
 			}
-			return &sweep_execution.SweepJob{
-				ID:                1,
-				Name:              req.Name,
-				Status:            "pending",
-				TotalCombinations: 10,
-			}, nil
+			if createdBy != "tester" {
+				t.Errorf("expected created_by 'tester', got %s", createdBy)
+			}
+			return &services.Job{ID: 1, Type: jobType, Status: "pending", CreatedBy: createdBy}, nil
 		},
 	}
 
@@ -137,6 +143,7 @@ func TestCreateSweep_Success(t *testing.T) {
 		Description:  "Test sweep description",
 		StartContest: 1,
 		EndContest:   100,
+		CreatedBy:    "tester",
 	}
 
 	body, _ := json.Marshal(reqBody)
@@ -152,8 +159,11 @@ func TestCreateSweep_Success(t *testing.T) {
 	if w.Code != http.StatusAccepted {
 		t.Errorf("expected status 202, got %d", w.Code)
 	}
+	if loc := w.Header().Get("Location"); loc != "/api/v1/jobs/1" {
+		t.Errorf("expected job location, got %q", loc)
+	}
 
-	var response sweep_execution.SweepJob
+	var response services.Job
 	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
@@ -162,8 +172,8 @@ func TestCreateSweep_Success(t *testing.T) {
 		t.Errorf("expected ID 1, got %d", response.ID)
 	}
 
-	if response.Name != "Test Sweep" {
-		t.Errorf("expected name 'Test Sweep', got %s", response.Name)
+	if response.Type != services.JobTypeSweep {
+		t.Errorf("expected type 'sweep', got %s", response.Type)
 	}
 }
 
@@ -280,18 +290,52 @@ func TestCancelSweep_Success(t *testing.T) {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
 
-	var response map[string]string
+	var response services.SweepCancellation
 	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 
-	if response["message"] != "Sweep job cancellation requested" {
-		t.Errorf("unexpected message: %s", response["message"])
+	if response.SweepID != 1 || response.Cancelled != 3 || response.Stopped != 1 || response.AlreadyCancelled {
+		t.Errorf("unexpected cancellation: %+v", response)
+	}
+}
+
+func TestCancelSweep_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", services.ErrSweepNotFound, http.StatusNotFound},
+		{"finished", services.ErrSweepNotCancellable, http.StatusConflict},
+		{"failure", fmt.Errorf("database is locked"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mockSweepService{
+				cancelSweepFunc: func(ctx context.Context, sweepID int64) (*services.SweepCancellation, error) {
+					return nil, tt.err
+				},
+			}
+
+			req := httptest.NewRequest("POST", "/api/v1/sweeps/1/cancel", nil)
+			w := httptest.NewRecorder()
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			CancelSweep(mockSvc).ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("expected status %d, got %d", tt.code, w.Code)
+			}
+		})
 	}
 }
 
 func TestCreateSweep_InvalidJSON(t *testing.T) {
-	mockSvc := &mockSweepService{}
+	mockSvc := &mockJobService{}
 
 	// Create request with invalid JSON
 	req := httptest.NewRequest("POST", "/api/v1/sweeps", bytes.NewReader([]byte("invalid json")))
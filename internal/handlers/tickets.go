@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+)
+
+// CreateTicket godoc
+// @Summary Register a placed ticket
+// @Description Record a ticket actually played on one or more consecutive contests. Contests already drawn are checked immediately; the rest are checked as their draws are imported.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param request body services.CreateTicketRequest true "Ticket request"
+// @Success 201 {object} models.TicketResponse "Ticket created"
+// @Failure 400 {object} models.APIError "Invalid request"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/tickets [post]
+func CreateTicket(ticketSvc services.TicketServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req services.CreateTicketRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_json", "Invalid request format"))
+			return
+		}
+
+		ticket, err := ticketSvc.CreateTicket(r.Context(), req)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidTicket) {
+				WriteError(w, r, *models.NewAPIError("invalid_ticket", err.Error()))
+				return
+			}
+			WriteError(w, r, *models.NewAPIError("create_ticket_failed", err.Error()))
+			return
+		}
+
+		WriteJSON(w, http.StatusCreated, toTicketResponse(*ticket, nil))
+	}
+}
+
+// GetTicket godoc
+// @Summary Get ticket
+// @Description Retrieve a ticket with its result for every contest checked so far
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} models.TicketResponse "Ticket details"
+// @Failure 400 {object} models.APIError "Invalid ID"
+// @Failure 404 {object} models.APIError "Ticket not found"
+// @Router /api/v1/tickets/{id} [get]
+func GetTicket(ticketSvc services.TicketServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("invalid_ticket_id", "Invalid ticket ID"))
+			return
+		}
+
+		detail, err := ticketSvc.GetTicket(r.Context(), id)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("ticket_not_found", "Ticket not found"))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, toTicketResponse(detail.Ticket, detail.Checks))
+	}
+}
+
+// ListTickets godoc
+// @Summary List tickets
+// @Description Retrieve a paginated list of placed tickets, newest first, optionally filtered by owner
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param owner query string false "Only return tickets of this owner"
+// @Param limit query integer false "Maximum number of tickets to return" default(10)
+// @Param offset query integer false "Number of tickets to skip" default(0)
+// @Success 200 {object} object{tickets=[]models.TicketResponse,limit=integer,offset=integer} "List of tickets"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/tickets [get]
+func ListTickets(ticketSvc services.TicketServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 10 // default
+		offset := 0 // default
+
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+				limit = l
+			}
+		}
+
+		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+			if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+				offset = o
+			}
+		}
+
+		tickets, err := ticketSvc.ListTickets(r.Context(), r.URL.Query().Get("owner"), limit, offset)
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("list_tickets_failed", err.Error()))
+			return
+		}
+
+		resp := make([]models.TicketResponse, len(tickets))
+		for i, t := range tickets {
+			resp[i] = toTicketResponse(t, nil)
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"tickets": resp,
+			"limit":   limit,
+			"offset":  offset,
+		})
+	}
+}
+
+func toTicketResponse(t finances.Ticket, checks []finances.TicketCheck) models.TicketResponse {
+	resp := models.TicketResponse{
+		ID:              t.ID,
+		Owner:           t.Owner,
+		FirstContest:    t.FirstContest,
+		LastContest:     t.LastContest,
+		CostCents:       t.CostCents,
+		Notes:           t.Notes.String,
+		Status:          t.Status,
+		TotalPrizeCents: t.TotalPrizeCents,
+		CreatedAt:       t.CreatedAt.String,
+	}
+	if t.PredictionID.Valid {
+		resp.PredictionID = &t.PredictionID.Int64
+	}
+	_ = json.Unmarshal([]byte(t.Numbers), &resp.Numbers)
+
+	for _, c := range checks {
+		check := models.TicketCheckResponse{
+			Contest:    c.Contest,
+			Hits:       c.Hits,
+			PrizeType:  c.PrizeType.String,
+			PrizeCents: c.PrizeCents,
+			CheckedAt:  c.CheckedAt.String,
+		}
+		_ = json.Unmarshal([]byte(c.MatchedNumbers), &check.MatchedNumbers)
+		resp.Checks = append(resp.Checks, check)
+	}
+
+	return resp
+}
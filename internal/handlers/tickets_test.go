@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+)
+
+// Mock implementation of TicketServicer for testing
+type mockTicketService struct {
+	createTicketFunc func(ctx context.Context, req services.CreateTicketRequest) (*finances.Ticket, error)
+	getTicketFunc    func(ctx context.Context, id int64) (*services.TicketDetail, error)
+	listTicketsFunc  func(ctx context.Context, owner string, limit, offset int) ([]finances.Ticket, error)
+}
+
+func (m *mockTicketService) CreateTicket(ctx context.Context, req services.CreateTicketRequest) (*finances.Ticket, error) {
+	if m.createTicketFunc != nil {
+		return m.createTicketFunc(ctx, req)
+	}
+	return &finances.Ticket{ID: 1, Numbers: "[1,2,3,4,5]", Status: "pending"}, nil
+}
+
+func (m *mockTicketService) GetTicket(ctx context.Context, id int64) (*services.TicketDetail, error) {
+	if m.getTicketFunc != nil {
+		return m.getTicketFunc(ctx, id)
+	}
+	return &services.TicketDetail{Ticket: finances.Ticket{ID: id, Numbers: "[1,2,3,4,5]", Status: "pending"}}, nil
+}
+
+func (m *mockTicketService) ListTickets(ctx context.Context, owner string, limit, offset int) ([]finances.Ticket, error) {
+	if m.listTicketsFunc != nil {
+		return m.listTicketsFunc(ctx, owner, limit, offset)
+	}
+	return []finances.Ticket{}, nil
+}
+
+func (m *mockTicketService) CheckContest(ctx context.Context, contest int) (int, error) {
+	return 0, nil
+}
+
+func TestCreateTicket_Success(t *testing.T) {
+	mockSvc := &mockTicketService{
+		createTicketFunc: func(ctx context.Context, req services.CreateTicketRequest) (*finances.Ticket, error) {
+			if req.Owner != "alice" || len(req.Numbers) != 6 || req.FirstContest != 6001 {
+				t.Errorf("unexpected request: %+v", req)
+			}
+			return &finances.Ticket{
+				ID:           3,
+				Owner:        req.Owner,
+				Numbers:      "[1,2,3,4,5,6]",
+				FirstContest: 6001,
+				LastContest:  6001,
+				CostCents:    1500,
+				PredictionID: sql.NullInt64{Int64: 9, Valid: true},
+				Status:       "pending",
+			}, nil
+		},
+	}
+
+	body, _ := json.Marshal(map[string]any{"owner": "alice", "numbers": []int{1, 2, 3, 4, 5, 6}, "first_contest": 6001, "prediction_id": 9})
+	req := httptest.NewRequest("POST", "/api/v1/tickets", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	CreateTicket(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var resp models.TicketResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Numbers) != 6 || resp.CostCents != 1500 {
+		t.Errorf("unexpected ticket: %+v", resp)
+	}
+	if resp.PredictionID == nil || *resp.PredictionID != 9 {
+		t.Errorf("expected prediction ID 9, got %v", resp.PredictionID)
+	}
+}
+
+func TestCreateTicket_Invalid(t *testing.T) {
+	mockSvc := &mockTicketService{
+		createTicketFunc: func(ctx context.Context, req services.CreateTicketRequest) (*finances.Ticket, error) {
+			return nil, fmt.Errorf("%w: owner is required", services.ErrInvalidTicket)
+		},
+	}
+
+	body, _ := json.Marshal(map[string]any{"numbers": []int{1, 2, 3, 4, 5}, "first_contest": 1})
+	req := httptest.NewRequest("POST", "/api/v1/tickets", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	CreateTicket(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetTicket_WithChecks(t *testing.T) {
+	mockSvc := &mockTicketService{
+		getTicketFunc: func(ctx context.Context, id int64) (*services.TicketDetail, error) {
+			return &services.TicketDetail{
+				Ticket: finances.Ticket{ID: id, Numbers: "[1,2,3,4,5]", Status: "settled", TotalPrizeCents: 10000},
+				Checks: []finances.TicketCheck{
+					{Contest: 6001, Hits: 3, MatchedNumbers: "[1,2,3]", PrizeType: sql.NullString{String: "terno", Valid: true}, PrizeCents: 10000},
+				},
+			}, nil
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/tickets/{id}", GetTicket(mockSvc))
+
+	req := httptest.NewRequest("GET", "/api/v1/tickets/3", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp models.TicketResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Checks) != 1 || resp.Checks[0].PrizeType != "terno" || len(resp.Checks[0].MatchedNumbers) != 3 {
+		t.Errorf("unexpected checks: %+v", resp.Checks)
+	}
+}
+
+func TestGetTicket_NotFound(t *testing.T) {
+	mockSvc := &mockTicketService{
+		getTicketFunc: func(ctx context.Context, id int64) (*services.TicketDetail, error) {
+			return nil, fmt.Errorf("get ticket: %w", sql.ErrNoRows)
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/tickets/{id}", GetTicket(mockSvc))
+
+	req := httptest.NewRequest("GET", "/api/v1/tickets/99", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestListTickets_OwnerFilter(t *testing.T) {
+	mockSvc := &mockTicketService{
+		listTicketsFunc: func(ctx context.Context, owner string, limit, offset int) ([]finances.Ticket, error) {
+			if owner != "bob" || limit != 5 || offset != 10 {
+				t.Errorf("unexpected filter: owner=%q limit=%d offset=%d", owner, limit, offset)
+			}
+			return []finances.Ticket{{ID: 1, Owner: "bob", Numbers: "[1,2,3,4,5]"}}, nil
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/tickets?owner=bob&limit=5&offset=10", nil)
+	w := httptest.NewRecorder()
+
+	ListTickets(mockSvc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+// ListWorkers reports the status of the worker fleet
+// @Summary List workers
+// @Description List registered worker processes with their status (live, dead or stopped), current jobs and throughput over the last 15 minutes
+// @Tags workers
+// @Produce json
+// @Success 200 {object} object{workers=[]services.WorkerStatus,total=integer,live=integer} "Worker fleet status"
+// @Failure 500 {object} models.APIError "Internal server error"
+// @Router /api/v1/workers [get]
+func ListWorkers(workerSvc services.WorkerServicer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workers, err := workerSvc.ListWorkers(r.Context())
+		if err != nil {
+			WriteError(w, r, *models.NewAPIError("list_workers_failed", err.Error()))
+			return
+		}
+
+		live := 0
+		for _, worker := range workers {
+			if worker.Status == services.WorkerStatusLive {
+				live++
+			}
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]any{
+			"workers": workers,
+			"total":   len(workers),
+			"live":    live,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+type mockWorkerService struct {
+	listWorkersFunc func(ctx context.Context) ([]services.WorkerStatus, error)
+}
+
+func (m *mockWorkerService) ListWorkers(ctx context.Context) ([]services.WorkerStatus, error) {
+	return m.listWorkersFunc(ctx)
+}
+
+func TestListWorkers(t *testing.T) {
+	svc := &mockWorkerService{
+		listWorkersFunc: func(ctx context.Context) ([]services.WorkerStatus, error) {
+			return []services.WorkerStatus{
+				{ID: "worker-a", Status: services.WorkerStatusLive, CurrentJobs: []int64{4}},
+				{ID: "worker-b", Status: services.WorkerStatusDead, CurrentJobs: []int64{}},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workers", nil)
+	w := httptest.NewRecorder()
+	ListWorkers(svc)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Workers []services.WorkerStatus `json:"workers"`
+		Total   int                     `json:"total"`
+		Live    int                     `json:"live"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 2 || resp.Live != 1 || resp.Workers[0].CurrentJobs[0] != 4 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestListWorkers_Error(t *testing.T) {
+	svc := &mockWorkerService{
+		listWorkersFunc: func(ctx context.Context) ([]services.WorkerStatus, error) {
+			return nil, errors.New("database is closed")
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workers", nil)
+	w := httptest.NewRecorder()
+	ListWorkers(svc)(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+}
@@ -28,6 +28,7 @@ type SweepStatusResponse struct {
 	Completed   int                             `json:"completed"`
 	Running     int                             `json:"running"`
 	Failed      int                             `json:"failed"`
+	Cancelled   int                             `json:"cancelled"`
 	Pending     int                             `json:"pending"`
 	Simulations []SweepSimulationDetailResponse `json:"simulations"`
 }
@@ -84,3 +85,48 @@ type LeaderboardEntryResponse struct {
 	MetricValue  float64 `json:"metric_value"`
 	CreatedAt    string  `json:"created_at"`
 }
+
+// PredictionResponse represents a ticket set predicted for an upcoming contest
+type PredictionResponse struct {
+	ID             int64   `json:"id"`
+	ConfigID       *int64  `json:"config_id,omitempty"`
+	RecipeName     string  `json:"recipe_name,omitempty"`
+	RecipeHash     string  `json:"recipe_hash"`
+	TargetContest  int64   `json:"target_contest"`
+	BasedOnContest int64   `json:"based_on_contest"`
+	Seed           int64   `json:"seed"`
+	Tickets        [][]int `json:"tickets"`
+	Status         string  `json:"status"`
+	ActualNumbers  []int   `json:"actual_numbers,omitempty"`
+	BestHits       *int64  `json:"best_hits,omitempty"`
+	Hits           []int   `json:"hits,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	CheckedAt      string  `json:"checked_at,omitempty"`
+	CreatedBy      string  `json:"created_by,omitempty"`
+}
+
+// TicketResponse represents a ticket placed on one or more real contests
+type TicketResponse struct {
+	ID              int64                 `json:"id"`
+	Owner           string                `json:"owner"`
+	Numbers         []int                 `json:"numbers"`
+	FirstContest    int64                 `json:"first_contest"`
+	LastContest     int64                 `json:"last_contest"`
+	CostCents       int64                 `json:"cost_cents"`
+	PredictionID    *int64                `json:"prediction_id,omitempty"`
+	Notes           string                `json:"notes,omitempty"`
+	Status          string                `json:"status"`
+	TotalPrizeCents int64                 `json:"total_prize_cents"`
+	CreatedAt       string                `json:"created_at"`
+	Checks          []TicketCheckResponse `json:"checks,omitempty"`
+}
+
+// TicketCheckResponse represents the result of a ticket in a single contest
+type TicketCheckResponse struct {
+	Contest        int64  `json:"contest"`
+	Hits           int64  `json:"hits"`
+	MatchedNumbers []int  `json:"matched_numbers"`
+	PrizeType      string `json:"prize_type,omitempty"`
+	PrizeCents     int64  `json:"prize_cents"`
+	CheckedAt      string `json:"checked_at"`
+}
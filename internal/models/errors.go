@@ -54,14 +54,16 @@ func (e *APIError) HTTPStatusCode() int {
 	switch e.Code {
 	case "method_not_allowed":
 		return http.StatusMethodNotAllowed
-	case "invalid_json", "invalid_form", "no_file", "missing_artifact_id", "missing_contest", "invalid_contest", "invalid_limit", "invalid_offset", "invalid_request", "invalid_simulation_id", "invalid_config_id", "invalid_sweep_config_id", "invalid_id", "invalid_comparison_id", "invalid_metric", "invalid_sweep_id", "find_best_failed", "get_visualization_failed":
+	case "invalid_json", "invalid_form", "no_file", "missing_artifact_id", "missing_contest", "invalid_contest", "invalid_limit", "invalid_offset", "invalid_request", "invalid_simulation_id", "invalid_config_id", "invalid_sweep_config_id", "invalid_id", "invalid_comparison_id", "invalid_metric", "invalid_sweep_id", "find_best_failed", "get_visualization_failed", "invalid_prediction_id", "invalid_ticket_id", "invalid_ticket", "invalid_tickets", "invalid_search", "invalid_contest_filter", "invalid_job_id", "invalid_job_filter", "limit_exceeded":
 		return http.StatusBadRequest
-	case "upload_failed", "import_failed", "get_draw_failed", "list_draws_failed", "simulation_creation_failed", "simulation_cancel_failed", "config_creation_failed", "config_update_failed", "config_delete_failed", "simulation_not_found", "preset_not_found":
+	case "upload_failed", "import_failed", "get_draw_failed", "list_draws_failed", "simulation_creation_failed", "simulation_cancel_failed", "config_creation_failed", "config_update_failed", "config_delete_failed", "prediction_creation_failed", "simulation_not_found", "preset_not_found":
 		return http.StatusInternalServerError
-	case "not_found", "config_not_found", "sweep_config_not_found", "draw_not_found":
+	case "not_found", "config_not_found", "sweep_config_not_found", "draw_not_found", "prediction_not_found", "ticket_not_found", "simulation_log_not_found", "job_not_found", "job_report_not_found", "worker_not_found", "sweep_not_found":
 		return http.StatusNotFound
 	case "validation_error":
 		return http.StatusBadRequest
+	case "no_draws", "simulation_not_queued", "job_not_cancellable", "lease_lost", "sweep_not_cancellable":
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
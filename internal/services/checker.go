@@ -0,0 +1,309 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+)
+
+var (
+	// ErrInvalidCheck is returned when tickets or contests to check are invalid.
+	ErrInvalidCheck = errors.New("invalid check request")
+	// ErrContestNotDrawn is returned when none of the requested contests has
+	// been imported yet.
+	ErrContestNotDrawn = errors.New("contest not drawn")
+)
+
+const (
+	maxCheckTickets  = 1000
+	maxCheckContests = 1000
+)
+
+// legacyTicketLine matches the "Jogo #01: [18 26 44 57 61]" lines written by
+// the old card generator.
+var legacyTicketLine = regexp.MustCompile(`Jogo #\d+: \[(.*?)\]`)
+
+type CheckerServicer interface {
+	ParseTickets(format string, data []byte) ([][]int, error)
+	CheckTickets(ctx context.Context, req CheckTicketsRequest) (*CheckTicketsResult, error)
+}
+
+// CheckerService checks arbitrary tickets against imported draws without
+// creating a simulation.
+type CheckerService struct {
+	resultsQueries  results.Querier
+	financesQueries finances.Querier
+	logger          *slog.Logger
+}
+
+func NewCheckerService(
+	resultsQueries results.Querier,
+	financesQueries finances.Querier,
+	logger *slog.Logger,
+) *CheckerService {
+	return &CheckerService{
+		resultsQueries:  resultsQueries,
+		financesQueries: financesQueries,
+		logger:          logger,
+	}
+}
+
+type CheckTicketsRequest struct {
+	Tickets      [][]int `json:"tickets"`
+	FirstContest int     `json:"first_contest"`
+	LastContest  int     `json:"last_contest"` // Defaults to FirstContest
+}
+
+type CheckTicketsResult struct {
+	FirstContest    int                 `json:"first_contest"`
+	LastContest     int                 `json:"last_contest"`
+	Contests        []int               `json:"contests"` // Contests actually drawn in the range
+	Tickets         []TicketCheckResult `json:"tickets"`
+	TierCounts      map[string]int      `json:"tier_counts"`
+	TotalPrizeCents int64               `json:"total_prize_cents"`
+}
+
+type TicketCheckResult struct {
+	Numbers         []int                `json:"numbers"`
+	Contests        []ContestCheckResult `json:"contests"`
+	TotalPrizeCents int64                `json:"total_prize_cents"`
+}
+
+type ContestCheckResult struct {
+	Contest        int    `json:"contest"`
+	ActualNumbers  []int  `json:"actual_numbers"`
+	Hits           int    `json:"hits"`
+	MatchedNumbers []int  `json:"matched_numbers"`
+	PrizeType      string `json:"prize_type,omitempty"`
+	// PrizeCents is nil when the contest has no prize rule for a winning tier.
+	PrizeCents *int64 `json:"prize_cents,omitempty"`
+}
+
+// ParseTickets reads tickets in one of the supported formats: "json" (an
+// array of number arrays), "csv" (one ticket per row, header optional) or
+// "text" (the legacy "Jogo #NN: [..]" lines).
+func (s *CheckerService) ParseTickets(format string, data []byte) ([][]int, error) {
+	var tickets [][]int
+	var err error
+
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &tickets)
+	case "csv":
+		tickets, err = parseTicketsCSV(data)
+	case "text":
+		tickets, err = parseTicketsText(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidCheck, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse %s tickets: %v", ErrInvalidCheck, format, err)
+	}
+
+	return tickets, nil
+}
+
+func parseTicketsCSV(data []byte) ([][]int, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	if bytes.Count(data, []byte(";")) > bytes.Count(data, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	var tickets [][]int
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var numbers []int
+		for _, field := range record {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				numbers = nil
+				if line == 1 {
+					break // header row
+				}
+				return nil, fmt.Errorf("line %d: invalid number %q", line, field)
+			}
+			numbers = append(numbers, n)
+		}
+		if len(numbers) > 0 {
+			tickets = append(tickets, numbers)
+		}
+	}
+
+	return tickets, nil
+}
+
+func parseTicketsText(data []byte) ([][]int, error) {
+	var tickets [][]int
+	for i, line := range strings.Split(string(data), "\n") {
+		m := legacyTicketLine.FindStringSubmatch(line)
+		if len(m) != 2 {
+			continue
+		}
+		var numbers []int
+		for _, field := range strings.Fields(m[1]) {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", i+1, field)
+			}
+			numbers = append(numbers, n)
+		}
+		tickets = append(tickets, numbers)
+	}
+
+	return tickets, nil
+}
+
+// CheckTickets scores every ticket against each drawn contest of the range.
+// Contests of the range that have not been imported are skipped.
+func (s *CheckerService) CheckTickets(ctx context.Context, req CheckTicketsRequest) (*CheckTicketsResult, error) {
+	if req.LastContest == 0 {
+		req.LastContest = req.FirstContest
+	}
+	if err := validateCheckRequest(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCheck, err)
+	}
+
+	draws, err := s.resultsQueries.ListDrawsByContestRange(ctx, results.ListDrawsByContestRangeParams{
+		FromContest: int64(req.FirstContest),
+		ToContest:   int64(req.LastContest),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list draws: %w", err)
+	}
+	if len(draws) == 0 {
+		return nil, ErrContestNotDrawn
+	}
+
+	result := &CheckTicketsResult{
+		FirstContest: req.FirstContest,
+		LastContest:  req.LastContest,
+		Tickets:      make([]TicketCheckResult, len(req.Tickets)),
+		TierCounts:   make(map[string]int),
+	}
+	for _, d := range draws {
+		result.Contests = append(result.Contests, int(d.Contest))
+	}
+
+	// Prize rules are looked up once per contest and tier
+	prizes := make(map[string]*int64)
+
+	for i, numbers := range req.Tickets {
+		ticket := TicketCheckResult{Numbers: numbers}
+
+		for _, d := range draws {
+			actual := []int{int(d.Bola1), int(d.Bola2), int(d.Bola3), int(d.Bola4), int(d.Bola5)}
+			matched := matchedNumbers(numbers, actual)
+
+			check := ContestCheckResult{
+				Contest:        int(d.Contest),
+				ActualNumbers:  actual,
+				Hits:           len(matched),
+				MatchedNumbers: matched,
+			}
+
+			var total int64
+			known := true
+			for _, tier := range prizeTiers {
+				combos := winningCombinations(len(numbers), check.Hits, tier.hits)
+				if check.Hits < tier.hits || combos == 0 {
+					continue
+				}
+				if check.PrizeType == "" {
+					check.PrizeType = tier.name
+					result.TierCounts[tier.name]++
+				}
+
+				key := fmt.Sprintf("%d/%s", d.Contest, tier.name)
+				amount, ok := prizes[key]
+				if !ok {
+					amount, err = s.prizeAmount(ctx, int(d.Contest), tier.name)
+					if err != nil {
+						return nil, err
+					}
+					prizes[key] = amount
+				}
+				if amount == nil {
+					known = false
+					continue
+				}
+				total += combos * *amount
+			}
+
+			if check.PrizeType != "" && known {
+				check.PrizeCents = &total
+				ticket.TotalPrizeCents += total
+			}
+			ticket.Contests = append(ticket.Contests, check)
+		}
+
+		result.TotalPrizeCents += ticket.TotalPrizeCents
+		result.Tickets[i] = ticket
+	}
+
+	return result, nil
+}
+
+// prizeAmount returns the prize paid per winning combination of a tier, or nil
+// when no prize rule has been recorded for the contest.
+func (s *CheckerService) prizeAmount(ctx context.Context, contest int, tier string) (*int64, error) {
+	rule, err := s.financesQueries.GetPrizeRule(ctx, finances.GetPrizeRuleParams{
+		Contest:   int64(contest),
+		PrizeType: tier,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get prize rule: %w", err)
+	}
+	return &rule.AmountCents, nil
+}
+
+func validateCheckRequest(req CheckTicketsRequest) error {
+	if len(req.Tickets) == 0 {
+		return fmt.Errorf("at least one ticket is required")
+	}
+	if len(req.Tickets) > maxCheckTickets {
+		return fmt.Errorf("at most %d tickets can be checked at once", maxCheckTickets)
+	}
+	for i, ticket := range req.Tickets {
+		if err := validateTicketNumbers(ticket); err != nil {
+			return fmt.Errorf("ticket %d: %w", i+1, err)
+		}
+	}
+	if req.FirstContest <= 0 {
+		return fmt.Errorf("contest must be positive")
+	}
+	if req.LastContest < req.FirstContest {
+		return fmt.Errorf("last_contest must not be before first_contest")
+	}
+	if req.LastContest-req.FirstContest+1 > maxCheckContests {
+		return fmt.Errorf("at most %d contests can be checked at once", maxCheckContests)
+	}
+	return nil
+}
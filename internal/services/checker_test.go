@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+	financesmock "github.com/garnizeh/luckyfive/internal/store/finances/mock"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	resultsmock "github.com/garnizeh/luckyfive/internal/store/results/mock"
+)
+
+func TestCheckerService_ParseTickets(t *testing.T) {
+	svc := NewCheckerService(nil, nil, createTestLogger())
+	want := [][]int{{18, 26, 44, 57, 61}, {1, 2, 3, 4, 5, 6}}
+
+	tests := []struct {
+		format string
+		data   string
+	}{
+		{"json", `[[18,26,44,57,61],[1,2,3,4,5,6]]`},
+		{"csv", "n1,n2,n3,n4,n5,n6\n18,26,44,57,61\n1,2,3,4,5,6\n"},
+		{"csv", "18;26;44;57;61\n1;2;3;4;5;6\n"},
+		{"text", "Jogos gerados\nJogo #01: [18 26 44 57 61]\nJogo #02: [1 2 3 4 5 6]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := svc.ParseTickets(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseTickets failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+
+	if _, err := svc.ParseTickets("csv", []byte("1,2,3,4,5\n6,x,8,9,10\n")); !errors.Is(err, ErrInvalidCheck) {
+		t.Errorf("expected ErrInvalidCheck for bad CSV row, got %v", err)
+	}
+	if _, err := svc.ParseTickets("xml", nil); !errors.Is(err, ErrInvalidCheck) {
+		t.Errorf("expected ErrInvalidCheck for unknown format, got %v", err)
+	}
+}
+
+func TestCheckerService_CheckTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	mockFinances := financesmock.NewMockQuerier(ctrl)
+	svc := NewCheckerService(mockResults, mockFinances, createTestLogger())
+
+	mockResults.EXPECT().ListDrawsByContestRange(gomock.Any(), results.ListDrawsByContestRangeParams{FromContest: 100, ToContest: 102}).
+		Return([]results.Draw{
+			{Contest: 100, Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 40, Bola5: 50},
+			{Contest: 102, Bola1: 10, Bola2: 20, Bola3: 30, Bola4: 41, Bola5: 51},
+		}, nil)
+
+	// Contest 100 has prize rules, contest 102 does not
+	rules := map[string]int64{"quadra": 10000, "terno": 200, "duque": 3}
+	mockFinances.EXPECT().GetPrizeRule(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg finances.GetPrizeRuleParams) (finances.PrizeRule, error) {
+			amount, ok := rules[arg.PrizeType]
+			if arg.Contest != 100 || !ok {
+				return finances.PrizeRule{}, sql.ErrNoRows
+			}
+			return finances.PrizeRule{AmountCents: amount}, nil
+		},
+	).AnyTimes()
+
+	res, err := svc.CheckTickets(context.Background(), CheckTicketsRequest{
+		Tickets:      [][]int{{1, 2, 3, 4, 5}, {1, 2, 3, 40, 60, 70}},
+		FirstContest: 100,
+		LastContest:  102,
+	})
+	if err != nil {
+		t.Fatalf("CheckTickets failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(res.Contests, []int{100, 102}) {
+		t.Errorf("expected drawn contests [100 102], got %v", res.Contests)
+	}
+
+	first := res.Tickets[0].Contests[0]
+	if first.Hits != 3 || first.PrizeType != "terno" || first.PrizeCents == nil || *first.PrizeCents != 200 {
+		t.Errorf("unexpected result for ticket 1: %+v", first)
+	}
+	if !reflect.DeepEqual(first.MatchedNumbers, []int{1, 2, 3}) {
+		t.Errorf("expected matched [1 2 3], got %v", first.MatchedNumbers)
+	}
+
+	// Six numbers with four hits win 2 quadras and 4 ternos, but no duque
+	second := res.Tickets[1].Contests[0]
+	wantPrize := int64(2*10000 + 4*200)
+	if second.Hits != 4 || second.PrizeType != "quadra" || second.PrizeCents == nil || *second.PrizeCents != wantPrize {
+		t.Errorf("unexpected result for ticket 2: %+v (want prize %d)", second, wantPrize)
+	}
+
+	// No winning tier in contest 102
+	if res.Tickets[1].Contests[1].PrizeType != "" || res.Tickets[1].Contests[1].PrizeCents != nil {
+		t.Errorf("expected no prize in contest 102, got %+v", res.Tickets[1].Contests[1])
+	}
+
+	if res.TierCounts["quadra"] != 1 || res.TierCounts["terno"] != 1 {
+		t.Errorf("unexpected tier counts: %v", res.TierCounts)
+	}
+	if res.TotalPrizeCents != 200+wantPrize {
+		t.Errorf("expected total prize %d, got %d", 200+wantPrize, res.TotalPrizeCents)
+	}
+}
+
+func TestCheckerService_CheckTickets_NotDrawn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewCheckerService(mockResults, nil, createTestLogger())
+
+	mockResults.EXPECT().ListDrawsByContestRange(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	_, err := svc.CheckTickets(context.Background(), CheckTicketsRequest{Tickets: [][]int{{1, 2, 3, 4, 5}}, FirstContest: 9000})
+	if !errors.Is(err, ErrContestNotDrawn) {
+		t.Errorf("expected ErrContestNotDrawn, got %v", err)
+	}
+}
+
+func TestCheckerService_CheckTickets_Invalid(t *testing.T) {
+	svc := NewCheckerService(nil, nil, createTestLogger())
+
+	tests := []CheckTicketsRequest{
+		{FirstContest: 1},
+		{Tickets: [][]int{{1, 2, 3}}, FirstContest: 1},
+		{Tickets: [][]int{{1, 2, 3, 4, 5}}},
+		{Tickets: [][]int{{1, 2, 3, 4, 5}}, FirstContest: 1, LastContest: 5000},
+	}
+
+	for _, req := range tests {
+		if _, err := svc.CheckTickets(context.Background(), req); !errors.Is(err, ErrInvalidCheck) {
+			t.Errorf("expected ErrInvalidCheck for %+v, got %v", req, err)
+		}
+	}
+}
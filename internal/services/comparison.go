@@ -46,6 +46,7 @@ type CompareRequest struct {
 	Description   string
 	SimulationIDs []int64
 	Metrics       []string // ["quina_rate", "avg_hits", "roi"]
+	UseLowerBound bool     // rank multi-seed simulations by the lower confidence bound
 }
 
 type ComparisonResult struct {
@@ -57,6 +58,7 @@ type ComparisonResult struct {
 	Rankings       map[string][]SimulationRank `json:"rankings"`         // metric -> ranked list
 	Statistics     map[string]MetricStats      `json:"statistics"`       // metric -> stats
 	WinnerByMetric map[string]int64            `json:"winner_by_metric"` // metric -> simulation_id
+	UseLowerBound  bool                        `json:"use_lower_bound,omitempty"`
 	CreatedAt      string                      `json:"created_at"`
 }
 
@@ -145,12 +147,13 @@ func (s *ComparisonService) Compare(
 		Rankings:       make(map[string][]SimulationRank),
 		Statistics:     make(map[string]MetricStats),
 		WinnerByMetric: make(map[string]int64),
+		UseLowerBound:  req.UseLowerBound,
 		CreatedAt:      comp.CreatedAt.String,
 	}
 
 	// Calculate each metric
 	for _, metric := range req.Metrics {
-		ranks, stats := s.calculateMetric(metric, simulations)
+		ranks, stats := s.calculateMetricWithBound(metric, simulations, req.UseLowerBound)
 		result.Rankings[metric] = ranks
 		result.Statistics[metric] = stats
 
@@ -192,6 +195,32 @@ func (s *ComparisonService) Compare(
 	return result, nil
 }
 
+// ComparisonJobType runs comparisons as analysis jobs. The payload is a
+// CompareRequest and the result its ComparisonResult.
+func (s *ComparisonService) ComparisonJobType() JobType {
+	return JobType{
+		Name: JobTypeComparison,
+		Validate: func(payload json.RawMessage) error {
+			var req CompareRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return err
+			}
+			return s.validateCompareRequest(req)
+		},
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			var req CompareRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+			}
+			result, err := s.Compare(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &JobOutput{Result: result}, nil
+		},
+	}
+}
+
 func (s *ComparisonService) GetComparison(ctx context.Context, id int64) (*ComparisonResult, error) {
 	comp, err := s.comparisonQueries.GetComparison(ctx, id)
 	if err != nil {
@@ -220,6 +249,16 @@ func (s *ComparisonService) ListComparisons(ctx context.Context, limit, offset i
 func (s *ComparisonService) calculateMetric(
 	metric string,
 	simulations map[int64]*simulations.Simulation,
+) ([]SimulationRank, MetricStats) {
+	return s.calculateMetricWithBound(metric, simulations, false)
+}
+
+// calculateMetricWithBound ranks simulations by metric, optionally using the
+// lower confidence bound reported by multi-seed simulations.
+func (s *ComparisonService) calculateMetricWithBound(
+	metric string,
+	simulations map[int64]*simulations.Simulation,
+	useLowerBound bool,
 ) ([]SimulationRank, MetricStats) {
 	values := make(map[int64]float64)
 
@@ -235,30 +274,10 @@ func (s *ComparisonService) calculateMetric(
 			continue
 		}
 
-		switch metric {
-		case "quina_rate":
-			values[id] = summary.HitRateQuina
-		case "quadra_rate":
-			values[id] = summary.HitRateQuadra
-		case "terno_rate":
-			values[id] = summary.HitRateTerno
-		case "avg_hits":
-			values[id] = summary.AverageHits
-		case "total_quinaz":
-			values[id] = float64(summary.QuinaHits)
-		case "total_quadras":
-			values[id] = float64(summary.QuadraHits)
-		case "total_ternos":
-			values[id] = float64(summary.TernoHits)
-		case "hit_efficiency":
-			// Custom metric: average hits per contest
-			if summary.TotalContests > 0 {
-				values[id] = summary.AverageHits
-			} else {
-				values[id] = 0
-			}
-		default:
-			values[id] = 0
+		if useLowerBound {
+			values[id] = summaryMetricLowerBound(metric, summary)
+		} else {
+			values[id] = summaryMetricValue(metric, summary)
 		}
 	}
 
@@ -209,6 +209,10 @@ func (m *mockSimulationQueries) GetSimulation(ctx context.Context, id int64) (si
 	return simulations.Simulation{}, errors.New("simulation not found")
 }
 
+func (m *mockSimulationQueries) AddSimulationTag(ctx context.Context, arg simulations.AddSimulationTagParams) error {
+	return nil
+}
+
 func (m *mockSimulationQueries) CancelSimulation(ctx context.Context, arg simulations.CancelSimulationParams) error {
 	return nil
 }
@@ -217,22 +221,46 @@ func (m *mockSimulationQueries) ClaimPendingSimulation(ctx context.Context, arg
 	return simulations.Simulation{}, nil
 }
 
-func (m *mockSimulationQueries) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) error {
-	return nil
+func (m *mockSimulationQueries) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) (int64, error) {
+	return 1, nil
 }
 
 func (m *mockSimulationQueries) CountSimulationsByStatus(ctx context.Context, status string) (int64, error) {
 	return 0, nil
 }
 
+func (m *mockSimulationQueries) FailExpiredSimulations(ctx context.Context, arg simulations.FailExpiredSimulationsParams) ([]int64, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) HeartbeatSimulation(ctx context.Context, arg simulations.HeartbeatSimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQueries) RequeueExpiredSimulations(ctx context.Context, arg simulations.RequeueExpiredSimulationsParams) ([]int64, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) CountContestResults(ctx context.Context, arg simulations.CountContestResultsParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQueries) CountSearchSimulations(ctx context.Context, arg simulations.CountSearchSimulationsParams) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockSimulationQueries) CreateSimulation(ctx context.Context, arg simulations.CreateSimulationParams) (simulations.Simulation, error) {
 	return simulations.Simulation{}, nil
 }
 
-func (m *mockSimulationQueries) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) error {
+func (m *mockSimulationQueries) DeleteSimulationTags(ctx context.Context, simulationID int64) error {
 	return nil
 }
 
+func (m *mockSimulationQueries) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) (int64, error) {
+	return 1, nil
+}
+
 func (m *mockSimulationQueries) GetContestResults(ctx context.Context, arg simulations.GetContestResultsParams) ([]simulations.SimulationContestResult, error) {
 	return nil, nil
 }
@@ -253,6 +281,62 @@ func (m *mockSimulationQueries) ListSimulationsByStatus(ctx context.Context, arg
 	return nil, nil
 }
 
+func (m *mockSimulationQueries) ListSimulationTags(ctx context.Context, simulationID int64) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) SaveSimulationLog(ctx context.Context, arg simulations.SaveSimulationLogParams) error {
+	return nil
+}
+
+func (m *mockSimulationQueries) ListContestResults(ctx context.Context, arg simulations.ListContestResultsParams) ([]simulations.SimulationContestResult, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) SearchSimulations(ctx context.Context, arg simulations.SearchSimulationsParams) ([]simulations.Simulation, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) RegisterWorker(ctx context.Context, arg simulations.RegisterWorkerParams) error {
+	return nil
+}
+
+func (m *mockSimulationQueries) HeartbeatWorker(ctx context.Context, arg simulations.HeartbeatWorkerParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQueries) StopWorker(ctx context.Context, arg simulations.StopWorkerParams) error {
+	return nil
+}
+
+func (m *mockSimulationQueries) ListWorkers(ctx context.Context, since string) ([]simulations.ListWorkersRow, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) ListRunningSimulationsByWorker(ctx context.Context) ([]simulations.ListRunningSimulationsByWorkerRow, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) ReleaseSimulation(ctx context.Context, arg simulations.ReleaseSimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQueries) RetrySimulation(ctx context.Context, arg simulations.RetrySimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQueries) RecordSimulationAttempt(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+	return nil
+}
+
+func (m *mockSimulationQueries) ListSimulationAttempts(ctx context.Context, simulationID int64) ([]simulations.SimulationAttempt, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQueries) UpdateQueuedSimulation(ctx context.Context, arg simulations.UpdateQueuedSimulationParams) (simulations.Simulation, error) {
+	return simulations.Simulation{}, nil
+}
+
 func (m *mockSimulationQueries) UpdateSimulationStatus(ctx context.Context, arg simulations.UpdateSimulationStatusParams) error {
 	return nil
 }
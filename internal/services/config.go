@@ -3,11 +3,11 @@ package services
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 
 	"github.com/garnizeh/luckyfive/internal/store/configs"
+	"github.com/garnizeh/luckyfive/pkg/recipe"
 )
 
 type ConfigService struct {
@@ -68,17 +68,17 @@ func (s *ConfigService) Create(ctx context.Context, req CreateConfigRequest) (co
 		return configs.Config{}, fmt.Errorf("invalid recipe: %w", err)
 	}
 
-	// Marshal recipe to JSON
-	recipeJSON, err := json.Marshal(req.Recipe)
+	// Marshal recipe to JSON in the current schema version
+	recipeJSON, err := encodeRecipe(req.Recipe)
 	if err != nil {
-		return configs.Config{}, fmt.Errorf("marshal recipe: %w", err)
+		return configs.Config{}, fmt.Errorf("invalid recipe: %w", err)
 	}
 
 	// Create config record
 	config, err := s.configsQueries.CreateConfig(ctx, configs.CreateConfigParams{
 		Name:        req.Name,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		RecipeJson:  string(recipeJSON),
+		RecipeJson:  recipeJSON,
 		Tags:        sql.NullString{String: req.Tags, Valid: req.Tags != ""},
 		IsDefault:   sql.NullInt64{Int64: 0, Valid: true}, // New configs are not default
 		Mode:        req.Mode,
@@ -92,26 +92,26 @@ func (s *ConfigService) Create(ctx context.Context, req CreateConfigRequest) (co
 }
 
 func (s *ConfigService) Get(ctx context.Context, id int64) (configs.Config, error) {
-	return s.configsQueries.GetConfig(ctx, id)
+	return upgradeConfig(s.configsQueries.GetConfig(ctx, id))
 }
 
 func (s *ConfigService) GetByName(ctx context.Context, name string) (configs.Config, error) {
-	return s.configsQueries.GetConfigByName(ctx, name)
+	return upgradeConfig(s.configsQueries.GetConfigByName(ctx, name))
 }
 
 func (s *ConfigService) List(ctx context.Context, limit, offset int64) ([]configs.Config, error) {
-	return s.configsQueries.ListConfigs(ctx, configs.ListConfigsParams{
+	return s.upgradeConfigs(s.configsQueries.ListConfigs(ctx, configs.ListConfigsParams{
 		Limit:  limit,
 		Offset: offset,
-	})
+	}))
 }
 
 func (s *ConfigService) ListByMode(ctx context.Context, mode string, limit, offset int64) ([]configs.Config, error) {
-	return s.configsQueries.ListConfigsByMode(ctx, configs.ListConfigsByModeParams{
+	return s.upgradeConfigs(s.configsQueries.ListConfigsByMode(ctx, configs.ListConfigsByModeParams{
 		Mode:   mode,
 		Limit:  limit,
 		Offset: offset,
-	})
+	}))
 }
 
 func (s *ConfigService) Update(ctx context.Context, id int64, req CreateConfigRequest) error {
@@ -120,15 +120,15 @@ func (s *ConfigService) Update(ctx context.Context, id int64, req CreateConfigRe
 		return fmt.Errorf("invalid recipe: %w", err)
 	}
 
-	// Marshal recipe to JSON
-	recipeJSON, err := json.Marshal(req.Recipe)
+	// Marshal recipe to JSON in the current schema version
+	recipeJSON, err := encodeRecipe(req.Recipe)
 	if err != nil {
-		return fmt.Errorf("marshal recipe: %w", err)
+		return fmt.Errorf("invalid recipe: %w", err)
 	}
 
 	return s.configsQueries.UpdateConfig(ctx, configs.UpdateConfigParams{
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		RecipeJson:  string(recipeJSON),
+		RecipeJson:  recipeJSON,
 		Tags:        sql.NullString{String: req.Tags, Valid: req.Tags != ""},
 		ID:          id,
 	})
@@ -152,7 +152,7 @@ func (s *ConfigService) SetDefault(ctx context.Context, id int64) error {
 }
 
 func (s *ConfigService) GetDefault(ctx context.Context, mode string) (configs.Config, error) {
-	return s.configsQueries.GetDefaultConfig(ctx, mode)
+	return upgradeConfig(s.configsQueries.GetDefaultConfig(ctx, mode))
 }
 
 func (s *ConfigService) IncrementUsage(ctx context.Context, id int64) error {
@@ -160,11 +160,72 @@ func (s *ConfigService) IncrementUsage(ctx context.Context, id int64) error {
 }
 
 func (s *ConfigService) GetPreset(ctx context.Context, name string) (configs.ConfigPreset, error) {
-	return s.configsQueries.GetPreset(ctx, name)
+	preset, err := s.configsQueries.GetPreset(ctx, name)
+	if err != nil {
+		return preset, err
+	}
+	preset.RecipeJson, err = upgradeRecipeJSON(preset.RecipeJson)
+	if err != nil {
+		return configs.ConfigPreset{}, fmt.Errorf("preset %s: %w", name, err)
+	}
+	return preset, nil
 }
 
 func (s *ConfigService) ListPresets(ctx context.Context) ([]configs.ConfigPreset, error) {
-	return s.configsQueries.ListPresets(ctx)
+	presets, err := s.configsQueries.ListPresets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// A broken preset is left out so that it does not hide the others
+	upgraded := presets[:0]
+	for _, preset := range presets {
+		preset.RecipeJson, err = upgradeRecipeJSON(preset.RecipeJson)
+		if err != nil {
+			s.logger.Warn("skipping preset with invalid recipe", "preset", preset.Name, "error", err)
+			continue
+		}
+		upgraded = append(upgraded, preset)
+	}
+	return upgraded, nil
+}
+
+// upgradeRecipeJSON rewrites a stored recipe in the current schema version.
+func upgradeRecipeJSON(data string) (string, error) {
+	normalized, err := recipe.Normalize([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+// upgradeConfig upgrades the recipe of a config read from the store.
+func upgradeConfig(config configs.Config, err error) (configs.Config, error) {
+	if err != nil {
+		return config, err
+	}
+	config.RecipeJson, err = upgradeRecipeJSON(config.RecipeJson)
+	if err != nil {
+		return configs.Config{}, fmt.Errorf("config %s: %w", config.Name, err)
+	}
+	return config, nil
+}
+
+// upgradeConfigs upgrades the recipes of configs read from the store. Configs
+// whose recipe cannot be upgraded are logged and left out of the list.
+func (s *ConfigService) upgradeConfigs(list []configs.Config, err error) ([]configs.Config, error) {
+	if err != nil {
+		return nil, err
+	}
+	upgraded := list[:0]
+	for _, config := range list {
+		config, err = upgradeConfig(config, nil)
+		if err != nil {
+			s.logger.Warn("skipping config with invalid recipe", "error", err)
+			continue
+		}
+		upgraded = append(upgraded, config)
+	}
+	return upgraded, nil
 }
 
 func (s *ConfigService) validateRecipe(recipe Recipe) error {
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"io"
 	"log/slog"
 	"reflect"
 	"testing"
@@ -62,6 +64,10 @@ func TestConfigService_Create(t *testing.T) {
 	}
 }
 
+// testRecipeJSON is a stored recipe already in the current version, which
+// reads return unchanged.
+const testRecipeJSON = `{"name":"test","parameters":{"alpha":0.1},"version":"1.2"}`
+
 func TestConfigService_Get(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -71,7 +77,7 @@ func TestConfigService_Get(t *testing.T) {
 
 	service := NewConfigService(mockQueries, nil, logger)
 
-	expectedConfig := configs.Config{ID: 1, Name: "test"}
+	expectedConfig := configs.Config{ID: 1, Name: "test", RecipeJson: testRecipeJSON}
 
 	mockQueries.EXPECT().
 		GetConfig(gomock.Any(), int64(1)).
@@ -97,7 +103,7 @@ func TestConfigService_ListPresets(t *testing.T) {
 	service := NewConfigService(mockQueries, nil, logger)
 
 	expectedPresets := []configs.ConfigPreset{
-		{ID: 1, Name: "preset1"},
+		{ID: 1, Name: "preset1", RecipeJson: testRecipeJSON},
 	}
 
 	mockQueries.EXPECT().
@@ -114,6 +120,105 @@ func TestConfigService_ListPresets(t *testing.T) {
 	}
 }
 
+func TestConfigService_ListSkipsInvalidRecipes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewConfigService(mockQueries, nil, logger)
+
+	broken := `{"version":"1.2","name":"broken","parameters":{"alpha":0.1,"unknown":1}}`
+
+	mockQueries.EXPECT().
+		ListPresets(gomock.Any()).
+		Return([]configs.ConfigPreset{
+			{ID: 1, Name: "preset1", RecipeJson: testRecipeJSON},
+			{ID: 2, Name: "broken", RecipeJson: broken},
+			{ID: 3, Name: "preset3", RecipeJson: testRecipeJSON},
+		}, nil)
+	mockQueries.EXPECT().
+		ListConfigs(gomock.Any(), gomock.Any()).
+		Return([]configs.Config{
+			{ID: 1, Name: "broken", RecipeJson: broken},
+			{ID: 2, Name: "config2", RecipeJson: testRecipeJSON},
+		}, nil)
+
+	presets, err := service.ListPresets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presets) != 2 || presets[0].Name != "preset1" || presets[1].Name != "preset3" {
+		t.Errorf("expected the two valid presets, got %v", presets)
+	}
+
+	list, err := service.List(context.Background(), 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "config2" {
+		t.Errorf("expected the valid config only, got %v", list)
+	}
+}
+
+func TestConfigService_GetPreset_UpgradesLegacyRecipe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock.NewMockQuerier(ctrl)
+	logger := &slog.Logger{}
+
+	service := NewConfigService(mockQueries, nil, logger)
+
+	// Seeded presets are flat and unversioned
+	mockQueries.EXPECT().
+		GetPreset(gomock.Any(), "balanced").
+		Return(configs.ConfigPreset{
+			ID:         1,
+			Name:       "balanced",
+			RecipeJson: `{"alpha":0.3,"beta":0.25,"gamma":0.25,"delta":0.2,"sim_prev_max":500,"sim_preds":20}`,
+		}, nil)
+
+	preset, err := service.GetPreset(context.Background(), "balanced")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var recipe Recipe
+	if err := json.Unmarshal([]byte(preset.RecipeJson), &recipe); err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Version != "1.2" {
+		t.Errorf("expected version 1.2, got %q", recipe.Version)
+	}
+	if recipe.Parameters.Alpha != 0.3 || recipe.Parameters.SimPrevMax != 500 || recipe.Parameters.SimPreds != 20 {
+		t.Errorf("parameters not carried over: %+v", recipe.Parameters)
+	}
+}
+
+func TestConfigService_Get_RejectsUnknownKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock.NewMockQuerier(ctrl)
+	logger := &slog.Logger{}
+
+	service := NewConfigService(mockQueries, nil, logger)
+
+	mockQueries.EXPECT().
+		GetConfig(gomock.Any(), int64(1)).
+		Return(configs.Config{
+			ID:         1,
+			Name:       "test",
+			RecipeJson: `{"version":"1.2","name":"test","parameters":{"alpha":0.1,"unknown":1}}`,
+		}, nil)
+
+	if _, err := service.Get(context.Background(), 1); err == nil {
+		t.Fatal("expected error for unknown recipe key, got nil")
+	}
+}
+
 func TestConfigService_GetByName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -123,7 +228,7 @@ func TestConfigService_GetByName(t *testing.T) {
 
 	service := NewConfigService(mockQueries, nil, logger)
 
-	expectedConfig := configs.Config{ID: 1, Name: "test"}
+	expectedConfig := configs.Config{ID: 1, Name: "test", RecipeJson: testRecipeJSON}
 
 	mockQueries.EXPECT().
 		GetConfigByName(gomock.Any(), "test").
@@ -149,8 +254,8 @@ func TestConfigService_List(t *testing.T) {
 	service := NewConfigService(mockQueries, nil, logger)
 
 	expectedConfigs := []configs.Config{
-		{ID: 1, Name: "config1"},
-		{ID: 2, Name: "config2"},
+		{ID: 1, Name: "config1", RecipeJson: testRecipeJSON},
+		{ID: 2, Name: "config2", RecipeJson: testRecipeJSON},
 	}
 
 	mockQueries.EXPECT().
@@ -177,7 +282,7 @@ func TestConfigService_ListByMode(t *testing.T) {
 	service := NewConfigService(mockQueries, nil, logger)
 
 	expectedConfigs := []configs.Config{
-		{ID: 1, Name: "config1", Mode: "simple"},
+		{ID: 1, Name: "config1", Mode: "simple", RecipeJson: testRecipeJSON},
 	}
 
 	mockQueries.EXPECT().
@@ -282,7 +387,7 @@ func TestConfigService_GetDefault(t *testing.T) {
 
 	service := NewConfigService(mockQueries, nil, logger)
 
-	expectedConfig := configs.Config{ID: 1, Name: "default", Mode: "simple"}
+	expectedConfig := configs.Config{ID: 1, Name: "default", Mode: "simple", RecipeJson: testRecipeJSON}
 
 	mockQueries.EXPECT().
 		GetDefaultConfig(gomock.Any(), "simple").
@@ -327,7 +432,7 @@ func TestConfigService_GetPreset(t *testing.T) {
 
 	service := NewConfigService(mockQueries, nil, logger)
 
-	expectedPreset := configs.ConfigPreset{ID: 1, Name: "preset1"}
+	expectedPreset := configs.ConfigPreset{ID: 1, Name: "preset1", RecipeJson: testRecipeJSON}
 
 	mockQueries.EXPECT().
 		GetPreset(gomock.Any(), "preset1").
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+// ErrInvalidContestFilter is returned when contest result filters are invalid.
+var ErrInvalidContestFilter = errors.New("invalid contest filter")
+
+const (
+	defaultContestResultsLimit = 50
+	maxContestResultsLimit     = 500
+	contestExportBatch         = 500
+)
+
+// ContestResultFilter selects the contest results of a simulation. Zero values
+// are ignored; the hit bounds are ignored when nil.
+type ContestResultFilter struct {
+	MinHits            *int
+	MaxHits            *int
+	ContestFrom        int
+	ContestTo          int
+	SortByHits         bool // Most hits first instead of contest order
+	IncludePredictions bool
+	Limit              int
+	Offset             int
+}
+
+// ContestPrediction is one prediction of a contest, scored against the draw.
+type ContestPrediction struct {
+	Index   int     `json:"index"`
+	Numbers []int   `json:"numbers"`
+	Hits    int     `json:"hits"`
+	Matched []int   `json:"matched"`
+	Score   float64 `json:"score"`
+	Method  string  `json:"method,omitempty"`
+}
+
+// ContestResultView is a decoded simulation contest result.
+type ContestResultView struct {
+	Contest               int                 `json:"contest"`
+	ActualNumbers         []int               `json:"actual_numbers"`
+	BestHits              int                 `json:"best_hits"`
+	BestPredictionIndex   int                 `json:"best_prediction_index"`
+	BestPredictionNumbers []int               `json:"best_prediction_numbers"`
+	Predictions           []ContestPrediction `json:"predictions,omitempty"`
+}
+
+type ContestResultPage struct {
+	SimulationID int64               `json:"simulation_id"`
+	Results      []ContestResultView `json:"results"`
+	Total        int64               `json:"total"`
+	Limit        int                 `json:"limit"`
+	Offset       int                 `json:"offset"`
+}
+
+// ListContestResults returns a filtered page of the contest results of a
+// simulation, with the total number of matches.
+func (s *SimulationService) ListContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter) (*ContestResultPage, error) {
+	count, err := buildContestFilter(simulationID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContestFilter, err)
+	}
+
+	limit, offset := filter.Limit, filter.Offset
+	if limit <= 0 {
+		limit = defaultContestResultsLimit
+	}
+	if limit > maxContestResultsLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrInvalidContestFilter, maxContestResultsLimit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidContestFilter)
+	}
+
+	if _, err := s.simulationsQueries.GetSimulation(ctx, simulationID); err != nil {
+		return nil, fmt.Errorf("get simulation: %w", err)
+	}
+
+	rows, err := s.simulationsQueries.ListContestResults(ctx, listContestParams(count, filter.SortByHits, limit, offset))
+	if err != nil {
+		return nil, fmt.Errorf("list contest results: %w", err)
+	}
+	total, err := s.simulationsQueries.CountContestResults(ctx, count)
+	if err != nil {
+		return nil, fmt.Errorf("count contest results: %w", err)
+	}
+
+	page := &ContestResultPage{
+		SimulationID: simulationID,
+		Results:      make([]ContestResultView, 0, len(rows)),
+		Total:        total,
+		Limit:        limit,
+		Offset:       offset,
+	}
+	for _, row := range rows {
+		view, err := decodeContestResult(row, filter.IncludePredictions)
+		if err != nil {
+			return nil, fmt.Errorf("decode contest %d: %w", row.Contest, err)
+		}
+		page.Results = append(page.Results, view)
+	}
+	return page, nil
+}
+
+// ContestReportRequest is the payload of contest report jobs, which export
+// the contest results of a simulation like ExportContestResults.
+type ContestReportRequest struct {
+	SimulationID       int64 `json:"simulation_id"`
+	MinHits            *int  `json:"min_hits,omitempty"`
+	MaxHits            *int  `json:"max_hits,omitempty"`
+	ContestFrom        int   `json:"contest_from,omitempty"`
+	ContestTo          int   `json:"contest_to,omitempty"`
+	SortByHits         bool  `json:"sort_by_hits,omitempty"`
+	IncludePredictions bool  `json:"include_predictions,omitempty"`
+}
+
+func (r ContestReportRequest) filter() ContestResultFilter {
+	return ContestResultFilter{
+		MinHits:            r.MinHits,
+		MaxHits:            r.MaxHits,
+		ContestFrom:        r.ContestFrom,
+		ContestTo:          r.ContestTo,
+		SortByHits:         r.SortByHits,
+		IncludePredictions: r.IncludePredictions,
+	}
+}
+
+// ContestReportJobType exports contest results as analysis jobs, for exports
+// too large to stream within a request. The CSV is stored as the job report.
+func (s *SimulationService) ContestReportJobType() JobType {
+	return JobType{
+		Name: JobTypeContestReport,
+		Validate: func(payload json.RawMessage) error {
+			var req ContestReportRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return err
+			}
+			if req.SimulationID <= 0 {
+				return fmt.Errorf("simulation_id is required")
+			}
+			_, err := buildContestFilter(req.SimulationID, req.filter())
+			return err
+		},
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			var req ContestReportRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+			}
+			var buf bytes.Buffer
+			if err := s.ExportContestResults(ctx, req.SimulationID, req.filter(), &buf); err != nil {
+				return nil, err
+			}
+			return &JobOutput{
+				Result:     map[string]any{"simulation_id": req.SimulationID, "size_bytes": buf.Len()},
+				Report:     buf.Bytes(),
+				ReportName: fmt.Sprintf("simulation_%d_contests.csv", req.SimulationID),
+			}, nil
+		},
+	}
+}
+
+// ExportContestResults writes every contest result matching filter to w as
+// CSV, ignoring Limit and Offset. With IncludePredictions there is one row per
+// prediction instead of one per contest.
+func (s *SimulationService) ExportContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter, w io.Writer) error {
+	count, err := buildContestFilter(simulationID, filter)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidContestFilter, err)
+	}
+	if _, err := s.simulationsQueries.GetSimulation(ctx, simulationID); err != nil {
+		return fmt.Errorf("get simulation: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+	if filter.IncludePredictions {
+		cw.Write([]string{"contest", "actual_numbers", "best_hits", "prediction_index", "prediction_numbers", "hits", "matched_numbers", "score"})
+	} else {
+		cw.Write([]string{"contest", "actual_numbers", "best_hits", "best_prediction_index", "best_prediction_numbers"})
+	}
+
+	for offset := 0; ; offset += contestExportBatch {
+		rows, err := s.simulationsQueries.ListContestResults(ctx, listContestParams(count, filter.SortByHits, contestExportBatch, offset))
+		if err != nil {
+			return fmt.Errorf("list contest results: %w", err)
+		}
+
+		for _, row := range rows {
+			view, err := decodeContestResult(row, filter.IncludePredictions)
+			if err != nil {
+				return fmt.Errorf("decode contest %d: %w", row.Contest, err)
+			}
+
+			contest := strconv.Itoa(view.Contest)
+			actual := joinNumbers(view.ActualNumbers)
+			bestHits := strconv.Itoa(view.BestHits)
+			if !filter.IncludePredictions {
+				cw.Write([]string{contest, actual, bestHits, strconv.Itoa(view.BestPredictionIndex), joinNumbers(view.BestPredictionNumbers)})
+				continue
+			}
+			for _, p := range view.Predictions {
+				cw.Write([]string{
+					contest, actual, bestHits,
+					strconv.Itoa(p.Index),
+					joinNumbers(p.Numbers),
+					strconv.Itoa(p.Hits),
+					joinNumbers(p.Matched),
+					strconv.FormatFloat(p.Score, 'f', -1, 64),
+				})
+			}
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+		if len(rows) < contestExportBatch {
+			return nil
+		}
+	}
+}
+
+// buildContestFilter validates filter and converts it into query parameters.
+func buildContestFilter(simulationID int64, filter ContestResultFilter) (simulations.CountContestResultsParams, error) {
+	f := simulations.CountContestResultsParams{SimulationID: simulationID}
+
+	for _, hits := range []*int{filter.MinHits, filter.MaxHits} {
+		if hits != nil && (*hits < 0 || *hits > 5) {
+			return f, fmt.Errorf("hits must be between 0 and 5")
+		}
+	}
+	if filter.MinHits != nil && filter.MaxHits != nil && *filter.MaxHits < *filter.MinHits {
+		return f, fmt.Errorf("max_hits must not be below min_hits")
+	}
+	if filter.ContestFrom < 0 || filter.ContestTo < 0 {
+		return f, fmt.Errorf("contests must not be negative")
+	}
+	if filter.ContestFrom > 0 && filter.ContestTo > 0 && filter.ContestTo < filter.ContestFrom {
+		return f, fmt.Errorf("contest_to must not be before contest_from")
+	}
+
+	if filter.MinHits != nil {
+		f.MinHits = sql.NullInt64{Int64: int64(*filter.MinHits), Valid: true}
+	}
+	if filter.MaxHits != nil {
+		f.MaxHits = sql.NullInt64{Int64: int64(*filter.MaxHits), Valid: true}
+	}
+	f.ContestFrom = sql.NullInt64{Int64: int64(filter.ContestFrom), Valid: filter.ContestFrom > 0}
+	f.ContestTo = sql.NullInt64{Int64: int64(filter.ContestTo), Valid: filter.ContestTo > 0}
+	return f, nil
+}
+
+func listContestParams(f simulations.CountContestResultsParams, sortByHits bool, limit, offset int) simulations.ListContestResultsParams {
+	return simulations.ListContestResultsParams{
+		SimulationID: f.SimulationID,
+		MinHits:      f.MinHits,
+		MaxHits:      f.MaxHits,
+		ContestFrom:  f.ContestFrom,
+		ContestTo:    f.ContestTo,
+		SortByHits:   sortByHits,
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+	}
+}
+
+// decodeContestResult decodes the JSON columns of a contest result and, when
+// asked, scores every stored prediction against the draw.
+func decodeContestResult(row simulations.SimulationContestResult, includePredictions bool) (ContestResultView, error) {
+	view := ContestResultView{
+		Contest:             int(row.Contest),
+		BestHits:            int(row.BestHits),
+		BestPredictionIndex: int(row.BestPredictionIndex.Int64),
+	}
+	if err := json.Unmarshal([]byte(row.ActualNumbers), &view.ActualNumbers); err != nil {
+		return view, fmt.Errorf("actual numbers: %w", err)
+	}
+	if row.BestPredictionNumbers.Valid {
+		if err := json.Unmarshal([]byte(row.BestPredictionNumbers.String), &view.BestPredictionNumbers); err != nil {
+			return view, fmt.Errorf("best prediction: %w", err)
+		}
+	}
+
+	if !includePredictions {
+		return view, nil
+	}
+
+	var preds []predictor.Prediction
+	if err := json.Unmarshal([]byte(row.PredictionsJson), &preds); err != nil {
+		return view, fmt.Errorf("predictions: %w", err)
+	}
+	view.Predictions = make([]ContestPrediction, len(preds))
+	for i, p := range preds {
+		matched := matchedNumbers(p.Numbers, view.ActualNumbers)
+		view.Predictions[i] = ContestPrediction{
+			Index:   i,
+			Numbers: p.Numbers,
+			Hits:    len(matched),
+			Matched: matched,
+			Score:   p.Score,
+			Method:  p.Method,
+		}
+	}
+	return view, nil
+}
+
+// joinNumbers formats numbers space-separated for a single CSV field.
+func joinNumbers(numbers []int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, " ")
+}
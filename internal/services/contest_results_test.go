@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	simulationsmock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
+)
+
+var testContestResult = simulations.SimulationContestResult{
+	SimulationID:          1,
+	Contest:               1001,
+	ActualNumbers:         "[6,7,8,9,10]",
+	BestHits:              3,
+	BestPredictionIndex:   sql.NullInt64{Int64: 1, Valid: true},
+	BestPredictionNumbers: sql.NullString{String: "[6,7,8,40,50]", Valid: true},
+	PredictionsJson:       `[{"Numbers":[1,2,3,4,10],"Score":0.5,"Method":"freq"},{"Numbers":[6,7,8,40,50],"Score":0.75,"Method":"freq"}]`,
+}
+
+func TestDecodeContestResult(t *testing.T) {
+	view, err := decodeContestResult(testContestResult, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if view.Contest != 1001 || view.BestHits != 3 || view.BestPredictionIndex != 1 {
+		t.Errorf("unexpected view: %+v", view)
+	}
+	if !reflect.DeepEqual(view.ActualNumbers, []int{6, 7, 8, 9, 10}) {
+		t.Errorf("unexpected actual numbers: %v", view.ActualNumbers)
+	}
+
+	want := []ContestPrediction{
+		{Index: 0, Numbers: []int{1, 2, 3, 4, 10}, Hits: 1, Matched: []int{10}, Score: 0.5, Method: "freq"},
+		{Index: 1, Numbers: []int{6, 7, 8, 40, 50}, Hits: 3, Matched: []int{6, 7, 8}, Score: 0.75, Method: "freq"},
+	}
+	if !reflect.DeepEqual(view.Predictions, want) {
+		t.Errorf("got %+v, want %+v", view.Predictions, want)
+	}
+
+	view, err = decodeContestResult(testContestResult, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Predictions != nil {
+		t.Errorf("expected no predictions, got %v", view.Predictions)
+	}
+}
+
+func TestBuildContestFilter_Invalid(t *testing.T) {
+	six, two, three := 6, 2, 3
+	tests := []struct {
+		name   string
+		filter ContestResultFilter
+	}{
+		{"hits out of range", ContestResultFilter{MinHits: &six}},
+		{"reversed hits", ContestResultFilter{MinHits: &three, MaxHits: &two}},
+		{"reversed contests", ContestResultFilter{ContestFrom: 20, ContestTo: 10}},
+		{"negative contest", ContestResultFilter{ContestTo: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildContestFilter(1, tt.filter); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSimulationService_ListContestResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	minHits := 0
+	mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(1)).Return(simulations.Simulation{ID: 1}, nil)
+	mockQueries.EXPECT().
+		ListContestResults(gomock.Any(), simulations.ListContestResultsParams{
+			SimulationID: 1,
+			MinHits:      sql.NullInt64{Int64: 0, Valid: true},
+			ContestFrom:  sql.NullInt64{Int64: 1000, Valid: true},
+			SortByHits:   true,
+			Limit:        defaultContestResultsLimit,
+		}).
+		Return([]simulations.SimulationContestResult{testContestResult}, nil)
+	mockQueries.EXPECT().CountContestResults(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+	page, err := service.ListContestResults(context.Background(), 1, ContestResultFilter{
+		MinHits:     &minHits,
+		ContestFrom: 1000,
+		SortByHits:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 || len(page.Results) != 1 || page.Results[0].Contest != 1001 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestSimulationService_ListContestResults_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(9)).Return(simulations.Simulation{}, sql.ErrNoRows)
+
+	_, err := service.ListContestResults(context.Background(), 9, ContestResultFilter{})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	_, err = service.ListContestResults(context.Background(), 9, ContestResultFilter{Limit: maxContestResultsLimit + 1})
+	if !errors.Is(err, ErrInvalidContestFilter) {
+		t.Errorf("expected ErrInvalidContestFilter, got %v", err)
+	}
+}
+
+func TestSimulationService_ExportContestResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(1)).Return(simulations.Simulation{ID: 1}, nil).Times(2)
+	mockQueries.EXPECT().
+		ListContestResults(gomock.Any(), gomock.Any()).
+		Return([]simulations.SimulationContestResult{testContestResult}, nil).
+		Times(2)
+
+	var buf bytes.Buffer
+	if err := service.ExportContestResults(context.Background(), 1, ContestResultFilter{}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "contest,actual_numbers,best_hits,best_prediction_index,best_prediction_numbers\n" +
+		"1001,6 7 8 9 10,3,1,6 7 8 40 50\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := service.ExportContestResults(context.Background(), 1, ContestResultFilter{IncludePredictions: true}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two prediction rows, got %q", buf.String())
+	}
+	if lines[2] != "1001,6 7 8 9 10,3,1,6 7 8 40 50,3,6 7 8,0.75" {
+		t.Errorf("unexpected prediction row: %q", lines[2])
+	}
+}
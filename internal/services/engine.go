@@ -14,21 +14,27 @@ type EngineServicer interface {
 	RunSimulation(ctx context.Context, cfg SimulationConfig) (*SimulationResult, error)
 }
 
+// DrawFetcher loads the draws of a contest range. results.Querier implements
+// it; remote workers fetch the draws from the API instead.
+type DrawFetcher interface {
+	ListDrawsByContestRange(ctx context.Context, arg results.ListDrawsByContestRangeParams) ([]results.Draw, error)
+}
+
 type EngineService struct {
-	resultsQueries results.Querier
-	predictor      predictor.Predictor
-	scorer         predictor.Scorer
-	logger         *slog.Logger
+	draws     DrawFetcher
+	predictor predictor.Predictor
+	scorer    predictor.Scorer
+	logger    *slog.Logger
 }
 
 func NewEngineService(
-	resultsQueries results.Querier,
+	draws DrawFetcher,
 	logger *slog.Logger,
 ) *EngineService {
 	return &EngineService{
-		resultsQueries: resultsQueries,
-		scorer:         predictor.NewScorer(),
-		logger:         logger,
+		draws:  draws,
+		scorer: predictor.NewScorer(),
+		logger: logger,
 	}
 }
 
@@ -42,6 +48,15 @@ type SimulationConfig struct {
 	EnableEvolution bool
 	Generations     int
 	MutationRate    float64
+
+	// FixedTickets, when set, are played on every contest instead of
+	// generated predictions ("teimosinha"). SimPreds and Weights are ignored.
+	FixedTickets [][]int
+
+	// Limits bound the run. Budget, when set, counts the candidate
+	// evaluations of several runs against one limit.
+	Limits ExecutionLimits   `json:"-"`
+	Budget *predictor.Budget `json:"-"`
 }
 
 type SimulationResult struct {
@@ -70,6 +85,11 @@ type Summary struct {
 	HitRateQuadra float64
 	HitRateTerno  float64
 	TotalHits     int // Add this field to track total hits across all contests
+
+	// Multi-seed (Monte Carlo) runs only: number of repetitions and the
+	// distribution of each metric across them.
+	Seeds         int                           `json:",omitempty"`
+	Distributions map[string]MetricDistribution `json:",omitempty"`
 }
 
 func (s *EngineService) RunSimulation(
@@ -78,8 +98,14 @@ func (s *EngineService) RunSimulation(
 ) (*SimulationResult, error) {
 	start := time.Now()
 
+	if err := cfg.Limits.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+	ctx, cfg, cancel := cfg.Limits.start(ctx, cfg)
+	defer cancel()
+
 	// Fetch historical draws
-	draws, err := s.resultsQueries.ListDrawsByContestRange(
+	draws, err := s.draws.ListDrawsByContestRange(
 		ctx,
 		results.ListDrawsByContestRangeParams{
 			FromContest: int64(cfg.StartContest - cfg.SimPrevMax),
@@ -96,6 +122,11 @@ func (s *EngineService) RunSimulation(
 	// Initialize predictor with seed
 	pred := predictor.NewAdvancedPredictor(cfg.Seed)
 
+	fixed := make([]predictor.Prediction, len(cfg.FixedTickets))
+	for i, ticket := range cfg.FixedTickets {
+		fixed[i] = predictor.Prediction{Numbers: ticket}
+	}
+
 	// Run simulation for each contest
 	var contestResults []ContestResult
 	var summary Summary
@@ -103,23 +134,27 @@ func (s *EngineService) RunSimulation(
 	for contest := cfg.StartContest; contest <= cfg.EndContest; contest++ {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, limitError(ctx, ctx.Err())
 		default:
 		}
 
-		// Get historical data up to this contest
-		history := s.getHistoryUpTo(historicalDraws, contest, cfg.SimPrevMax)
-
-		// Generate predictions
-		predictions, err := pred.GeneratePredictions(ctx, predictor.PredictionParams{
-			HistoricalDraws: history,
-			MaxHistory:      cfg.SimPrevMax,
-			NumPredictions:  cfg.SimPreds,
-			Weights:         cfg.Weights,
-			Seed:            cfg.Seed + int64(contest),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("generate predictions: %w", err)
+		predictions := fixed
+		if len(predictions) == 0 {
+			// Get historical data up to this contest
+			history := s.getHistoryUpTo(historicalDraws, contest, cfg.SimPrevMax)
+
+			// Generate predictions
+			predictions, err = pred.GeneratePredictions(ctx, predictor.PredictionParams{
+				HistoricalDraws: history,
+				MaxHistory:      cfg.SimPrevMax,
+				NumPredictions:  cfg.SimPreds,
+				Weights:         cfg.Weights,
+				Seed:            cfg.Seed + int64(contest),
+				Budget:          cfg.Budget,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("generate predictions: %w", limitError(ctx, err))
+			}
 		}
 
 		// Get actual result
@@ -68,6 +68,51 @@ func TestEngineService_RunSimulation(t *testing.T) {
 	}
 }
 
+func TestEngineService_RunSimulation_FixedTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockDraws := []results.Draw{
+		{Contest: 10, Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 4, Bola5: 5},
+		{Contest: 11, Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 40, Bola5: 50},
+		{Contest: 12, Bola1: 60, Bola2: 61, Bola3: 62, Bola4: 63, Bola5: 64},
+	}
+
+	// No history is needed when the tickets are fixed
+	mockQuerier.EXPECT().ListDrawsByContestRange(
+		gomock.Any(),
+		results.ListDrawsByContestRangeParams{FromContest: 10, ToContest: 12},
+	).Return(mockDraws, nil)
+
+	eng := NewEngineService(mockQuerier, logger)
+	res, err := eng.RunSimulation(context.Background(), SimulationConfig{
+		StartContest: 10,
+		EndContest:   12,
+		FixedTickets: [][]int{{1, 2, 3, 4, 5}, {1, 2, 3, 40, 50, 70}},
+	})
+	if err != nil {
+		t.Fatalf("RunSimulation error: %v", err)
+	}
+
+	if len(res.ContestResults) != 3 {
+		t.Fatalf("expected 3 contest results, got %d", len(res.ContestResults))
+	}
+	for _, cr := range res.ContestResults {
+		if len(cr.AllPredictions) != 2 {
+			t.Errorf("contest %d: expected both fixed tickets, got %d", cr.Contest, len(cr.AllPredictions))
+		}
+	}
+	if res.ContestResults[1].BestPredictionIndex != 1 || res.ContestResults[1].BestHits != 5 {
+		t.Errorf("contest 11: expected ticket 2 with 5 hits, got ticket %d with %d", res.ContestResults[1].BestPredictionIndex+1, res.ContestResults[1].BestHits)
+	}
+	if res.Summary.QuinaHits != 2 || res.Summary.TernoHits != 2 || res.Summary.TotalHits != 10 {
+		t.Errorf("unexpected summary: %+v", res.Summary)
+	}
+}
+
 func TestEngineService_RunSimulation_ErrorFetchingDraws(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -74,7 +74,7 @@ func (s *FinancialService) GetBetCost(
 func (s *FinancialService) GetPrize(
 	ctx context.Context,
 	contest int,
-	prizeType string, // "quina", "quadra", "terno"
+	prizeType string, // "quina", "quadra", "terno", "duque"
 ) (int64, error) {
 	prize, err := s.financesQueries.GetPrizeRule(ctx, finances.GetPrizeRuleParams{
 		Contest:   int64(contest),
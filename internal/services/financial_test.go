@@ -591,3 +591,27 @@ func (m *mockFinancesQuerier) UpdateSimulationFinances(ctx context.Context, arg
 func (m *mockFinancesQuerier) UpsertPrizeRule(ctx context.Context, arg finances.UpsertPrizeRuleParams) error {
 	return nil
 }
+func (m *mockFinancesQuerier) AddTicketPrize(ctx context.Context, arg finances.AddTicketPrizeParams) error {
+	return nil
+}
+func (m *mockFinancesQuerier) CreateTicket(ctx context.Context, arg finances.CreateTicketParams) (finances.Ticket, error) {
+	return finances.Ticket{}, nil
+}
+func (m *mockFinancesQuerier) CreateTicketCheck(ctx context.Context, arg finances.CreateTicketCheckParams) (finances.TicketCheck, error) {
+	return finances.TicketCheck{}, nil
+}
+func (m *mockFinancesQuerier) GetTicket(ctx context.Context, id int64) (finances.Ticket, error) {
+	return finances.Ticket{}, nil
+}
+func (m *mockFinancesQuerier) GetTicketChecks(ctx context.Context, ticketID int64) ([]finances.TicketCheck, error) {
+	return nil, nil
+}
+func (m *mockFinancesQuerier) ListPendingTicketsForContest(ctx context.Context, arg finances.ListPendingTicketsForContestParams) ([]finances.Ticket, error) {
+	return nil, nil
+}
+func (m *mockFinancesQuerier) ListTickets(ctx context.Context, arg finances.ListTicketsParams) ([]finances.Ticket, error) {
+	return nil, nil
+}
+func (m *mockFinancesQuerier) ListTicketsByOwner(ctx context.Context, arg finances.ListTicketsByOwnerParams) ([]finances.Ticket, error) {
+	return nil, nil
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Origins of the candidates of a genetic search.
+const (
+	OptimizationOriginRandom    = "random"    // Drawn for the first generation
+	OptimizationOriginCrossover = "crossover" // Bred from two parents
+	OptimizationOriginMutation  = "mutation"  // Bred from two parents, then mutated
+	OptimizationOriginElite     = "elite"     // Carried over from the last generation
+)
+
+const (
+	geneticTournamentSize = 3
+	// geneticBreedAttempts bounds the draws of a candidate that satisfies the
+	// sweep constraints.
+	geneticBreedAttempts = 100
+)
+
+// genetic evolves Population candidates over Generations generations on the
+// whole range. The first generation is drawn at random; each next one keeps
+// the Elite fittest individuals and breeds the rest by uniform crossover of
+// tournament winners and mutation. Every generation is bred whole and then
+// run as a batch of queued simulations. Every individual of every generation
+// is a trial, so the trajectory holds the lineage of the winner. A candidate
+// bred again reuses its earlier run instead of simulating it twice.
+func (r *optimizationRun) genetic(ctx context.Context) error {
+	rng := rand.New(rand.NewSource(r.req.Seed))
+	gen := r.service.generator
+	params := r.req.SweepConfig.Parameters
+	runs := make(map[string]int) // Trial of every candidate run
+	satisfies, err := gen.ConstraintChecker(r.req.SweepConfig)
+	if err != nil {
+		return err
+	}
+
+	first := make([]offspring, 0, r.req.Population)
+	for len(first) < r.req.Population {
+		values, err := breed(satisfies, func() (map[string]float64, error) {
+			return gen.RandomValues(rng, params)
+		})
+		if err != nil {
+			return err
+		}
+		first = append(first, offspring{values: values, origin: OptimizationOriginRandom})
+	}
+	population, err := r.runGeneration(ctx, 0, first, runs)
+	if err != nil {
+		return err
+	}
+
+	for generation := 1; generation <= r.req.Generations; generation++ {
+		sort.SliceStable(population, func(i, j int) bool {
+			return r.fitness(population[i]) > r.fitness(population[j])
+		})
+
+		next := make([]int, 0, r.req.Population)
+		for _, parent := range population[:r.req.Elite] {
+			elite := r.result.Trials[parent]
+			elite.Round = generation
+			elite.Origin = OptimizationOriginElite
+			elite.Parents = []int{parent}
+			r.record(elite)
+			next = append(next, len(r.result.Trials)-1)
+		}
+
+		children := make([]offspring, 0, r.req.Population-len(next))
+		for len(next)+len(children) < r.req.Population {
+			a, b := r.tournament(rng, population), r.tournament(rng, population)
+			origin := OptimizationOriginCrossover
+			values, err := breed(satisfies, func() (map[string]float64, error) {
+				child := gen.Crossover(rng, params, r.trialValues(a), r.trialValues(b))
+				child, changed, err := gen.Mutate(rng, params, child, r.req.MutationRate)
+				if changed > 0 {
+					origin = OptimizationOriginMutation
+				} else {
+					origin = OptimizationOriginCrossover
+				}
+				return child, err
+			})
+			if err != nil {
+				return err
+			}
+			children = append(children, offspring{values: values, origin: origin, parents: []int{a, b}})
+		}
+
+		trials, err := r.runGeneration(ctx, generation, children, runs)
+		if err != nil {
+			return err
+		}
+		population = append(next, trials...)
+	}
+	return nil
+}
+
+// offspring is an individual bred for a generation, before it runs.
+type offspring struct {
+	values  map[string]float64
+	origin  string
+	parents []int
+}
+
+// breed draws values until they satisfy the sweep constraints.
+func breed(satisfies func(map[string]float64) bool, draw func() (map[string]float64, error)) (map[string]float64, error) {
+	for range geneticBreedAttempts {
+		values, err := draw()
+		if err != nil {
+			return nil, err
+		}
+		if satisfies(values) {
+			return values, nil
+		}
+	}
+	return nil, fmt.Errorf("no candidate satisfying the constraints in %d attempts", geneticBreedAttempts)
+}
+
+// runGeneration runs the individuals of a generation as one batch and
+// records their trials in order, returning their indexes. Individuals run
+// before, in this generation or an earlier one, reuse that run. When the
+// budget runs out, the individuals that fit are run and recorded, and
+// errBudgetExhausted is returned.
+func (r *optimizationRun) runGeneration(ctx context.Context, generation int, individuals []offspring, runs map[string]int) ([]int, error) {
+	contests := r.req.EndContest - r.req.StartContest + 1
+	used := r.result.ContestsUsed
+
+	// fmt prints maps with sorted keys
+	keys := make([]string, len(individuals))
+	batch := make(map[string]int) // Candidate of every new run
+	var candidates []int
+	var budgetErr error
+	for i, ind := range individuals {
+		keys[i] = fmt.Sprint(ind.values)
+		if _, ok := runs[keys[i]]; ok {
+			continue
+		}
+		if _, ok := batch[keys[i]]; ok {
+			continue
+		}
+		if used+contests > r.req.Budget {
+			individuals, budgetErr = individuals[:i], errBudgetExhausted
+			break
+		}
+		used += contests
+
+		candidate := len(r.candidates)
+		r.candidates = append(r.candidates, r.service.generator.BuildRecipe(r.req.SweepConfig.BaseRecipe, r.req.SweepConfig.Parameters, ind.values, candidate))
+		batch[keys[i]] = candidate
+		candidates = append(candidates, candidate)
+	}
+
+	outcomes, err := r.runBatch(ctx, generation, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	trials := make([]int, 0, len(individuals))
+	for i, ind := range individuals {
+		if earlier, ok := runs[keys[i]]; ok {
+			trial := r.result.Trials[earlier]
+			trial.Round = generation
+			trial.Origin = ind.origin
+			trial.Parents = ind.parents
+			r.record(trial)
+		} else {
+			candidate := batch[keys[i]]
+			out := outcomes[candidate]
+			r.finish(OptimizationTrial{
+				Round:        generation,
+				Candidate:    candidate,
+				StartContest: r.req.StartContest,
+				EndContest:   r.req.EndContest,
+				Origin:       ind.origin,
+				Parents:      ind.parents,
+			}, out.simID, out.score, out.err)
+			runs[keys[i]] = len(r.result.Trials) - 1
+		}
+		trials = append(trials, len(r.result.Trials)-1)
+	}
+	return trials, budgetErr
+}
+
+// batchOutcome is how the simulation of a candidate in a batch ended.
+type batchOutcome struct {
+	simID int64
+	score float64
+	err   error
+}
+
+// runBatch queues a simulation of every candidate on the whole range and
+// waits until all of them finish. When ctx is done first, the simulations
+// still queued or running are cancelled.
+func (r *optimizationRun) runBatch(ctx context.Context, round int, candidates []int) (map[int]batchOutcome, error) {
+	simService := r.service.simulationService
+	outcomes := make(map[int]batchOutcome, len(candidates))
+	waiting := make(map[int]int64) // Simulation of every unfinished candidate
+
+	for _, candidate := range candidates {
+		if err := ctx.Err(); err != nil {
+			r.cancelBatch(waiting)
+			return nil, err
+		}
+		sim, err := simService.CreateSimulation(ctx, r.simulationRequest(r.candidates[candidate], round, r.req.StartContest, r.req.EndContest, true))
+		if err != nil {
+			outcomes[candidate] = batchOutcome{err: err}
+			continue
+		}
+		waiting[candidate] = sim.ID
+	}
+
+	ticker := time.NewTicker(r.service.batchPollInterval)
+	defer ticker.Stop()
+	for len(waiting) > 0 {
+		select {
+		case <-ctx.Done():
+			r.cancelBatch(waiting)
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		for candidate, simID := range waiting {
+			sim, err := simService.GetSimulation(ctx, simID)
+			if err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				return nil, fmt.Errorf("get simulation %d: %w", simID, err)
+			}
+			if sim.Status == "pending" || sim.Status == "running" {
+				continue
+			}
+			score, _, err := r.score(sim)
+			outcomes[candidate] = batchOutcome{simID: simID, score: score, err: err}
+			delete(waiting, candidate)
+		}
+	}
+	return outcomes, nil
+}
+
+// cancelBatch cancels the unfinished simulations of an interrupted batch, so
+// that no worker spends time on them.
+func (r *optimizationRun) cancelBatch(waiting map[int]int64) {
+	for _, simID := range waiting {
+		if err := r.service.simulationService.CancelSimulation(context.Background(), simID); err != nil {
+			r.service.logger.Error("failed to cancel optimization simulation", "name", r.req.Name, "simulation_id", simID, "error", err)
+		}
+	}
+}
+
+// tournament returns the fittest of a few random individuals.
+func (r *optimizationRun) tournament(rng *rand.Rand, population []int) int {
+	best := population[rng.Intn(len(population))]
+	for range geneticTournamentSize - 1 {
+		if contender := population[rng.Intn(len(population))]; r.fitness(contender) > r.fitness(best) {
+			best = contender
+		}
+	}
+	return best
+}
+
+// fitness is the score of a trial, or -Inf if its run failed.
+func (r *optimizationRun) fitness(trial int) float64 {
+	if t := r.result.Trials[trial]; t.Error == "" {
+		return t.Score
+	}
+	return math.Inf(-1)
+}
+
+func (r *optimizationRun) trialValues(trial int) map[string]float64 {
+	return r.candidates[r.result.Trials[trial].Candidate].Values
+}
@@ -22,12 +22,19 @@ type DBInterface interface {
 	WithResultsTx(ctx context.Context, fn func(results.Querier) error) error
 }
 
+// DrawObserver is notified with the contests inserted by an import once their
+// transaction has been committed.
+type DrawObserver interface {
+	OnDrawsImported(ctx context.Context, contests []int) error
+}
+
 // ImportService handles importing lottery draw data from various sources
 type ImportService struct {
 	db        DBInterface // Database access
 	logger    *slog.Logger
 	artifacts map[string][]byte // Temporary storage for uploaded artifacts
 	nextID    int               // Simple ID generator
+	observers []DrawObserver
 }
 
 // NewImportService creates a new import service
@@ -40,6 +47,11 @@ func NewImportService(db DBInterface, logger *slog.Logger) *ImportService {
 	}
 }
 
+// AddDrawObserver registers an observer notified after draws are imported.
+func (s *ImportService) AddDrawObserver(o DrawObserver) {
+	s.observers = append(s.observers, o)
+}
+
 // ParseXLSX parses an XLSX file and returns a slice of Draw structs
 func (s *ImportService) ParseXLSX(reader io.Reader, sheet string) ([]models.Draw, error) {
 	f, err := excelize.OpenReader(reader)
@@ -171,6 +183,7 @@ func (s *ImportService) ImportDraws(ctx context.Context, draws []models.Draw) er
 	const batchSize = 100
 	totalImported := 0
 	totalSkipped := 0
+	var insertedContests []int
 
 	// Process draws in batches
 	for i := 0; i < len(draws); i += batchSize {
@@ -180,10 +193,13 @@ func (s *ImportService) ImportDraws(ctx context.Context, draws []models.Draw) er
 		s.logger.Info("Processing batch", "batch_start", i, "batch_end", end-1, "batch_size", len(batch))
 
 		// Import batch in a transaction
-		imported, skipped, err := s.importBatch(ctx, batch)
+		inserted, skipped, err := s.importBatch(ctx, batch)
 		if err != nil {
+			s.notifyObservers(ctx, insertedContests)
 			return fmt.Errorf("failed to import batch %d-%d: %w", i, end-1, err)
 		}
+		imported := len(inserted)
+		insertedContests = append(insertedContests, inserted...)
 
 		totalImported += imported
 		totalSkipped += skipped
@@ -192,6 +208,8 @@ func (s *ImportService) ImportDraws(ctx context.Context, draws []models.Draw) er
 	}
 
 	s.logger.Info("Import completed", "total_imported", totalImported, "total_skipped", totalSkipped)
+	s.notifyObservers(ctx, insertedContests)
+
 	if totalSkipped > 0 {
 		return fmt.Errorf("import completed with %d errors out of %d draws", totalSkipped, len(draws))
 	}
@@ -199,11 +217,28 @@ func (s *ImportService) ImportDraws(ctx context.Context, draws []models.Draw) er
 	return nil
 }
 
-// importBatch imports a batch of draws within a single transaction
-func (s *ImportService) importBatch(ctx context.Context, draws []models.Draw) (imported int, skipped int, err error) {
+// notifyObservers hands newly inserted contests to every registered observer.
+// Observer failures are logged and never fail the import itself.
+func (s *ImportService) notifyObservers(ctx context.Context, contests []int) {
+	if len(contests) == 0 {
+		return
+	}
+	for _, o := range s.observers {
+		if err := o.OnDrawsImported(ctx, contests); err != nil {
+			s.logger.Error("Draw observer failed", "contests", len(contests), "error", err)
+		}
+	}
+}
+
+// importBatch imports a batch of draws within a single transaction and
+// returns the contests that were inserted
+func (s *ImportService) importBatch(ctx context.Context, draws []models.Draw) (inserted []int, skipped int, err error) {
 	if s.db == nil {
 		// For testing without DB
-		return len(draws), 0, nil
+		for _, draw := range draws {
+			inserted = append(inserted, draw.Contest)
+		}
+		return inserted, 0, nil
 	}
 
 	err = s.db.WithResultsTx(ctx, func(q results.Querier) error {
@@ -226,11 +261,14 @@ func (s *ImportService) importBatch(ctx context.Context, draws []models.Draw) (i
 				skipped++
 				continue
 			}
-			imported++
+			inserted = append(inserted, draw.Contest)
 		}
 		return nil
 	})
-	return imported, skipped, err
+	if err != nil {
+		return nil, skipped, err
+	}
+	return inserted, skipped, nil
 }
 
 // SaveArtifact saves an uploaded XLSX file temporarily and returns an artifact ID
@@ -391,6 +391,37 @@ func TestImportService_ImportDraws(t *testing.T) {
 	}
 }
 
+type recordingObserver struct {
+	contests []int
+}
+
+func (o *recordingObserver) OnDrawsImported(ctx context.Context, contests []int) error {
+	o.contests = append(o.contests, contests...)
+	return nil
+}
+
+func TestImportService_ImportDraws_NotifiesObservers(t *testing.T) {
+	mockDB := &mockDB{querier: &mockQuerier{}}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	service := NewImportService(mockDB, logger)
+
+	observer := &recordingObserver{}
+	service.AddDrawObserver(observer)
+
+	draws := []models.Draw{
+		{Contest: 10, DrawDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 4, Bola5: 5},
+		{Contest: 11, DrawDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Bola1: 6, Bola2: 7, Bola3: 8, Bola4: 9, Bola5: 10},
+	}
+
+	if err := service.ImportDraws(context.Background(), draws); err != nil {
+		t.Fatalf("ImportDraws failed: %v", err)
+	}
+
+	if len(observer.contests) != 2 || observer.contests[0] != 10 || observer.contests[1] != 11 {
+		t.Errorf("expected observer to receive contests [10 11], got %v", observer.contests)
+	}
+}
+
 func TestImportService_SaveArtifact(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	service := NewImportService(nil, logger)
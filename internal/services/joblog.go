@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// maxJobLogBytes bounds the log kept for a single simulation run. Records past
+// the limit are counted and dropped.
+const maxJobLogBytes = 1 << 20
+
+// jobLog is a bounded buffer of JSON log lines written by one job.
+type jobLog struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	max     int
+	dropped int
+}
+
+func newJobLog(max int) *jobLog {
+	return &jobLog{max: max}
+}
+
+// Write stores one record; slog handlers write each record in a single call.
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buf.Len()+len(p) > l.max {
+		l.dropped++
+		return len(p), nil
+	}
+	return l.buf.Write(p)
+}
+
+// Compress returns the gzipped log, noting how many records were dropped.
+func (l *jobLog) Compress() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	if _, err := zw.Write(l.buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if l.dropped > 0 {
+		if _, err := fmt.Fprintf(zw, "{\"msg\":\"log truncated\",\"dropped_records\":%d}\n", l.dropped); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// teeHandler sends every record to the job log and, when it accepts the
+// level, to the service logger.
+type teeHandler struct {
+	next    slog.Handler
+	capture slog.Handler
+}
+
+// newJobLogger returns a logger that tees into log, with the job ID attached.
+func newJobLogger(base *slog.Logger, log *jobLog, jobID int64) *slog.Logger {
+	h := &teeHandler{
+		next:    base.Handler(),
+		capture: slog.NewJSONHandler(log, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	}
+	return slog.New(h).With("job_id", jobID)
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.capture.Enabled(ctx, level) || h.next.Enabled(ctx, level)
+}
+
+func (h *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.next.Enabled(ctx, r.Level) {
+		if err := h.next.Handle(ctx, r.Clone()); err != nil {
+			return err
+		}
+	}
+	return h.capture.Handle(ctx, r)
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &teeHandler{next: h.next.WithAttrs(attrs), capture: h.capture.WithAttrs(attrs)}
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return &teeHandler{next: h.next.WithGroup(name), capture: h.capture.WithGroup(name)}
+}
+
+// decompressJobLog returns a reader over a log stored by jobLog.Compress.
+func decompressJobLog(blob []byte) (io.ReadCloser, error) {
+	return gzip.NewReader(bytes.NewReader(blob))
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestJobLogger_TeesWithJobID(t *testing.T) {
+	var base bytes.Buffer
+	log := newJobLog(maxJobLogBytes)
+	logger := newJobLogger(slog.New(slog.NewTextHandler(&base, nil)), log, 42)
+
+	logger.Debug("debug only in job log")
+	logger.Info("simulation started", "mode", "simple")
+
+	if !strings.Contains(base.String(), "simulation started") || !strings.Contains(base.String(), "job_id=42") {
+		t.Errorf("expected record in service log, got %q", base.String())
+	}
+	if strings.Contains(base.String(), "debug only") {
+		t.Errorf("expected debug record to stay out of the service log, got %q", base.String())
+	}
+
+	got := readJobLog(t, log)
+	if !strings.Contains(got, `"msg":"debug only in job log"`) || !strings.Contains(got, `"job_id":42`) {
+		t.Errorf("expected debug record with job ID in job log, got %q", got)
+	}
+}
+
+func TestJobLogger_DiscardedBase(t *testing.T) {
+	log := newJobLog(maxJobLogBytes)
+	logger := newJobLogger(slog.New(slog.DiscardHandler), log, 1)
+
+	logger.Info("captured")
+
+	if got := readJobLog(t, log); !strings.Contains(got, "captured") {
+		t.Errorf("expected record in job log, got %q", got)
+	}
+}
+
+func TestJobLog_Bounded(t *testing.T) {
+	log := newJobLog(200)
+	logger := newJobLogger(slog.New(slog.NewTextHandler(io.Discard, nil)), log, 1)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("a fairly long message to fill the buffer", "i", i)
+	}
+
+	got := readJobLog(t, log)
+	if !strings.Contains(got, `"msg":"log truncated"`) {
+		t.Errorf("expected truncation marker, got %q", got)
+	}
+	if strings.Count(got, "fill the buffer") >= 10 {
+		t.Errorf("expected records to be dropped, got %q", got)
+	}
+}
+
+func readJobLog(t *testing.T, log *jobLog) string {
+	t.Helper()
+
+	blob, err := log.Compress()
+	if err != nil {
+		t.Fatalf("compress: %v", err)
+	}
+	r, err := decompressJobLog(blob)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data)
+}
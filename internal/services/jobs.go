@@ -0,0 +1,361 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+)
+
+// Analysis job types run by workers.
+const (
+	JobTypeComparison    = "comparison"
+	JobTypeSweep         = "sweep"
+	JobTypeContestReport = "contest_report"
+)
+
+var (
+	// ErrUnknownJobType is returned for job types that are not registered.
+	ErrUnknownJobType = errors.New("unknown job type")
+	// ErrInvalidJobPayload is returned when a job type rejects a payload.
+	ErrInvalidJobPayload = errors.New("invalid job payload")
+	// ErrJobNotFound is returned for unknown job IDs.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotCancellable is returned when cancelling a finished job.
+	ErrJobNotCancellable = errors.New("job is not pending or running")
+	// ErrJobReportNotFound is returned for jobs without a report.
+	ErrJobReportNotFound = errors.New("job has no report")
+)
+
+// JobProgressFunc reports how much of a job is done, in units chosen by the
+// job type.
+type JobProgressFunc func(done, total int64)
+
+// JobOutput is what a job produces. Result is stored as JSON and Report as a
+// downloadable artifact named ReportName.
+type JobOutput struct {
+	Result     any
+	Report     []byte
+	ReportName string
+}
+
+// JobType is a kind of analysis job. Payloads are the JSON the job was queued
+// with.
+type JobType struct {
+	Name string
+	// Validate checks a payload before it is queued. Optional.
+	Validate func(payload json.RawMessage) error
+	// Run executes a job on a worker.
+	Run func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error)
+}
+
+// Job is an analysis job and its outcome.
+type Job struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	Status        string          `json:"status"`
+	Payload       json.RawMessage `json:"payload"`
+	ProgressDone  int64           `json:"progress_done"`
+	ProgressTotal int64           `json:"progress_total"`
+	Result        json.RawMessage `json:"result,omitempty"`
+	ReportName    string          `json:"report_name,omitempty"` // Download at /api/v1/jobs/{id}/report
+	Error         string          `json:"error,omitempty"`
+	WorkerID      string          `json:"worker_id,omitempty"`
+	Attempts      int64           `json:"attempts"`
+	CreatedBy     string          `json:"created_by,omitempty"`
+	CreatedAt     string          `json:"created_at"`
+	StartedAt     *string         `json:"started_at,omitempty"`
+	FinishedAt    *string         `json:"finished_at,omitempty"`
+}
+
+// JobFilter selects jobs to list. Empty fields match all jobs.
+type JobFilter struct {
+	Type   string
+	Status string
+	Limit  int
+	Offset int
+}
+
+type JobService struct {
+	jobsQueries jobs.Querier
+	types       map[string]JobType
+	notifier    JobNotifier
+	logger      *slog.Logger
+}
+
+type JobServicer interface {
+	EnqueueJob(ctx context.Context, jobType string, payload any, createdBy string) (*Job, error)
+	GetJob(ctx context.Context, id int64) (*Job, error)
+	ListJobs(ctx context.Context, filter JobFilter) ([]Job, error)
+	GetJobReport(ctx context.Context, id int64) (string, []byte, error)
+	CancelJob(ctx context.Context, id int64) error
+	ExecuteJob(ctx context.Context, job jobs.AnalysisJob) error
+	JobTypes() []string
+}
+
+func NewJobService(jobsQueries jobs.Querier, logger *slog.Logger) *JobService {
+	return &JobService{
+		jobsQueries: jobsQueries,
+		types:       make(map[string]JobType),
+		logger:      logger,
+	}
+}
+
+// RegisterJobType makes t available to EnqueueJob and ExecuteJob, replacing
+// a type of the same name. Register types before serving requests or
+// starting workers.
+func (s *JobService) RegisterJobType(t JobType) {
+	s.types[t.Name] = t
+}
+
+// SetJobNotifier makes EnqueueJob notify n about queued jobs.
+func (s *JobService) SetJobNotifier(n JobNotifier) {
+	s.notifier = n
+}
+
+// JobTypes returns the registered job types in name order.
+func (s *JobService) JobTypes() []string {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnqueueJob validates payload for jobType and queues the job for a worker.
+func (s *JobService) EnqueueJob(ctx context.Context, jobType string, payload any, createdBy string) (*Job, error) {
+	t, ok := s.types[jobType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+	}
+	if t.Validate != nil {
+		if err := t.Validate(payloadJSON); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+		}
+	}
+
+	row, err := s.jobsQueries.CreateAnalysisJob(ctx, jobs.CreateAnalysisJobParams{
+		JobType:    jobType,
+		ConfigJson: string(payloadJSON),
+		CreatedBy:  sql.NullString{String: createdBy, Valid: createdBy != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create job: %w", err)
+	}
+
+	s.logger.Info("job queued", "job_id", row.ID, "type", jobType)
+	if s.notifier != nil {
+		s.notifier.NotifyJobQueued()
+	}
+
+	job := jobFromRow(row)
+	return &job, nil
+}
+
+func (s *JobService) GetJob(ctx context.Context, id int64) (*Job, error) {
+	row, err := s.jobsQueries.GetAnalysisJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	job := jobFromRow(row)
+	return &job, nil
+}
+
+func (s *JobService) ListJobs(ctx context.Context, filter JobFilter) ([]Job, error) {
+	rows, err := s.jobsQueries.ListAnalysisJobs(ctx, jobs.ListAnalysisJobsParams{
+		JobType: filter.Type,
+		Status:  filter.Status,
+		Limit:   int64(filter.Limit),
+		Offset:  int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	list := make([]Job, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, jobFromRow(jobs.AnalysisJob{
+			ID:            row.ID,
+			CreatedAt:     row.CreatedAt,
+			StartedAt:     row.StartedAt,
+			FinishedAt:    row.FinishedAt,
+			Status:        row.Status,
+			JobType:       row.JobType,
+			CreatedBy:     row.CreatedBy,
+			ConfigJson:    row.ConfigJson,
+			ProgressDone:  row.ProgressDone,
+			ProgressTotal: row.ProgressTotal,
+			ResultJson:    row.ResultJson,
+			ReportName:    row.ReportName,
+			ErrorMessage:  row.ErrorMessage,
+			WorkerID:      row.WorkerID,
+			Attempts:      row.Attempts,
+		}))
+	}
+	return list, nil
+}
+
+// GetJobReport returns the name and content of the report a job produced.
+func (s *JobService) GetJobReport(ctx context.Context, id int64) (string, []byte, error) {
+	row, err := s.jobsQueries.GetAnalysisJobReport(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrJobNotFound
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("get job report: %w", err)
+	}
+	if !row.ReportName.Valid {
+		return "", nil, ErrJobReportNotFound
+	}
+	return row.ReportName.String, row.ReportBlob, nil
+}
+
+// CancelJob cancels a pending or running job. A running job is interrupted
+// when its worker next extends the lease.
+func (s *JobService) CancelJob(ctx context.Context, id int64) error {
+	n, err := s.jobsQueries.CancelAnalysisJob(ctx, jobs.CancelAnalysisJobParams{
+		FinishedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:         id,
+	})
+	if err != nil {
+		return fmt.Errorf("cancel job: %w", err)
+	}
+	if n == 0 {
+		if _, err := s.GetJob(ctx, id); err != nil {
+			return err
+		}
+		return ErrJobNotCancellable
+	}
+	s.logger.Info("job cancelled", "job_id", id)
+	return nil
+}
+
+// ExecuteJob runs a job claimed by a worker and stores its outcome. Jobs
+// interrupted by ErrLeaseLost or ErrWorkerShutdown are left to the worker. A
+// job the worker no longer owns when it finishes returns ErrLeaseLost.
+func (s *JobService) ExecuteJob(ctx context.Context, job jobs.AnalysisJob) error {
+	start := time.Now()
+
+	t, ok := s.types[job.JobType]
+	if !ok {
+		err := fmt.Errorf("%w: %s", ErrUnknownJobType, job.JobType)
+		s.failJob(ctx, job, err)
+		return err
+	}
+
+	progress := func(done, total int64) {
+		_, err := s.jobsQueries.UpdateAnalysisJobProgress(ctx, jobs.UpdateAnalysisJobProgressParams{
+			ProgressDone:  done,
+			ProgressTotal: total,
+			ID:            job.ID,
+			WorkerID:      job.WorkerID,
+		})
+		if err != nil && ctx.Err() == nil {
+			s.logger.Warn("update job progress failed", "job_id", job.ID, "error", err)
+		}
+	}
+
+	out, err := t.Run(ctx, json.RawMessage(job.ConfigJson), progress)
+	if cause := interruption(ctx); cause != nil {
+		return cause
+	}
+	if err != nil {
+		s.failJob(ctx, job, err)
+		return err
+	}
+	if out == nil {
+		out = &JobOutput{}
+	}
+
+	result := sql.NullString{}
+	if out.Result != nil {
+		resultJSON, err := json.Marshal(out.Result)
+		if err != nil {
+			err = fmt.Errorf("encode result: %w", err)
+			s.failJob(ctx, job, err)
+			return err
+		}
+		result = sql.NullString{String: string(resultJSON), Valid: true}
+	}
+
+	n, err := s.jobsQueries.CompleteAnalysisJob(ctx, jobs.CompleteAnalysisJobParams{
+		FinishedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		ResultJson: result,
+		ReportBlob: out.Report,
+		ReportName: sql.NullString{String: out.ReportName, Valid: out.ReportName != ""},
+		ID:         job.ID,
+		WorkerID:   job.WorkerID,
+	})
+	if err != nil {
+		return fmt.Errorf("complete job: %w", err)
+	}
+	if n == 0 {
+		// Cancelled or reclaimed while running, so its outcome is discarded
+		return ErrLeaseLost
+	}
+
+	s.logger.Info("job completed", "job_id", job.ID, "type", job.JobType, "duration", time.Since(start))
+	return nil
+}
+
+func (s *JobService) failJob(ctx context.Context, job jobs.AnalysisJob, cause error) {
+	_, err := s.jobsQueries.FailAnalysisJob(ctx, jobs.FailAnalysisJobParams{
+		FinishedAt:   sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		ErrorMessage: sql.NullString{String: cause.Error(), Valid: true},
+		ID:           job.ID,
+		WorkerID:     job.WorkerID,
+	})
+	if err != nil {
+		s.logger.Error("fail job failed", "job_id", job.ID, "error", err)
+	}
+}
+
+// decodeJobPayload decodes a payload, rejecting unknown fields.
+func decodeJobPayload(payload json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func jobFromRow(row jobs.AnalysisJob) Job {
+	job := Job{
+		ID:            row.ID,
+		Type:          row.JobType,
+		Status:        row.Status,
+		Payload:       json.RawMessage(row.ConfigJson),
+		ProgressDone:  row.ProgressDone,
+		ProgressTotal: row.ProgressTotal,
+		ReportName:    row.ReportName.String,
+		Error:         row.ErrorMessage.String,
+		WorkerID:      row.WorkerID.String,
+		Attempts:      row.Attempts,
+		CreatedBy:     row.CreatedBy.String,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.ResultJson.Valid {
+		job.Result = json.RawMessage(row.ResultJson.String)
+	}
+	if row.StartedAt.Valid {
+		job.StartedAt = &row.StartedAt.String
+	}
+	if row.FinishedAt.Valid {
+		job.FinishedAt = &row.FinishedAt.String
+	}
+	return job
+}
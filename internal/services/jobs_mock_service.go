@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/jobs.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jobs "github.com/garnizeh/luckyfive/internal/store/jobs"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJobServicer is a mock of JobServicer interface.
+type MockJobServicer struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobServicerMockRecorder
+}
+
+// MockJobServicerMockRecorder is the mock recorder for MockJobServicer.
+type MockJobServicerMockRecorder struct {
+	mock *MockJobServicer
+}
+
+// NewMockJobServicer creates a new mock instance.
+func NewMockJobServicer(ctrl *gomock.Controller) *MockJobServicer {
+	mock := &MockJobServicer{ctrl: ctrl}
+	mock.recorder = &MockJobServicerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobServicer) EXPECT() *MockJobServicerMockRecorder {
+	return m.recorder
+}
+
+// CancelJob mocks base method.
+func (m *MockJobServicer) CancelJob(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockJobServicerMockRecorder) CancelJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockJobServicer)(nil).CancelJob), ctx, id)
+}
+
+// EnqueueJob mocks base method.
+func (m *MockJobServicer) EnqueueJob(ctx context.Context, jobType string, payload any, createdBy string) (*Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueJob", ctx, jobType, payload, createdBy)
+	ret0, _ := ret[0].(*Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueJob indicates an expected call of EnqueueJob.
+func (mr *MockJobServicerMockRecorder) EnqueueJob(ctx, jobType, payload, createdBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueJob", reflect.TypeOf((*MockJobServicer)(nil).EnqueueJob), ctx, jobType, payload, createdBy)
+}
+
+// ExecuteJob mocks base method.
+func (m *MockJobServicer) ExecuteJob(ctx context.Context, job jobs.AnalysisJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteJob indicates an expected call of ExecuteJob.
+func (mr *MockJobServicerMockRecorder) ExecuteJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteJob", reflect.TypeOf((*MockJobServicer)(nil).ExecuteJob), ctx, job)
+}
+
+// GetJob mocks base method.
+func (m *MockJobServicer) GetJob(ctx context.Context, id int64) (*Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, id)
+	ret0, _ := ret[0].(*Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockJobServicerMockRecorder) GetJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockJobServicer)(nil).GetJob), ctx, id)
+}
+
+// GetJobReport mocks base method.
+func (m *MockJobServicer) GetJobReport(ctx context.Context, id int64) (string, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobReport", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetJobReport indicates an expected call of GetJobReport.
+func (mr *MockJobServicerMockRecorder) GetJobReport(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobReport", reflect.TypeOf((*MockJobServicer)(nil).GetJobReport), ctx, id)
+}
+
+// JobTypes mocks base method.
+func (m *MockJobServicer) JobTypes() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobTypes")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// JobTypes indicates an expected call of JobTypes.
+func (mr *MockJobServicerMockRecorder) JobTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobTypes", reflect.TypeOf((*MockJobServicer)(nil).JobTypes))
+}
+
+// ListJobs mocks base method.
+func (m *MockJobServicer) ListJobs(ctx context.Context, filter JobFilter) ([]Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, filter)
+	ret0, _ := ret[0].([]Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockJobServicerMockRecorder) ListJobs(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockJobServicer)(nil).ListJobs), ctx, filter)
+}
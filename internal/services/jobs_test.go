@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+	jobsmock "github.com/garnizeh/luckyfive/internal/store/jobs/mock"
+)
+
+type echoPayload struct {
+	Value int `json:"value"`
+}
+
+func newTestJobService(t *testing.T) (*JobService, *jobsmock.MockQuerier) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockQueries := jobsmock.NewMockQuerier(ctrl)
+	service := NewJobService(mockQueries, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.RegisterJobType(JobType{
+		Name: "echo",
+		Validate: func(payload json.RawMessage) error {
+			var p echoPayload
+			if err := decodeJobPayload(payload, &p); err != nil {
+				return err
+			}
+			if p.Value < 0 {
+				return errors.New("value must be non-negative")
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			var p echoPayload
+			if err := decodeJobPayload(payload, &p); err != nil {
+				return nil, err
+			}
+			if p.Value == 13 {
+				return nil, errors.New("unlucky")
+			}
+			progress(1, 1)
+			return &JobOutput{Result: p, Report: []byte("value\n1\n"), ReportName: "echo.csv"}, nil
+		},
+	})
+	return service, mockQueries
+}
+
+func TestJobService_EnqueueJob(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+	notifier := &countingNotifier{}
+	service.SetJobNotifier(notifier)
+
+	mockQueries.EXPECT().
+		CreateAnalysisJob(gomock.Any(), jobs.CreateAnalysisJobParams{
+			JobType:    "echo",
+			ConfigJson: `{"value":3}`,
+			CreatedBy:  sql.NullString{String: "alice", Valid: true},
+		}).
+		Return(jobs.AnalysisJob{ID: 7, JobType: "echo", Status: "pending", ConfigJson: `{"value":3}`}, nil)
+
+	job, err := service.EnqueueJob(context.Background(), "echo", echoPayload{Value: 3}, "alice")
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	if job.ID != 7 || job.Status != "pending" || string(job.Payload) != `{"value":3}` {
+		t.Errorf("unexpected job %+v", job)
+	}
+	if notifier.notified != 1 {
+		t.Errorf("expected 1 notification, got %d", notifier.notified)
+	}
+}
+
+func TestJobService_EnqueueJob_Rejected(t *testing.T) {
+	service, _ := newTestJobService(t)
+
+	if _, err := service.EnqueueJob(context.Background(), "missing", nil, ""); !errors.Is(err, ErrUnknownJobType) {
+		t.Errorf("expected ErrUnknownJobType, got %v", err)
+	}
+	if _, err := service.EnqueueJob(context.Background(), "echo", echoPayload{Value: -1}, ""); !errors.Is(err, ErrInvalidJobPayload) {
+		t.Errorf("expected ErrInvalidJobPayload, got %v", err)
+	}
+	if _, err := service.EnqueueJob(context.Background(), "echo", map[string]int{"other": 1}, ""); !errors.Is(err, ErrInvalidJobPayload) {
+		t.Errorf("expected ErrInvalidJobPayload for unknown field, got %v", err)
+	}
+}
+
+func TestJobService_ExecuteJob_Completes(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+	worker := sql.NullString{String: "w1", Valid: true}
+
+	mockQueries.EXPECT().
+		UpdateAnalysisJobProgress(gomock.Any(), jobs.UpdateAnalysisJobProgressParams{ProgressDone: 1, ProgressTotal: 1, ID: 4, WorkerID: worker}).
+		Return(int64(1), nil)
+	mockQueries.EXPECT().
+		CompleteAnalysisJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg jobs.CompleteAnalysisJobParams) (int64, error) {
+			if arg.ID != 4 || arg.WorkerID != worker {
+				t.Errorf("unexpected job %d/%v", arg.ID, arg.WorkerID)
+			}
+			if arg.ResultJson.String != `{"value":5}` {
+				t.Errorf("unexpected result %q", arg.ResultJson.String)
+			}
+			if arg.ReportName.String != "echo.csv" || string(arg.ReportBlob) != "value\n1\n" {
+				t.Errorf("unexpected report %q: %q", arg.ReportName.String, arg.ReportBlob)
+			}
+			if !arg.FinishedAt.Valid {
+				t.Error("expected finished_at")
+			}
+			return 1, nil
+		})
+
+	err := service.ExecuteJob(context.Background(), jobs.AnalysisJob{ID: 4, JobType: "echo", ConfigJson: `{"value":5}`, WorkerID: worker})
+	if err != nil {
+		t.Fatalf("ExecuteJob failed: %v", err)
+	}
+}
+
+func TestJobService_ExecuteJob_NoLongerOwned(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+
+	// The job was cancelled or reclaimed while it ran
+	mockQueries.EXPECT().UpdateAnalysisJobProgress(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	mockQueries.EXPECT().CompleteAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	err := service.ExecuteJob(context.Background(), jobs.AnalysisJob{ID: 4, JobType: "echo", ConfigJson: `{"value":5}`})
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost, got %v", err)
+	}
+}
+
+func TestJobService_ExecuteJob_Fails(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+
+	mockQueries.EXPECT().
+		FailAnalysisJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg jobs.FailAnalysisJobParams) (int64, error) {
+			if arg.ID != 4 || arg.ErrorMessage.String != "unlucky" {
+				t.Errorf("unexpected failure %d: %q", arg.ID, arg.ErrorMessage.String)
+			}
+			return 1, nil
+		})
+	mockQueries.EXPECT().
+		FailAnalysisJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg jobs.FailAnalysisJobParams) (int64, error) {
+			if arg.ID != 5 {
+				t.Errorf("expected job 5, got %d", arg.ID)
+			}
+			return 1, nil
+		})
+
+	if err := service.ExecuteJob(context.Background(), jobs.AnalysisJob{ID: 4, JobType: "echo", ConfigJson: `{"value":13}`}); err == nil {
+		t.Error("expected run error")
+	}
+	if err := service.ExecuteJob(context.Background(), jobs.AnalysisJob{ID: 5, JobType: "gone"}); !errors.Is(err, ErrUnknownJobType) {
+		t.Errorf("expected ErrUnknownJobType, got %v", err)
+	}
+}
+
+func TestJobService_ExecuteJob_Interrupted(t *testing.T) {
+	service, _ := newTestJobService(t)
+	service.RegisterJobType(JobType{
+		Name: "slow",
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrWorkerShutdown)
+
+	// No store calls are expected: the worker requeues interrupted jobs.
+	err := service.ExecuteJob(ctx, jobs.AnalysisJob{ID: 4, JobType: "slow"})
+	if !errors.Is(err, ErrWorkerShutdown) {
+		t.Errorf("expected ErrWorkerShutdown, got %v", err)
+	}
+}
+
+func TestJobService_CancelJob(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+
+	mockQueries.EXPECT().CancelAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	if err := service.CancelJob(context.Background(), 1); err != nil {
+		t.Errorf("CancelJob failed: %v", err)
+	}
+
+	mockQueries.EXPECT().CancelAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	mockQueries.EXPECT().GetAnalysisJob(gomock.Any(), int64(2)).Return(jobs.AnalysisJob{ID: 2, Status: "completed"}, nil)
+	if err := service.CancelJob(context.Background(), 2); !errors.Is(err, ErrJobNotCancellable) {
+		t.Errorf("expected ErrJobNotCancellable, got %v", err)
+	}
+
+	mockQueries.EXPECT().CancelAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	mockQueries.EXPECT().GetAnalysisJob(gomock.Any(), int64(3)).Return(jobs.AnalysisJob{}, sql.ErrNoRows)
+	if err := service.CancelJob(context.Background(), 3); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestJobService_GetJobReport(t *testing.T) {
+	service, mockQueries := newTestJobService(t)
+
+	mockQueries.EXPECT().GetAnalysisJobReport(gomock.Any(), int64(1)).
+		Return(jobs.GetAnalysisJobReportRow{ReportName: sql.NullString{String: "echo.csv", Valid: true}, ReportBlob: []byte("x")}, nil)
+	name, report, err := service.GetJobReport(context.Background(), 1)
+	if err != nil || name != "echo.csv" || string(report) != "x" {
+		t.Errorf("unexpected report %q %q %v", name, report, err)
+	}
+
+	mockQueries.EXPECT().GetAnalysisJobReport(gomock.Any(), int64(2)).Return(jobs.GetAnalysisJobReportRow{}, nil)
+	if _, _, err := service.GetJobReport(context.Background(), 2); !errors.Is(err, ErrJobReportNotFound) {
+		t.Errorf("expected ErrJobReportNotFound, got %v", err)
+	}
+}
@@ -47,6 +47,10 @@ type LeaderboardRequest struct {
 	DateTo   string `json:"date_to"`   // RFC3339 format
 	Limit    int    `json:"limit"`
 	Offset   int    `json:"offset"`
+
+	// UseLowerBound ranks multi-seed simulations by the lower bound of the
+	// metric's confidence interval instead of the point estimate.
+	UseLowerBound bool `json:"use_lower_bound"`
 }
 
 func (s *LeaderboardService) GetLeaderboard(
@@ -94,6 +98,9 @@ func (s *LeaderboardService) GetLeaderboard(
 		}
 
 		metricValue := s.calculateMetricValue(req.Metric, summary)
+		if req.UseLowerBound {
+			metricValue = summaryMetricLowerBound(req.Metric, summary)
+		}
 
 		entry := LeaderboardEntry{
 			SimulationID:   sim.ID,
@@ -211,29 +218,7 @@ func (s *LeaderboardService) fetchSimulations(
 }
 
 func (s *LeaderboardService) calculateMetricValue(metric string, summary Summary) float64 {
-	switch metric {
-	case "quina_rate":
-		return summary.HitRateQuina
-	case "quadra_rate":
-		return summary.HitRateQuadra
-	case "terno_rate":
-		return summary.HitRateTerno
-	case "avg_hits":
-		return summary.AverageHits
-	case "total_quinaz":
-		return float64(summary.QuinaHits)
-	case "total_quadras":
-		return float64(summary.QuadraHits)
-	case "total_ternos":
-		return float64(summary.TernoHits)
-	case "hit_efficiency":
-		if summary.TotalContests > 0 {
-			return summary.AverageHits
-		}
-		return 0
-	default:
-		return 0
-	}
+	return summaryMetricValue(metric, summary)
 }
 
 func (s *LeaderboardService) isValidMetric(metric string) bool {
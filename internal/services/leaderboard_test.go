@@ -17,6 +17,10 @@ type mockSimulationQuerier struct {
 	listSimulationsFunc func(ctx context.Context, arg simulations.ListSimulationsParams) ([]simulations.Simulation, error)
 }
 
+func (m *mockSimulationQuerier) AddSimulationTag(ctx context.Context, arg simulations.AddSimulationTagParams) error {
+	return nil
+}
+
 func (m *mockSimulationQuerier) CancelSimulation(ctx context.Context, arg simulations.CancelSimulationParams) error {
 	return nil
 }
@@ -25,22 +29,46 @@ func (m *mockSimulationQuerier) ClaimPendingSimulation(ctx context.Context, arg
 	return simulations.Simulation{}, nil
 }
 
-func (m *mockSimulationQuerier) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) error {
-	return nil
+func (m *mockSimulationQuerier) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) (int64, error) {
+	return 1, nil
 }
 
 func (m *mockSimulationQuerier) CountSimulationsByStatus(ctx context.Context, status string) (int64, error) {
 	return 0, nil
 }
 
+func (m *mockSimulationQuerier) FailExpiredSimulations(ctx context.Context, arg simulations.FailExpiredSimulationsParams) ([]int64, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) HeartbeatSimulation(ctx context.Context, arg simulations.HeartbeatSimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQuerier) RequeueExpiredSimulations(ctx context.Context, arg simulations.RequeueExpiredSimulationsParams) ([]int64, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) CountContestResults(ctx context.Context, arg simulations.CountContestResultsParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQuerier) CountSearchSimulations(ctx context.Context, arg simulations.CountSearchSimulationsParams) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockSimulationQuerier) CreateSimulation(ctx context.Context, arg simulations.CreateSimulationParams) (simulations.Simulation, error) {
 	return simulations.Simulation{}, nil
 }
 
-func (m *mockSimulationQuerier) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) error {
+func (m *mockSimulationQuerier) DeleteSimulationTags(ctx context.Context, simulationID int64) error {
 	return nil
 }
 
+func (m *mockSimulationQuerier) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) (int64, error) {
+	return 1, nil
+}
+
 func (m *mockSimulationQuerier) GetContestResults(ctx context.Context, arg simulations.GetContestResultsParams) ([]simulations.SimulationContestResult, error) {
 	return nil, nil
 }
@@ -68,6 +96,62 @@ func (m *mockSimulationQuerier) ListSimulationsByStatus(ctx context.Context, arg
 	return []simulations.Simulation{}, nil
 }
 
+func (m *mockSimulationQuerier) ListSimulationTags(ctx context.Context, simulationID int64) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) SaveSimulationLog(ctx context.Context, arg simulations.SaveSimulationLogParams) error {
+	return nil
+}
+
+func (m *mockSimulationQuerier) ListContestResults(ctx context.Context, arg simulations.ListContestResultsParams) ([]simulations.SimulationContestResult, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) SearchSimulations(ctx context.Context, arg simulations.SearchSimulationsParams) ([]simulations.Simulation, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) RegisterWorker(ctx context.Context, arg simulations.RegisterWorkerParams) error {
+	return nil
+}
+
+func (m *mockSimulationQuerier) HeartbeatWorker(ctx context.Context, arg simulations.HeartbeatWorkerParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQuerier) StopWorker(ctx context.Context, arg simulations.StopWorkerParams) error {
+	return nil
+}
+
+func (m *mockSimulationQuerier) ListWorkers(ctx context.Context, since string) ([]simulations.ListWorkersRow, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) ListRunningSimulationsByWorker(ctx context.Context) ([]simulations.ListRunningSimulationsByWorkerRow, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) ReleaseSimulation(ctx context.Context, arg simulations.ReleaseSimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQuerier) RetrySimulation(ctx context.Context, arg simulations.RetrySimulationParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSimulationQuerier) RecordSimulationAttempt(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+	return nil
+}
+
+func (m *mockSimulationQuerier) ListSimulationAttempts(ctx context.Context, simulationID int64) ([]simulations.SimulationAttempt, error) {
+	return nil, nil
+}
+
+func (m *mockSimulationQuerier) UpdateQueuedSimulation(ctx context.Context, arg simulations.UpdateQueuedSimulationParams) (simulations.Simulation, error) {
+	return simulations.Simulation{}, nil
+}
+
 func (m *mockSimulationQuerier) UpdateSimulationStatus(ctx context.Context, arg simulations.UpdateSimulationStatusParams) error {
 	return nil
 }
@@ -139,6 +223,51 @@ func TestLeaderboardService_GetLeaderboard_Success(t *testing.T) {
 	}
 }
 
+func TestLeaderboardService_GetLeaderboard_UseLowerBound(t *testing.T) {
+	// Higher point estimate but a wide interval
+	volatile, _ := json.Marshal(Summary{
+		HitRateQuina:  0.08,
+		Seeds:         10,
+		Distributions: map[string]MetricDistribution{"quina_rate": {Mean: 0.08, CILow: 0.01, CIHigh: 0.15}},
+	})
+	// Lower point estimate but a tight interval
+	stable, _ := json.Marshal(Summary{
+		HitRateQuina:  0.05,
+		Seeds:         10,
+		Distributions: map[string]MetricDistribution{"quina_rate": {Mean: 0.05, CILow: 0.04, CIHigh: 0.06}},
+	})
+
+	mockQuerier := &mockSimulationQuerier{
+		listSimulationsFunc: func(ctx context.Context, arg simulations.ListSimulationsParams) ([]simulations.Simulation, error) {
+			return []simulations.Simulation{
+				{ID: 1, Status: "completed", Mode: "simple", SummaryJson: sql.NullString{String: string(volatile), Valid: true}, CreatedAt: time.Now().Format(time.RFC3339)},
+				{ID: 2, Status: "completed", Mode: "simple", SummaryJson: sql.NullString{String: string(stable), Valid: true}, CreatedAt: time.Now().Format(time.RFC3339)},
+			}, nil
+		},
+	}
+
+	service := NewLeaderboardService(mockQuerier, nil)
+
+	entries, err := service.GetLeaderboard(context.Background(), LeaderboardRequest{Metric: "quina_rate", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if entries[0].SimulationID != 1 {
+		t.Errorf("expected simulation 1 first by point estimate, got %d", entries[0].SimulationID)
+	}
+
+	entries, err = service.GetLeaderboard(context.Background(), LeaderboardRequest{Metric: "quina_rate", Limit: 10, UseLowerBound: true})
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if entries[0].SimulationID != 2 {
+		t.Errorf("expected simulation 2 first by lower bound, got %d", entries[0].SimulationID)
+	}
+	if entries[0].MetricValue != 0.04 {
+		t.Errorf("expected metric value 0.04, got %f", entries[0].MetricValue)
+	}
+}
+
 func TestLeaderboardService_GetLeaderboard_FilterByMode(t *testing.T) {
 	summaryJSON, _ := json.Marshal(Summary{HitRateQuina: 0.05})
 
@@ -0,0 +1,474 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+var (
+	// ErrNoPendingSimulation is returned to remote workers when no simulation
+	// can be claimed.
+	ErrNoPendingSimulation = errors.New("no pending simulation")
+	// ErrWorkerNotRegistered is returned for heartbeats of workers missing
+	// from the registry. The worker registers again.
+	ErrWorkerNotRegistered = errors.New("worker not registered")
+	// ErrInvalidLeaseRequest is returned for malformed requests of remote
+	// workers, e.g. a completed run without a result.
+	ErrInvalidLeaseRequest = errors.New("invalid lease request")
+)
+
+// WorkerRegistration describes a worker process in the registry.
+type WorkerRegistration struct {
+	Host        string `json:"host"`
+	Version     string `json:"version"`
+	Concurrency int    `json:"concurrency"`
+}
+
+// SimulationLease is a simulation claimed by a remote worker, with everything
+// needed to run it without the simulations database.
+type SimulationLease struct {
+	SimulationID    int64            `json:"simulation_id"`
+	Attempt         int64            `json:"attempt"`
+	RecipeName      string           `json:"recipe_name"`
+	Config          SimulationConfig `json:"config"`
+	Limits          ExecutionLimits  `json:"limits"`
+	Seeds           int              `json:"seeds,omitempty"`
+	ConfidenceLevel float64          `json:"confidence_level,omitempty"`
+}
+
+// LeaseReport is the outcome of a leased run sent back by a remote worker:
+// a result when the run completed, an error otherwise.
+type LeaseReport struct {
+	Result     *SimulationResult `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	ErrorStack string            `json:"error_stack,omitempty"` // Set for panics
+	Transient  bool              `json:"transient,omitempty"`
+	Log        []byte            `json:"log,omitempty"` // Gzipped job log
+}
+
+// ReapResult lists the simulations whose lease expired.
+type ReapResult struct {
+	Failed   []int64 `json:"failed"`   // Out of attempts
+	Requeued []int64 `json:"requeued"` // Pending again
+}
+
+// remoteError is a failed run reported by a remote worker.
+type remoteError struct {
+	message   string
+	transient bool
+}
+
+func (e *remoteError) Error() string { return e.message }
+
+func (e *remoteError) Is(target error) bool { return e.transient && target == ErrTransient }
+
+// LeaseService lets remote workers claim and run simulations through the API,
+// so that only the API process opens the simulations database. Runs reported
+// by remote workers go through the same retry policy, history and observers
+// as runs of local workers.
+type LeaseService struct {
+	simulationService  *SimulationService
+	simulationsQueries simulations.Querier
+	draws              DrawFetcher
+	logger             *slog.Logger
+}
+
+type LeaseServicer interface {
+	RegisterWorker(ctx context.Context, workerID string, registration WorkerRegistration) error
+	HeartbeatWorker(ctx context.Context, workerID string, activeJobs int) error
+	StopWorker(ctx context.Context, workerID string) error
+	ClaimSimulation(ctx context.Context, workerID string, lease time.Duration) (*SimulationLease, error)
+	HeartbeatSimulation(ctx context.Context, workerID string, simID int64, lease time.Duration) error
+	ReleaseSimulation(ctx context.Context, workerID string, simID int64) error
+	CompleteSimulation(ctx context.Context, workerID string, simID int64, report LeaseReport) error
+	FailSimulation(ctx context.Context, workerID string, simID int64, report LeaseReport) (bool, error)
+	ReapExpiredSimulations(ctx context.Context) (*ReapResult, error)
+	ListDraws(ctx context.Context, fromContest, toContest int) ([]results.Draw, error)
+}
+
+// NewLeaseService creates a lease service. simulationService decides on
+// retries and limits of leased runs, like it does for local workers.
+func NewLeaseService(
+	simulationService *SimulationService,
+	simulationsQueries simulations.Querier,
+	draws DrawFetcher,
+	logger *slog.Logger,
+) *LeaseService {
+	return &LeaseService{
+		simulationService:  simulationService,
+		simulationsQueries: simulationsQueries,
+		draws:              draws,
+		logger:             logger,
+	}
+}
+
+// RegisterWorker adds a remote worker to the registry, or resets its entry
+// when it restarts with the same ID.
+func (s *LeaseService) RegisterWorker(ctx context.Context, workerID string, registration WorkerRegistration) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := s.simulationsQueries.RegisterWorker(ctx, simulations.RegisterWorkerParams{
+		ID:          workerID,
+		Host:        registration.Host,
+		Version:     registration.Version,
+		Concurrency: int64(registration.Concurrency),
+		StartedAt:   now,
+		HeartbeatAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("register worker: %w", err)
+	}
+	return nil
+}
+
+// HeartbeatWorker refreshes the registry entry of a remote worker.
+func (s *LeaseService) HeartbeatWorker(ctx context.Context, workerID string, activeJobs int) error {
+	n, err := s.simulationsQueries.HeartbeatWorker(ctx, simulations.HeartbeatWorkerParams{
+		HeartbeatAt: time.Now().UTC().Format(time.RFC3339),
+		ActiveJobs:  int64(activeJobs),
+		ID:          workerID,
+	})
+	if err != nil {
+		return fmt.Errorf("heartbeat worker: %w", err)
+	}
+	if n == 0 {
+		return ErrWorkerNotRegistered
+	}
+	return nil
+}
+
+// StopWorker marks a remote worker stopped once it drained its jobs.
+func (s *LeaseService) StopWorker(ctx context.Context, workerID string) error {
+	err := s.simulationsQueries.StopWorker(ctx, simulations.StopWorkerParams{
+		StoppedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:        workerID,
+	})
+	if err != nil {
+		return fmt.Errorf("stop worker: %w", err)
+	}
+	return nil
+}
+
+// ClaimSimulation claims the next pending simulation for a remote worker and
+// returns its run configuration. Simulations whose recipe or limits cannot be
+// run are failed on the way, and the next one is claimed.
+func (s *LeaseService) ClaimSimulation(ctx context.Context, workerID string, lease time.Duration) (*SimulationLease, error) {
+	for {
+		now := time.Now().UTC()
+		sim, err := s.simulationsQueries.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+			StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+			WorkerID:       sql.NullString{String: workerID, Valid: true},
+			LeaseExpiresAt: sql.NullString{String: now.Add(lease).Format(time.RFC3339), Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoPendingSimulation
+		}
+		if err != nil {
+			return nil, fmt.Errorf("claim simulation: %w", err)
+		}
+
+		logger := s.logger.With("simulation_id", sim.ID, "worker_id", workerID)
+		leased, err := s.leaseSimulation(sim)
+		if err != nil {
+			s.simulationService.failSimulation(ctx, sim, err, logger)
+			s.simulationService.NotifySimulationFinished(context.WithoutCancel(ctx), sim.ID)
+			continue
+		}
+
+		logger.Info("simulation leased", "attempt", sim.Attempts, "lease", lease)
+		s.simulationService.notifySimulationStarted(ctx, sim.ID)
+		return leased, nil
+	}
+}
+
+// leaseSimulation builds the run configuration of a claimed simulation.
+func (s *LeaseService) leaseSimulation(sim simulations.Simulation) (*SimulationLease, error) {
+	recipe, err := decodeRecipe(sim.RecipeJson)
+	if err != nil {
+		return nil, fmt.Errorf("decode recipe: %w", err)
+	}
+	limits, err := s.simulationService.limitPolicy.Resolve(recipe.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := engineConfig(sim, recipe, limits)
+	cfg.Limits = ExecutionLimits{}
+	return &SimulationLease{
+		SimulationID:    sim.ID,
+		Attempt:         sim.Attempts,
+		RecipeName:      recipe.Name,
+		Config:          cfg,
+		Limits:          limits,
+		Seeds:           recipe.Parameters.Seeds,
+		ConfidenceLevel: recipe.Parameters.ConfidenceLevel,
+	}, nil
+}
+
+// HeartbeatSimulation extends the lease of a simulation run by a remote
+// worker. It fails with ErrLeaseLost when the worker no longer owns it.
+func (s *LeaseService) HeartbeatSimulation(ctx context.Context, workerID string, simID int64, lease time.Duration) error {
+	now := time.Now().UTC()
+	n, err := s.simulationsQueries.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(lease).Format(time.RFC3339), Valid: true},
+		ID:             simID,
+		WorkerID:       sql.NullString{String: workerID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("heartbeat simulation: %w", err)
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ReleaseSimulation returns a simulation interrupted by the shutdown of a
+// remote worker to the queue without counting the run as an attempt.
+func (s *LeaseService) ReleaseSimulation(ctx context.Context, workerID string, simID int64) error {
+	n, err := s.simulationsQueries.ReleaseSimulation(ctx, simulations.ReleaseSimulationParams{
+		ID:       simID,
+		WorkerID: sql.NullString{String: workerID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("release simulation: %w", err)
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// CompleteSimulation stores the result of a run of a remote worker. A result
+// that cannot be stored fails the run like it would for a local worker. It
+// fails with ErrLeaseLost when the worker no longer owns the simulation.
+func (s *LeaseService) CompleteSimulation(ctx context.Context, workerID string, simID int64, report LeaseReport) error {
+	if report.Result == nil {
+		return fmt.Errorf("%w: result is required", ErrInvalidLeaseRequest)
+	}
+	sim, err := s.ownedSimulation(ctx, workerID, simID)
+	if err != nil {
+		return err
+	}
+	logger := s.logger.With("simulation_id", simID, "worker_id", workerID)
+	s.saveLog(ctx, simID, report)
+
+	if err := s.simulationService.saveResults(ctx, sim, report.Result); err != nil {
+		if errors.Is(err, ErrLeaseLost) {
+			return err
+		}
+		err = s.simulationService.failSimulation(ctx, sim, err, logger)
+		if errors.Is(err, ErrLeaseLost) {
+			return ErrLeaseLost
+		}
+		if !errors.Is(err, ErrRetryScheduled) {
+			s.simulationService.NotifySimulationFinished(context.WithoutCancel(ctx), simID)
+		}
+		return err
+	}
+
+	s.simulationService.recordAttempt(ctx, simulations.RecordSimulationAttemptParams{
+		SimulationID: simID,
+		Attempt:      attemptNumber(sim),
+		WorkerID:     sim.WorkerID,
+		StartedAt:    sim.StartedAt,
+		FinishedAt:   time.Now().UTC().Format(time.RFC3339),
+		Outcome:      attemptCompleted,
+	}, logger)
+
+	logger.Info("simulation completed", "contest_results", len(report.Result.ContestResults))
+	s.simulationService.NotifySimulationFinished(context.WithoutCancel(ctx), simID)
+	return nil
+}
+
+// FailSimulation records a failed run of a remote worker. It reports whether
+// the simulation was returned to the queue for another attempt.
+func (s *LeaseService) FailSimulation(ctx context.Context, workerID string, simID int64, report LeaseReport) (bool, error) {
+	if report.Error == "" {
+		return false, fmt.Errorf("%w: error is required", ErrInvalidLeaseRequest)
+	}
+	sim, err := s.ownedSimulation(ctx, workerID, simID)
+	if err != nil {
+		return false, err
+	}
+	logger := s.logger.With("simulation_id", simID, "worker_id", workerID)
+	s.saveLog(ctx, simID, report)
+
+	if report.ErrorStack != "" {
+		// Panics are never retried, as in ExecuteSimulation
+		errorMessage := sql.NullString{String: report.Error, Valid: true}
+		n, err := s.simulationsQueries.FailSimulation(context.WithoutCancel(ctx), simulations.FailSimulationParams{
+			ID:           simID,
+			WorkerID:     sim.WorkerID,
+			FinishedAt:   sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+			ErrorMessage: errorMessage,
+			ErrorStack:   sql.NullString{String: report.ErrorStack, Valid: true},
+		})
+		if err != nil {
+			return false, fmt.Errorf("fail simulation: %w", err)
+		}
+		s.simulationService.recordAttempt(ctx, simulations.RecordSimulationAttemptParams{
+			SimulationID: simID,
+			Attempt:      attemptNumber(sim),
+			WorkerID:     sim.WorkerID,
+			StartedAt:    sim.StartedAt,
+			FinishedAt:   time.Now().UTC().Format(time.RFC3339),
+			Outcome:      attemptFailed,
+			ErrorMessage: errorMessage,
+		}, logger)
+		if n == 0 {
+			return false, ErrLeaseLost
+		}
+		logger.Error("simulation panicked on remote worker", "error", report.Error)
+		s.simulationService.NotifySimulationFinished(context.WithoutCancel(ctx), simID)
+		return false, nil
+	}
+
+	// failSimulation checks the owner again, in case the simulation was
+	// reclaimed since ownedSimulation
+	err = s.simulationService.failSimulation(ctx, sim, &remoteError{message: report.Error, transient: report.Transient}, logger)
+	if errors.Is(err, ErrRetryScheduled) {
+		return true, nil
+	}
+	if errors.Is(err, ErrLeaseLost) {
+		return false, ErrLeaseLost
+	}
+	logger.Error("simulation failed on remote worker", "error", report.Error)
+	s.simulationService.NotifySimulationFinished(context.WithoutCancel(ctx), simID)
+	return false, nil
+}
+
+// ownedSimulation returns a simulation the worker is running. It fails with
+// ErrLeaseLost when the simulation was reaped, cancelled or finished.
+func (s *LeaseService) ownedSimulation(ctx context.Context, workerID string, simID int64) (simulations.Simulation, error) {
+	sim, err := s.simulationsQueries.GetSimulation(ctx, simID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sim, ErrLeaseLost
+	}
+	if err != nil {
+		return sim, fmt.Errorf("get simulation: %w", err)
+	}
+	if sim.Status != "running" || sim.WorkerID.String != workerID {
+		return sim, ErrLeaseLost
+	}
+	return sim, nil
+}
+
+func (s *LeaseService) saveLog(ctx context.Context, simID int64, report LeaseReport) {
+	if len(report.Log) > 0 {
+		s.simulationService.saveSimulationLogBlob(context.WithoutCancel(ctx), simID, report.Log)
+	}
+}
+
+// ReapExpiredSimulations fails expired simulations that used all their
+// attempts and returns the others to pending. Remote workers call it in place
+// of the lease recovery local workers run against the database. The attempts
+// are those of the retry policy of the simulation service, so that no remote
+// worker decides them for the whole fleet.
+func (s *LeaseService) ReapExpiredSimulations(ctx context.Context) (*ReapResult, error) {
+	now := sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true}
+	maxAttempts := max(s.simulationService.retryPolicy.MaxAttempts, 1)
+
+	failed, err := s.simulationsQueries.FailExpiredSimulations(ctx, simulations.FailExpiredSimulationsParams{
+		Now:         now,
+		MaxAttempts: int64(maxAttempts),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fail expired simulations: %w", err)
+	}
+	for _, id := range failed {
+		s.simulationService.NotifySimulationFinished(ctx, id)
+	}
+
+	requeued, err := s.simulationsQueries.RequeueExpiredSimulations(ctx, simulations.RequeueExpiredSimulationsParams{
+		Now:         now,
+		MaxAttempts: int64(maxAttempts),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("requeue expired simulations: %w", err)
+	}
+
+	result := &ReapResult{Failed: failed, Requeued: requeued}
+	if result.Failed == nil {
+		result.Failed = []int64{}
+	}
+	if result.Requeued == nil {
+		result.Requeued = []int64{}
+	}
+	return result, nil
+}
+
+// ListDraws returns the draws of a contest range for remote workers, without
+// the raw import rows.
+func (s *LeaseService) ListDraws(ctx context.Context, fromContest, toContest int) ([]results.Draw, error) {
+	if fromContest > toContest {
+		return nil, fmt.Errorf("%w: from contest %d is after to contest %d", ErrInvalidLeaseRequest, fromContest, toContest)
+	}
+	draws, err := s.draws.ListDrawsByContestRange(ctx, results.ListDrawsByContestRangeParams{
+		FromContest: int64(fromContest),
+		ToContest:   int64(toContest),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list draws: %w", err)
+	}
+	for i := range draws {
+		draws[i].RawRow = sql.NullString{}
+	}
+	if draws == nil {
+		draws = []results.Draw{}
+	}
+	return draws, nil
+}
+
+// RunSimulationLease runs a leased simulation with engine, the way
+// ExecuteSimulation runs claimed ones, and returns the report for the API.
+// The report carries the run's log; a panic is reported with its stack.
+func RunSimulationLease(ctx context.Context, engine EngineServicer, lease SimulationLease, base *slog.Logger) (report LeaseReport) {
+	log := newJobLog(maxJobLogBytes)
+	logger := newJobLogger(base, log, lease.SimulationID)
+
+	defer func() {
+		if r := recover(); r != nil {
+			report = LeaseReport{Error: fmt.Sprintf("simulation panicked: %v", r), ErrorStack: string(debug.Stack())}
+			logger.Error("simulation panicked", "panic", r)
+		} else if report.Error != "" {
+			logger.Error("simulation failed", "error", report.Error)
+		}
+		if blob, err := log.Compress(); err == nil {
+			report.Log = blob
+		}
+	}()
+
+	logger.Info("simulation started",
+		"attempt", lease.Attempt,
+		"start_contest", lease.Config.StartContest,
+		"end_contest", lease.Config.EndContest,
+		"recipe", lease.RecipeName,
+	)
+	logger.Debug("execution limits", "limits", lease.Limits)
+
+	cfg := lease.Config
+	cfg.Limits = lease.Limits
+	result, err := runMonteCarlo(ctx, engine, cfg, lease.Seeds, lease.ConfidenceLevel)
+	if err != nil {
+		err = fmt.Errorf("run simulation: %w", err)
+		return LeaseReport{Error: err.Error(), Transient: IsTransientError(err)}
+	}
+
+	logger.Info("backtest finished",
+		"duration_ms", result.DurationMs,
+		"contests", result.Summary.TotalContests,
+		"quina_hits", result.Summary.QuinaHits,
+		"quadra_hits", result.Summary.QuadraHits,
+		"terno_hits", result.Summary.TernoHits,
+	)
+	return LeaseReport{Result: result}
+}
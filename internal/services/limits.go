@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+// ErrLimitExceeded is returned when a simulation needs more than its
+// execution limits allow. Such runs are failed, never retried.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ExecutionLimits bound the work of one simulation run. Zero fields are
+// unlimited.
+type ExecutionLimits struct {
+	Timeout                 time.Duration // Wall clock, all Monte Carlo seeds included
+	MaxContests             int
+	MaxPredictions          int // Per contest
+	MaxCandidateEvaluations int64
+}
+
+// RecipeLimits override the default execution limits of a simulation. Zero
+// fields keep the default.
+type RecipeLimits struct {
+	TimeoutSeconds          int   `json:"timeout_seconds,omitempty"`
+	MaxContests             int   `json:"max_contests,omitempty"`
+	MaxPredictions          int   `json:"max_predictions,omitempty"`
+	MaxCandidateEvaluations int64 `json:"max_candidate_evaluations,omitempty"`
+}
+
+// LimitPolicy holds the default execution limits and the ceiling recipes may
+// raise them to. A zero ceiling field leaves that limit uncapped.
+type LimitPolicy struct {
+	Defaults ExecutionLimits
+	Ceiling  ExecutionLimits
+}
+
+// Validate checks that no default is above its ceiling.
+func (p LimitPolicy) Validate() error {
+	for _, l := range p.limits(p.Defaults) {
+		if l.ceiling > 0 && l.value > l.ceiling {
+			return fmt.Errorf("default %s %d is above the ceiling of %d", l.name, l.value, l.ceiling)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the limits of a run of a recipe with the given overrides.
+// Limits without a default are set to the ceiling, and overrides above the
+// ceiling fail with ErrLimitExceeded.
+func (p LimitPolicy) Resolve(overrides *RecipeLimits) (ExecutionLimits, error) {
+	limits := p.Defaults
+	if overrides != nil {
+		if overrides.TimeoutSeconds > 0 {
+			limits.Timeout = time.Duration(overrides.TimeoutSeconds) * time.Second
+		}
+		if overrides.MaxContests > 0 {
+			limits.MaxContests = overrides.MaxContests
+		}
+		if overrides.MaxPredictions > 0 {
+			limits.MaxPredictions = overrides.MaxPredictions
+		}
+		if overrides.MaxCandidateEvaluations > 0 {
+			limits.MaxCandidateEvaluations = overrides.MaxCandidateEvaluations
+		}
+	}
+
+	for _, l := range p.limits(limits) {
+		if l.ceiling > 0 && l.value > l.ceiling {
+			return ExecutionLimits{}, fmt.Errorf("%w: %s %d is above the ceiling of %d", ErrLimitExceeded, l.name, l.value, l.ceiling)
+		}
+	}
+
+	if limits.Timeout == 0 {
+		limits.Timeout = p.Ceiling.Timeout
+	}
+	if limits.MaxContests == 0 {
+		limits.MaxContests = p.Ceiling.MaxContests
+	}
+	if limits.MaxPredictions == 0 {
+		limits.MaxPredictions = p.Ceiling.MaxPredictions
+	}
+	if limits.MaxCandidateEvaluations == 0 {
+		limits.MaxCandidateEvaluations = p.Ceiling.MaxCandidateEvaluations
+	}
+	return limits, nil
+}
+
+type namedLimit struct {
+	name           string
+	value, ceiling int64
+}
+
+// limits pairs each of the given limits with its ceiling, named as in
+// recipes.
+func (p LimitPolicy) limits(l ExecutionLimits) []namedLimit {
+	return []namedLimit{
+		{"timeout_seconds", int64(l.Timeout / time.Second), int64(p.Ceiling.Timeout / time.Second)},
+		{"max_contests", int64(l.MaxContests), int64(p.Ceiling.MaxContests)},
+		{"max_predictions", int64(l.MaxPredictions), int64(p.Ceiling.MaxPredictions)},
+		{"max_candidate_evaluations", l.MaxCandidateEvaluations, p.Ceiling.MaxCandidateEvaluations},
+	}
+}
+
+// CheckConfig fails with ErrLimitExceeded when the contest range or the
+// predictions per contest of cfg are above the limits.
+func (l ExecutionLimits) CheckConfig(cfg SimulationConfig) error {
+	if contests := cfg.EndContest - cfg.StartContest + 1; l.MaxContests > 0 && contests > l.MaxContests {
+		return fmt.Errorf("%w: %d contests, max_contests is %d", ErrLimitExceeded, contests, l.MaxContests)
+	}
+	predictions := cfg.SimPreds
+	if len(cfg.FixedTickets) > 0 {
+		predictions = len(cfg.FixedTickets)
+	}
+	if l.MaxPredictions > 0 && predictions > l.MaxPredictions {
+		return fmt.Errorf("%w: %d predictions per contest, max_predictions is %d", ErrLimitExceeded, predictions, l.MaxPredictions)
+	}
+	return nil
+}
+
+// start applies the limits to a run: the returned context ends with an
+// ErrLimitExceeded cause at the timeout, and cfg gets a candidate budget
+// unless it shares one already. The limits are cleared from the returned
+// config so that nested runs do not apply them again.
+func (l ExecutionLimits) start(ctx context.Context, cfg SimulationConfig) (context.Context, SimulationConfig, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if l.Timeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(ctx, l.Timeout, fmt.Errorf("%w: timeout of %s reached", ErrLimitExceeded, l.Timeout))
+	}
+	if cfg.Budget == nil && l.MaxCandidateEvaluations > 0 {
+		cfg.Budget = predictor.NewBudget(l.MaxCandidateEvaluations)
+	}
+	cfg.Limits = ExecutionLimits{}
+	return ctx, cfg, cancel
+}
+
+// limitError returns err as an ErrLimitExceeded error when it was caused by
+// a limit: the timeout of ctx or an exhausted candidate budget.
+func limitError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrLimitExceeded) {
+		return cause
+	}
+	if errors.Is(err, predictor.ErrBudgetExhausted) {
+		return fmt.Errorf("%w: %v", ErrLimitExceeded, err)
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/results/mock"
+)
+
+func TestLimitPolicy_Resolve(t *testing.T) {
+	policy := LimitPolicy{
+		Defaults: ExecutionLimits{Timeout: time.Minute, MaxContests: 100, MaxPredictions: 10},
+		Ceiling:  ExecutionLimits{Timeout: time.Hour, MaxPredictions: 50, MaxCandidateEvaluations: 1000},
+	}
+
+	limits, err := policy.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	want := ExecutionLimits{Timeout: time.Minute, MaxContests: 100, MaxPredictions: 10, MaxCandidateEvaluations: 1000}
+	if limits != want {
+		t.Errorf("expected defaults with the evaluation ceiling %+v, got %+v", want, limits)
+	}
+
+	limits, err = policy.Resolve(&RecipeLimits{TimeoutSeconds: 600, MaxContests: 5000, MaxPredictions: 50})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	want = ExecutionLimits{Timeout: 10 * time.Minute, MaxContests: 5000, MaxPredictions: 50, MaxCandidateEvaluations: 1000}
+	if limits != want {
+		t.Errorf("expected overrides %+v, got %+v", want, limits)
+	}
+
+	for name, overrides := range map[string]*RecipeLimits{
+		"timeout":     {TimeoutSeconds: 7200},
+		"predictions": {MaxPredictions: 51},
+		"evaluations": {MaxCandidateEvaluations: 1001},
+	} {
+		if _, err := policy.Resolve(overrides); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: expected ErrLimitExceeded above the ceiling, got %v", name, err)
+		}
+	}
+}
+
+func TestLimitPolicy_Validate(t *testing.T) {
+	if err := (LimitPolicy{}).Validate(); err != nil {
+		t.Errorf("expected unlimited policy to be valid, got %v", err)
+	}
+	policy := LimitPolicy{
+		Defaults: ExecutionLimits{MaxContests: 200},
+		Ceiling:  ExecutionLimits{MaxContests: 100},
+	}
+	if err := policy.Validate(); err == nil {
+		t.Error("expected default above the ceiling to be rejected")
+	}
+}
+
+func TestExecutionLimits_CheckConfig(t *testing.T) {
+	limits := ExecutionLimits{MaxContests: 10, MaxPredictions: 5}
+
+	if err := limits.CheckConfig(SimulationConfig{StartContest: 1, EndContest: 10, SimPreds: 5}); err != nil {
+		t.Errorf("expected config within limits, got %v", err)
+	}
+	if err := limits.CheckConfig(SimulationConfig{StartContest: 1, EndContest: 11, SimPreds: 5}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected too many contests to exceed the limit, got %v", err)
+	}
+	if err := limits.CheckConfig(SimulationConfig{StartContest: 1, EndContest: 2, SimPreds: 6}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected too many predictions to exceed the limit, got %v", err)
+	}
+	tickets := make([][]int, 6)
+	if err := limits.CheckConfig(SimulationConfig{StartContest: 1, EndContest: 2, SimPreds: 1, FixedTickets: tickets}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected too many fixed tickets to exceed the limit, got %v", err)
+	}
+}
+
+func TestEngineService_RunSimulation_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mock.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().ListDrawsByContestRange(gomock.Any(), gomock.Any()).Return(testDraws(1, 10), nil).AnyTimes()
+	eng := NewEngineService(mockQuerier, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	cfg := SimulationConfig{StartContest: 6, EndContest: 10, SimPrevMax: 5, SimPreds: 3, Seed: 1}
+
+	// 53 evaluations per contest fit two contests of five
+	budgetCfg := cfg
+	budgetCfg.Limits = ExecutionLimits{MaxCandidateEvaluations: 106}
+	if _, err := eng.RunSimulation(context.Background(), budgetCfg); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected the candidate budget to be exceeded, got %v", err)
+	}
+
+	timeoutCfg := cfg
+	timeoutCfg.Limits = ExecutionLimits{Timeout: time.Nanosecond}
+	if _, err := eng.RunSimulation(context.Background(), timeoutCfg); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected the timeout to be exceeded, got %v", err)
+	}
+
+	contestsCfg := cfg
+	contestsCfg.Limits = ExecutionLimits{MaxContests: 4}
+	if _, err := eng.RunSimulation(context.Background(), contestsCfg); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected max contests to be exceeded, got %v", err)
+	}
+
+	okCfg := cfg
+	okCfg.Limits = ExecutionLimits{Timeout: time.Minute, MaxContests: 5, MaxPredictions: 3, MaxCandidateEvaluations: 265}
+	if _, err := eng.RunSimulation(context.Background(), okCfg); err != nil {
+		t.Errorf("expected run within limits, got %v", err)
+	}
+}
+
+func TestRunMonteCarlo_SharesLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mock.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().ListDrawsByContestRange(gomock.Any(), gomock.Any()).Return(testDraws(1, 10), nil).AnyTimes()
+	eng := NewEngineService(mockQuerier, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// One seed needs 265 evaluations; the budget covers the job, not each seed
+	cfg := SimulationConfig{StartContest: 6, EndContest: 10, SimPrevMax: 5, SimPreds: 3, Seed: 1}
+	cfg.Limits = ExecutionLimits{MaxCandidateEvaluations: 600}
+	if _, err := runMonteCarlo(context.Background(), eng, cfg, 3, 0); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected three seeds to exceed the budget, got %v", err)
+	}
+	if _, err := runMonteCarlo(context.Background(), eng, cfg, 2, 0); err != nil {
+		t.Errorf("expected two seeds to fit the budget, got %v", err)
+	}
+}
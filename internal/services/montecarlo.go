@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// seedStride separates the seeds of consecutive Monte Carlo repetitions.
+	// The engine derives per-contest seeds as seed+contest, so the stride must
+	// be larger than any contest number to keep repetitions independent.
+	seedStride = 1_000_003
+
+	defaultConfidenceLevel   = 0.95
+	defaultBootstrapResample = 1000
+)
+
+// MetricDistribution summarises one metric across Monte Carlo repetitions.
+type MetricDistribution struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	CILow  float64 `json:"ci_low"`
+	CIHigh float64 `json:"ci_high"`
+}
+
+// summaryMetrics lists every metric reported in Summary.Distributions, with
+// its value in a summary and the JSON path of that value in summary_json.
+var summaryMetrics = []struct {
+	name  string
+	path  string
+	value func(Summary) float64
+}{
+	{"quina_rate", "$.HitRateQuina", func(s Summary) float64 { return s.HitRateQuina }},
+	{"quadra_rate", "$.HitRateQuadra", func(s Summary) float64 { return s.HitRateQuadra }},
+	{"terno_rate", "$.HitRateTerno", func(s Summary) float64 { return s.HitRateTerno }},
+	{"avg_hits", "$.AverageHits", func(s Summary) float64 { return s.AverageHits }},
+	{"total_quinaz", "$.QuinaHits", func(s Summary) float64 { return float64(s.QuinaHits) }},
+	{"total_quadras", "$.QuadraHits", func(s Summary) float64 { return float64(s.QuadraHits) }},
+	{"total_ternos", "$.TernoHits", func(s Summary) float64 { return float64(s.TernoHits) }},
+	{"hit_efficiency", "$.AverageHits", func(s Summary) float64 {
+		if s.TotalContests > 0 {
+			return s.AverageHits
+		}
+		return 0
+	}},
+}
+
+// summaryMetricNames lists the names of summaryMetrics in order.
+var summaryMetricNames = func() []string {
+	names := make([]string, len(summaryMetrics))
+	for i, m := range summaryMetrics {
+		names[i] = m.name
+	}
+	return names
+}()
+
+// summaryMetricValue extracts a named metric from a simulation summary.
+func summaryMetricValue(metric string, summary Summary) float64 {
+	for _, m := range summaryMetrics {
+		if m.name == metric {
+			return m.value(summary)
+		}
+	}
+	return 0
+}
+
+// summaryMetricLowerBound returns the lower confidence bound of a metric when
+// the summary comes from a multi-seed run, falling back to the point estimate.
+func summaryMetricLowerBound(metric string, summary Summary) float64 {
+	if dist, ok := summary.Distributions[metric]; ok {
+		return dist.CILow
+	}
+	return summaryMetricValue(metric, summary)
+}
+
+// runMonteCarlo repeats the backtest for the given number of seeds and
+// returns the first repetition's contest results together with a summary
+// carrying the distribution of every metric across repetitions.
+func runMonteCarlo(
+	ctx context.Context,
+	engine EngineServicer,
+	cfg SimulationConfig,
+	seeds int,
+	confidence float64,
+) (*SimulationResult, error) {
+	if seeds <= 1 {
+		return engine.RunSimulation(ctx, cfg)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		confidence = defaultConfidenceLevel
+	}
+
+	// Limits apply to the repetitions together
+	if err := cfg.Limits.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+	ctx, cfg, cancel := cfg.Limits.start(ctx, cfg)
+	defer cancel()
+
+	baseSeed := cfg.Seed
+	runs := make([]*SimulationResult, 0, seeds)
+	for i := 0; i < seeds; i++ {
+		runCfg := cfg
+		runCfg.Seed = baseSeed + int64(i)*seedStride
+
+		run, err := engine.RunSimulation(ctx, runCfg)
+		if err != nil {
+			return nil, fmt.Errorf("seed %d: %w", i, limitError(ctx, err))
+		}
+		runs = append(runs, run)
+	}
+
+	summaries := make([]Summary, len(runs))
+	var totalDuration int64
+	for i, run := range runs {
+		summaries[i] = run.Summary
+		totalDuration += run.DurationMs
+	}
+
+	result := *runs[0]
+	result.Config = cfg
+	result.DurationMs = totalDuration
+	result.Summary.Seeds = seeds
+	result.Summary.Distributions = summarizeDistributions(summaries, confidence, rand.New(rand.NewSource(baseSeed)))
+
+	return &result, nil
+}
+
+// summarizeDistributions computes mean, standard deviation and a percentile
+// bootstrap confidence interval of the mean for every summary metric.
+func summarizeDistributions(summaries []Summary, confidence float64, rng *rand.Rand) map[string]MetricDistribution {
+	dists := make(map[string]MetricDistribution, len(summaryMetricNames))
+	for _, metric := range summaryMetricNames {
+		values := make([]float64, len(summaries))
+		for i, summary := range summaries {
+			values[i] = summaryMetricValue(metric, summary)
+		}
+
+		mean, stddev := meanStdDev(values)
+		low, high := bootstrapCI(values, defaultBootstrapResample, confidence, rng)
+		dists[metric] = MetricDistribution{
+			Mean:   mean,
+			StdDev: stddev,
+			CILow:  low,
+			CIHigh: high,
+		}
+	}
+	return dists
+}
+
+// meanStdDev returns the mean and sample standard deviation of values.
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// bootstrapCI estimates a percentile bootstrap confidence interval for the
+// mean of values using the given number of resamples.
+func bootstrapCI(values []float64, resamples int, confidence float64, rng *rand.Rand) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	if len(values) == 1 {
+		return values[0], values[0]
+	}
+
+	means := make([]float64, resamples)
+	for r := 0; r < resamples; r++ {
+		sum := 0.0
+		for range values {
+			sum += values[rng.Intn(len(values))]
+		}
+		means[r] = sum / float64(len(values))
+	}
+	sort.Float64s(means)
+
+	alpha := (1 - confidence) / 2
+	lowIdx := int(math.Floor(alpha * float64(resamples-1)))
+	highIdx := int(math.Ceil((1 - alpha) * float64(resamples-1)))
+	return means[lowIdx], means[highIdx]
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRunMonteCarlo_SingleSeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := NewMockEngineServicer(ctrl)
+	cfg := SimulationConfig{StartContest: 1, EndContest: 10, Seed: 42}
+	mockEngine.EXPECT().RunSimulation(gomock.Any(), cfg).Return(&SimulationResult{Summary: Summary{HitRateQuina: 0.1}}, nil)
+
+	res, err := runMonteCarlo(context.Background(), mockEngine, cfg, 1, 0)
+	if err != nil {
+		t.Fatalf("runMonteCarlo failed: %v", err)
+	}
+	if res.Summary.Seeds != 0 || res.Summary.Distributions != nil {
+		t.Errorf("single seed run should not report distributions, got %+v", res.Summary)
+	}
+}
+
+func TestRunMonteCarlo_MultipleSeeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := NewMockEngineServicer(ctrl)
+	cfg := SimulationConfig{StartContest: 1, EndContest: 10, Seed: 7}
+
+	var seen []int64
+	rates := []float64{0.1, 0.2, 0.3}
+	mockEngine.EXPECT().RunSimulation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, c SimulationConfig) (*SimulationResult, error) {
+			i := len(seen)
+			seen = append(seen, c.Seed)
+			return &SimulationResult{
+				DurationMs: 10,
+				Summary:    Summary{TotalContests: 10, HitRateQuina: rates[i]},
+			}, nil
+		},
+	).Times(3)
+
+	res, err := runMonteCarlo(context.Background(), mockEngine, cfg, 3, 0.9)
+	if err != nil {
+		t.Fatalf("runMonteCarlo failed: %v", err)
+	}
+
+	if seen[0] != 7 || seen[1] != 7+seedStride || seen[2] != 7+2*seedStride {
+		t.Errorf("unexpected seeds: %v", seen)
+	}
+	if res.DurationMs != 30 {
+		t.Errorf("expected summed duration 30, got %d", res.DurationMs)
+	}
+	if res.Summary.Seeds != 3 {
+		t.Errorf("expected 3 seeds, got %d", res.Summary.Seeds)
+	}
+
+	dist, ok := res.Summary.Distributions["quina_rate"]
+	if !ok {
+		t.Fatal("missing quina_rate distribution")
+	}
+	if math.Abs(dist.Mean-0.2) > 1e-9 {
+		t.Errorf("expected mean 0.2, got %f", dist.Mean)
+	}
+	if math.Abs(dist.StdDev-0.1) > 1e-9 {
+		t.Errorf("expected std dev 0.1, got %f", dist.StdDev)
+	}
+	if dist.CILow > dist.Mean || dist.CIHigh < dist.Mean {
+		t.Errorf("confidence interval [%f, %f] does not contain mean %f", dist.CILow, dist.CIHigh, dist.Mean)
+	}
+	if got := summaryMetricLowerBound("quina_rate", res.Summary); got != dist.CILow {
+		t.Errorf("expected lower bound %f, got %f", dist.CILow, got)
+	}
+}
+
+func TestBootstrapCI(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	low, high := bootstrapCI([]float64{5}, 100, 0.95, rng)
+	if low != 5 || high != 5 {
+		t.Errorf("expected degenerate interval [5, 5], got [%f, %f]", low, high)
+	}
+
+	low, high = bootstrapCI([]float64{1, 2, 3, 4, 5}, 1000, 0.95, rng)
+	if low < 1 || high > 5 || low >= high {
+		t.Errorf("unexpected interval [%f, %f]", low, high)
+	}
+}
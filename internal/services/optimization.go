@@ -0,0 +1,687 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	"github.com/garnizeh/luckyfive/pkg/sweep"
+)
+
+// JobTypeOptimization searches a sweep's parameter space adaptively instead
+// of running every combination.
+const JobTypeOptimization = "optimization"
+
+// Optimization methods.
+const (
+	// OptimizationSuccessiveHalving runs every candidate on the most recent
+	// contests first and promotes the best 1/eta to ranges eta times longer
+	// until the survivors run on the whole range.
+	OptimizationSuccessiveHalving = "successive_halving"
+	// OptimizationBayesian runs a few random candidates on the whole range,
+	// then the candidate a Gaussian-process surrogate expects to improve the
+	// most on the best score so far, one at a time.
+	OptimizationBayesian = "bayesian"
+	// OptimizationGenetic evolves a population of parameter values on the
+	// whole range, breeding each generation from the fittest of the last.
+	OptimizationGenetic = "genetic"
+)
+
+const (
+	defaultOptimizationEta           = 3
+	defaultOptimizationInitialPoints = 5
+	defaultOptimizationPopulation    = 20
+	defaultOptimizationGenerations   = 10
+	defaultOptimizationMutationRate  = 0.1
+	defaultOptimizationElite         = 1
+	// defaultBatchPollInterval is how often a genetic search checks the
+	// simulations of its queued generation.
+	defaultBatchPollInterval = time.Second
+	// optimizationLengthScale is the RBF kernel length scale of the
+	// surrogate, in units of the normalised parameter ranges.
+	optimizationLengthScale = 0.3
+	optimizationNoise       = 1e-6
+	// optimizationExploration is how much expected improvement must beat the
+	// best score by, favouring unexplored regions.
+	optimizationExploration = 0.01
+)
+
+// Why an optimization stopped.
+const (
+	OptimizationStopCompleted = "completed" // The method ran to its end
+	OptimizationStopBudget    = "budget"    // The next run would exceed the budget
+)
+
+// OptimizationRequest is the payload of an optimization job. The candidates
+// of successive halving and Bayesian searches are the recipes SweepConfig
+// generates, so its sampling strategy bounds the search space; genetic
+// searches breed values anywhere in the parameter domains that satisfy the
+// constraints. Scores are the SweepService metric Metric, maximised.
+type OptimizationRequest struct {
+	Name         string            `json:"name"`
+	SweepConfig  sweep.SweepConfig `json:"sweep_config"`
+	Method       string            `json:"method"`
+	Metric       string            `json:"metric"`
+	StartContest int               `json:"start_contest"`
+	EndContest   int               `json:"end_contest"`
+	// Budget caps the contests simulated across all runs.
+	Budget int `json:"budget"`
+	// Successive halving: keep the best 1/Eta of each round (default 3),
+	// with rounds of at least MinContests contests (default 1).
+	Eta         int `json:"eta,omitempty"`
+	MinContests int `json:"min_contests,omitempty"`
+	// Bayesian: random candidates run before the surrogate proposes any
+	// (default 5), and a cap on the runs (default: until the budget).
+	InitialPoints  int `json:"initial_points,omitempty"`
+	MaxEvaluations int `json:"max_evaluations,omitempty"`
+	// Genetic: individuals per generation (default 20), generations bred
+	// after the random first one (default 10), probability of mutating each
+	// parameter of a child (default 0.1) and fittest individuals carried
+	// over unchanged (default 1).
+	Population   int     `json:"population,omitempty"`
+	Generations  int     `json:"generations,omitempty"`
+	MutationRate float64 `json:"mutation_rate,omitempty"`
+	Elite        int     `json:"elite,omitempty"`
+	// Seed draws the initial Bayesian candidates and, when the sampling
+	// has no seed, the sampled candidates.
+	Seed      int64  `json:"seed,omitempty"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// OptimizationTrial is a run of a candidate, in the order the search ran it.
+type OptimizationTrial struct {
+	Round        int            `json:"round"`
+	Candidate    int            `json:"candidate"` // Index among the generated recipes
+	SimulationID int64          `json:"simulation_id,omitempty"`
+	Params       map[string]any `json:"params"`
+	StartContest int            `json:"start_contest"`
+	EndContest   int            `json:"end_contest"`
+	Score        float64        `json:"score"`
+	BestScore    float64        `json:"best_score"` // Best score on this range so far
+	Error        string         `json:"error,omitempty"`
+	// Genetic lineage: how the candidate came about and the trials of its
+	// parents, or of the same candidate in the last generation for elites.
+	Origin  string `json:"origin,omitempty"`
+	Parents []int  `json:"parents,omitempty"`
+}
+
+// OptimizationResult is the result of an optimization job: the best trial
+// and the whole search trajectory.
+type OptimizationResult struct {
+	Method       string              `json:"method"`
+	Metric       string              `json:"metric"`
+	Candidates   int                 `json:"candidates"`
+	Budget       int                 `json:"budget"`
+	ContestsUsed int                 `json:"contests_used"`
+	Stopped      string              `json:"stopped"`
+	Best         *OptimizationTrial  `json:"best,omitempty"`
+	Trials       []OptimizationTrial `json:"trials"`
+}
+
+// OptimizationJobType runs adaptive parameter searches as analysis jobs. The
+// payload is an OptimizationRequest and the result its OptimizationResult.
+// Progress counts the contests simulated against the budget.
+//
+// Successive halving and Bayesian runs are executed by the job itself, one
+// after another, since each run decides the next one. Genetic generations are
+// queued as batches of simulations, which the job waits for; they are run by
+// the other worker slots and remote workers, so the job's worker needs more
+// than one slot when no remote worker is running.
+func (s *SweepService) OptimizationJobType() JobType {
+	return JobType{
+		Name: JobTypeOptimization,
+		Validate: func(payload json.RawMessage) error {
+			var req OptimizationRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return err
+			}
+			_, err := s.validateOptimizationRequest(req)
+			return err
+		},
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			var req OptimizationRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+			}
+			result, err := s.Optimize(ctx, req, progress)
+			if err != nil {
+				return nil, err
+			}
+			return &JobOutput{Result: result}, nil
+		},
+	}
+}
+
+// SetBatchPollInterval configures how often genetic searches check the
+// simulations of their queued generation.
+func (s *SweepService) SetBatchPollInterval(interval time.Duration) {
+	if interval > 0 {
+		s.batchPollInterval = interval
+	}
+}
+
+func (s *SweepService) validateOptimizationRequest(req OptimizationRequest) (OptimizationRequest, error) {
+	if req.Name == "" {
+		return req, errors.New("name is required")
+	}
+	if err := req.SweepConfig.Validate(); err != nil {
+		return req, fmt.Errorf("invalid sweep config: %w", err)
+	}
+	config, err := validateSweepConfig(req.SweepConfig)
+	if err != nil {
+		return req, fmt.Errorf("invalid sweep config: %w", err)
+	}
+	req.SweepConfig = config
+
+	switch req.Method {
+	case OptimizationSuccessiveHalving, OptimizationBayesian, OptimizationGenetic:
+	default:
+		return req, fmt.Errorf("unknown method: %s", req.Method)
+	}
+	if !s.isValidMetric(req.Metric) {
+		return req, fmt.Errorf("invalid metric: %s", req.Metric)
+	}
+	if req.StartContest <= 0 || req.EndContest < req.StartContest {
+		return req, fmt.Errorf("invalid contest range %d-%d", req.StartContest, req.EndContest)
+	}
+	if req.Budget <= 0 {
+		return req, errors.New("budget must be positive")
+	}
+
+	if req.Eta == 0 {
+		req.Eta = defaultOptimizationEta
+	}
+	if req.MinContests == 0 {
+		req.MinContests = 1
+	}
+	if req.InitialPoints == 0 {
+		req.InitialPoints = defaultOptimizationInitialPoints
+	}
+	if req.Population == 0 {
+		req.Population = defaultOptimizationPopulation
+	}
+	if req.Generations == 0 {
+		req.Generations = defaultOptimizationGenerations
+	}
+	if req.MutationRate == 0 {
+		req.MutationRate = defaultOptimizationMutationRate
+	}
+	if req.Elite == 0 {
+		req.Elite = defaultOptimizationElite
+	}
+	if req.Eta < 2 {
+		return req, errors.New("eta must be at least 2")
+	}
+	if req.MinContests < 0 || req.InitialPoints < 0 || req.MaxEvaluations < 0 || req.Generations < 0 {
+		return req, errors.New("min_contests, initial_points, max_evaluations and generations cannot be negative")
+	}
+	if req.Method == OptimizationGenetic {
+		if !req.SweepConfig.Sampling.IsGrid() {
+			return req, errors.New("sampling is not used by the genetic method")
+		}
+		if req.Population < 2 {
+			return req, errors.New("population must be at least 2")
+		}
+		if req.Elite < 0 || req.Elite >= req.Population {
+			return req, errors.New("elite must be between 0 and population - 1")
+		}
+		if req.MutationRate < 0 || req.MutationRate > 1 {
+			return req, errors.New("mutation_rate must be between 0 and 1")
+		}
+	}
+	return req, nil
+}
+
+// Optimize runs an adaptive search and returns its trajectory. Candidates
+// that fail to run are recorded and never promoted. progress is optional.
+func (s *SweepService) Optimize(ctx context.Context, req OptimizationRequest, progress JobProgressFunc) (*OptimizationResult, error) {
+	req, err := s.validateOptimizationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Genetic searches breed their candidates as they go
+	var candidates []sweep.GeneratedRecipe
+	if req.Method != OptimizationGenetic {
+		// Sampled candidates use the request seed unless the sampling has one
+		if !req.SweepConfig.Sampling.IsGrid() && req.SweepConfig.Sampling.Seed == 0 {
+			sampling := *req.SweepConfig.Sampling
+			sampling.Seed = req.Seed
+			req.SweepConfig.Sampling = &sampling
+		}
+		candidates, err = s.generator.Generate(req.SweepConfig)
+		if err != nil {
+			return nil, fmt.Errorf("generate candidates: %w", err)
+		}
+		if len(candidates) < 2 {
+			return nil, fmt.Errorf("the sweep config generates %d candidates, at least 2 are needed", len(candidates))
+		}
+	}
+
+	run := &optimizationRun{
+		service:    s,
+		req:        req,
+		candidates: candidates,
+		progress:   progress,
+		result: &OptimizationResult{
+			Method:  req.Method,
+			Metric:  req.Metric,
+			Budget:  req.Budget,
+			Stopped: OptimizationStopCompleted,
+			Trials:  []OptimizationTrial{},
+		},
+	}
+
+	switch req.Method {
+	case OptimizationSuccessiveHalving:
+		err = run.successiveHalving(ctx)
+	case OptimizationBayesian:
+		err = run.bayesian(ctx)
+	case OptimizationGenetic:
+		err = run.genetic(ctx)
+	}
+	if errors.Is(err, errBudgetExhausted) {
+		run.result.Stopped = OptimizationStopBudget
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	run.result.Candidates = len(run.candidates)
+	run.result.Best = run.best()
+	s.logger.Info("optimization finished", "name", req.Name, "method", req.Method, "trials", len(run.result.Trials), "contests", run.result.ContestsUsed, "stopped", run.result.Stopped)
+	return run.result, nil
+}
+
+// errBudgetExhausted stops a search whose next run would exceed the budget.
+var errBudgetExhausted = errors.New("optimization budget exhausted")
+
+// optimizationRun is the state of one search.
+type optimizationRun struct {
+	service    *SweepService
+	req        OptimizationRequest
+	candidates []sweep.GeneratedRecipe
+	progress   JobProgressFunc
+	result     *OptimizationResult
+}
+
+// evaluate runs the candidate of trial on its contest range and records
+// the trial. It fails with errBudgetExhausted, without running, when the range
+// does not fit in the remaining budget. A run that fails is recorded with its
+// error.
+func (r *optimizationRun) evaluate(ctx context.Context, trial OptimizationTrial) (OptimizationTrial, error) {
+	contests := trial.EndContest - trial.StartContest + 1
+	if r.result.ContestsUsed+contests > r.req.Budget {
+		return OptimizationTrial{}, errBudgetExhausted
+	}
+	if err := ctx.Err(); err != nil {
+		return OptimizationTrial{}, err
+	}
+
+	gr := r.candidates[trial.Candidate]
+	score, simID, err := r.run(ctx, gr, trial.Round, trial.StartContest, trial.EndContest)
+	if ctx.Err() != nil {
+		return OptimizationTrial{}, ctx.Err()
+	}
+	return r.finish(trial, simID, score, err), nil
+}
+
+// finish records the trial of a run that ended with score or err, counting
+// its contests against the budget.
+func (r *optimizationRun) finish(trial OptimizationTrial, simID int64, score float64, err error) OptimizationTrial {
+	trial.SimulationID = simID
+	trial.Params = r.candidates[trial.Candidate].Parameters
+	trial.Score = score
+	if err != nil {
+		trial.Error = err.Error()
+		r.service.logger.Warn("optimization run failed", "name", r.req.Name, "candidate", trial.Candidate, "error", err)
+	}
+	r.result.ContestsUsed += trial.EndContest - trial.StartContest + 1
+	return r.record(trial)
+}
+
+// record appends a trial to the trajectory with the best score on its range
+// so far, and reports progress.
+func (r *optimizationRun) record(trial OptimizationTrial) OptimizationTrial {
+	trial.BestScore = math.Inf(-1)
+	for _, t := range append(r.result.Trials, trial) {
+		if t.Error == "" && t.StartContest == trial.StartContest && t.EndContest == trial.EndContest {
+			trial.BestScore = math.Max(trial.BestScore, t.Score)
+		}
+	}
+	if math.IsInf(trial.BestScore, -1) {
+		trial.BestScore = 0
+	}
+
+	r.result.Trials = append(r.result.Trials, trial)
+	if r.progress != nil {
+		r.progress(int64(r.result.ContestsUsed), int64(r.req.Budget))
+	}
+	return trial
+}
+
+// run executes a candidate as a simulation and returns its score.
+func (r *optimizationRun) run(ctx context.Context, gr sweep.GeneratedRecipe, round, startContest, endContest int) (float64, int64, error) {
+	sim, err := r.service.simulationService.CreateSimulation(ctx, r.simulationRequest(gr, round, startContest, endContest, false))
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.score(sim)
+}
+
+// simulationRequest is the simulation of a candidate on a contest range.
+func (r *optimizationRun) simulationRequest(gr sweep.GeneratedRecipe, round, startContest, endContest int, async bool) CreateSimulationRequest {
+	return CreateSimulationRequest{
+		Mode:         "simple",
+		RecipeName:   fmt.Sprintf("%s_r%d_%s", r.req.Name, round, gr.ID),
+		Recipe:       r.service.convertToServiceRecipe(gr),
+		StartContest: startContest,
+		EndContest:   endContest,
+		Async:        async,
+		CreatedBy:    r.req.CreatedBy,
+		Tags:         []string{JobTypeOptimization},
+	}
+}
+
+// score returns the metric of a finished simulation.
+func (r *optimizationRun) score(sim *simulations.Simulation) (float64, int64, error) {
+	if sim.Status != "completed" || !sim.SummaryJson.Valid {
+		return 0, sim.ID, fmt.Errorf("simulation %d ended %s", sim.ID, sim.Status)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal([]byte(sim.SummaryJson.String), &summary); err != nil {
+		return 0, sim.ID, fmt.Errorf("unmarshal summary: %w", err)
+	}
+	return r.service.calculateMetrics(&summary)[r.req.Metric], sim.ID, nil
+}
+
+// successiveHalving runs rounds on growing suffixes of the contest range.
+// With n candidates there are floor(log_eta(n)) promotions; round i runs on
+// the last length/eta^(rounds-i) contests, and the last round on all of them.
+func (r *optimizationRun) successiveHalving(ctx context.Context) error {
+	eta := r.req.Eta
+	length := r.req.EndContest - r.req.StartContest + 1
+
+	rounds := 0
+	for n := len(r.candidates); n >= eta; n /= eta {
+		rounds++
+	}
+
+	survivors := make([]int, len(r.candidates))
+	for i := range survivors {
+		survivors[i] = i
+	}
+
+	for round := 0; round <= rounds && len(survivors) > 0; round++ {
+		contests := length / int(math.Pow(float64(eta), float64(rounds-round)))
+		contests = min(max(contests, r.req.MinContests, 1), length)
+		start := r.req.EndContest - contests + 1
+
+		type scored struct {
+			candidate int
+			score     float64
+		}
+		results := make([]scored, 0, len(survivors))
+		for _, candidate := range survivors {
+			trial, err := r.evaluate(ctx, OptimizationTrial{
+				Round:        round,
+				Candidate:    candidate,
+				StartContest: start,
+				EndContest:   r.req.EndContest,
+			})
+			if err != nil {
+				return err
+			}
+			if trial.Error == "" {
+				results = append(results, scored{candidate, trial.Score})
+			}
+		}
+
+		// Promote the best 1/eta, keeping candidate order among ties
+		sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+		keep := min(max(len(results)/eta, 1), len(results))
+		survivors = survivors[:0]
+		for _, res := range results[:keep] {
+			survivors = append(survivors, res.candidate)
+		}
+	}
+	return nil
+}
+
+// bayesian runs InitialPoints random candidates and then, until the budget or
+// MaxEvaluations runs out, the candidate with the highest expected
+// improvement under a Gaussian-process surrogate of the metric.
+func (r *optimizationRun) bayesian(ctx context.Context) error {
+	rng := rand.New(rand.NewSource(r.req.Seed))
+	points := r.normalizedCandidates()
+
+	maxEvaluations := len(r.candidates)
+	if r.req.MaxEvaluations > 0 {
+		maxEvaluations = min(maxEvaluations, r.req.MaxEvaluations)
+	}
+
+	evaluated := make(map[int]bool)
+	var observed [][]float64
+	var scores []float64
+
+	order := rng.Perm(len(r.candidates))
+	for evaluations := 0; evaluations < maxEvaluations; evaluations++ {
+		var candidate int
+		if evaluations < r.req.InitialPoints || len(scores) < 2 {
+			for _, c := range order {
+				if !evaluated[c] {
+					candidate = c
+					break
+				}
+			}
+		} else {
+			candidate = nextByExpectedImprovement(points, evaluated, observed, scores)
+		}
+
+		trial, err := r.evaluate(ctx, OptimizationTrial{
+			Candidate:    candidate,
+			StartContest: r.req.StartContest,
+			EndContest:   r.req.EndContest,
+		})
+		if err != nil {
+			return err
+		}
+		evaluated[candidate] = true
+		if trial.Error == "" {
+			observed = append(observed, points[candidate])
+			scores = append(scores, trial.Score)
+		}
+	}
+	return nil
+}
+
+// normalizedCandidates maps the swept parameters of every candidate to
+// [0, 1], so that the surrogate weighs all parameters alike.
+func (r *optimizationRun) normalizedCandidates() [][]float64 {
+	params := r.req.SweepConfig.Parameters
+	points := make([][]float64, len(r.candidates))
+	for i, gr := range r.candidates {
+		points[i] = make([]float64, len(params))
+		for j, param := range params {
+			points[i][j] = gr.Values[param.Name]
+		}
+	}
+
+	for j := range params {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, p := range points {
+			lo, hi = math.Min(lo, p[j]), math.Max(hi, p[j])
+		}
+		for _, p := range points {
+			if hi > lo {
+				p[j] = (p[j] - lo) / (hi - lo)
+			} else {
+				p[j] = 0
+			}
+		}
+	}
+	return points
+}
+
+// best returns the best successful trial on the longest range run.
+func (r *optimizationRun) best() *OptimizationTrial {
+	var best *OptimizationTrial
+	for i := range r.result.Trials {
+		t := &r.result.Trials[i]
+		if t.Error != "" {
+			continue
+		}
+		if best == nil {
+			best = t
+			continue
+		}
+		length, bestLength := t.EndContest-t.StartContest, best.EndContest-best.StartContest
+		if length > bestLength || (length == bestLength && t.Score > best.Score) {
+			best = t
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	out := *best
+	return &out
+}
+
+// nextByExpectedImprovement fits a Gaussian process with an RBF kernel to the
+// observed scores and returns the unevaluated candidate with the highest
+// expected improvement over the best score.
+func nextByExpectedImprovement(points [][]float64, evaluated map[int]bool, observed [][]float64, scores []float64) int {
+	// Standardise the scores so that the unit-variance prior fits them
+	mean, std := 0.0, 0.0
+	for _, y := range scores {
+		mean += y
+	}
+	mean /= float64(len(scores))
+	for _, y := range scores {
+		std += (y - mean) * (y - mean)
+	}
+	std = math.Sqrt(std / float64(len(scores)))
+	if std == 0 {
+		std = 1
+	}
+	y := make([]float64, len(scores))
+	best := math.Inf(-1)
+	for i, score := range scores {
+		y[i] = (score - mean) / std
+		best = math.Max(best, y[i])
+	}
+
+	n := len(observed)
+	k := make([][]float64, n)
+	for i := range k {
+		k[i] = make([]float64, n)
+		for j := range k[i] {
+			k[i][j] = rbfKernel(observed[i], observed[j])
+		}
+		k[i][i] += optimizationNoise
+	}
+	l := cholesky(k)
+	alpha := choleskySolve(l, y)
+
+	next, bestEI := -1, math.Inf(-1)
+	kStar := make([]float64, n)
+	for c, p := range points {
+		if evaluated[c] {
+			continue
+		}
+		for i, o := range observed {
+			kStar[i] = rbfKernel(p, o)
+		}
+		mu := 0.0
+		for i := range kStar {
+			mu += kStar[i] * alpha[i]
+		}
+		v := forwardSubstitute(l, kStar)
+		variance := 1 + optimizationNoise
+		for _, vi := range v {
+			variance -= vi * vi
+		}
+		sigma := math.Sqrt(math.Max(variance, 1e-12))
+
+		improvement := mu - best - optimizationExploration
+		z := improvement / sigma
+		ei := improvement*normalCDF(z) + sigma*normalPDF(z)
+		if ei > bestEI {
+			next, bestEI = c, ei
+		}
+	}
+	return next
+}
+
+func rbfKernel(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Exp(-d / (2 * optimizationLengthScale * optimizationLengthScale))
+}
+
+// cholesky returns the lower-triangular L with L*Lᵀ = a for a symmetric
+// positive-definite a.
+func cholesky(a [][]float64) [][]float64 {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				l[i][j] = math.Sqrt(math.Max(sum, 1e-12))
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l
+}
+
+// forwardSubstitute solves L*x = b.
+func forwardSubstitute(l [][]float64, b []float64) []float64 {
+	x := make([]float64, len(b))
+	for i := range b {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
+
+// choleskySolve solves L*Lᵀ*x = b.
+func choleskySolve(l [][]float64, b []float64) []float64 {
+	y := forwardSubstitute(l, b)
+	x := make([]float64, len(y))
+	for i := len(y) - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < len(y); k++ {
+			sum -= l[k][i] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+func normalPDF(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
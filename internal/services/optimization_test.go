@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	"github.com/garnizeh/luckyfive/pkg/sweep"
+)
+
+// newOptimizationRun returns a run over one candidate per score, swept on
+// alpha. A candidate's simulation scores its alpha as avg_hits, and fails
+// when the score is negative.
+func newOptimizationRun(t *testing.T, req OptimizationRequest, scores []float64) *optimizationRun {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	simSvc := NewMockSimulationServicer(ctrl)
+	var nextID int64
+	simSvc.EXPECT().CreateSimulation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req CreateSimulationRequest) (*simulations.Simulation, error) {
+			nextID++
+			alpha := req.Recipe.Parameters.Alpha
+			if alpha < 0 {
+				return &simulations.Simulation{ID: nextID, Status: "failed"}, nil
+			}
+			return &simulations.Simulation{
+				ID:          nextID,
+				Status:      "completed",
+				SummaryJson: sql.NullString{String: fmt.Sprintf(`{"AverageHits":%g}`, alpha), Valid: true},
+			}, nil
+		}).AnyTimes()
+
+	candidates := make([]sweep.GeneratedRecipe, len(scores))
+	for i, score := range scores {
+		candidates[i] = sweep.GeneratedRecipe{
+			ID:         fmt.Sprintf("c%d", i),
+			Parameters: map[string]any{"alpha": score},
+			Values:     map[string]float64{"alpha": score},
+		}
+	}
+
+	req.Name = "opt"
+	req.Metric = "avg_hits"
+	req.SweepConfig.Parameters = []sweep.ParameterSweep{{Name: "alpha", Type: "discrete"}}
+	if req.MinContests == 0 {
+		req.MinContests = 1
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return &optimizationRun{
+		service:    NewSweepService(nil, nil, simSvc, logger),
+		req:        req,
+		candidates: candidates,
+		result:     &OptimizationResult{Trials: []OptimizationTrial{}},
+	}
+}
+
+func TestOptimizationRun_SuccessiveHalving(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          OptimizationRequest
+		scores       []float64
+		wantErr      error
+		wantRounds   [][]int // Candidates run in each round, in order
+		wantContests []int   // Contests of each round
+	}{
+		{
+			name:         "promotes the best third",
+			req:          OptimizationRequest{Eta: 3, StartContest: 1, EndContest: 90, Budget: 1000},
+			scores:       []float64{0.5, 0.9, 0.1, 0.7, 0.3, 0.8, 0.2, 0.6, 0.4},
+			wantRounds:   [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8}, {1, 5, 3}, {1}},
+			wantContests: []int{10, 30, 90},
+		},
+		{
+			name:         "ties keep candidate order",
+			req:          OptimizationRequest{Eta: 2, StartContest: 1, EndContest: 40, Budget: 1000},
+			scores:       []float64{0.1, 0.5, 0.5, 0.5},
+			wantRounds:   [][]int{{0, 1, 2, 3}, {1, 2}, {1}},
+			wantContests: []int{10, 20, 40},
+		},
+		{
+			name:         "failed runs are never promoted",
+			req:          OptimizationRequest{Eta: 3, StartContest: 1, EndContest: 30, Budget: 1000},
+			scores:       []float64{-1, 0.2, 0.4},
+			wantRounds:   [][]int{{0, 1, 2}, {2}},
+			wantContests: []int{10, 30},
+		},
+		{
+			name:         "min contests floors the short rounds",
+			req:          OptimizationRequest{Eta: 3, StartContest: 1, EndContest: 30, Budget: 1000, MinContests: 15},
+			scores:       []float64{0.3, 0.2, 0.1},
+			wantRounds:   [][]int{{0, 1, 2}, {0}},
+			wantContests: []int{15, 30},
+		},
+		{
+			name:         "stops before the run that exceeds the budget",
+			req:          OptimizationRequest{Eta: 3, StartContest: 1, EndContest: 90, Budget: 150},
+			scores:       []float64{0.5, 0.9, 0.1, 0.7, 0.3, 0.8, 0.2, 0.6, 0.4},
+			wantErr:      errBudgetExhausted,
+			wantRounds:   [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8}, {1, 5}},
+			wantContests: []int{10, 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := newOptimizationRun(t, tt.req, tt.scores)
+
+			err := run.successiveHalving(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			rounds := make([][]int, len(tt.wantRounds))
+			used := 0
+			for _, trial := range run.result.Trials {
+				if trial.Round >= len(rounds) {
+					t.Fatalf("unexpected trial in round %d: %+v", trial.Round, trial)
+				}
+				rounds[trial.Round] = append(rounds[trial.Round], trial.Candidate)
+
+				contests := trial.EndContest - trial.StartContest + 1
+				if contests != tt.wantContests[trial.Round] || trial.EndContest != tt.req.EndContest {
+					t.Errorf("round %d ran contests %d-%d, want the last %d", trial.Round, trial.StartContest, trial.EndContest, tt.wantContests[trial.Round])
+				}
+				used += contests
+			}
+
+			if fmt.Sprint(rounds) != fmt.Sprint(tt.wantRounds) {
+				t.Errorf("expected rounds %v, got %v", tt.wantRounds, rounds)
+			}
+			if run.result.ContestsUsed != used || used > tt.req.Budget {
+				t.Errorf("expected %d contests used within budget %d, got %d", used, tt.req.Budget, run.result.ContestsUsed)
+			}
+		})
+	}
+}
+
+func TestOptimizationRun_EvaluateBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       int
+		used         int
+		startContest int
+		endContest   int
+		wantErr      error
+		wantUsed     int
+	}{
+		{name: "fits the budget exactly", budget: 10, used: 0, startContest: 1, endContest: 10, wantUsed: 10},
+		{name: "fits the remaining budget", budget: 10, used: 5, startContest: 6, endContest: 10, wantUsed: 10},
+		{name: "one contest over", budget: 10, used: 1, startContest: 1, endContest: 10, wantErr: errBudgetExhausted, wantUsed: 1},
+		{name: "budget spent", budget: 10, used: 10, startContest: 10, endContest: 10, wantErr: errBudgetExhausted, wantUsed: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := newOptimizationRun(t, OptimizationRequest{Budget: tt.budget, StartContest: 1, EndContest: 10}, []float64{0.5})
+			run.result.ContestsUsed = tt.used
+
+			_, err := run.evaluate(context.Background(), OptimizationTrial{
+				Candidate:    0,
+				StartContest: tt.startContest,
+				EndContest:   tt.endContest,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			wantTrials := 1
+			if tt.wantErr != nil {
+				wantTrials = 0
+			}
+			if len(run.result.Trials) != wantTrials {
+				t.Errorf("expected %d trials, got %d", wantTrials, len(run.result.Trials))
+			}
+			if run.result.ContestsUsed != tt.wantUsed {
+				t.Errorf("expected %d contests used, got %d", tt.wantUsed, run.result.ContestsUsed)
+			}
+		})
+	}
+}
+
+func TestNextByExpectedImprovement(t *testing.T) {
+	// Eleven candidates evenly spread over one normalised parameter
+	points := make([][]float64, 11)
+	for i := range points {
+		points[i] = []float64{float64(i) / 10}
+	}
+
+	tests := []struct {
+		name      string
+		evaluated []int
+		scores    []float64 // Of the evaluated candidates, in order
+		want      int
+	}{
+		{
+			name:      "next to the best score",
+			evaluated: []int{0, 5, 10},
+			scores:    []float64{0, 1, 0.5},
+			want:      6,
+		},
+		{
+			name:      "flat scores explore the farthest candidate",
+			evaluated: []int{0, 1},
+			scores:    []float64{0.3, 0.3},
+			want:      10,
+		},
+		{
+			name:      "only the unevaluated candidate",
+			evaluated: []int{0, 1, 2, 3, 4, 6, 7, 8, 9, 10},
+			scores:    []float64{0, 0.1, 0.2, 0.3, 0.4, 0.6, 0.7, 0.8, 0.9, 1},
+			want:      5,
+		},
+		{
+			name:      "none left",
+			evaluated: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			scores:    []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1},
+			want:      -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := make(map[int]bool)
+			var observed [][]float64
+			for _, c := range tt.evaluated {
+				evaluated[c] = true
+				observed = append(observed, points[c])
+			}
+
+			if got := nextByExpectedImprovement(points, evaluated, observed, tt.scores); got != tt.want {
+				t.Errorf("expected candidate %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOptimizationRun_Bayesian(t *testing.T) {
+	// Scores peak at alpha 0.7
+	scores := make([]float64, 11)
+	for i := range scores {
+		scores[i] = 1 - float64(max(i-7, 7-i))/10
+	}
+
+	tests := []struct {
+		name string
+		seed int64
+	}{
+		{name: "seed 1", seed: 1},
+		{name: "seed 7", seed: 7},
+		{name: "seed 42", seed: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := OptimizationRequest{
+				StartContest:   1,
+				EndContest:     10,
+				Budget:         1000,
+				InitialPoints:  3,
+				MaxEvaluations: 7,
+				Seed:           tt.seed,
+			}
+
+			trajectory := func() []int {
+				run := newOptimizationRun(t, req, scores)
+				if err := run.bayesian(context.Background()); err != nil {
+					t.Fatalf("bayesian failed: %v", err)
+				}
+				var candidates []int
+				for _, trial := range run.result.Trials {
+					candidates = append(candidates, trial.Candidate)
+				}
+				if best := run.best(); best == nil || best.Candidate != 7 {
+					t.Errorf("expected the peak candidate 7 to be best, got %+v", best)
+				}
+				return candidates
+			}
+
+			first := trajectory()
+			if len(first) != req.MaxEvaluations {
+				t.Fatalf("expected %d trials, got %v", req.MaxEvaluations, first)
+			}
+			seen := make(map[int]bool)
+			for _, c := range first {
+				if seen[c] {
+					t.Errorf("candidate %d run twice in %v", c, first)
+				}
+				seen[c] = true
+			}
+
+			// The same seed proposes the same points
+			if second := trajectory(); fmt.Sprint(second) != fmt.Sprint(first) {
+				t.Errorf("expected the same trajectory, got %v and %v", first, second)
+			}
+		})
+	}
+}
+
+func TestOptimizationRun_InterruptedBatchIsCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The simulations stay queued until the search is cancelled
+	simSvc := NewMockSimulationServicer(ctrl)
+	var nextID int64
+	simSvc.EXPECT().CreateSimulation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req CreateSimulationRequest) (*simulations.Simulation, error) {
+			if !req.Async {
+				t.Error("expected the batch to be queued")
+			}
+			nextID++
+			return &simulations.Simulation{ID: nextID, Status: "pending"}, nil
+		}).Times(3)
+	simSvc.EXPECT().GetSimulation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id int64) (*simulations.Simulation, error) {
+			cancel()
+			return &simulations.Simulation{ID: id, Status: "pending"}, nil
+		}).AnyTimes()
+	cancelled := make(map[int64]bool)
+	simSvc.EXPECT().CancelSimulation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id int64) error {
+			cancelled[id] = true
+			return nil
+		}).Times(3)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewSweepService(nil, nil, simSvc, logger)
+	service.SetBatchPollInterval(time.Millisecond)
+	run := &optimizationRun{
+		service: service,
+		req:     OptimizationRequest{Name: "opt", StartContest: 1, EndContest: 10, Budget: 1000},
+		candidates: []sweep.GeneratedRecipe{
+			{ID: "c0", Parameters: map[string]any{"alpha": 0.1}},
+			{ID: "c1", Parameters: map[string]any{"alpha": 0.2}},
+			{ID: "c2", Parameters: map[string]any{"alpha": 0.3}},
+		},
+		result: &OptimizationResult{Trials: []OptimizationTrial{}},
+	}
+
+	if _, err := run.runBatch(ctx, 0, []int{0, 1, 2}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(cancelled) != 3 {
+		t.Errorf("expected the 3 queued simulations cancelled, got %v", cancelled)
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/predictions"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+var (
+	// ErrNoDraws is returned when results.db holds no draws to predict from.
+	ErrNoDraws = errors.New("no draws available")
+	// ErrInvalidPrediction is returned for prediction requests without a
+	// recipe or whose config does not exist.
+	ErrInvalidPrediction = errors.New("invalid prediction request")
+)
+
+type PredictionServicer interface {
+	CreatePrediction(ctx context.Context, req CreatePredictionRequest) (*predictions.Prediction, error)
+	GetPrediction(ctx context.Context, id int64) (*predictions.Prediction, error)
+	ListPredictions(ctx context.Context, limit, offset int) ([]predictions.Prediction, error)
+	CheckContest(ctx context.Context, contest int) (int, error)
+}
+
+// PredictionService generates tickets for the next contest from the latest
+// imported draws and checks them once that contest is drawn.
+type PredictionService struct {
+	predictionsQueries predictions.Querier
+	resultsQueries     results.Querier
+	configService      ConfigServicer
+	scorer             predictor.Scorer
+	logger             *slog.Logger
+}
+
+func NewPredictionService(
+	predictionsQueries predictions.Querier,
+	resultsQueries results.Querier,
+	configService ConfigServicer,
+	logger *slog.Logger,
+) *PredictionService {
+	return &PredictionService{
+		predictionsQueries: predictionsQueries,
+		resultsQueries:     resultsQueries,
+		configService:      configService,
+		scorer:             predictor.NewScorer(),
+		logger:             logger,
+	}
+}
+
+type CreatePredictionRequest struct {
+	ConfigID  int64   // Saved config to use; ignored when Recipe is set
+	Recipe    *Recipe // Inline recipe
+	Seed      int64   // Optional; derived from the recipe hash when zero
+	CreatedBy string
+}
+
+// PredictionCheck holds the outcome of checking a prediction set against the
+// actual draw of its target contest.
+type PredictionCheck struct {
+	ActualNumbers []int       `json:"actual_numbers"`
+	BestHits      int         `json:"best_hits"`
+	Hits          []int       `json:"hits"`
+	Distribution  map[int]int `json:"hit_distribution"`
+}
+
+func (s *PredictionService) CreatePrediction(
+	ctx context.Context,
+	req CreatePredictionRequest,
+) (*predictions.Prediction, error) {
+	recipe, configID, err := s.resolveRecipe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipe.Parameters.SimPrevMax <= 0 {
+		return nil, fmt.Errorf("%w: sim_prev_max must be positive", ErrInvalidRecipe)
+	}
+	if recipe.Parameters.SimPreds <= 0 {
+		return nil, fmt.Errorf("%w: sim_preds must be positive", ErrInvalidRecipe)
+	}
+
+	recipeJSON, err := json.Marshal(recipe)
+	if err != nil {
+		return nil, fmt.Errorf("marshal recipe: %w", err)
+	}
+	hash := sha256.Sum256(recipeJSON)
+
+	// Find the latest imported contest
+	rng, err := s.resultsQueries.GetContestRange(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get contest range: %w", err)
+	}
+	latest, ok := rng.MaxContest.(int64)
+	if !ok || latest <= 0 {
+		return nil, ErrNoDraws
+	}
+	target := latest + 1
+
+	draws, err := s.resultsQueries.ListDrawsByContestRange(ctx, results.ListDrawsByContestRangeParams{
+		FromContest: latest - int64(recipe.Parameters.SimPrevMax) + 1,
+		ToContest:   latest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch draws: %w", err)
+	}
+
+	history := make([]predictor.Draw, len(draws))
+	for i, d := range draws {
+		history[i] = predictor.Draw{
+			Contest: int(d.Contest),
+			Numbers: []int{int(d.Bola1), int(d.Bola2), int(d.Bola3), int(d.Bola4), int(d.Bola5)},
+		}
+	}
+
+	// Same recipe and history yield the same tickets unless a seed is given
+	seed := req.Seed
+	if seed == 0 {
+		seed = int64(binary.BigEndian.Uint64(hash[:8]) >> 1)
+	}
+
+	pred := predictor.NewAdvancedPredictor(seed)
+	tickets, err := pred.GeneratePredictions(ctx, predictor.PredictionParams{
+		HistoricalDraws: history,
+		MaxHistory:      recipe.Parameters.SimPrevMax,
+		NumPredictions:  recipe.Parameters.SimPreds,
+		Weights: predictor.Weights{
+			Alpha: recipe.Parameters.Alpha,
+			Beta:  recipe.Parameters.Beta,
+			Gamma: recipe.Parameters.Gamma,
+			Delta: recipe.Parameters.Delta,
+		},
+		Seed: seed + target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate predictions: %w", err)
+	}
+
+	predsJSON, err := json.Marshal(tickets)
+	if err != nil {
+		return nil, fmt.Errorf("marshal predictions: %w", err)
+	}
+
+	p, err := s.predictionsQueries.CreatePrediction(ctx, predictions.CreatePredictionParams{
+		ConfigID:        sql.NullInt64{Int64: configID, Valid: configID != 0},
+		RecipeName:      sql.NullString{String: recipe.Name, Valid: recipe.Name != ""},
+		RecipeJson:      string(recipeJSON),
+		RecipeHash:      hex.EncodeToString(hash[:]),
+		TargetContest:   target,
+		BasedOnContest:  latest,
+		Seed:            seed,
+		PredictionsJson: string(predsJSON),
+		CreatedBy:       sql.NullString{String: req.CreatedBy, Valid: req.CreatedBy != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create prediction: %w", err)
+	}
+
+	if configID != 0 {
+		if err := s.configService.IncrementUsage(ctx, configID); err != nil {
+			s.logger.Warn("failed to increment config usage", "config_id", configID, "error", err)
+		}
+	}
+
+	s.logger.Info("prediction created", "id", p.ID, "target_contest", target, "tickets", len(tickets))
+	return &p, nil
+}
+
+// resolveRecipe returns the inline recipe or loads the saved config.
+func (s *PredictionService) resolveRecipe(ctx context.Context, req CreatePredictionRequest) (Recipe, int64, error) {
+	if req.Recipe != nil {
+		return *req.Recipe, 0, nil
+	}
+	if req.ConfigID <= 0 {
+		return Recipe{}, 0, fmt.Errorf("%w: either config_id or recipe is required", ErrInvalidPrediction)
+	}
+
+	cfg, err := s.configService.Get(ctx, req.ConfigID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Recipe{}, 0, fmt.Errorf("%w: config %d not found", ErrInvalidPrediction, req.ConfigID)
+	}
+	if err != nil {
+		return Recipe{}, 0, fmt.Errorf("get config: %w", err)
+	}
+
+	var recipe Recipe
+	if err := json.Unmarshal([]byte(cfg.RecipeJson), &recipe); err != nil {
+		return Recipe{}, 0, fmt.Errorf("unmarshal recipe: %w", err)
+	}
+	return recipe, cfg.ID, nil
+}
+
+func (s *PredictionService) GetPrediction(ctx context.Context, id int64) (*predictions.Prediction, error) {
+	p, err := s.predictionsQueries.GetPrediction(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get prediction: %w", err)
+	}
+	return &p, nil
+}
+
+func (s *PredictionService) ListPredictions(ctx context.Context, limit, offset int) ([]predictions.Prediction, error) {
+	return s.predictionsQueries.ListPredictions(ctx, predictions.ListPredictionsParams{
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+}
+
+// CheckContest scores every pending prediction targeting contest against its
+// draw and returns how many predictions were checked. A prediction that fails
+// does not stop the others; the failures are returned together.
+func (s *PredictionService) CheckContest(ctx context.Context, contest int) (int, error) {
+	pending, err := s.predictionsQueries.ListPendingPredictionsByContest(ctx, int64(contest))
+	if err != nil {
+		return 0, fmt.Errorf("list pending predictions: %w", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	draw, err := s.resultsQueries.GetDraw(ctx, int64(contest))
+	if err != nil {
+		return 0, fmt.Errorf("get draw: %w", err)
+	}
+	actual := []int{int(draw.Bola1), int(draw.Bola2), int(draw.Bola3), int(draw.Bola4), int(draw.Bola5)}
+
+	checked := 0
+	var errs []error
+	for _, p := range pending {
+		var tickets []predictor.Prediction
+		if err := json.Unmarshal([]byte(p.PredictionsJson), &tickets); err != nil {
+			s.logger.Error("failed to unmarshal predictions", "id", p.ID, "error", err)
+			continue
+		}
+
+		check := s.score(tickets, actual)
+		actualJSON, _ := json.Marshal(check.ActualNumbers)
+		hitsJSON, _ := json.Marshal(check.Hits)
+
+		err := s.predictionsQueries.MarkPredictionChecked(ctx, predictions.MarkPredictionCheckedParams{
+			ActualNumbers: sql.NullString{String: string(actualJSON), Valid: true},
+			BestHits:      sql.NullInt64{Int64: int64(check.BestHits), Valid: true},
+			HitsJson:      sql.NullString{String: string(hitsJSON), Valid: true},
+			CheckedAt:     sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+			ID:            p.ID,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mark prediction %d checked: %w", p.ID, err))
+			continue
+		}
+		checked++
+	}
+
+	s.logger.Info("predictions checked", "contest", contest, "count", checked, "failed", len(errs))
+	return checked, errors.Join(errs...)
+}
+
+// OnDrawsImported implements DrawObserver, checking predictions as soon as
+// their target contest is imported. Every contest is checked, even after one
+// fails.
+func (s *PredictionService) OnDrawsImported(ctx context.Context, contests []int) error {
+	var errs []error
+	for _, contest := range contests {
+		if _, err := s.CheckContest(ctx, contest); err != nil {
+			errs = append(errs, fmt.Errorf("check contest %d: %w", contest, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *PredictionService) score(tickets []predictor.Prediction, actual []int) PredictionCheck {
+	result := s.scorer.ScorePredictions(tickets, actual)
+
+	hits := make([]int, len(tickets))
+	for i, t := range tickets {
+		hits[i] = s.scorer.ScorePredictions([]predictor.Prediction{t}, actual).BestHits
+	}
+
+	return PredictionCheck{
+		ActualNumbers: actual,
+		BestHits:      result.BestHits,
+		Hits:          hits,
+		Distribution:  result.HitDistribution,
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/configs"
+	configsmock "github.com/garnizeh/luckyfive/internal/store/configs/mock"
+	"github.com/garnizeh/luckyfive/internal/store/predictions"
+	predictionsmock "github.com/garnizeh/luckyfive/internal/store/predictions/mock"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	resultsmock "github.com/garnizeh/luckyfive/internal/store/results/mock"
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+func testDraws(from, to int64) []results.Draw {
+	var draws []results.Draw
+	for c := from; c <= to; c++ {
+		n := c % 70
+		draws = append(draws, results.Draw{
+			Contest: c,
+			Bola1:   n + 1,
+			Bola2:   n + 2,
+			Bola3:   n + 3,
+			Bola4:   n + 4,
+			Bola5:   n + 5,
+		})
+	}
+	return draws
+}
+
+func TestPredictionService_CreatePrediction_InlineRecipe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPreds := predictionsmock.NewMockQuerier(ctrl)
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(mockPreds, mockResults, NewConfigService(nil, nil, createTestLogger()), createTestLogger())
+
+	mockResults.EXPECT().GetContestRange(gomock.Any()).Return(results.GetContestRangeRow{MinContest: int64(1), MaxContest: int64(100)}, nil)
+	mockResults.EXPECT().ListDrawsByContestRange(gomock.Any(), results.ListDrawsByContestRangeParams{FromContest: 91, ToContest: 100}).
+		Return(testDraws(91, 100), nil)
+
+	var stored predictions.CreatePredictionParams
+	mockPreds.EXPECT().CreatePrediction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg predictions.CreatePredictionParams) (predictions.Prediction, error) {
+			stored = arg
+			return predictions.Prediction{ID: 1, TargetContest: arg.TargetContest, PredictionsJson: arg.PredictionsJson}, nil
+		},
+	)
+
+	recipe := &Recipe{Version: "1.0", Name: "inline", Parameters: RecipeParameters{Alpha: 1, Beta: 1, Gamma: 1, Delta: 1, SimPrevMax: 10, SimPreds: 3}}
+	p, err := svc.CreatePrediction(context.Background(), CreatePredictionRequest{Recipe: recipe})
+	if err != nil {
+		t.Fatalf("CreatePrediction failed: %v", err)
+	}
+
+	if p.TargetContest != 101 || stored.BasedOnContest != 100 {
+		t.Errorf("expected target 101 based on 100, got %d based on %d", stored.TargetContest, stored.BasedOnContest)
+	}
+	if stored.ConfigID.Valid {
+		t.Error("inline recipe should not reference a config")
+	}
+	if len(stored.RecipeHash) != 64 {
+		t.Errorf("expected sha256 hex recipe hash, got %q", stored.RecipeHash)
+	}
+	if stored.Seed == 0 {
+		t.Error("expected seed derived from recipe hash")
+	}
+
+	var tickets []predictor.Prediction
+	if err := json.Unmarshal([]byte(stored.PredictionsJson), &tickets); err != nil {
+		t.Fatalf("unmarshal tickets: %v", err)
+	}
+	if len(tickets) != 3 {
+		t.Errorf("expected 3 tickets, got %d", len(tickets))
+	}
+}
+
+func TestPredictionService_CreatePrediction_FromConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPreds := predictionsmock.NewMockQuerier(ctrl)
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	mockConfigs := configsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(mockPreds, mockResults, NewConfigService(mockConfigs, nil, createTestLogger()), createTestLogger())
+
+	recipeJSON, _ := json.Marshal(Recipe{Version: "1.0", Name: "saved", Parameters: RecipeParameters{SimPrevMax: 5, SimPreds: 2}})
+	mockConfigs.EXPECT().GetConfig(gomock.Any(), int64(4)).Return(configs.Config{ID: 4, RecipeJson: string(recipeJSON)}, nil)
+	mockConfigs.EXPECT().IncrementConfigUsage(gomock.Any(), int64(4)).Return(nil)
+	mockResults.EXPECT().GetContestRange(gomock.Any()).Return(results.GetContestRangeRow{MinContest: int64(1), MaxContest: int64(50)}, nil)
+	mockResults.EXPECT().ListDrawsByContestRange(gomock.Any(), gomock.Any()).Return(testDraws(46, 50), nil)
+	mockPreds.EXPECT().CreatePrediction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg predictions.CreatePredictionParams) (predictions.Prediction, error) {
+			if !arg.ConfigID.Valid || arg.ConfigID.Int64 != 4 {
+				t.Errorf("expected config ID 4, got %+v", arg.ConfigID)
+			}
+			if arg.RecipeName.String != "saved" {
+				t.Errorf("expected recipe name saved, got %q", arg.RecipeName.String)
+			}
+			return predictions.Prediction{ID: 2}, nil
+		},
+	)
+
+	if _, err := svc.CreatePrediction(context.Background(), CreatePredictionRequest{ConfigID: 4}); err != nil {
+		t.Fatalf("CreatePrediction failed: %v", err)
+	}
+}
+
+func TestPredictionService_CreatePrediction_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfigs := configsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(nil, nil, NewConfigService(mockConfigs, nil, createTestLogger()), createTestLogger())
+
+	mockConfigs.EXPECT().GetConfig(gomock.Any(), int64(9)).Return(configs.Config{}, sql.ErrNoRows)
+
+	tests := []struct {
+		name    string
+		req     CreatePredictionRequest
+		wantErr error
+	}{
+		{"no source", CreatePredictionRequest{}, ErrInvalidPrediction},
+		{"unknown config", CreatePredictionRequest{ConfigID: 9}, ErrInvalidPrediction},
+		{"no predictions", CreatePredictionRequest{Recipe: &Recipe{Version: "1.0", Parameters: RecipeParameters{SimPrevMax: 5}}}, ErrInvalidRecipe},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreatePrediction(context.Background(), tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPredictionService_CreatePrediction_NoDraws(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(nil, mockResults, nil, createTestLogger())
+
+	mockResults.EXPECT().GetContestRange(gomock.Any()).Return(results.GetContestRangeRow{}, nil)
+
+	recipe := &Recipe{Version: "1.0", Parameters: RecipeParameters{SimPrevMax: 5, SimPreds: 2}}
+	_, err := svc.CreatePrediction(context.Background(), CreatePredictionRequest{Recipe: recipe})
+	if !errors.Is(err, ErrNoDraws) {
+		t.Errorf("expected ErrNoDraws, got %v", err)
+	}
+}
+
+func TestPredictionService_OnDrawsImported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPreds := predictionsmock.NewMockQuerier(ctrl)
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(mockPreds, mockResults, nil, createTestLogger())
+
+	tickets, _ := json.Marshal([]predictor.Prediction{{Numbers: []int{1, 2, 3, 4, 5}}, {Numbers: []int{1, 2, 30, 40, 50}}})
+	mockPreds.EXPECT().ListPendingPredictionsByContest(gomock.Any(), int64(101)).Return([]predictions.Prediction{
+		{ID: 9, TargetContest: 101, PredictionsJson: string(tickets)},
+	}, nil)
+	mockPreds.EXPECT().ListPendingPredictionsByContest(gomock.Any(), int64(102)).Return(nil, nil)
+	mockResults.EXPECT().GetDraw(gomock.Any(), int64(101)).Return(results.Draw{Contest: 101, Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 40, Bola5: 50}, nil)
+	mockPreds.EXPECT().MarkPredictionChecked(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg predictions.MarkPredictionCheckedParams) error {
+			if arg.ID != 9 {
+				t.Errorf("expected prediction 9, got %d", arg.ID)
+			}
+			if arg.BestHits != (sql.NullInt64{Int64: 4, Valid: true}) {
+				t.Errorf("expected best hits 4, got %+v", arg.BestHits)
+			}
+			if arg.HitsJson.String != "[3,4]" {
+				t.Errorf("expected hits [3,4], got %s", arg.HitsJson.String)
+			}
+			return nil
+		},
+	)
+
+	if err := svc.OnDrawsImported(context.Background(), []int{101, 102}); err != nil {
+		t.Fatalf("OnDrawsImported failed: %v", err)
+	}
+}
+
+func TestPredictionService_OnDrawsImported_ChecksEveryPrediction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPreds := predictionsmock.NewMockQuerier(ctrl)
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewPredictionService(mockPreds, mockResults, nil, createTestLogger())
+
+	tickets, _ := json.Marshal([]predictor.Prediction{{Numbers: []int{1, 2, 3, 4, 5}}})
+	drawErr := errors.New("database is locked")
+	markErr := errors.New("disk I/O error")
+	mockPreds.EXPECT().ListPendingPredictionsByContest(gomock.Any(), int64(101)).Return([]predictions.Prediction{
+		{ID: 8, TargetContest: 101, PredictionsJson: string(tickets)},
+	}, nil)
+	mockResults.EXPECT().GetDraw(gomock.Any(), int64(101)).Return(results.Draw{}, drawErr)
+	mockPreds.EXPECT().ListPendingPredictionsByContest(gomock.Any(), int64(102)).Return([]predictions.Prediction{
+		{ID: 9, TargetContest: 102, PredictionsJson: string(tickets)},
+		{ID: 10, TargetContest: 102, PredictionsJson: string(tickets)},
+	}, nil)
+	mockResults.EXPECT().GetDraw(gomock.Any(), int64(102)).Return(results.Draw{Contest: 102, Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 40, Bola5: 50}, nil)
+
+	var marked []int64
+	mockPreds.EXPECT().MarkPredictionChecked(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg predictions.MarkPredictionCheckedParams) error {
+			marked = append(marked, arg.ID)
+			if arg.ID == 9 {
+				return markErr
+			}
+			return nil
+		},
+	).Times(2)
+
+	err := svc.OnDrawsImported(context.Background(), []int{101, 102})
+	if !errors.Is(err, drawErr) || !errors.Is(err, markErr) {
+		t.Errorf("expected both failures, got %v", err)
+	}
+	if len(marked) != 2 || marked[1] != 10 {
+		t.Errorf("expected predictions 9 and 10 marked, got %v", marked)
+	}
+}
@@ -59,6 +59,12 @@ func (s *ResultsService) SetTempDir(dir string) {
 	s.tempDir = dir
 }
 
+// AddDrawObserver registers an observer notified whenever an import inserts
+// new draws.
+func (s *ResultsService) AddDrawObserver(o DrawObserver) {
+	s.importService.AddDrawObserver(o)
+}
+
 // ImportArtifact imports data from a previously uploaded artifact
 func (s *ResultsService) ImportArtifact(ctx context.Context, artifactID string, sheet string) (*ImportResult, error) {
 	startTime := time.Now()
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTransient marks errors that are expected to go away on their own,
+	// so the failed simulation is worth running again.
+	ErrTransient = errors.New("transient error")
+	// ErrRetryScheduled is returned by ExecuteSimulation when a failed run was
+	// returned to the queue for another attempt instead of being failed.
+	ErrRetryScheduled = errors.New("simulation retry scheduled")
+)
+
+// SQLite primary result codes of transient lock contention
+// (https://www.sqlite.org/rescode.html).
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// Outcomes recorded in the attempts history of a simulation.
+const (
+	attemptCompleted = "completed"
+	attemptFailed    = "failed"
+	attemptRetrying  = "retrying"
+)
+
+// RetryPolicy decides whether a failed simulation is queued again. Only
+// transient errors are retried, with an exponential backoff between attempts.
+// The zero value never retries.
+type RetryPolicy struct {
+	MaxAttempts    int           // Runs per simulation, including the first
+	InitialBackoff time.Duration // Delay after the first failed run
+	MaxBackoff     time.Duration // Upper bound of the delay, 0 for none
+}
+
+// ShouldRetry reports whether a run that failed with err on the given
+// attempt (starting at 1) gets another attempt.
+func (p RetryPolicy) ShouldRetry(attempt int, err error) bool {
+	return attempt < p.MaxAttempts && IsTransientError(err)
+}
+
+// Backoff returns the delay after the given failed attempt. It doubles with
+// every attempt up to MaxBackoff.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// IsTransientError classifies errors that may succeed when retried: errors
+// wrapping ErrTransient and SQLite busy or locked errors.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+
+	var coded interface{ Code() int }
+	if errors.As(err, &coded) {
+		// Extended result codes keep the primary code in the low byte
+		switch coded.Code() & 0xff {
+		case sqliteBusy, sqliteLocked:
+			return true
+		}
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "SQLITE_BUSY")
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	simulationsmock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
+)
+
+type codedError struct{ code int }
+
+func (e codedError) Error() string { return fmt.Sprintf("sqlite error %d", e.code) }
+func (e codedError) Code() int     { return e.code }
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("recipe is broken"), false},
+		{"marked", fmt.Errorf("fetch draws: %w", ErrTransient), true},
+		{"busy code", fmt.Errorf("commit: %w", codedError{code: sqliteBusy}), true},
+		{"extended busy code", codedError{code: sqliteBusy | 2<<8}, true},
+		{"locked code", codedError{code: sqliteLocked}, true},
+		{"constraint code", codedError{code: 19}, false},
+		{"locked message", errors.New("insert contest result: database is locked (5) (SQLITE_BUSY)"), true},
+		{"cancelled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: 5 * time.Second, MaxBackoff: 12 * time.Second}
+
+	for attempt, want := range map[int]time.Duration{1: 5 * time.Second, 2: 10 * time.Second, 3: 12 * time.Second, 40: 12 * time.Second} {
+		if got := policy.Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+
+	locked := errors.New("database is locked")
+	if !policy.ShouldRetry(2, locked) {
+		t.Error("expected a transient failure of attempt 2 to be retried")
+	}
+	if policy.ShouldRetry(3, locked) {
+		t.Error("expected the last attempt not to be retried")
+	}
+	if policy.ShouldRetry(1, errors.New("invalid recipe")) {
+		t.Error("expected permanent errors not to be retried")
+	}
+	if (RetryPolicy{}).ShouldRetry(1, locked) {
+		t.Error("expected the zero policy never to retry")
+	}
+}
+
+func newRetryTestService(ctrl *gomock.Controller, runErr error) (*SimulationService, *simulationsmock.MockQuerier) {
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	mockEngine := NewMockEngineServicer(ctrl)
+	service := NewSimulationService(mockQueries, nil, mockEngine, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour})
+
+	mockEngine.EXPECT().RunSimulation(gomock.Any(), gomock.Any()).Return(nil, runErr)
+	mockQueries.EXPECT().SaveSimulationLog(gomock.Any(), gomock.Any()).Return(nil)
+	return service, mockQueries
+}
+
+func runningSimulation(attempts int64) simulations.Simulation {
+	return simulations.Simulation{
+		ID:           1,
+		Status:       "running",
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{"sim_prev_max":10,"sim_preds":5}}`,
+		StartContest: 100,
+		EndContest:   110,
+		WorkerID:     sql.NullString{String: "worker-a", Valid: true},
+		Attempts:     attempts,
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_RetriesTransientFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockQueries := newRetryTestService(ctrl, fmt.Errorf("load draws: %w", ErrTransient))
+	mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(1)).Return(runningSimulation(2), nil)
+
+	before := time.Now().UTC()
+	var retry simulations.RetrySimulationParams
+	mockQueries.EXPECT().
+		RetrySimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.RetrySimulationParams) (int64, error) {
+			retry = arg
+			return 1, nil
+		})
+
+	var record simulations.RecordSimulationAttemptParams
+	mockQueries.EXPECT().
+		RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+			record = arg
+			return nil
+		})
+
+	err := service.ExecuteSimulation(context.Background(), 1)
+	if !errors.Is(err, ErrRetryScheduled) || !errors.Is(err, ErrTransient) {
+		t.Fatalf("expected scheduled retry of a transient error, got %v", err)
+	}
+
+	// The second failed attempt waits twice the initial backoff
+	retryAt, parseErr := time.Parse(time.RFC3339, retry.NextAttemptAt.String)
+	if parseErr != nil {
+		t.Fatalf("invalid next attempt time %q: %v", retry.NextAttemptAt.String, parseErr)
+	}
+	if wait := retryAt.Sub(before); wait < 2*time.Minute-time.Second || wait > 2*time.Minute+time.Second {
+		t.Errorf("expected retry in 2m, got %s", wait)
+	}
+	if retry.WorkerID.String != "worker-a" {
+		t.Errorf("expected retry guarded by worker-a, got %v", retry.WorkerID)
+	}
+	if record.Outcome != attemptRetrying || record.Attempt != 2 || record.Transient != 1 || record.RetryAt != retry.NextAttemptAt {
+		t.Errorf("unexpected attempt record: %+v", record)
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_FailsWithoutRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int64
+		runErr    error
+		transient int64
+	}{
+		{"permanent error", 1, errors.New("engine exploded"), 0},
+		{"attempts used up", 3, errors.New("database is locked"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mockQueries := newRetryTestService(ctrl, tt.runErr)
+			mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(1)).Return(runningSimulation(tt.attempts), nil)
+			mockQueries.EXPECT().FailSimulation(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+			var record simulations.RecordSimulationAttemptParams
+			mockQueries.EXPECT().
+				RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+					record = arg
+					return nil
+				})
+
+			err := service.ExecuteSimulation(context.Background(), 1)
+			if err == nil || errors.Is(err, ErrRetryScheduled) {
+				t.Fatalf("expected the simulation to fail, got %v", err)
+			}
+			if record.Outcome != attemptFailed || record.Attempt != tt.attempts || record.Transient != tt.transient || record.RetryAt.Valid {
+				t.Errorf("unexpected attempt record: %+v", record)
+			}
+		})
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_RetryOfCancelledSimulation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockQueries := newRetryTestService(ctrl, errors.New("database is locked"))
+	mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(1)).Return(runningSimulation(1), nil)
+
+	// The simulation was cancelled while running, so it is neither requeued
+	// nor failed
+	mockQueries.EXPECT().RetrySimulation(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	mockQueries.EXPECT().RecordSimulationAttempt(gomock.Any(), gomock.Any()).Return(nil)
+
+	if err := service.ExecuteSimulation(context.Background(), 1); err == nil || errors.Is(err, ErrRetryScheduled) {
+		t.Fatalf("expected the run to fail, got %v", err)
+	}
+}
@@ -4,18 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"runtime/debug"
 	"time"
 
 	"github.com/garnizeh/luckyfive/internal/store/simulations"
 	"github.com/garnizeh/luckyfive/pkg/predictor"
+	"github.com/garnizeh/luckyfive/pkg/recipe"
 )
 
+var (
+	// ErrInvalidRecipe is returned when a simulation recipe fails validation.
+	ErrInvalidRecipe = errors.New("invalid recipe")
+	// ErrSimulationLogNotFound is returned for simulations that have not
+	// finished a run since logs started being captured.
+	ErrSimulationLogNotFound = errors.New("simulation log not found")
+	// ErrLeaseLost is the cancellation cause used by workers that no longer
+	// own a simulation, e.g. because its lease expired or it was cancelled.
+	// The simulation is then left for its new owner instead of being failed.
+	ErrLeaseLost = errors.New("simulation lease lost")
+	// ErrWorkerShutdown is the cancellation cause used by workers that stop
+	// before the simulation finished. The worker returns it to the queue.
+	ErrWorkerShutdown = errors.New("worker shutting down")
+)
+
+// syncWorkerID owns the simulations executed synchronously by CreateSimulation.
+const syncWorkerID = "sync"
+
+// interruption returns the cancellation cause of a run stopped by its worker,
+// or nil. Interrupted simulations are left to the worker instead of failed.
+func interruption(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrLeaseLost) || errors.Is(cause, ErrWorkerShutdown) {
+		return cause
+	}
+	return nil
+}
+
 type SimulationService struct {
 	simulationsQueries simulations.Querier // Mockable
 	simulationsDB      *sql.DB             // For transactions
 	engineService      EngineServicer
+	retryPolicy        RetryPolicy
+	limitPolicy        LimitPolicy
+	notifier           JobNotifier // Optional, wakes in-process workers
+	observers          []SimulationObserver
 	logger             *slog.Logger
 }
 
@@ -25,7 +61,16 @@ type SimulationServicer interface {
 	ListSimulations(ctx context.Context, limit, offset int) ([]simulations.Simulation, error)
 	CancelSimulation(ctx context.Context, id int64) error
 	GetContestResults(ctx context.Context, simulationID int64, limit, offset int) ([]simulations.SimulationContestResult, error)
+	ListContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter) (*ContestResultPage, error)
+	ExportContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter, w io.Writer) error
 	ExecuteSimulation(ctx context.Context, simID int64) error
+	GetSimulationLog(ctx context.Context, id int64) (io.ReadCloser, error)
+	SearchSimulations(ctx context.Context, search SimulationSearch) (*SimulationSearchResult, error)
+	SetSimulationTags(ctx context.Context, id int64, tags []string) ([]string, error)
+	GetSimulationTags(ctx context.Context, id int64) ([]string, error)
+	UpdateQueuedSimulation(ctx context.Context, id int64, update QueueUpdate) (*simulations.Simulation, error)
+	ListSimulationAttempts(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error)
+	NotifySimulationFinished(ctx context.Context, simID int64)
 }
 
 func NewSimulationService(
@@ -42,6 +87,22 @@ func NewSimulationService(
 	}
 }
 
+// SetRetryPolicy makes transient failures of claimed simulations return them
+// to the queue instead of failing them. Without a policy nothing is retried.
+func (s *SimulationService) SetRetryPolicy(policy RetryPolicy) {
+	s.retryPolicy = policy
+}
+
+// SetLimitPolicy sets the execution limits of simulations created and run by
+// the service. Without a policy runs are unlimited.
+func (s *SimulationService) SetLimitPolicy(policy LimitPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	s.limitPolicy = policy
+	return nil
+}
+
 type CreateSimulationRequest struct {
 	Mode         string
 	RecipeName   string
@@ -50,12 +111,15 @@ type CreateSimulationRequest struct {
 	EndContest   int
 	Async        bool
 	CreatedBy    string
+	Tags         []string
+	Priority     int // Higher priorities are claimed first by workers
 }
 
 type Recipe struct {
 	Version    string           `json:"version"`
 	Name       string           `json:"name"`
 	Parameters RecipeParameters `json:"parameters"`
+	Limits     *RecipeLimits    `json:"limits,omitempty"` // Up to the admin ceiling
 }
 
 type RecipeParameters struct {
@@ -68,6 +132,49 @@ type RecipeParameters struct {
 	EnableEvolutionary bool    `json:"enableEvolutionary"`
 	Generations        int     `json:"generations"`
 	MutationRate       float64 `json:"mutationRate"`
+
+	// Seeds repeats the backtest across N seeds (Monte Carlo). Values <= 1
+	// run a single backtest seeded with the simulation ID.
+	Seeds           int     `json:"seeds,omitempty"`
+	ConfidenceLevel float64 `json:"confidence_level,omitempty"`
+
+	// FixedTickets replaces the predictor with the same tickets played on
+	// every contest of the range.
+	FixedTickets [][]int `json:"fixed_tickets,omitempty"`
+}
+
+// encodeRecipe marshals a recipe and checks it against the current schema.
+func encodeRecipe(r Recipe) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("marshal recipe: %w", err)
+	}
+	normalized, err := recipe.Normalize(data)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+// DecodeRecipe decodes a recipe sent by a client the way stored recipes are
+// decoded: keys its schema version does not define are rejected and older
+// versions are upgraded to the current one.
+func DecodeRecipe(data []byte) (Recipe, error) {
+	return decodeRecipe(string(data))
+}
+
+// decodeRecipe upgrades a stored recipe to the current schema version and
+// decodes it.
+func decodeRecipe(data string) (Recipe, error) {
+	var r Recipe
+	normalized, err := recipe.Normalize([]byte(data))
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(normalized, &r); err != nil {
+		return r, fmt.Errorf("unmarshal recipe: %w", err)
+	}
+	return r, nil
 }
 
 func (s *SimulationService) CreateSimulation(
@@ -76,30 +183,73 @@ func (s *SimulationService) CreateSimulation(
 ) (*simulations.Simulation, error) {
 	// Validate recipe
 	if err := s.validateRecipe(req.Recipe); err != nil {
-		return nil, fmt.Errorf("invalid recipe: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecipe, err)
+	}
+
+	// Marshal recipe to JSON in the current schema version
+	recipeJSON, err := encodeRecipe(req.Recipe)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecipe, err)
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTags, err)
 	}
 
-	// Marshal recipe to JSON
-	recipeJSON, err := json.Marshal(req.Recipe)
+	if err := validatePriority(req.Priority); err != nil {
+		return nil, err
+	}
+
+	// Reject runs that cannot fit in their limits before queueing them
+	limits, err := s.limitPolicy.Resolve(req.Recipe.Limits)
 	if err != nil {
-		return nil, fmt.Errorf("marshal recipe: %w", err)
+		return nil, err
+	}
+	if err := limits.CheckConfig(SimulationConfig{
+		StartContest: req.StartContest,
+		EndContest:   req.EndContest,
+		SimPreds:     req.Recipe.Parameters.SimPreds,
+		FixedTickets: req.Recipe.Parameters.FixedTickets,
+	}); err != nil {
+		return nil, err
 	}
 
 	// Create simulation record
 	sim, err := s.simulationsQueries.CreateSimulation(ctx, simulations.CreateSimulationParams{
 		RecipeName:   sql.NullString{String: req.RecipeName, Valid: req.RecipeName != ""},
-		RecipeJson:   string(recipeJSON),
+		RecipeJson:   recipeJSON,
 		Mode:         req.Mode,
 		StartContest: int64(req.StartContest),
 		EndContest:   int64(req.EndContest),
 		CreatedBy:    sql.NullString{String: req.CreatedBy, Valid: req.CreatedBy != ""},
+		Priority:     int64(req.Priority),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create simulation: %w", err)
 	}
 
+	for _, tag := range tags {
+		if err := s.simulationsQueries.AddSimulationTag(ctx, simulations.AddSimulationTagParams{SimulationID: sim.ID, Tag: tag}); err != nil {
+			return nil, fmt.Errorf("add tag: %w", err)
+		}
+	}
+
+	if req.Async {
+		s.notifyJobQueued()
+	}
+
 	// If sync mode, execute immediately
 	if !req.Async {
+		err = s.simulationsQueries.UpdateSimulationStatus(ctx, simulations.UpdateSimulationStatusParams{
+			Status:    "running",
+			StartedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+			WorkerID:  sql.NullString{String: syncWorkerID, Valid: true},
+			ID:        sim.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("start simulation: %w", err)
+		}
 		if err := s.ExecuteSimulation(ctx, sim.ID); err != nil {
 			return nil, fmt.Errorf("execute simulation: %w", err)
 		}
@@ -114,21 +264,152 @@ func (s *SimulationService) CreateSimulation(
 	return &sim, nil
 }
 
-func (s *SimulationService) ExecuteSimulation(ctx context.Context, simID int64) error {
-	// Get simulation
-	sim, err := s.simulationsQueries.GetSimulation(ctx, simID)
+// ExecuteSimulation runs a simulation and stores its results. Everything
+// logged during the run is kept, gzipped, in the simulation's log_blob; a
+// panic fails the simulation with its stack in error_stack. A simulation the
+// worker no longer owns, e.g. because it was cancelled, is left alone and the
+// returned error wraps ErrLeaseLost.
+func (s *SimulationService) ExecuteSimulation(ctx context.Context, simID int64) (err error) {
+	log := newJobLog(maxJobLogBytes)
+	logger := newJobLogger(s.logger, log, simID)
+	var sim simulations.Simulation
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("simulation panicked: %v", r)
+			logger.Error("simulation panicked", "panic", r)
+			errorMessage := sql.NullString{String: err.Error(), Valid: true}
+			n, failErr := s.simulationsQueries.FailSimulation(context.WithoutCancel(ctx), simulations.FailSimulationParams{
+				ID:           simID,
+				WorkerID:     sim.WorkerID,
+				FinishedAt:   sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+				ErrorMessage: errorMessage,
+				ErrorStack:   sql.NullString{String: string(debug.Stack()), Valid: true},
+			})
+			// Panics are never retried, so the run is recorded as failed
+			s.recordAttempt(ctx, simulations.RecordSimulationAttemptParams{
+				SimulationID: simID,
+				Attempt:      attemptNumber(sim),
+				WorkerID:     sim.WorkerID,
+				StartedAt:    sim.StartedAt,
+				FinishedAt:   time.Now().UTC().Format(time.RFC3339),
+				Outcome:      attemptFailed,
+				ErrorMessage: errorMessage,
+			}, logger)
+			if failErr == nil && n == 0 {
+				err = fmt.Errorf("%w: %w", ErrLeaseLost, err)
+			}
+		} else if err != nil && !errors.Is(err, ErrRetryScheduled) {
+			logger.Error("simulation failed", "error", err)
+		}
+		s.saveSimulationLog(context.WithoutCancel(ctx), simID, log)
+
+		// Retried simulations go on, interrupted ones are left to the worker
+		// and lost ones were finished by whoever took them over
+		if !errors.Is(err, ErrRetryScheduled) && !errors.Is(err, ErrLeaseLost) && interruption(ctx) == nil {
+			s.NotifySimulationFinished(context.WithoutCancel(ctx), simID)
+		}
+	}()
+
+	sim, err = s.simulationsQueries.GetSimulation(ctx, simID)
 	if err != nil {
 		return fmt.Errorf("get simulation: %w", err)
 	}
+	return s.executeSimulation(ctx, sim, logger)
+}
+
+// saveSimulationLog stores the compressed job log. Failures are only logged so
+// they never mask the simulation outcome.
+func (s *SimulationService) saveSimulationLog(ctx context.Context, simID int64, log *jobLog) {
+	blob, err := log.Compress()
+	if err != nil {
+		s.logger.Error("save simulation log failed", "simulation_id", simID, "error", err)
+		return
+	}
+	s.saveSimulationLogBlob(ctx, simID, blob)
+}
+
+// saveSimulationLogBlob stores a compressed job log, e.g. one sent by a
+// remote worker.
+func (s *SimulationService) saveSimulationLogBlob(ctx context.Context, simID int64, blob []byte) {
+	err := s.simulationsQueries.SaveSimulationLog(ctx, simulations.SaveSimulationLogParams{
+		LogBlob: blob,
+		ID:      simID,
+	})
+	if err != nil {
+		s.logger.Error("save simulation log failed", "simulation_id", simID, "error", err)
+	}
+}
+
+func (s *SimulationService) executeSimulation(ctx context.Context, sim simulations.Simulation, logger *slog.Logger) error {
+	simID := sim.ID
+
+	// Parse recipe, upgrading it to the current schema version
+	recipe, err := decodeRecipe(sim.RecipeJson)
+	if err != nil {
+		return fmt.Errorf("decode recipe: %w", err)
+	}
+
+	logger.Info("simulation started",
+		"mode", sim.Mode,
+		"start_contest", sim.StartContest,
+		"end_contest", sim.EndContest,
+		"recipe", recipe.Name,
+	)
+	logger.Debug("recipe parameters", "parameters", recipe.Parameters)
+	s.notifySimulationStarted(ctx, simID)
 
-	// Parse recipe
-	var recipe Recipe
-	if err := json.Unmarshal([]byte(sim.RecipeJson), &recipe); err != nil {
-		return fmt.Errorf("unmarshal recipe: %w", err)
+	limits, err := s.limitPolicy.Resolve(recipe.Limits)
+	if err != nil {
+		return s.failSimulation(ctx, sim, err, logger)
 	}
+	logger.Debug("execution limits", "limits", limits)
 
-	// Build engine config
-	engineCfg := SimulationConfig{
+	// Run simulation (repeated across seeds when requested)
+	engineCfg := engineConfig(sim, recipe, limits)
+	result, err := runMonteCarlo(ctx, s.engineService, engineCfg, recipe.Parameters.Seeds, recipe.Parameters.ConfidenceLevel)
+	if err != nil {
+		if cause := interruption(ctx); cause != nil {
+			return fmt.Errorf("run simulation: %w", cause)
+		}
+		return s.failSimulation(ctx, sim, fmt.Errorf("run simulation: %w", err), logger)
+	}
+
+	logger.Info("backtest finished",
+		"duration_ms", result.DurationMs,
+		"contests", result.Summary.TotalContests,
+		"quina_hits", result.Summary.QuinaHits,
+		"quadra_hits", result.Summary.QuadraHits,
+		"terno_hits", result.Summary.TernoHits,
+	)
+
+	if err := s.saveResults(ctx, sim, result); err != nil {
+		if cause := interruption(ctx); cause != nil {
+			return fmt.Errorf("save results: %w", cause)
+		}
+		if errors.Is(err, ErrLeaseLost) {
+			logger.Warn("simulation no longer owned, results discarded")
+			return err
+		}
+		return s.failSimulation(ctx, sim, err, logger)
+	}
+
+	s.recordAttempt(ctx, simulations.RecordSimulationAttemptParams{
+		SimulationID: simID,
+		Attempt:      attemptNumber(sim),
+		WorkerID:     sim.WorkerID,
+		StartedAt:    sim.StartedAt,
+		FinishedAt:   time.Now().UTC().Format(time.RFC3339),
+		Outcome:      attemptCompleted,
+	}, logger)
+
+	logger.Info("simulation completed", "contest_results", len(result.ContestResults))
+	return nil
+}
+
+// engineConfig builds the engine config of a run of sim.
+func engineConfig(sim simulations.Simulation, recipe Recipe, limits ExecutionLimits) SimulationConfig {
+	return SimulationConfig{
 		StartContest: int(sim.StartContest),
 		EndContest:   int(sim.EndContest),
 		SimPrevMax:   recipe.Parameters.SimPrevMax,
@@ -143,21 +424,16 @@ func (s *SimulationService) ExecuteSimulation(ctx context.Context, simID int64)
 		EnableEvolution: recipe.Parameters.EnableEvolutionary,
 		Generations:     recipe.Parameters.Generations,
 		MutationRate:    recipe.Parameters.MutationRate,
+		FixedTickets:    recipe.Parameters.FixedTickets,
+		Limits:          limits,
 	}
+}
 
-	// Run simulation
-	result, err := s.engineService.RunSimulation(ctx, engineCfg)
-	if err != nil {
-		// Mark as failed
-		s.simulationsQueries.FailSimulation(ctx, simulations.FailSimulationParams{
-			ID:           simID,
-			FinishedAt:   sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
-			ErrorMessage: sql.NullString{String: err.Error(), Valid: true},
-		})
-		return fmt.Errorf("run simulation: %w", err)
-	}
-
-	// Save results in transaction
+// saveResults stores the contest results and completes the simulation in one
+// transaction. Nothing is stored and ErrLeaseLost is returned when the
+// simulation is no longer running on the worker of sim.
+func (s *SimulationService) saveResults(ctx context.Context, sim simulations.Simulation, result *SimulationResult) error {
+	simID := sim.ID
 	tx, err := s.simulationsDB.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -190,8 +466,9 @@ func (s *SimulationService) ExecuteSimulation(ctx context.Context, simID int64)
 	summaryJSON, _ := json.Marshal(result.Summary)
 	outputJSON, _ := json.Marshal(result)
 
-	err = txQueries.CompleteSimulation(ctx, simulations.CompleteSimulationParams{
+	n, err := txQueries.CompleteSimulation(ctx, simulations.CompleteSimulationParams{
 		ID:            simID,
+		WorkerID:      sim.WorkerID,
 		FinishedAt:    sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
 		RunDurationMs: sql.NullInt64{Int64: result.DurationMs, Valid: true},
 		SummaryJson:   sql.NullString{String: string(summaryJSON), Valid: true},
@@ -201,8 +478,106 @@ func (s *SimulationService) ExecuteSimulation(ctx context.Context, simID int64)
 	if err != nil {
 		return fmt.Errorf("complete simulation: %w", err)
 	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// failSimulation handles a failed run. A transient failure of a claimed
+// simulation is returned to the queue while the retry policy allows it, and
+// the returned error then wraps ErrRetryScheduled. A simulation that is no
+// longer running on the worker of sim is left alone and the returned error
+// wraps ErrLeaseLost. Every run is recorded in the attempts history.
+func (s *SimulationService) failSimulation(ctx context.Context, sim simulations.Simulation, runErr error, logger *slog.Logger) error {
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now().UTC()
+	attempt := attemptNumber(sim)
+
+	record := simulations.RecordSimulationAttemptParams{
+		SimulationID: sim.ID,
+		Attempt:      attempt,
+		WorkerID:     sim.WorkerID,
+		StartedAt:    sim.StartedAt,
+		FinishedAt:   now.Format(time.RFC3339),
+		Outcome:      attemptFailed,
+		ErrorMessage: sql.NullString{String: runErr.Error(), Valid: true},
+	}
+	if IsTransientError(runErr) {
+		record.Transient = 1
+	}
+
+	if s.retryPolicy.ShouldRetry(int(attempt), runErr) {
+		retryAt := sql.NullString{String: now.Add(s.retryPolicy.Backoff(int(attempt))).Format(time.RFC3339), Valid: true}
+		n, err := s.simulationsQueries.RetrySimulation(ctx, simulations.RetrySimulationParams{
+			NextAttemptAt: retryAt,
+			ErrorMessage:  record.ErrorMessage,
+			ID:            sim.ID,
+			WorkerID:      sim.WorkerID,
+		})
+		switch {
+		case err != nil:
+			logger.Error("schedule retry failed", "error", err)
+		case n == 0:
+			// No longer owned, e.g. cancelled or reclaimed, so its status is left alone
+			s.recordAttempt(ctx, record, logger)
+			return fmt.Errorf("%w: %w", ErrLeaseLost, runErr)
+		default:
+			record.Outcome = attemptRetrying
+			record.RetryAt = retryAt
+			s.recordAttempt(ctx, record, logger)
+			logger.Warn("simulation retry scheduled", "attempt", attempt, "retry_at", retryAt.String, "error", runErr)
+			return fmt.Errorf("%w: attempt %d: %w", ErrRetryScheduled, attempt, runErr)
+		}
+	}
+
+	n, err := s.simulationsQueries.FailSimulation(ctx, simulations.FailSimulationParams{
+		ID:           sim.ID,
+		WorkerID:     sim.WorkerID,
+		FinishedAt:   sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+		ErrorMessage: record.ErrorMessage,
+	})
+	s.recordAttempt(ctx, record, logger)
+	if err != nil {
+		logger.Error("fail simulation failed", "error", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %w", ErrLeaseLost, runErr)
+	}
+	return runErr
+}
+
+// recordAttempt adds a run to the attempts history. Failures are only logged
+// so they never mask the simulation outcome.
+func (s *SimulationService) recordAttempt(ctx context.Context, record simulations.RecordSimulationAttemptParams, logger *slog.Logger) {
+	if err := s.simulationsQueries.RecordSimulationAttempt(context.WithoutCancel(ctx), record); err != nil {
+		logger.Error("record simulation attempt failed", "attempt", record.Attempt, "error", err)
+	}
+}
 
-	return tx.Commit()
+// attemptNumber returns the attempt a run belongs to. Simulations executed
+// synchronously are never claimed and only have a first attempt.
+func attemptNumber(sim simulations.Simulation) int64 {
+	return max(sim.Attempts, 1)
+}
+
+// ListSimulationAttempts returns the attempts history of a simulation, oldest
+// first.
+func (s *SimulationService) ListSimulationAttempts(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error) {
+	if _, err := s.simulationsQueries.GetSimulation(ctx, id); err != nil {
+		return nil, err
+	}
+	attempts, err := s.simulationsQueries.ListSimulationAttempts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list simulation attempts: %w", err)
+	}
+	if attempts == nil {
+		attempts = []simulations.SimulationAttempt{}
+	}
+	return attempts, nil
 }
 
 func (s *SimulationService) GetSimulation(ctx context.Context, id int64) (*simulations.Simulation, error) {
@@ -213,11 +588,33 @@ func (s *SimulationService) GetSimulation(ctx context.Context, id int64) (*simul
 	return &sim, nil
 }
 
+// GetSimulationLog returns the JSON lines logged by the last run of a
+// simulation.
+func (s *SimulationService) GetSimulationLog(ctx context.Context, id int64) (io.ReadCloser, error) {
+	sim, err := s.simulationsQueries.GetSimulation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sim.LogBlob) == 0 {
+		return nil, ErrSimulationLogNotFound
+	}
+	r, err := decompressJobLog(sim.LogBlob)
+	if err != nil {
+		return nil, fmt.Errorf("decompress log: %w", err)
+	}
+	return r, nil
+}
+
 func (s *SimulationService) CancelSimulation(ctx context.Context, id int64) error {
-	return s.simulationsQueries.CancelSimulation(ctx, simulations.CancelSimulationParams{
+	err := s.simulationsQueries.CancelSimulation(ctx, simulations.CancelSimulationParams{
 		ID:         id,
 		FinishedAt: sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
 	})
+	if err != nil {
+		return err
+	}
+	s.NotifySimulationFinished(ctx, id)
+	return nil
 }
 
 func (s *SimulationService) GetContestResults(ctx context.Context, simulationID int64, limit, offset int) ([]simulations.SimulationContestResult, error) {
@@ -233,11 +630,28 @@ func (s *SimulationService) validateRecipe(recipe Recipe) error {
 	if recipe.Version == "" {
 		return fmt.Errorf("recipe version is required")
 	}
-	if recipe.Parameters.SimPrevMax <= 0 {
-		return fmt.Errorf("sim_prev_max must be positive")
+	if len(recipe.Parameters.FixedTickets) > 0 {
+		for i, ticket := range recipe.Parameters.FixedTickets {
+			if err := validateTicketNumbers(ticket); err != nil {
+				return fmt.Errorf("fixed ticket %d: %w", i+1, err)
+			}
+		}
+		if recipe.Parameters.Seeds > 1 {
+			return fmt.Errorf("seeds cannot be used with fixed tickets")
+		}
+	} else {
+		if recipe.Parameters.SimPrevMax <= 0 {
+			return fmt.Errorf("sim_prev_max must be positive")
+		}
+		if recipe.Parameters.SimPreds <= 0 {
+			return fmt.Errorf("sim_preds must be positive")
+		}
+	}
+	if recipe.Parameters.Seeds < 0 {
+		return fmt.Errorf("seeds must be non-negative")
 	}
-	if recipe.Parameters.SimPreds <= 0 {
-		return fmt.Errorf("sim_preds must be positive")
+	if recipe.Parameters.ConfidenceLevel < 0 || recipe.Parameters.ConfidenceLevel >= 1 {
+		return fmt.Errorf("confidence_level must be in [0, 1)")
 	}
 	// Add more validations as needed
 	return nil
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+)
+
+// SimulationObserver is notified about lifecycle changes of simulations once
+// they are stored. Observers run in the process executing the simulation.
+type SimulationObserver interface {
+	// OnSimulationStarted is called when a run of the simulation starts,
+	// once per attempt.
+	OnSimulationStarted(ctx context.Context, simID int64) error
+	// OnSimulationFinished is called when the simulation completed, failed
+	// or was cancelled.
+	OnSimulationFinished(ctx context.Context, simID int64) error
+}
+
+// AddSimulationObserver registers an observer notified about simulation
+// lifecycle changes.
+func (s *SimulationService) AddSimulationObserver(o SimulationObserver) {
+	s.observers = append(s.observers, o)
+}
+
+func (s *SimulationService) notifySimulationStarted(ctx context.Context, simID int64) {
+	for _, o := range s.observers {
+		if err := o.OnSimulationStarted(ctx, simID); err != nil {
+			s.logger.Error("Simulation observer failed", "simulation_id", simID, "event", "started", "error", err)
+		}
+	}
+}
+
+// NotifySimulationFinished tells observers that a simulation reached a final
+// status. It is called by the service itself and by workers failing
+// simulations whose lease expired.
+func (s *SimulationService) NotifySimulationFinished(ctx context.Context, simID int64) {
+	for _, o := range s.observers {
+		if err := o.OnSimulationFinished(ctx, simID); err != nil {
+			s.logger.Error("Simulation observer failed", "simulation_id", simID, "event", "finished", "error", err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package services
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	simulations "github.com/garnizeh/luckyfive/internal/store/simulations"
@@ -78,6 +79,20 @@ func (mr *MockSimulationServicerMockRecorder) ExecuteSimulation(ctx, simID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteSimulation", reflect.TypeOf((*MockSimulationServicer)(nil).ExecuteSimulation), ctx, simID)
 }
 
+// ExportContestResults mocks base method.
+func (m *MockSimulationServicer) ExportContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportContestResults", ctx, simulationID, filter, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportContestResults indicates an expected call of ExportContestResults.
+func (mr *MockSimulationServicerMockRecorder) ExportContestResults(ctx, simulationID, filter, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportContestResults", reflect.TypeOf((*MockSimulationServicer)(nil).ExportContestResults), ctx, simulationID, filter, w)
+}
+
 // GetContestResults mocks base method.
 func (m *MockSimulationServicer) GetContestResults(ctx context.Context, simulationID int64, limit, offset int) ([]simulations.SimulationContestResult, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +123,66 @@ func (mr *MockSimulationServicerMockRecorder) GetSimulation(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulation", reflect.TypeOf((*MockSimulationServicer)(nil).GetSimulation), ctx, id)
 }
 
+// GetSimulationLog mocks base method.
+func (m *MockSimulationServicer) GetSimulationLog(ctx context.Context, id int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimulationLog", ctx, id)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimulationLog indicates an expected call of GetSimulationLog.
+func (mr *MockSimulationServicerMockRecorder) GetSimulationLog(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulationLog", reflect.TypeOf((*MockSimulationServicer)(nil).GetSimulationLog), ctx, id)
+}
+
+// GetSimulationTags mocks base method.
+func (m *MockSimulationServicer) GetSimulationTags(ctx context.Context, id int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimulationTags", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimulationTags indicates an expected call of GetSimulationTags.
+func (mr *MockSimulationServicerMockRecorder) GetSimulationTags(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulationTags", reflect.TypeOf((*MockSimulationServicer)(nil).GetSimulationTags), ctx, id)
+}
+
+// ListContestResults mocks base method.
+func (m *MockSimulationServicer) ListContestResults(ctx context.Context, simulationID int64, filter ContestResultFilter) (*ContestResultPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContestResults", ctx, simulationID, filter)
+	ret0, _ := ret[0].(*ContestResultPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContestResults indicates an expected call of ListContestResults.
+func (mr *MockSimulationServicerMockRecorder) ListContestResults(ctx, simulationID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContestResults", reflect.TypeOf((*MockSimulationServicer)(nil).ListContestResults), ctx, simulationID, filter)
+}
+
+// ListSimulationAttempts mocks base method.
+func (m *MockSimulationServicer) ListSimulationAttempts(ctx context.Context, id int64) ([]simulations.SimulationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSimulationAttempts", ctx, id)
+	ret0, _ := ret[0].([]simulations.SimulationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSimulationAttempts indicates an expected call of ListSimulationAttempts.
+func (mr *MockSimulationServicerMockRecorder) ListSimulationAttempts(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSimulationAttempts", reflect.TypeOf((*MockSimulationServicer)(nil).ListSimulationAttempts), ctx, id)
+}
+
 // ListSimulations mocks base method.
 func (m *MockSimulationServicer) ListSimulations(ctx context.Context, limit, offset int) ([]simulations.Simulation, error) {
 	m.ctrl.T.Helper()
@@ -122,3 +197,60 @@ func (mr *MockSimulationServicerMockRecorder) ListSimulations(ctx, limit, offset
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSimulations", reflect.TypeOf((*MockSimulationServicer)(nil).ListSimulations), ctx, limit, offset)
 }
+
+// NotifySimulationFinished mocks base method.
+func (m *MockSimulationServicer) NotifySimulationFinished(ctx context.Context, simID int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifySimulationFinished", ctx, simID)
+}
+
+// NotifySimulationFinished indicates an expected call of NotifySimulationFinished.
+func (mr *MockSimulationServicerMockRecorder) NotifySimulationFinished(ctx, simID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifySimulationFinished", reflect.TypeOf((*MockSimulationServicer)(nil).NotifySimulationFinished), ctx, simID)
+}
+
+// SearchSimulations mocks base method.
+func (m *MockSimulationServicer) SearchSimulations(ctx context.Context, search SimulationSearch) (*SimulationSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSimulations", ctx, search)
+	ret0, _ := ret[0].(*SimulationSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSimulations indicates an expected call of SearchSimulations.
+func (mr *MockSimulationServicerMockRecorder) SearchSimulations(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSimulations", reflect.TypeOf((*MockSimulationServicer)(nil).SearchSimulations), ctx, search)
+}
+
+// SetSimulationTags mocks base method.
+func (m *MockSimulationServicer) SetSimulationTags(ctx context.Context, id int64, tags []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSimulationTags", ctx, id, tags)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSimulationTags indicates an expected call of SetSimulationTags.
+func (mr *MockSimulationServicerMockRecorder) SetSimulationTags(ctx, id, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSimulationTags", reflect.TypeOf((*MockSimulationServicer)(nil).SetSimulationTags), ctx, id, tags)
+}
+
+// UpdateQueuedSimulation mocks base method.
+func (m *MockSimulationServicer) UpdateQueuedSimulation(ctx context.Context, id int64, update QueueUpdate) (*simulations.Simulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQueuedSimulation", ctx, id, update)
+	ret0, _ := ret[0].(*simulations.Simulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQueuedSimulation indicates an expected call of UpdateQueuedSimulation.
+func (mr *MockSimulationServicerMockRecorder) UpdateQueuedSimulation(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueuedSimulation", reflect.TypeOf((*MockSimulationServicer)(nil).UpdateQueuedSimulation), ctx, id, update)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+var (
+	// ErrInvalidPriority is returned for priorities outside the allowed range.
+	ErrInvalidPriority = errors.New("invalid priority")
+	// ErrInvalidQueueUpdate is returned when a queue update changes nothing.
+	ErrInvalidQueueUpdate = errors.New("invalid queue update")
+	// ErrSimulationNotQueued is returned when reprioritising or pausing a
+	// simulation that is no longer pending.
+	ErrSimulationNotQueued = errors.New("simulation is not queued")
+)
+
+// Workers claim higher priorities first. Sweeps queue their simulations below
+// one-off simulations unless they ask for a priority.
+const (
+	MinSimulationPriority     = -100
+	MaxSimulationPriority     = 100
+	DefaultSimulationPriority = 0
+	SweepSimulationPriority   = -10
+)
+
+// JobNotifier is told when simulations become claimable, so that a worker in
+// the same process can claim them without waiting for its next poll.
+// Notifications must not block.
+type JobNotifier interface {
+	NotifyJobQueued()
+}
+
+// SetJobNotifier registers the notifier told about queued simulations.
+// Without one, workers only find new simulations by polling.
+func (s *SimulationService) SetJobNotifier(notifier JobNotifier) {
+	s.notifier = notifier
+}
+
+func (s *SimulationService) notifyJobQueued() {
+	if s.notifier != nil {
+		s.notifier.NotifyJobQueued()
+	}
+}
+
+// QueueUpdate changes how a pending simulation is scheduled. Nil fields are
+// left unchanged.
+type QueueUpdate struct {
+	Priority *int  `json:"priority,omitempty"`
+	Paused   *bool `json:"paused,omitempty"`
+}
+
+func validatePriority(priority int) error {
+	if priority < MinSimulationPriority || priority > MaxSimulationPriority {
+		return fmt.Errorf("%w: %d is outside %d..%d", ErrInvalidPriority, priority, MinSimulationPriority, MaxSimulationPriority)
+	}
+	return nil
+}
+
+// UpdateQueuedSimulation reprioritises, pauses or resumes a pending
+// simulation. Paused simulations stay pending but are skipped by workers.
+func (s *SimulationService) UpdateQueuedSimulation(ctx context.Context, id int64, update QueueUpdate) (*simulations.Simulation, error) {
+	if update.Priority == nil && update.Paused == nil {
+		return nil, fmt.Errorf("%w: priority or paused is required", ErrInvalidQueueUpdate)
+	}
+
+	params := simulations.UpdateQueuedSimulationParams{ID: id}
+	if update.Priority != nil {
+		if err := validatePriority(*update.Priority); err != nil {
+			return nil, err
+		}
+		params.Priority = sql.NullInt64{Int64: int64(*update.Priority), Valid: true}
+	}
+	if update.Paused != nil {
+		params.Paused = sql.NullInt64{Valid: true}
+		if *update.Paused {
+			params.Paused.Int64 = 1
+		}
+	}
+
+	sim, err := s.simulationsQueries.UpdateQueuedSimulation(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Tell missing simulations apart from ones that already left the queue
+		current, getErr := s.simulationsQueries.GetSimulation(ctx, id)
+		if getErr != nil {
+			return nil, fmt.Errorf("get simulation: %w", getErr)
+		}
+		return nil, fmt.Errorf("%w: simulation %d is %s", ErrSimulationNotQueued, id, current.Status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update queued simulation: %w", err)
+	}
+
+	s.logger.Info("simulation queue updated", "simulation_id", id, "priority", sim.Priority, "paused", sim.Paused == 1)
+	if sim.Paused == 0 {
+		s.notifyJobQueued()
+	}
+	return &sim, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	simulationsmock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
+)
+
+func TestSimulationService_UpdateQueuedSimulation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	priority, paused := 25, true
+	mockQueries.EXPECT().
+		UpdateQueuedSimulation(gomock.Any(), simulations.UpdateQueuedSimulationParams{
+			Priority: sql.NullInt64{Int64: 25, Valid: true},
+			Paused:   sql.NullInt64{Int64: 1, Valid: true},
+			ID:       7,
+		}).
+		Return(simulations.Simulation{ID: 7, Status: "pending", Priority: 25, Paused: 1}, nil)
+
+	sim, err := service.UpdateQueuedSimulation(context.Background(), 7, QueueUpdate{Priority: &priority, Paused: &paused})
+	if err != nil {
+		t.Fatalf("UpdateQueuedSimulation failed: %v", err)
+	}
+	if sim.Priority != 25 || sim.Paused != 1 {
+		t.Errorf("unexpected simulation: %+v", sim)
+	}
+
+	// Resuming leaves the priority unchanged
+	resume := false
+	mockQueries.EXPECT().
+		UpdateQueuedSimulation(gomock.Any(), simulations.UpdateQueuedSimulationParams{
+			Paused: sql.NullInt64{Valid: true},
+			ID:     7,
+		}).
+		Return(simulations.Simulation{ID: 7, Status: "pending", Priority: 25}, nil)
+
+	if _, err := service.UpdateQueuedSimulation(context.Background(), 7, QueueUpdate{Paused: &resume}); err != nil {
+		t.Fatalf("UpdateQueuedSimulation failed: %v", err)
+	}
+}
+
+func TestSimulationService_UpdateQueuedSimulation_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewSimulationService(simulationsmock.NewMockQuerier(ctrl), nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tooHigh := MaxSimulationPriority + 1
+	tests := []struct {
+		name   string
+		update QueueUpdate
+		want   error
+	}{
+		{"empty", QueueUpdate{}, ErrInvalidQueueUpdate},
+		{"priority out of range", QueueUpdate{Priority: &tooHigh}, ErrInvalidPriority},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UpdateQueuedSimulation(context.Background(), 1, tt.update)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSimulationService_UpdateQueuedSimulation_NotQueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	priority := 5
+	mockQueries.EXPECT().UpdateQueuedSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).Times(2)
+	gomock.InOrder(
+		mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(3)).Return(simulations.Simulation{ID: 3, Status: "running"}, nil),
+		mockQueries.EXPECT().GetSimulation(gomock.Any(), int64(4)).Return(simulations.Simulation{}, sql.ErrNoRows),
+	)
+
+	_, err := service.UpdateQueuedSimulation(context.Background(), 3, QueueUpdate{Priority: &priority})
+	if !errors.Is(err, ErrSimulationNotQueued) {
+		t.Errorf("expected ErrSimulationNotQueued, got %v", err)
+	}
+
+	_, err = service.UpdateQueuedSimulation(context.Background(), 4, QueueUpdate{Priority: &priority})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestSimulationService_CreateSimulation_InvalidPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewSimulationService(simulationsmock.NewMockQuerier(ctrl), nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.CreateSimulation(context.Background(), CreateSimulationRequest{
+		Mode:         "simple",
+		Recipe:       Recipe{Version: "1.0", Name: "test", Parameters: RecipeParameters{Alpha: 0.1, Beta: 0.1, Gamma: 0.1, SimPrevMax: 10, SimPreds: 5}},
+		StartContest: 1,
+		EndContest:   10,
+		Async:        true,
+		Priority:     MinSimulationPriority - 1,
+	})
+	if !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("expected ErrInvalidPriority, got %v", err)
+	}
+}
+
+type countingNotifier struct {
+	notified int
+}
+
+func (n *countingNotifier) NotifyJobQueued() {
+	n.notified++
+}
+
+func TestSimulationService_NotifiesQueuedSimulations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	notifier := &countingNotifier{}
+	service.SetJobNotifier(notifier)
+
+	mockQueries.EXPECT().
+		CreateSimulation(gomock.Any(), gomock.Any()).
+		Return(simulations.Simulation{ID: 1, Status: "pending"}, nil)
+
+	_, err := service.CreateSimulation(context.Background(), CreateSimulationRequest{
+		Mode:         "simple",
+		Recipe:       Recipe{Version: "1.0", Name: "test", Parameters: RecipeParameters{Alpha: 0.1, Beta: 0.1, Gamma: 0.1, SimPrevMax: 10, SimPreds: 5}},
+		StartContest: 1,
+		EndContest:   10,
+		Async:        true,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+	if notifier.notified != 1 {
+		t.Errorf("expected 1 notification for a queued simulation, got %d", notifier.notified)
+	}
+
+	// Paused simulations cannot be claimed, resumed ones can
+	paused, resumed := true, false
+	gomock.InOrder(
+		mockQueries.EXPECT().
+			UpdateQueuedSimulation(gomock.Any(), gomock.Any()).
+			Return(simulations.Simulation{ID: 1, Status: "pending", Paused: 1}, nil),
+		mockQueries.EXPECT().
+			UpdateQueuedSimulation(gomock.Any(), gomock.Any()).
+			Return(simulations.Simulation{ID: 1, Status: "pending"}, nil),
+	)
+	if _, err := service.UpdateQueuedSimulation(context.Background(), 1, QueueUpdate{Paused: &paused}); err != nil {
+		t.Fatalf("UpdateQueuedSimulation failed: %v", err)
+	}
+	if notifier.notified != 1 {
+		t.Errorf("expected no notification when pausing, got %d", notifier.notified)
+	}
+	if _, err := service.UpdateQueuedSimulation(context.Background(), 1, QueueUpdate{Paused: &resumed}); err != nil {
+		t.Fatalf("UpdateQueuedSimulation failed: %v", err)
+	}
+	if notifier.notified != 2 {
+		t.Errorf("expected a notification when resuming, got %d", notifier.notified)
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+var (
+	// ErrInvalidSearch is returned when simulation search filters are invalid.
+	ErrInvalidSearch = errors.New("invalid simulation search")
+	// ErrInvalidTags is returned when simulation tags are invalid.
+	ErrInvalidTags = errors.New("invalid tags")
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+	maxTags            = 20
+	maxTagLength       = 64
+)
+
+// summaryMetricPaths maps the metric names used by leaderboards and
+// comparisons to their JSON path in summary_json.
+var summaryMetricPaths = func() map[string]string {
+	paths := make(map[string]string, len(summaryMetrics))
+	for _, m := range summaryMetrics {
+		paths[m.name] = m.path
+	}
+	return paths
+}()
+
+var simulationStatuses = []string{"pending", "running", "completed", "failed", "cancelled"}
+
+// metricFilter matches thresholds such as "quina_rate>0" or "avg_hits >= 1.5".
+var metricFilter = regexp.MustCompile(`^\s*(\w+)\s*(>=|<=|>|<|=)\s*(\S+)\s*$`)
+
+// SimulationSearch holds the filters of a simulation search. Zero values are
+// ignored.
+type SimulationSearch struct {
+	Status      string
+	Mode        string
+	RecipeName  string // Prefix
+	CreatedBy   string
+	ContestFrom int // Simulations whose contest range overlaps [ContestFrom, ContestTo]
+	ContestTo   int
+	CreatedFrom string // YYYY-MM-DD or RFC3339, inclusive
+	CreatedTo   string // YYYY-MM-DD (inclusive) or RFC3339 (exclusive)
+	Metric      string // Threshold on a summary metric, e.g. "quina_rate>0"
+	Tags        []string
+	SortBy      string // Summary metric; defaults to newest first
+	SortOrder   string // "asc" or "desc" (default)
+	Limit       int
+	Offset      int
+}
+
+type SimulationSearchResult struct {
+	Simulations []simulations.Simulation `json:"simulations"`
+	Total       int64                    `json:"total"`
+	Limit       int                      `json:"limit"`
+	Offset      int                      `json:"offset"`
+}
+
+// SearchSimulations filters, sorts and paginates simulations in the database
+// and returns the total number of matches.
+func (s *SimulationService) SearchSimulations(ctx context.Context, search SimulationSearch) (*SimulationSearchResult, error) {
+	filter, err := buildSearchFilter(search)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+	}
+
+	limit, offset := search.Limit, search.Offset
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrInvalidSearch, maxSearchLimit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidSearch)
+	}
+
+	params := simulations.SearchSimulationsParams{
+		Status:       filter.Status,
+		Mode:         filter.Mode,
+		RecipePrefix: filter.RecipePrefix,
+		CreatedBy:    filter.CreatedBy,
+		ContestFrom:  filter.ContestFrom,
+		ContestTo:    filter.ContestTo,
+		CreatedFrom:  filter.CreatedFrom,
+		CreatedTo:    filter.CreatedTo,
+		MetricPath:   filter.MetricPath,
+		MetricOp:     filter.MetricOp,
+		MetricValue:  filter.MetricValue,
+		Tags:         filter.Tags,
+		SortDesc:     search.SortOrder != "asc",
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+	}
+	if search.SortBy != "" {
+		path, ok := summaryMetricPaths[search.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort metric %q", ErrInvalidSearch, search.SortBy)
+		}
+		params.SortPath = sql.NullString{String: path, Valid: true}
+	}
+	if search.SortOrder != "" && search.SortOrder != "asc" && search.SortOrder != "desc" {
+		return nil, fmt.Errorf("%w: sort order must be asc or desc", ErrInvalidSearch)
+	}
+
+	sims, err := s.simulationsQueries.SearchSimulations(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("search simulations: %w", err)
+	}
+	total, err := s.simulationsQueries.CountSearchSimulations(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("count simulations: %w", err)
+	}
+
+	if sims == nil {
+		sims = []simulations.Simulation{}
+	}
+	return &SimulationSearchResult{
+		Simulations: sims,
+		Total:       total,
+		Limit:       limit,
+		Offset:      offset,
+	}, nil
+}
+
+// buildSearchFilter converts search filters into query parameters.
+func buildSearchFilter(search SimulationSearch) (simulations.CountSearchSimulationsParams, error) {
+	var f simulations.CountSearchSimulationsParams
+
+	if search.Status != "" {
+		if !slices.Contains(simulationStatuses, search.Status) {
+			return f, fmt.Errorf("unknown status %q", search.Status)
+		}
+		f.Status = sql.NullString{String: search.Status, Valid: true}
+	}
+	if search.Mode != "" {
+		if search.Mode != "simple" && search.Mode != "advanced" {
+			return f, fmt.Errorf("unknown mode %q", search.Mode)
+		}
+		f.Mode = sql.NullString{String: search.Mode, Valid: true}
+	}
+	f.RecipePrefix = sql.NullString{String: search.RecipeName, Valid: search.RecipeName != ""}
+	f.CreatedBy = sql.NullString{String: search.CreatedBy, Valid: search.CreatedBy != ""}
+
+	if search.ContestFrom < 0 || search.ContestTo < 0 {
+		return f, fmt.Errorf("contests must not be negative")
+	}
+	if search.ContestFrom > 0 && search.ContestTo > 0 && search.ContestTo < search.ContestFrom {
+		return f, fmt.Errorf("contest_to must not be before contest_from")
+	}
+	f.ContestFrom = sql.NullInt64{Int64: int64(search.ContestFrom), Valid: search.ContestFrom > 0}
+	f.ContestTo = sql.NullInt64{Int64: int64(search.ContestTo), Valid: search.ContestTo > 0}
+
+	if search.CreatedFrom != "" {
+		from, _, err := parseSearchTime(search.CreatedFrom)
+		if err != nil {
+			return f, fmt.Errorf("created_from: %w", err)
+		}
+		f.CreatedFrom = sql.NullString{String: from, Valid: true}
+	}
+	if search.CreatedTo != "" {
+		to, dateOnly, err := parseSearchTime(search.CreatedTo)
+		if err != nil {
+			return f, fmt.Errorf("created_to: %w", err)
+		}
+		if dateOnly {
+			// Whole days are inclusive
+			t, _ := time.Parse(time.DateOnly, search.CreatedTo)
+			to = t.AddDate(0, 0, 1).Format(time.DateTime)
+		}
+		f.CreatedTo = sql.NullString{String: to, Valid: true}
+	}
+
+	if search.Metric != "" {
+		m := metricFilter.FindStringSubmatch(search.Metric)
+		if m == nil {
+			return f, fmt.Errorf("metric filter must look like quina_rate>0")
+		}
+		path, ok := summaryMetricPaths[m[1]]
+		if !ok {
+			return f, fmt.Errorf("unknown metric %q", m[1])
+		}
+		value, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return f, fmt.Errorf("metric threshold %q is not a number", m[3])
+		}
+		f.MetricPath = sql.NullString{String: path, Valid: true}
+		f.MetricOp = sql.NullString{String: m[2], Valid: true}
+		f.MetricValue = sql.NullFloat64{Float64: value, Valid: true}
+	}
+
+	if len(search.Tags) > 0 {
+		tags, err := normalizeTags(search.Tags)
+		if err != nil {
+			return f, err
+		}
+		data, _ := json.Marshal(tags)
+		f.Tags = sql.NullString{String: string(data), Valid: true}
+	}
+
+	return f, nil
+}
+
+// parseSearchTime converts a date or RFC3339 timestamp to the format SQLite
+// uses for created_at.
+func parseSearchTime(s string) (string, bool, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.Format(time.DateTime), true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return "", false, fmt.Errorf("must be YYYY-MM-DD or RFC3339")
+	}
+	return t.UTC().Format(time.DateTime), false, nil
+}
+
+// normalizeTags trims, de-duplicates and validates tags.
+func normalizeTags(tags []string) ([]string, error) {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	if len(out) > maxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	return out, nil
+}
+
+// SetSimulationTags replaces the tags of a simulation and returns them sorted.
+func (s *SimulationService) SetSimulationTags(ctx context.Context, id int64, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTags, err)
+	}
+
+	tx, err := s.simulationsDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	txQueries := simulations.New(tx)
+
+	if _, err := txQueries.GetSimulation(ctx, id); err != nil {
+		return nil, fmt.Errorf("get simulation: %w", err)
+	}
+	if err := txQueries.DeleteSimulationTags(ctx, id); err != nil {
+		return nil, fmt.Errorf("delete tags: %w", err)
+	}
+	for _, tag := range tags {
+		if err := txQueries.AddSimulationTag(ctx, simulations.AddSimulationTagParams{SimulationID: id, Tag: tag}); err != nil {
+			return nil, fmt.Errorf("add tag: %w", err)
+		}
+	}
+
+	stored, err := txQueries.ListSimulationTags(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	if stored == nil {
+		stored = []string{}
+	}
+	return stored, nil
+}
+
+// GetSimulationTags returns the tags of a simulation, sorted.
+func (s *SimulationService) GetSimulationTags(ctx context.Context, id int64) ([]string, error) {
+	tags, err := s.simulationsQueries.ListSimulationTags(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	simulationsmock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
+)
+
+func TestBuildSearchFilter(t *testing.T) {
+	f, err := buildSearchFilter(SimulationSearch{
+		Status:      "completed",
+		Mode:        "advanced",
+		RecipeName:  "freq",
+		ContestFrom: 100,
+		ContestTo:   200,
+		CreatedFrom: "2025-01-01",
+		CreatedTo:   "2025-01-31",
+		Metric:      "quina_rate >= 0.5",
+		Tags:        []string{" baseline ", "v2", "baseline", ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Status.String != "completed" || f.Mode.String != "advanced" || f.RecipePrefix.String != "freq" {
+		t.Errorf("unexpected text filters: %+v", f)
+	}
+	if f.CreatedBy.Valid {
+		t.Errorf("expected created_by to be unset")
+	}
+	if f.ContestFrom.Int64 != 100 || f.ContestTo.Int64 != 200 {
+		t.Errorf("unexpected contest range: %v %v", f.ContestFrom, f.ContestTo)
+	}
+	if f.CreatedFrom.String != "2025-01-01 00:00:00" || f.CreatedTo.String != "2025-02-01 00:00:00" {
+		t.Errorf("unexpected created range: %q %q", f.CreatedFrom.String, f.CreatedTo.String)
+	}
+	if f.MetricPath.String != "$.HitRateQuina" || f.MetricOp.String != ">=" || f.MetricValue.Float64 != 0.5 {
+		t.Errorf("unexpected metric filter: %v %v %v", f.MetricPath, f.MetricOp, f.MetricValue)
+	}
+	if f.Tags.String != `["baseline","v2"]` {
+		t.Errorf("unexpected tags: %q", f.Tags.String)
+	}
+}
+
+func TestBuildSearchFilter_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		search SimulationSearch
+	}{
+		{"unknown status", SimulationSearch{Status: "done"}},
+		{"unknown mode", SimulationSearch{Mode: "fast"}},
+		{"reversed contests", SimulationSearch{ContestFrom: 200, ContestTo: 100}},
+		{"bad date", SimulationSearch{CreatedFrom: "yesterday"}},
+		{"malformed metric", SimulationSearch{Metric: "quina_rate"}},
+		{"unknown metric", SimulationSearch{Metric: "jackpot>0"}},
+		{"non-numeric threshold", SimulationSearch{Metric: "avg_hits>high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildSearchFilter(tt.search); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSimulationService_SearchSimulations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mockQueries.EXPECT().
+		SearchSimulations(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.SearchSimulationsParams) ([]simulations.Simulation, error) {
+			if arg.SortPath.String != "$.AverageHits" || arg.SortDesc {
+				t.Errorf("unexpected sort: %v desc=%v", arg.SortPath, arg.SortDesc)
+			}
+			if arg.Limit != 2 || arg.Offset != 4 {
+				t.Errorf("unexpected page: limit=%d offset=%d", arg.Limit, arg.Offset)
+			}
+			if arg.Status.String != "completed" {
+				t.Errorf("unexpected status: %v", arg.Status)
+			}
+			return []simulations.Simulation{{ID: 5}, {ID: 6}}, nil
+		})
+	mockQueries.EXPECT().
+		CountSearchSimulations(gomock.Any(), simulations.CountSearchSimulationsParams{
+			Status: sql.NullString{String: "completed", Valid: true},
+		}).
+		Return(int64(9), nil)
+
+	result, err := service.SearchSimulations(context.Background(), SimulationSearch{
+		Status:    "completed",
+		SortBy:    "avg_hits",
+		SortOrder: "asc",
+		Limit:     2,
+		Offset:    4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Simulations) != 2 || result.Total != 9 || result.Limit != 2 || result.Offset != 4 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestSimulationService_SearchSimulations_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, search := range []SimulationSearch{
+		{SortBy: "luck"},
+		{SortOrder: "sideways"},
+		{Limit: maxSearchLimit + 1},
+		{Offset: -1},
+		{Metric: "quina_rate"},
+	} {
+		if _, err := service.SearchSimulations(context.Background(), search); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("%+v: expected ErrInvalidSearch, got %v", search, err)
+		}
+	}
+}
+
+func TestNormalizeTags_Limits(t *testing.T) {
+	long := make([]byte, maxTagLength+1)
+	for i := range long {
+		long[i] = 'x'
+	}
+	if _, err := normalizeTags([]string{string(long)}); err == nil {
+		t.Error("expected error for long tag")
+	}
+
+	many := make([]string, maxTags+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	if _, err := normalizeTags(many); err == nil {
+		t.Error("expected error for too many tags")
+	}
+}
+
+func TestSimulationService_CreateSimulation_Tags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewSimulationService(mockQueries, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mockQueries.EXPECT().
+		CreateSimulation(gomock.Any(), gomock.Any()).
+		Return(simulations.Simulation{ID: 3, Status: "pending"}, nil)
+	mockQueries.EXPECT().
+		AddSimulationTag(gomock.Any(), simulations.AddSimulationTagParams{SimulationID: 3, Tag: "baseline"}).
+		Return(nil)
+
+	_, err := service.CreateSimulation(context.Background(), CreateSimulationRequest{
+		Mode:         "simple",
+		Recipe:       Recipe{Version: "1.1", Name: "test", Parameters: RecipeParameters{Alpha: 0.1, SimPrevMax: 10, SimPreds: 5}},
+		StartContest: 1000,
+		EndContest:   1010,
+		Async:        true,
+		Tags:         []string{"baseline", " baseline"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -20,7 +23,7 @@ func TestSimulationService_CreateSimulation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -75,7 +78,7 @@ func TestSimulationService_GetSimulation_QueryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -95,7 +98,7 @@ func TestSimulationService_CancelSimulation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -110,12 +113,54 @@ func TestSimulationService_CancelSimulation(t *testing.T) {
 	}
 }
 
+type recordingSimulationObserver struct {
+	started  []int64
+	finished []int64
+}
+
+func (o *recordingSimulationObserver) OnSimulationStarted(_ context.Context, simID int64) error {
+	o.started = append(o.started, simID)
+	return nil
+}
+
+func (o *recordingSimulationObserver) OnSimulationFinished(_ context.Context, simID int64) error {
+	o.finished = append(o.finished, simID)
+	return nil
+}
+
+func TestSimulationService_CancelSimulation_NotifiesObservers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, nil, logger)
+	observer := &recordingSimulationObserver{}
+	service.AddSimulationObserver(observer)
+
+	mockQueries.EXPECT().
+		CancelSimulation(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	if err := service.CancelSimulation(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(observer.finished) != 1 || observer.finished[0] != 7 {
+		t.Errorf("expected finished notification for simulation 7, got %v", observer.finished)
+	}
+	if len(observer.started) != 0 {
+		t.Errorf("expected no started notification, got %v", observer.started)
+	}
+}
+
 func TestSimulationService_ListSimulations(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -143,7 +188,7 @@ func TestSimulationService_ListSimulationsByStatus(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -170,7 +215,7 @@ func TestSimulationService_GetContestResults(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -198,7 +243,7 @@ func TestSimulationService_ExecuteSimulation(t *testing.T) {
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
 	mockEngine := NewMockEngineServicer(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	// Note: Full ExecuteSimulation test requires database setup, testing logic separately
 	// For unit test coverage, the error cases below provide sufficient coverage
@@ -212,7 +257,7 @@ func TestSimulationService_ExecuteSimulation_GetSimulationError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -220,6 +265,10 @@ func TestSimulationService_ExecuteSimulation_GetSimulationError(t *testing.T) {
 		GetSimulation(gomock.Any(), int64(1)).
 		Return(simulations.Simulation{}, sql.ErrNoRows)
 
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
 	err := service.ExecuteSimulation(context.Background(), 1)
 
 	if err == nil {
@@ -233,7 +282,7 @@ func TestSimulationService_ExecuteSimulation_RunSimulationError(t *testing.T) {
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
 	mockEngine := NewMockEngineServicer(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, mockEngine, logger)
 
@@ -254,6 +303,14 @@ func TestSimulationService_ExecuteSimulation_RunSimulationError(t *testing.T) {
 
 	mockQueries.EXPECT().
 		FailSimulation(gomock.Any(), gomock.Any()).
+		Return(int64(1), nil)
+
+	mockQueries.EXPECT().
+		RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
 		Return(nil)
 
 	err := service.ExecuteSimulation(context.Background(), 1)
@@ -263,6 +320,90 @@ func TestSimulationService_ExecuteSimulation_RunSimulationError(t *testing.T) {
 	}
 }
 
+func TestSimulationService_ExecuteSimulation_LeaseLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	mockEngine := NewMockEngineServicer(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, mockEngine, logger)
+
+	sim := simulations.Simulation{
+		ID:           1,
+		RecipeJson:   `{"version":"1.0","name":"test","parameters":{"alpha":0.1,"beta":0.2,"gamma":0.3,"delta":0.4,"sim_prev_max":10,"sim_preds":5}}`,
+		StartContest: 100,
+		EndContest:   110,
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(sim, nil)
+
+	mockEngine.EXPECT().
+		RunSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cfg SimulationConfig) (*SimulationResult, error) {
+			cancel(ErrLeaseLost)
+			return nil, ctx.Err()
+		})
+
+	// The simulation belongs to another worker now, so it must not be failed
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.ExecuteSimulation(ctx, 1)
+
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_WorkerShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	mockEngine := NewMockEngineServicer(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, mockEngine, logger)
+
+	sim := simulations.Simulation{
+		ID:           1,
+		RecipeJson:   `{"version":"1.0","name":"test","parameters":{"alpha":0.1,"beta":0.2,"gamma":0.3,"delta":0.4,"sim_prev_max":10,"sim_preds":5}}`,
+		StartContest: 100,
+		EndContest:   110,
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(sim, nil)
+
+	mockEngine.EXPECT().
+		RunSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cfg SimulationConfig) (*SimulationResult, error) {
+			cancel(ErrWorkerShutdown)
+			return nil, ctx.Err()
+		})
+
+	// The worker returns the simulation to the queue, so it must not be failed
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.ExecuteSimulation(ctx, 1)
+
+	if !errors.Is(err, ErrWorkerShutdown) {
+		t.Fatalf("expected ErrWorkerShutdown, got %v", err)
+	}
+}
+
 func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -282,6 +423,7 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 			start_contest INTEGER,
 			end_contest INTEGER,
 			status TEXT,
+			worker_id TEXT,
 			finished_at TEXT,
 			run_duration_ms INTEGER,
 			summary_json TEXT,
@@ -298,6 +440,7 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 			best_prediction_numbers TEXT,
 			predictions_json TEXT
 		);
+		INSERT INTO simulations (id, status, worker_id) VALUES (1, 'running', 'worker-a');
 	`)
 	if err != nil {
 		t.Fatalf("failed to create tables: %v", err)
@@ -305,7 +448,7 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
 	mockEngine := NewMockEngineServicer(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, db, mockEngine, logger)
 
@@ -314,6 +457,8 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 		RecipeJson:   `{"version":"1.0","name":"test","parameters":{"alpha":0.1,"beta":0.2,"gamma":0.3,"delta":0.4,"sim_prev_max":10,"sim_preds":5}}`,
 		StartContest: 100,
 		EndContest:   110,
+		Status:       "running",
+		WorkerID:     sql.NullString{String: "worker-a", Valid: true},
 	}
 
 	result := &SimulationResult{
@@ -345,6 +490,14 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 		RunSimulation(gomock.Any(), gomock.Any()).
 		Return(result, nil)
 
+	mockQueries.EXPECT().
+		RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
 	err = service.ExecuteSimulation(context.Background(), 1)
 
 	if err != nil {
@@ -352,12 +505,203 @@ func TestSimulationService_ExecuteSimulation_Success(t *testing.T) {
 	}
 }
 
+func TestSimulationService_ExecuteSimulation_CancelledWhileRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open in-memory sqlite: %v", err)
+	}
+	defer db.Close()
+
+	// The simulation was cancelled while the engine was running
+	_, err = db.Exec(`
+		CREATE TABLE simulations (
+			id INTEGER PRIMARY KEY,
+			status TEXT,
+			worker_id TEXT,
+			finished_at TEXT,
+			run_duration_ms INTEGER,
+			summary_json TEXT,
+			output_blob BLOB,
+			output_name TEXT
+		);
+		CREATE TABLE simulation_contest_results (
+			id INTEGER PRIMARY KEY,
+			simulation_id INTEGER,
+			contest INTEGER,
+			actual_numbers TEXT,
+			best_hits INTEGER,
+			best_prediction_index INTEGER,
+			best_prediction_numbers TEXT,
+			predictions_json TEXT
+		);
+		INSERT INTO simulations (id, status, worker_id) VALUES (1, 'cancelled', 'worker-a');
+	`)
+	if err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	mockEngine := NewMockEngineServicer(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, db, mockEngine, logger)
+	observer := &recordingSimulationObserver{}
+	service.AddSimulationObserver(observer)
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(simulations.Simulation{
+			ID:           1,
+			RecipeJson:   `{"version":"1.0","name":"test","parameters":{"sim_prev_max":10,"sim_preds":5}}`,
+			StartContest: 100,
+			EndContest:   110,
+			Status:       "running",
+			WorkerID:     sql.NullString{String: "worker-a", Valid: true},
+		}, nil)
+
+	mockEngine.EXPECT().
+		RunSimulation(gomock.Any(), gomock.Any()).
+		Return(&SimulationResult{
+			ContestResults: []ContestResult{{Contest: 105, ActualNumbers: []int{1, 2, 3, 4, 5}}},
+			Summary:        Summary{TotalContests: 1},
+		}, nil)
+
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	// Neither FailSimulation nor RecordSimulationAttempt may be called
+	err = service.ExecuteSimulation(context.Background(), 1)
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+
+	var status string
+	var results int
+	if err := db.QueryRow(`SELECT status FROM simulations WHERE id = 1`).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM simulation_contest_results`).Scan(&results); err != nil {
+		t.Fatal(err)
+	}
+	if status != "cancelled" || results != 0 {
+		t.Errorf("expected cancelled simulation without results, got %q with %d results", status, results)
+	}
+	if len(observer.finished) != 0 {
+		t.Errorf("expected no finished notification, got %v", observer.finished)
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_PanicCapturesStack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	mockEngine := NewMockEngineServicer(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, mockEngine, logger)
+
+	sim := simulations.Simulation{
+		ID:           1,
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{"sim_prev_max":10,"sim_preds":5}}`,
+		StartContest: 100,
+		EndContest:   110,
+	}
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(sim, nil)
+
+	mockEngine.EXPECT().
+		RunSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cfg SimulationConfig) (*SimulationResult, error) {
+			panic("boom")
+		})
+
+	var failed simulations.FailSimulationParams
+	mockQueries.EXPECT().
+		FailSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.FailSimulationParams) (int64, error) {
+			failed = arg
+			return 1, nil
+		})
+
+	var attempt simulations.RecordSimulationAttemptParams
+	mockQueries.EXPECT().
+		RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+			attempt = arg
+			return nil
+		})
+
+	var saved simulations.SaveSimulationLogParams
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.SaveSimulationLogParams) error {
+			saved = arg
+			return nil
+		})
+
+	err := service.ExecuteSimulation(context.Background(), 1)
+
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+	if !failed.ErrorStack.Valid || !strings.Contains(failed.ErrorStack.String, "goroutine") {
+		t.Errorf("expected stack trace in error_stack, got %q", failed.ErrorStack.String)
+	}
+	if attempt.SimulationID != 1 || attempt.Attempt != 1 || attempt.Outcome != attemptFailed || !strings.Contains(attempt.ErrorMessage.String, "boom") {
+		t.Errorf("expected failed first attempt with the panic, got %+v", attempt)
+	}
+
+	// The stored log ends with the panic record
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(simulations.Simulation{ID: 1, LogBlob: saved.LogBlob}, nil)
+
+	logs, err := service.GetSimulationLog(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetSimulationLog returned error: %v", err)
+	}
+	defer logs.Close()
+
+	data, err := io.ReadAll(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"msg":"simulation started"`) || !strings.Contains(string(data), `"msg":"simulation panicked"`) {
+		t.Errorf("expected start and panic records in log, got %q", data)
+	}
+}
+
+func TestSimulationService_GetSimulationLog_NotCaptured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, nil, logger)
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(simulations.Simulation{ID: 1}, nil)
+
+	if _, err := service.GetSimulationLog(context.Background(), 1); !errors.Is(err, ErrSimulationLogNotFound) {
+		t.Fatalf("expected ErrSimulationLogNotFound, got %v", err)
+	}
+}
+
 func TestSimulationService_ExecuteSimulation_InvalidJSON(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -372,6 +716,10 @@ func TestSimulationService_ExecuteSimulation_InvalidJSON(t *testing.T) {
 		GetSimulation(gomock.Any(), int64(1)).
 		Return(sim, nil)
 
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
 	err := service.ExecuteSimulation(context.Background(), 1)
 
 	if err == nil {
@@ -384,7 +732,7 @@ func TestSimulationService_validateRecipe(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -447,6 +795,26 @@ func TestSimulationService_validateRecipe(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for invalid sim_preds, got nil")
 	}
+
+	// Fixed tickets replace the predictor parameters
+	fixedRecipe := Recipe{
+		Version:    "1.0",
+		Name:       "fixed",
+		Parameters: RecipeParameters{FixedTickets: [][]int{{1, 2, 3, 4, 5}, {10, 20, 30, 40, 50, 60}}},
+	}
+
+	err = service.validateRecipe(fixedRecipe)
+	if err != nil {
+		t.Errorf("expected no error for fixed-ticket recipe, got %v", err)
+	}
+
+	// Invalid fixed ticket
+	fixedRecipe.Parameters.FixedTickets = [][]int{{1, 2, 3, 4, 81}}
+
+	err = service.validateRecipe(fixedRecipe)
+	if err == nil {
+		t.Error("expected error for invalid fixed ticket, got nil")
+	}
 }
 
 func TestSimulationService_CreateSimulation_ValidationError(t *testing.T) {
@@ -454,7 +822,7 @@ func TestSimulationService_CreateSimulation_ValidationError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
@@ -481,6 +849,69 @@ func TestSimulationService_CreateSimulation_ValidationError(t *testing.T) {
 	}
 }
 
+func TestSimulationService_CreateSimulation_UnknownVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, nil, logger)
+
+	req := CreateSimulationRequest{
+		Mode:       "simple",
+		RecipeName: "test-recipe",
+		Recipe: Recipe{
+			Version: "9.9",
+			Name:    "test",
+			Parameters: RecipeParameters{
+				SimPrevMax: 10,
+				SimPreds:   5,
+			},
+		},
+		StartContest: 1000,
+		EndContest:   1010,
+		Async:        true,
+	}
+
+	_, err := service.CreateSimulation(context.Background(), req)
+
+	if !errors.Is(err, ErrInvalidRecipe) {
+		t.Fatalf("expected ErrInvalidRecipe, got %v", err)
+	}
+}
+
+func TestSimulationService_ExecuteSimulation_UnknownRecipeKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSimulationService(mockQueries, nil, nil, logger)
+
+	sim := simulations.Simulation{
+		ID:           1,
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{"alpha":0.1,"sim_count":1000}}`,
+		StartContest: 100,
+		EndContest:   110,
+	}
+
+	mockQueries.EXPECT().
+		GetSimulation(gomock.Any(), int64(1)).
+		Return(sim, nil)
+
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.ExecuteSimulation(context.Background(), 1)
+
+	if err == nil || !strings.Contains(err.Error(), "parameters.sim_count: unknown key") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
+
 func TestSimulationService_CreateSimulation_SyncExecution(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -527,6 +958,8 @@ func TestSimulationService_CreateSimulation_SyncExecution(t *testing.T) {
 			processed_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY(simulation_id) REFERENCES simulations(id) ON DELETE CASCADE
 		);
+		INSERT INTO simulations (id, status, recipe_json, mode, start_contest, end_contest, worker_id)
+		VALUES (1, 'running', '{}', 'simple', 1000, 1010, 'sync');
 	`)
 	if err != nil {
 		t.Fatalf("failed to create tables: %v", err)
@@ -534,7 +967,7 @@ func TestSimulationService_CreateSimulation_SyncExecution(t *testing.T) {
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
 	mockEngine := NewMockEngineServicer(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, db, mockEngine, logger)
 
@@ -570,6 +1003,10 @@ func TestSimulationService_CreateSimulation_SyncExecution(t *testing.T) {
 		CreatedBy:    sql.NullString{String: "user1", Valid: true},
 	}
 
+	runningSim := expectedSim
+	runningSim.Status = "running"
+	runningSim.WorkerID = sql.NullString{String: syncWorkerID, Valid: true}
+
 	completedSim := simulations.Simulation{
 		ID:           1,
 		RecipeName:   sql.NullString{String: "test-recipe", Valid: true},
@@ -606,14 +1043,31 @@ func TestSimulationService_CreateSimulation_SyncExecution(t *testing.T) {
 		CreateSimulation(gomock.Any(), gomock.Any()).
 		Return(expectedSim, nil)
 
+	mockQueries.EXPECT().
+		UpdateSimulationStatus(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.UpdateSimulationStatusParams) error {
+			if arg.Status != "running" || arg.WorkerID.String != syncWorkerID {
+				t.Errorf("expected simulation to run on %q, got %+v", syncWorkerID, arg)
+			}
+			return nil
+		})
+
 	mockQueries.EXPECT().
 		GetSimulation(gomock.Any(), int64(1)).
-		Return(expectedSim, nil)
+		Return(runningSim, nil)
 
 	mockEngine.EXPECT().
 		RunSimulation(gomock.Any(), gomock.Any()).
 		Return(result, nil)
 
+	mockQueries.EXPECT().
+		RecordSimulationAttempt(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	mockQueries.EXPECT().
+		SaveSimulationLog(gomock.Any(), gomock.Any()).
+		Return(nil)
+
 	mockQueries.EXPECT().
 		GetSimulation(gomock.Any(), int64(1)).
 		Return(completedSim, nil)
@@ -633,7 +1087,7 @@ func TestSimulationService_CreateSimulation_CreateError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := simulationsmock.NewMockQuerier(ctrl)
-	logger := slog.New(slog.NewTextHandler(nil, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	service := NewSimulationService(mockQueries, nil, nil, logger)
 
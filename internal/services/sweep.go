@@ -4,18 +4,30 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"math/rand"
 	"time"
 
 	"github.com/garnizeh/luckyfive/internal/store/sweep_execution"
+	"github.com/garnizeh/luckyfive/pkg/recipe"
 	"github.com/garnizeh/luckyfive/pkg/sweep"
 )
 
+var (
+	// ErrSweepNotFound is returned for unknown sweep IDs.
+	ErrSweepNotFound = errors.New("sweep not found")
+	// ErrSweepNotCancellable is returned when cancelling a finished sweep.
+	ErrSweepNotCancellable = errors.New("sweep already finished")
+)
+
 type SweepServicer interface {
 	CreateSweep(ctx context.Context, req CreateSweepRequest) (*sweep_execution.SweepJob, error)
 	GetSweepStatus(ctx context.Context, sweepID int64) (*SweepStatus, error)
 	UpdateSweepProgress(ctx context.Context, sweepID int64) error
+	CancelSweep(ctx context.Context, sweepID int64) (*SweepCancellation, error)
 	FindBest(ctx context.Context, sweepID int64, metric string) (*BestConfiguration, error)
 	GetVisualizationData(ctx context.Context, sweepID int64, metrics []string) (*VisualizationData, error)
 }
@@ -25,6 +37,8 @@ type SweepService struct {
 	simulationsDB         *sql.DB
 	simulationService     SimulationServicer
 	generator             *sweep.Generator
+	bestMetric            string // Cached on finish when set
+	batchPollInterval     time.Duration
 	logger                *slog.Logger
 }
 
@@ -39,6 +53,7 @@ func NewSweepService(
 		simulationsDB:         simulationsDB,
 		simulationService:     simulationService,
 		generator:             sweep.NewGenerator(),
+		batchPollInterval:     defaultBatchPollInterval,
 		logger:                logger,
 	}
 }
@@ -50,6 +65,7 @@ type CreateSweepRequest struct {
 	StartContest int
 	EndContest   int
 	CreatedBy    string
+	Priority     *int // Defaults to SweepSimulationPriority
 }
 
 type SweepStatus struct {
@@ -58,10 +74,20 @@ type SweepStatus struct {
 	Completed   int
 	Running     int
 	Failed      int
+	Cancelled   int
 	Pending     int
 	Simulations []sweep_execution.GetSweepSimulationDetailsRow
 }
 
+// SweepCancellation reports the child simulations a sweep cancellation
+// affected. Cancelling a cancelled sweep again affects none.
+type SweepCancellation struct {
+	SweepID          int64 `json:"sweep_id"`
+	AlreadyCancelled bool  `json:"already_cancelled"`
+	Cancelled        int   `json:"cancelled"` // Pending, never run
+	Stopped          int   `json:"stopped"`   // Running, stop at their next heartbeat
+}
+
 type BestConfiguration struct {
 	SweepID         int64              `json:"sweep_id"`
 	SimulationID    int64              `json:"simulation_id"`
@@ -88,60 +114,95 @@ func (s *SweepService) CreateSweep(
 	ctx context.Context,
 	req CreateSweepRequest,
 ) (*sweep_execution.SweepJob, error) {
-	// Validate sweep config
-	if err := req.SweepConfig.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid sweep config: %w", err)
+	return s.createSweep(ctx, req, nil)
+}
+
+// SweepJobType creates sweeps as analysis jobs. The payload is a
+// CreateSweepRequest and the result the created sweep job. Progress counts
+// the child simulations created.
+func (s *SweepService) SweepJobType() JobType {
+	return JobType{
+		Name: JobTypeSweep,
+		Validate: func(payload json.RawMessage) error {
+			var req CreateSweepRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return err
+			}
+			_, _, err := validateCreateSweepRequest(req)
+			return err
+		},
+		Run: func(ctx context.Context, payload json.RawMessage, progress JobProgressFunc) (*JobOutput, error) {
+			var req CreateSweepRequest
+			if err := decodeJobPayload(payload, &req); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidJobPayload, err)
+			}
+			sweepJob, err := s.createSweep(ctx, req, progress)
+			if err != nil {
+				return nil, err
+			}
+			return &JobOutput{Result: sweepJob}, nil
+		},
 	}
+}
 
-	// Generate all recipe combinations
-	recipes, err := s.generator.Generate(req.SweepConfig)
+func validateCreateSweepRequest(req CreateSweepRequest) (sweep.SweepConfig, int, error) {
+	if err := req.SweepConfig.Validate(); err != nil {
+		return sweep.SweepConfig{}, 0, fmt.Errorf("invalid sweep config: %w", err)
+	}
+	sweepConfig, err := validateSweepConfig(req.SweepConfig)
 	if err != nil {
-		return nil, fmt.Errorf("generate recipes: %w", err)
+		return sweep.SweepConfig{}, 0, fmt.Errorf("invalid sweep config: %w", err)
 	}
 
-	if len(recipes) == 0 {
-		return nil, fmt.Errorf("no valid combinations generated")
+	priority := SweepSimulationPriority
+	if req.Priority != nil {
+		priority = *req.Priority
 	}
+	if err := validatePriority(priority); err != nil {
+		return sweep.SweepConfig{}, 0, err
+	}
+	return sweepConfig, priority, nil
+}
 
-	s.logger.Info("generated recipes", "count", len(recipes))
-
-	// Create child simulations first (outside transaction to avoid deadlocks)
-	var simulationIDs []int64
-	for i, recipe := range recipes {
-		// Convert sweep recipe to service recipe
-		serviceRecipe := s.convertToServiceRecipe(recipe)
-
-		// Create simulation (async mode)
-		sim, err := s.simulationService.CreateSimulation(ctx, CreateSimulationRequest{
-			Mode:         "simple",
-			RecipeName:   fmt.Sprintf("%s_var_%d", req.Name, i),
-			Recipe:       serviceRecipe,
-			StartContest: req.StartContest,
-			EndContest:   req.EndContest,
-			Async:        true,
-			CreatedBy:    req.CreatedBy,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("create simulation %d: %w", i, err)
-		}
+// createSweep reports the number of child simulations created to progress
+// when it is not nil. When it fails or ctx ends partway through, the sweep and
+// the children created so far are cancelled.
+func (s *SweepService) createSweep(
+	ctx context.Context,
+	req CreateSweepRequest,
+	progress JobProgressFunc,
+) (*sweep_execution.SweepJob, error) {
+	sweepConfig, priority, err := validateCreateSweepRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
-		simulationIDs = append(simulationIDs, sim.ID)
+	// Store the seed of sampled sweeps so that their config reproduces them
+	if !sweepConfig.Sampling.IsGrid() && sweepConfig.Sampling.Seed == 0 {
+		sampling := *sweepConfig.Sampling
+		sampling.Seed = rand.Int63n(math.MaxInt64-1) + 1
+		sweepConfig.Sampling = &sampling
 	}
 
-	// Start transaction for sweep job creation and linking
-	tx, err := s.simulationsDB.BeginTx(ctx, nil)
+	// Generate all recipe combinations
+	recipes, err := s.generator.Generate(sweepConfig)
 	if err != nil {
-		return nil, fmt.Errorf("begin tx: %w", err)
+		return nil, fmt.Errorf("generate recipes: %w", err)
 	}
-	defer tx.Rollback()
 
-	txQueries := sweep_execution.New(tx)
+	if len(recipes) == 0 {
+		return nil, fmt.Errorf("no valid combinations generated")
+	}
 
-	// Create sweep job record
-	sweepConfigJSON, _ := json.Marshal(req.SweepConfig)
+	s.logger.Info("generated recipes", "count", len(recipes))
+
+	// Create the sweep first so that every child is linked as it is created.
+	// Children are created one by one, not in a transaction, because
+	// CreateSimulation writes through its own connection.
+	sweepConfigJSON, _ := json.Marshal(sweepConfig)
 	contestRange := fmt.Sprintf("%d-%d", req.StartContest, req.EndContest)
 
-	sweepJob, err := txQueries.CreateSweepJob(ctx, sweep_execution.CreateSweepJobParams{
+	sweepJob, err := s.sweepExecutionQueries.CreateSweepJob(ctx, sweep_execution.CreateSweepJobParams{
 		Name:              req.Name,
 		Description:       sql.NullString{String: req.Description, Valid: req.Description != ""},
 		SweepConfigJson:   string(sweepConfigJSON),
@@ -153,29 +214,73 @@ func (s *SweepService) CreateSweep(
 		return nil, fmt.Errorf("create sweep job: %w", err)
 	}
 
-	// Link simulations to sweep
+	if err := s.createSweepSimulations(ctx, sweepJob.ID, req, priority, recipes, progress); err != nil {
+		// Cancel the children created so far so that none runs outside a
+		// live sweep, e.g. when the job is cancelled or its worker stops
+		if _, cancelErr := s.CancelSweep(context.WithoutCancel(ctx), sweepJob.ID); cancelErr != nil {
+			s.logger.Error("cancel partially created sweep failed", "sweep_id", sweepJob.ID, "error", cancelErr)
+		}
+		return nil, err
+	}
+
+	// Workers may have started simulations before they were linked
+	if err := s.UpdateSweepProgress(ctx, sweepJob.ID); err != nil {
+		s.logger.Error("update sweep progress failed", "sweep_id", sweepJob.ID, "error", err)
+	}
+
+	return &sweepJob, nil
+}
+
+// createSweepSimulations queues a child simulation per recipe and links it to
+// the sweep right away. A child that cannot be linked is cancelled.
+func (s *SweepService) createSweepSimulations(
+	ctx context.Context,
+	sweepID int64,
+	req CreateSweepRequest,
+	priority int,
+	recipes []sweep.GeneratedRecipe,
+	progress JobProgressFunc,
+) error {
 	for i, recipe := range recipes {
-		// Link to sweep
+		sim, err := s.simulationService.CreateSimulation(ctx, CreateSimulationRequest{
+			Mode:         "simple",
+			RecipeName:   fmt.Sprintf("%s_var_%d", req.Name, i),
+			Recipe:       s.convertToServiceRecipe(recipe),
+			StartContest: req.StartContest,
+			EndContest:   req.EndContest,
+			Async:        true,
+			CreatedBy:    req.CreatedBy,
+			Priority:     priority,
+		})
+		if err != nil {
+			return fmt.Errorf("create simulation %d: %w", i, err)
+		}
+
 		paramsJSON, _ := json.Marshal(recipe.Parameters)
-		err = txQueries.CreateSweepSimulation(ctx, sweep_execution.CreateSweepSimulationParams{
-			SweepJobID:      sweepJob.ID,
-			SimulationID:    simulationIDs[i],
+		err = s.sweepExecutionQueries.CreateSweepSimulation(ctx, sweep_execution.CreateSweepSimulationParams{
+			SweepJobID:      sweepID,
+			SimulationID:    sim.ID,
 			VariationIndex:  int64(i),
 			VariationParams: string(paramsJSON),
 		})
 		if err != nil {
-			return nil, fmt.Errorf("link simulation %d: %w", i, err)
+			if cancelErr := s.simulationService.CancelSimulation(context.WithoutCancel(ctx), sim.ID); cancelErr != nil {
+				s.logger.Error("cancel unlinked simulation failed", "simulation_id", sim.ID, "error", cancelErr)
+			}
+			return fmt.Errorf("link simulation %d: %w", i, err)
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit: %w", err)
+		if progress != nil {
+			progress(int64(i+1), int64(len(recipes)))
+		}
 	}
 
-	return &sweepJob, nil
+	// A job stopped after the last child must not leave a sweep its rerun
+	// would create again
+	return ctx.Err()
 }
 
-func (s *SweepService) convertToServiceRecipe(recipe sweep.GeneratedRecipe) Recipe {
+func (s *SweepService) convertToServiceRecipe(gr sweep.GeneratedRecipe) Recipe {
 	params := RecipeParameters{
 		// Set defaults
 		SimPrevMax: 10,
@@ -183,41 +288,81 @@ func (s *SweepService) convertToServiceRecipe(recipe sweep.GeneratedRecipe) Reci
 	}
 
 	// Extract parameters from the map
-	if alpha, ok := recipe.Parameters["alpha"].(float64); ok {
+	if alpha, ok := numberParam(gr.Parameters, "alpha"); ok {
 		params.Alpha = alpha
 	}
-	if beta, ok := recipe.Parameters["beta"].(float64); ok {
+	if beta, ok := numberParam(gr.Parameters, "beta"); ok {
 		params.Beta = beta
 	}
-	if gamma, ok := recipe.Parameters["gamma"].(float64); ok {
+	if gamma, ok := numberParam(gr.Parameters, "gamma"); ok {
 		params.Gamma = gamma
 	}
-	if delta, ok := recipe.Parameters["delta"].(float64); ok {
+	if delta, ok := numberParam(gr.Parameters, "delta"); ok {
 		params.Delta = delta
 	}
-	if simPrevMax, ok := recipe.Parameters["sim_prev_max"].(float64); ok {
+	if simPrevMax, ok := numberParam(gr.Parameters, "sim_prev_max"); ok {
 		params.SimPrevMax = int(simPrevMax)
 	}
-	if simPreds, ok := recipe.Parameters["sim_preds"].(float64); ok {
+	if simPreds, ok := numberParam(gr.Parameters, "sim_preds"); ok {
 		params.SimPreds = int(simPreds)
 	}
-	if enableEvolutionary, ok := recipe.Parameters["enableEvolutionary"].(bool); ok {
+	if enableEvolutionary, ok := gr.Parameters["enableEvolutionary"].(bool); ok {
 		params.EnableEvolutionary = enableEvolutionary
 	}
-	if generations, ok := recipe.Parameters["generations"].(float64); ok {
+	if generations, ok := numberParam(gr.Parameters, "generations"); ok {
 		params.Generations = int(generations)
 	}
-	if mutationRate, ok := recipe.Parameters["mutationRate"].(float64); ok {
+	if mutationRate, ok := numberParam(gr.Parameters, "mutationRate"); ok {
 		params.MutationRate = mutationRate
 	}
+	if seeds, ok := numberParam(gr.Parameters, "seeds"); ok {
+		params.Seeds = int(seeds)
+	}
+	if confidenceLevel, ok := numberParam(gr.Parameters, "confidence_level"); ok {
+		params.ConfidenceLevel = confidenceLevel
+	}
+	if tickets, ok := ticketsParam(gr.Parameters, "fixed_tickets"); ok {
+		params.FixedTickets = tickets
+	}
 
 	return Recipe{
-		Version:    "1.0",
-		Name:       recipe.Name,
+		Version:    recipe.CurrentVersion,
+		Name:       gr.Name,
 		Parameters: params,
 	}
 }
 
+// numberParam reads a number from generated recipe parameters, which hold
+// float64 values decoded from JSON and int values of int_range sweeps.
+func numberParam(params map[string]any, name string) (float64, bool) {
+	switch v := params[name].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// ticketsParam reads a list of tickets from generated recipe parameters. It
+// only comes from the base recipe, decoded from JSON as nested []any.
+func ticketsParam(params map[string]any, name string) ([][]int, bool) {
+	raw, ok := params[name]
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var tickets [][]int
+	if err := json.Unmarshal(data, &tickets); err != nil {
+		return nil, false
+	}
+	return tickets, true
+}
+
 func (s *SweepService) GetSweepStatus(ctx context.Context, sweepID int64) (*SweepStatus, error) {
 	sweepJob, err := s.sweepExecutionQueries.GetSweepJob(ctx, sweepID)
 	if err != nil {
@@ -229,9 +374,11 @@ func (s *SweepService) GetSweepStatus(ctx context.Context, sweepID int64) (*Swee
 		return nil, fmt.Errorf("get sweep simulation details: %w", err)
 	}
 
+	// Children still being created count towards the total, so that the
+	// sweep does not finish before the last of them is linked
 	status := &SweepStatus{
 		Sweep:       sweepJob,
-		Total:       len(details),
+		Total:       max(len(details), int(sweepJob.TotalCombinations)),
 		Completed:   0,
 		Running:     0,
 		Failed:      0,
@@ -247,6 +394,8 @@ func (s *SweepService) GetSweepStatus(ctx context.Context, sweepID int64) (*Swee
 			status.Running++
 		case "failed":
 			status.Failed++
+		case "cancelled":
+			status.Cancelled++
 		case "pending":
 			status.Pending++
 		}
@@ -255,57 +404,222 @@ func (s *SweepService) GetSweepStatus(ctx context.Context, sweepID int64) (*Swee
 	return status, nil
 }
 
+// SetBestMetric makes finished sweeps compute and cache their best
+// configuration for metric, which FindBest then returns without recomputing
+// it. An empty metric disables caching.
+func (s *SweepService) SetBestMetric(metric string) error {
+	if metric != "" && !s.isValidMetric(metric) {
+		return fmt.Errorf("invalid metric: %s", metric)
+	}
+	s.bestMetric = metric
+	return nil
+}
+
+// OnSimulationStarted implements SimulationObserver, moving the sweep of the
+// simulation to running.
+func (s *SweepService) OnSimulationStarted(ctx context.Context, simID int64) error {
+	return s.updateSweepOf(ctx, simID)
+}
+
+// OnSimulationFinished implements SimulationObserver, counting the finished
+// simulation and finishing its sweep after the last one.
+func (s *SweepService) OnSimulationFinished(ctx context.Context, simID int64) error {
+	return s.updateSweepOf(ctx, simID)
+}
+
+func (s *SweepService) updateSweepOf(ctx context.Context, simID int64) error {
+	sweepID, err := s.sweepExecutionQueries.GetSweepJobIDBySimulation(ctx, simID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Not part of a sweep, or CreateSweep has not linked it yet
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get sweep of simulation %d: %w", simID, err)
+	}
+	return s.UpdateSweepProgress(ctx, sweepID)
+}
+
+// UpdateSweepProgress recomputes the status and counters of a sweep from its
+// simulations. The sweep starts running with its first started simulation and
+// finishes once every simulation completed, failed or was cancelled. Finished
+// and cancelled sweeps are left alone.
 func (s *SweepService) UpdateSweepProgress(ctx context.Context, sweepID int64) error {
 	status, err := s.GetSweepStatus(ctx, sweepID)
 	if err != nil {
 		return err
 	}
+	if status.Sweep.FinishedAt.Valid || status.Sweep.Status == "cancelled" {
+		return nil
+	}
 
 	// Determine overall status
+	done := status.Completed + status.Failed + status.Cancelled
 	var overallStatus string
-	if status.Failed > 0 && status.Completed+status.Failed == status.Total {
+	switch {
+	case done == status.Total && status.Failed > 0:
 		overallStatus = "failed"
-	} else if status.Completed == status.Total {
+	case done == status.Total && status.Completed > 0:
 		overallStatus = "completed"
-	} else if status.Running > 0 || status.Completed > 0 {
+	case done == status.Total:
+		overallStatus = "cancelled"
+	case status.Running > 0 || done > 0:
 		overallStatus = "running"
-	} else {
+	default:
 		overallStatus = "pending"
 	}
 
-	// Update progress
+	now := time.Now().UTC()
+	startedAt := sql.NullString{}
+	if overallStatus != "pending" {
+		startedAt = sql.NullString{String: now.Format(time.RFC3339), Valid: true}
+	}
+	completed := sql.NullInt64{Int64: int64(status.Completed), Valid: true}
+	failed := sql.NullInt64{Int64: int64(status.Failed), Valid: true}
+
+	// Update progress, setting started_at unless already set
 	err = s.sweepExecutionQueries.UpdateSweepJobProgress(ctx, sweep_execution.UpdateSweepJobProgressParams{
-		CompletedSimulations: sql.NullInt64{Int64: int64(status.Completed), Valid: true},
-		FailedSimulations:    sql.NullInt64{Int64: int64(status.Failed), Valid: true},
+		CompletedSimulations: completed,
+		FailedSimulations:    failed,
 		Status:               overallStatus,
+		StartedAt:            startedAt,
 		ID:                   sweepID,
 	})
 	if err != nil {
 		return fmt.Errorf("update sweep progress: %w", err)
 	}
 
-	// If completed, mark finish time
-	if overallStatus == "completed" || overallStatus == "failed" {
-		duration := int64(0)
-		if status.Sweep.StartedAt.Valid {
-			startTime, _ := time.Parse(time.RFC3339, status.Sweep.StartedAt.String)
-			duration = time.Since(startTime).Milliseconds()
-		}
+	if overallStatus == "pending" || overallStatus == "running" {
+		return nil
+	}
 
-		err = s.sweepExecutionQueries.FinishSweepJob(ctx, sweep_execution.FinishSweepJobParams{
-			Status:        overallStatus,
-			FinishedAt:    sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
-			RunDurationMs: sql.NullInt64{Int64: duration, Valid: true},
-			ID:            sweepID,
-		})
-		if err != nil {
-			return fmt.Errorf("finish sweep job: %w", err)
+	// The last simulation finished, so mark the finish time
+	duration := int64(0)
+	if status.Sweep.StartedAt.Valid {
+		if startTime, err := time.Parse(time.RFC3339, status.Sweep.StartedAt.String); err == nil {
+			duration = now.Sub(startTime).Milliseconds()
 		}
 	}
 
+	err = s.sweepExecutionQueries.FinishSweepJob(ctx, sweep_execution.FinishSweepJobParams{
+		Status:               overallStatus,
+		CompletedSimulations: completed,
+		FailedSimulations:    failed,
+		FinishedAt:           sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		RunDurationMs:        sql.NullInt64{Int64: duration, Valid: true},
+		ID:                   sweepID,
+	})
+	if err != nil {
+		return fmt.Errorf("finish sweep job: %w", err)
+	}
+	s.logger.Info("sweep finished", "sweep_id", sweepID, "status", overallStatus, "completed", status.Completed, "failed", status.Failed, "duration_ms", duration)
+
+	if overallStatus == "completed" && s.bestMetric != "" {
+		s.cacheBest(ctx, sweepID)
+	}
 	return nil
 }
 
+// CancelSweep cancels a sweep and its unfinished simulations in one
+// transaction. Pending simulations are never run; running ones stop when
+// their worker next extends the lease. Cancelling a cancelled sweep again
+// changes nothing.
+func (s *SweepService) CancelSweep(ctx context.Context, sweepID int64) (*SweepCancellation, error) {
+	sweepJob, err := s.sweepExecutionQueries.GetSweepJob(ctx, sweepID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSweepNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get sweep job: %w", err)
+	}
+	if sweepJob.Status == "cancelled" {
+		return &SweepCancellation{SweepID: sweepID, AlreadyCancelled: true}, nil
+	}
+	if sweepJob.FinishedAt.Valid {
+		return nil, ErrSweepNotCancellable
+	}
+
+	tx, err := s.simulationsDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	txQueries := sweep_execution.New(tx)
+	now := time.Now().UTC()
+	finishedAt := sql.NullString{String: now.Format(time.RFC3339), Valid: true}
+
+	cancelled, err := txQueries.CancelSweepSimulations(ctx, sweep_execution.CancelSweepSimulationsParams{
+		FinishedAt: finishedAt,
+		Status:     "pending",
+		SweepJobID: sweepID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cancel pending simulations: %w", err)
+	}
+	stopped, err := txQueries.CancelSweepSimulations(ctx, sweep_execution.CancelSweepSimulationsParams{
+		FinishedAt: finishedAt,
+		Status:     "running",
+		SweepJobID: sweepID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cancel running simulations: %w", err)
+	}
+
+	duration := int64(0)
+	if sweepJob.StartedAt.Valid {
+		if startTime, err := time.Parse(time.RFC3339, sweepJob.StartedAt.String); err == nil {
+			duration = now.Sub(startTime).Milliseconds()
+		}
+	}
+	n, err := txQueries.CancelSweepJob(ctx, sweep_execution.CancelSweepJobParams{
+		FinishedAt:    finishedAt,
+		RunDurationMs: sql.NullInt64{Int64: duration, Valid: true},
+		ID:            sweepID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cancel sweep job: %w", err)
+	}
+	if n == 0 {
+		// Finished or cancelled since it was read; the rollback keeps its
+		// simulations as they were
+		tx.Rollback()
+		return s.CancelSweep(ctx, sweepID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	// The sweep is cancelled already, so observers leave it alone
+	for _, id := range append(cancelled, stopped...) {
+		s.simulationService.NotifySimulationFinished(ctx, id)
+	}
+	s.logger.Info("sweep cancelled", "sweep_id", sweepID, "cancelled", len(cancelled), "stopped", len(stopped), "duration_ms", duration)
+
+	return &SweepCancellation{
+		SweepID:   sweepID,
+		Cancelled: len(cancelled),
+		Stopped:   len(stopped),
+	}, nil
+}
+
+// cacheBest stores the best configuration of a completed sweep. Failures are
+// only logged since FindBest can still compute it.
+func (s *SweepService) cacheBest(ctx context.Context, sweepID int64) {
+	best, err := s.FindBest(ctx, sweepID, s.bestMetric)
+	if err == nil {
+		bestJSON, _ := json.Marshal(best)
+		err = s.sweepExecutionQueries.CacheSweepBest(ctx, sweep_execution.CacheSweepBestParams{
+			BestMetric: sql.NullString{String: s.bestMetric, Valid: true},
+			BestJson:   sql.NullString{String: string(bestJSON), Valid: true},
+			ID:         sweepID,
+		})
+	}
+	if err != nil {
+		s.logger.Error("cache sweep best failed", "sweep_id", sweepID, "metric", s.bestMetric, "error", err)
+	}
+}
+
 func (s *SweepService) FindBest(
 	ctx context.Context,
 	sweepID int64,
@@ -326,6 +640,14 @@ func (s *SweepService) FindBest(
 		return nil, fmt.Errorf("sweep %d is not completed (status: %s)", sweepID, status.Sweep.Status)
 	}
 
+	// Cached when the sweep finished
+	if status.Sweep.BestMetric.String == metric && status.Sweep.BestJson.Valid {
+		var best BestConfiguration
+		if err := json.Unmarshal([]byte(status.Sweep.BestJson.String), &best); err == nil {
+			return &best, nil
+		}
+	}
+
 	// Calculate metrics for all completed simulations
 	type simResult struct {
 		simulationID    int64
@@ -396,9 +718,9 @@ func (s *SweepService) FindBest(
 		return nil, fmt.Errorf("get simulation %d: %w", best.simulationID, err)
 	}
 
-	var recipe Recipe
-	if err := json.Unmarshal([]byte(simulation.RecipeJson), &recipe); err != nil {
-		return nil, fmt.Errorf("unmarshal recipe: %w", err)
+	recipe, err := decodeRecipe(simulation.RecipeJson)
+	if err != nil {
+		return nil, fmt.Errorf("decode recipe: %w", err)
 	}
 
 	return &BestConfiguration{
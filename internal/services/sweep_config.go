@@ -8,6 +8,7 @@ import (
 	"log/slog"
 
 	"github.com/garnizeh/luckyfive/internal/store/sweeps"
+	"github.com/garnizeh/luckyfive/pkg/recipe"
 	"github.com/garnizeh/luckyfive/pkg/sweep"
 )
 
@@ -58,9 +59,13 @@ func (s *SweepConfigService) Create(ctx context.Context, req CreateSweepConfigRe
 	if err := req.Config.Validate(); err != nil {
 		return sweeps.Sweep{}, fmt.Errorf("invalid sweep config: %w", err)
 	}
+	config, err := validateSweepConfig(req.Config)
+	if err != nil {
+		return sweeps.Sweep{}, fmt.Errorf("invalid sweep config: %w", err)
+	}
 
 	// Marshal config to JSON
-	configJSON, err := json.Marshal(req.Config)
+	configJSON, err := json.Marshal(config)
 	if err != nil {
 		return sweeps.Sweep{}, fmt.Errorf("marshal config: %w", err)
 	}
@@ -81,18 +86,27 @@ func (s *SweepConfigService) Create(ctx context.Context, req CreateSweepConfigRe
 }
 
 func (s *SweepConfigService) Get(ctx context.Context, id int64) (sweeps.Sweep, error) {
-	return s.sweepsQueries.GetSweep(ctx, id)
+	return upgradeSweep(s.sweepsQueries.GetSweep(ctx, id))
 }
 
 func (s *SweepConfigService) GetByName(ctx context.Context, name string) (sweeps.Sweep, error) {
-	return s.sweepsQueries.GetSweepByName(ctx, name)
+	return upgradeSweep(s.sweepsQueries.GetSweepByName(ctx, name))
 }
 
 func (s *SweepConfigService) List(ctx context.Context, limit, offset int64) ([]sweeps.Sweep, error) {
-	return s.sweepsQueries.ListSweeps(ctx, sweeps.ListSweepsParams{
+	list, err := s.sweepsQueries.ListSweeps(ctx, sweeps.ListSweepsParams{
 		Limit:  limit,
 		Offset: offset,
 	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i], err = upgradeSweep(list[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
 }
 
 func (s *SweepConfigService) Update(ctx context.Context, id int64, req CreateSweepConfigRequest) error {
@@ -100,9 +114,13 @@ func (s *SweepConfigService) Update(ctx context.Context, id int64, req CreateSwe
 	if err := req.Config.Validate(); err != nil {
 		return fmt.Errorf("invalid sweep config: %w", err)
 	}
+	config, err := validateSweepConfig(req.Config)
+	if err != nil {
+		return fmt.Errorf("invalid sweep config: %w", err)
+	}
 
 	// Marshal config to JSON
-	configJSON, err := json.Marshal(req.Config)
+	configJSON, err := json.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
 	}
@@ -122,3 +140,85 @@ func (s *SweepConfigService) Delete(ctx context.Context, id int64) error {
 func (s *SweepConfigService) IncrementUsage(ctx context.Context, id int64) error {
 	return s.sweepsQueries.IncrementSweepUsage(ctx, id)
 }
+
+// upgradeSweepConfig upgrades the base recipe of a sweep to the current
+// recipe version.
+func upgradeSweepConfig(config sweep.SweepConfig) (sweep.SweepConfig, error) {
+	data, err := json.Marshal(config.BaseRecipe)
+	if err != nil {
+		return config, fmt.Errorf("marshal base recipe: %w", err)
+	}
+	normalized, err := recipe.Normalize(data)
+	if err != nil {
+		return config, fmt.Errorf("base recipe: %w", err)
+	}
+	var base sweep.Recipe
+	if err := json.Unmarshal(normalized, &base); err != nil {
+		return config, fmt.Errorf("unmarshal base recipe: %w", err)
+	}
+	config.BaseRecipe = base
+	return config, nil
+}
+
+// validateSweepConfig upgrades the base recipe of a sweep about to be stored
+// or run and rejects swept parameters the current recipe does not have.
+func validateSweepConfig(config sweep.SweepConfig) (sweep.SweepConfig, error) {
+	config, err := upgradeSweepConfig(config)
+	if err != nil {
+		return config, err
+	}
+	for _, param := range config.Parameters {
+		paramType, ok := recipe.ParameterType(param.Name)
+		if !ok {
+			return config, fmt.Errorf("parameter %s: unknown recipe parameter", param.Name)
+		}
+		if !sweepMatchesType(param.Type, paramType) {
+			return config, fmt.Errorf("parameter %s: a %s sweep cannot set a %s parameter", param.Name, param.Type, paramType)
+		}
+	}
+	return config, nil
+}
+
+// sweepMatchesType reports whether a sweep of the given type sets values of
+// a recipe parameter of the given JSON Schema type. Number sweeps may set
+// integer parameters, which truncate them.
+func sweepMatchesType(sweepType, paramType string) bool {
+	switch sweepType {
+	case "bool":
+		return paramType == "boolean"
+	case "categorical":
+		return paramType == "string"
+	default:
+		return paramType == "number" || paramType == "integer"
+	}
+}
+
+// upgradeSweep upgrades the base recipe stored in a sweep record.
+func upgradeSweep(record sweeps.Sweep, err error) (sweeps.Sweep, error) {
+	if err != nil {
+		return record, err
+	}
+	var config sweep.SweepConfig
+	if err := json.Unmarshal([]byte(record.ConfigJson), &config); err != nil {
+		return sweeps.Sweep{}, fmt.Errorf("sweep %s: unmarshal config: %w", record.Name, err)
+	}
+	config, err = upgradeSweepConfig(config)
+	if err != nil {
+		return sweeps.Sweep{}, fmt.Errorf("sweep %s: %w", record.Name, err)
+	}
+
+	// Stored sweeps may still vary parameters the upgrade dropped
+	params := config.Parameters[:0]
+	for _, param := range config.Parameters {
+		if recipe.IsParameter(param.Name) {
+			params = append(params, param)
+		}
+	}
+	config.Parameters = params
+	data, err := json.Marshal(config)
+	if err != nil {
+		return sweeps.Sweep{}, fmt.Errorf("sweep %s: marshal config: %w", record.Name, err)
+	}
+	record.ConfigJson = string(data)
+	return record, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"io"
 	"log/slog"
 	"reflect"
@@ -91,7 +92,7 @@ func TestSweepConfigService_Get(t *testing.T) {
 		ID:          1,
 		Name:        "test_sweep",
 		Description: sql.NullString{String: "Test sweep", Valid: true},
-		ConfigJson:  `{"name":"test_sweep"}`,
+		ConfigJson:  `{"name":"test_sweep","description":"","base_recipe":{"version":"1.2","name":"advanced","parameters":{"alpha":0.1}},"parameters":[]}`,
 		CreatedAt:   "2025-01-01 00:00:00",
 		UpdatedAt:   "2025-01-01 00:00:00",
 		CreatedBy:   sql.NullString{String: "test_user", Valid: true},
@@ -126,7 +127,7 @@ func TestSweepConfigService_GetByName(t *testing.T) {
 		ID:          1,
 		Name:        "test_sweep",
 		Description: sql.NullString{String: "Test sweep", Valid: true},
-		ConfigJson:  `{"name":"test_sweep"}`,
+		ConfigJson:  `{"name":"test_sweep","description":"","base_recipe":{"version":"1.2","name":"advanced","parameters":{"alpha":0.1}},"parameters":[]}`,
 		CreatedAt:   "2025-01-01 00:00:00",
 		UpdatedAt:   "2025-01-01 00:00:00",
 		CreatedBy:   sql.NullString{String: "test_user", Valid: true},
@@ -162,7 +163,7 @@ func TestSweepConfigService_List(t *testing.T) {
 			ID:          1,
 			Name:        "test_sweep_1",
 			Description: sql.NullString{String: "Test sweep 1", Valid: true},
-			ConfigJson:  `{"name":"test_sweep_1"}`,
+			ConfigJson:  `{"name":"test_sweep_1","description":"","base_recipe":{"version":"1.2","name":"advanced","parameters":{"alpha":0.1}},"parameters":[]}`,
 			CreatedAt:   "2025-01-01 00:00:00",
 			UpdatedAt:   "2025-01-01 00:00:00",
 			CreatedBy:   sql.NullString{String: "test_user", Valid: true},
@@ -173,7 +174,7 @@ func TestSweepConfigService_List(t *testing.T) {
 			ID:          2,
 			Name:        "test_sweep_2",
 			Description: sql.NullString{String: "Test sweep 2", Valid: true},
-			ConfigJson:  `{"name":"test_sweep_2"}`,
+			ConfigJson:  `{"name":"test_sweep_2","description":"","base_recipe":{"version":"1.2","name":"advanced","parameters":{"alpha":0.1}},"parameters":[]}`,
 			CreatedAt:   "2025-01-02 00:00:00",
 			UpdatedAt:   "2025-01-02 00:00:00",
 			CreatedBy:   sql.NullString{String: "test_user", Valid: true},
@@ -369,3 +370,96 @@ func TestSweepConfigService_Update_InvalidConfig(t *testing.T) {
 		t.Fatal("expected validation error, got nil")
 	}
 }
+
+func TestSweepConfigService_Get_UpgradesSeededSweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	service := NewSweepConfigService(mockQueries, nil, logger)
+
+	// Seeded example sweeps use 1.0 keys the engine never read
+	mockQueries.EXPECT().
+		GetSweep(gomock.Any(), int64(1)).
+		Return(sweeps.Sweep{
+			ID:         1,
+			Name:       "multi_param_sweep",
+			ConfigJson: `{"name":"multi_param_sweep","base_recipe":{"version":"1.0","name":"advanced","parameters":{"alpha":0.1,"sim_prev_max":100,"sim_count":1000,"scorer_type":"frequency"}},"parameters":[{"name":"alpha","type":"discrete","values":{"values":[0.1,0.2]}},{"name":"sim_count","type":"discrete","values":{"values":[100,1000]}}]}`,
+		}, nil)
+
+	result, err := service.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config sweep.SweepConfig
+	if err := json.Unmarshal([]byte(result.ConfigJson), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.BaseRecipe.Version != "1.2" {
+		t.Errorf("expected base recipe version 1.2, got %q", config.BaseRecipe.Version)
+	}
+	if _, ok := config.BaseRecipe.Parameters["sim_count"]; ok {
+		t.Error("expected sim_count to be dropped from the base recipe")
+	}
+	if len(config.Parameters) != 1 || config.Parameters[0].Name != "alpha" {
+		t.Errorf("expected only the alpha sweep to remain, got %+v", config.Parameters)
+	}
+}
+
+func TestSweepConfigService_Create_UnknownParameter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	service := NewSweepConfigService(mockQueries, nil, logger)
+
+	req := CreateSweepConfigRequest{
+		Name: "bad_sweep",
+		Config: sweep.SweepConfig{
+			Name: "bad_sweep",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.2",
+				Name:       "advanced",
+				Parameters: map[string]any{"alpha": 0.1},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "sim_count", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{100, 1000}}},
+			},
+		},
+	}
+
+	if _, err := service.Create(context.Background(), req); err == nil {
+		t.Fatal("expected error for unknown swept parameter, got nil")
+	}
+}
+
+func TestValidateSweepConfig_ParameterTypes(t *testing.T) {
+	base := sweep.Recipe{Version: "1.2", Name: "advanced", Parameters: map[string]any{"alpha": 0.1}}
+
+	tests := []struct {
+		name    string
+		param   sweep.ParameterSweep
+		wantErr bool
+	}{
+		{"int range on integer", sweep.ParameterSweep{Name: "sim_preds", Type: "int_range", Values: sweep.IntRangeValues{Min: 5, Max: 10, Step: 5}}, false},
+		{"bool on boolean", sweep.ParameterSweep{Name: "enableEvolutionary", Type: "bool", Values: sweep.BoolValues{}}, false},
+		{"discrete on integer", sweep.ParameterSweep{Name: "sim_preds", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{5}}}, false},
+		{"bool on number", sweep.ParameterSweep{Name: "alpha", Type: "bool", Values: sweep.BoolValues{}}, true},
+		{"categorical on integer", sweep.ParameterSweep{Name: "sim_preds", Type: "categorical", Values: sweep.CategoricalValues{Values: []string{"a"}}}, true},
+		{"range on boolean", sweep.ParameterSweep{Name: "enableEvolutionary", Type: "range", Values: sweep.RangeValues{Min: 0, Max: 1, Step: 1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateSweepConfig(sweep.SweepConfig{Name: "typed", BaseRecipe: base, Parameters: []sweep.ParameterSweep{tt.param}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSweepConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"testing"
@@ -43,7 +46,9 @@ func TestSweepService_CreateSweep(t *testing.T) {
 			started_at TEXT,
 			finished_at TEXT,
 			run_duration_ms INTEGER,
-			created_by TEXT
+			created_by TEXT,
+			best_metric TEXT,
+			best_json TEXT
 		);
 		CREATE TABLE sweep_simulations (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -61,6 +66,10 @@ func TestSweepService_CreateSweep(t *testing.T) {
 			end_contest INTEGER NOT NULL,
 			status TEXT NOT NULL DEFAULT 'pending',
 			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+			started_at TEXT,
+			finished_at TEXT,
+			run_duration_ms INTEGER,
+			summary_json TEXT,
 			created_by TEXT
 		);
 	`)
@@ -110,7 +119,12 @@ func TestSweepService_CreateSweep(t *testing.T) {
 
 	mockSimService.EXPECT().
 		CreateSimulation(gomock.Any(), gomock.Any()).
-		Return(&expectedSim, nil).
+		DoAndReturn(func(ctx context.Context, req CreateSimulationRequest) (*simulations.Simulation, error) {
+			if req.Priority != SweepSimulationPriority {
+				t.Errorf("Expected sweep priority %d, got %d", SweepSimulationPriority, req.Priority)
+			}
+			return &expectedSim, nil
+		}).
 		Times(3) // 3 combinations: alpha=0.0, 0.5, 1.0
 
 	// Execute
@@ -236,7 +250,9 @@ func TestSweepService_GetVisualizationData(t *testing.T) {
 			started_at TEXT,
 			finished_at TEXT,
 			run_duration_ms INTEGER,
-			created_by TEXT
+			created_by TEXT,
+			best_metric TEXT,
+			best_json TEXT
 		);
 		CREATE TABLE sweep_simulations (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -423,7 +439,9 @@ func TestSweepService_GetVisualizationData_DefaultMetrics(t *testing.T) {
 			started_at TEXT,
 			finished_at TEXT,
 			run_duration_ms INTEGER,
-			created_by TEXT
+			created_by TEXT,
+			best_metric TEXT,
+			best_json TEXT
 		);
 		CREATE TABLE sweep_simulations (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -801,7 +819,7 @@ func TestSweepService_convertToServiceRecipe(t *testing.T) {
 				},
 			},
 			expected: Recipe{
-				Version: "1.0",
+				Version: "1.2",
 				Name:    "test_var_0",
 				Parameters: RecipeParameters{
 					Alpha:      0.5,
@@ -823,7 +841,7 @@ func TestSweepService_convertToServiceRecipe(t *testing.T) {
 				},
 			},
 			expected: Recipe{
-				Version: "1.0",
+				Version: "1.2",
 				Name:    "test_var_1",
 				Parameters: RecipeParameters{
 					SimPrevMax: 100,
@@ -831,6 +849,49 @@ func TestSweepService_convertToServiceRecipe(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with typed params",
+			recipe: sweep.GeneratedRecipe{
+				ID:   "test_2",
+				Name: "test_var_2",
+				Parameters: map[string]any{
+					"sim_preds":          15,
+					"enableEvolutionary": true,
+				},
+			},
+			expected: Recipe{
+				Version: "1.2",
+				Name:    "test_var_2",
+				Parameters: RecipeParameters{
+					SimPrevMax:         10,
+					SimPreds:           15,
+					EnableEvolutionary: true,
+				},
+			},
+		},
+		{
+			name: "with monte carlo and fixed tickets",
+			recipe: sweep.GeneratedRecipe{
+				ID:   "test_3",
+				Name: "test_var_3",
+				Parameters: map[string]any{
+					"seeds":            8,
+					"confidence_level": 0.9,
+					"fixed_tickets":    []any{[]any{1.0, 2.0, 3.0, 4.0, 5.0}},
+				},
+			},
+			expected: Recipe{
+				Version: "1.2",
+				Name:    "test_var_3",
+				Parameters: RecipeParameters{
+					SimPrevMax:      10,
+					SimPreds:        5,
+					Seeds:           8,
+					ConfidenceLevel: 0.9,
+					FixedTickets:    [][]int{{1, 2, 3, 4, 5}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -854,6 +915,18 @@ func TestSweepService_convertToServiceRecipe(t *testing.T) {
 			if result.Parameters.SimPreds != tt.expected.Parameters.SimPreds {
 				t.Errorf("Expected simPreds %d, got %d", tt.expected.Parameters.SimPreds, result.Parameters.SimPreds)
 			}
+			if result.Parameters.EnableEvolutionary != tt.expected.Parameters.EnableEvolutionary {
+				t.Errorf("Expected enableEvolutionary %v, got %v", tt.expected.Parameters.EnableEvolutionary, result.Parameters.EnableEvolutionary)
+			}
+			if result.Parameters.Seeds != tt.expected.Parameters.Seeds {
+				t.Errorf("Expected seeds %d, got %d", tt.expected.Parameters.Seeds, result.Parameters.Seeds)
+			}
+			if result.Parameters.ConfidenceLevel != tt.expected.Parameters.ConfidenceLevel {
+				t.Errorf("Expected confidence level %f, got %f", tt.expected.Parameters.ConfidenceLevel, result.Parameters.ConfidenceLevel)
+			}
+			if fmt.Sprint(result.Parameters.FixedTickets) != fmt.Sprint(tt.expected.Parameters.FixedTickets) {
+				t.Errorf("Expected fixed tickets %v, got %v", tt.expected.Parameters.FixedTickets, result.Parameters.FixedTickets)
+			}
 		})
 	}
 }
@@ -971,3 +1044,240 @@ func TestSweepService_CreateSweep_InvalidConfig(t *testing.T) {
 		t.Error("Expected error for invalid config")
 	}
 }
+
+func TestSweepService_OnSimulationStarted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, NewMockSimulationServicer(ctrl), logger)
+
+	mockQueries.EXPECT().
+		GetSweepJobIDBySimulation(gomock.Any(), int64(100)).
+		Return(int64(1), nil)
+	mockQueries.EXPECT().
+		GetSweepJob(gomock.Any(), int64(1)).
+		Return(sweep_execution.SweepJob{ID: 1, Status: "pending", TotalCombinations: 2}, nil)
+	mockQueries.EXPECT().
+		GetSweepSimulationDetails(gomock.Any(), int64(1)).
+		Return([]sweep_execution.GetSweepSimulationDetailsRow{
+			{SimulationID: 100, Status: "running"},
+			{SimulationID: 101, Status: "pending"},
+		}, nil)
+	mockQueries.EXPECT().
+		UpdateSweepJobProgress(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg sweep_execution.UpdateSweepJobProgressParams) error {
+			if arg.Status != "running" {
+				t.Errorf("Expected status running, got %s", arg.Status)
+			}
+			if !arg.StartedAt.Valid {
+				t.Errorf("Expected started_at to be set")
+			}
+			return nil
+		})
+
+	if err := service.OnSimulationStarted(context.Background(), 100); err != nil {
+		t.Fatalf("OnSimulationStarted failed: %v", err)
+	}
+}
+
+func TestSweepService_OnSimulationFinished_CachesBest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	mockSimSvc := NewMockSimulationServicer(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, mockSimSvc, logger)
+	if err := service.SetBestMetric("quina_rate"); err != nil {
+		t.Fatalf("SetBestMetric failed: %v", err)
+	}
+
+	sweepJob := sweep_execution.SweepJob{
+		ID:                1,
+		Status:            "running",
+		TotalCombinations: 2,
+		StartedAt:         sql.NullString{String: "2025-11-22T10:00:00Z", Valid: true},
+	}
+	details := []sweep_execution.GetSweepSimulationDetailsRow{
+		{
+			SimulationID:    100,
+			Status:          "completed",
+			SummaryJson:     sql.NullString{String: `{"hitRateQuina":0.05,"totalContests":100}`, Valid: true},
+			VariationParams: `{"alpha":0.0}`,
+		},
+		{
+			SimulationID:    101,
+			Status:          "completed",
+			SummaryJson:     sql.NullString{String: `{"hitRateQuina":0.08,"totalContests":100}`, Valid: true},
+			VariationParams: `{"alpha":0.5}`,
+		},
+	}
+
+	mockQueries.EXPECT().
+		GetSweepJobIDBySimulation(gomock.Any(), int64(101)).
+		Return(int64(1), nil)
+	gomock.InOrder(
+		mockQueries.EXPECT().GetSweepJob(gomock.Any(), int64(1)).Return(sweepJob, nil),
+		mockQueries.EXPECT().GetSweepJob(gomock.Any(), int64(1)).DoAndReturn(
+			func(context.Context, int64) (sweep_execution.SweepJob, error) {
+				finished := sweepJob
+				finished.Status = "completed"
+				return finished, nil
+			}),
+	)
+	mockQueries.EXPECT().
+		GetSweepSimulationDetails(gomock.Any(), int64(1)).
+		Return(details, nil).
+		Times(2)
+	mockQueries.EXPECT().
+		UpdateSweepJobProgress(gomock.Any(), gomock.Any()).
+		Return(nil)
+	mockQueries.EXPECT().
+		FinishSweepJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg sweep_execution.FinishSweepJobParams) error {
+			if arg.Status != "completed" {
+				t.Errorf("Expected status completed, got %s", arg.Status)
+			}
+			if arg.CompletedSimulations.Int64 != 2 {
+				t.Errorf("Expected 2 completed simulations, got %d", arg.CompletedSimulations.Int64)
+			}
+			if !arg.FinishedAt.Valid || arg.RunDurationMs.Int64 <= 0 {
+				t.Errorf("Expected finish time and duration, got %+v", arg)
+			}
+			return nil
+		})
+	mockSimSvc.EXPECT().
+		GetSimulation(gomock.Any(), int64(101)).
+		Return(&simulations.Simulation{ID: 101, RecipeJson: `{"version":"1.0","name":"test","parameters":{"alpha":0.5}}`}, nil)
+	mockQueries.EXPECT().
+		CacheSweepBest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg sweep_execution.CacheSweepBestParams) error {
+			if arg.BestMetric.String != "quina_rate" {
+				t.Errorf("Expected best metric quina_rate, got %s", arg.BestMetric.String)
+			}
+			var best BestConfiguration
+			if err := json.Unmarshal([]byte(arg.BestJson.String), &best); err != nil {
+				t.Fatalf("Invalid best json: %v", err)
+			}
+			if best.SimulationID != 101 {
+				t.Errorf("Expected best simulation 101, got %d", best.SimulationID)
+			}
+			return nil
+		})
+
+	if err := service.OnSimulationFinished(context.Background(), 101); err != nil {
+		t.Fatalf("OnSimulationFinished failed: %v", err)
+	}
+}
+
+func TestSweepService_OnSimulationFinished_NotInSweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, NewMockSimulationServicer(ctrl), logger)
+
+	mockQueries.EXPECT().
+		GetSweepJobIDBySimulation(gomock.Any(), int64(7)).
+		Return(int64(0), sql.ErrNoRows)
+
+	if err := service.OnSimulationFinished(context.Background(), 7); err != nil {
+		t.Fatalf("OnSimulationFinished failed: %v", err)
+	}
+}
+
+func TestSweepService_UpdateSweepProgress_CancelledSweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, NewMockSimulationServicer(ctrl), logger)
+
+	mockQueries.EXPECT().
+		GetSweepJob(gomock.Any(), int64(1)).
+		Return(sweep_execution.SweepJob{ID: 1, Status: "cancelled", TotalCombinations: 2}, nil)
+	mockQueries.EXPECT().
+		GetSweepSimulationDetails(gomock.Any(), int64(1)).
+		Return([]sweep_execution.GetSweepSimulationDetailsRow{
+			{Status: "cancelled"},
+			{Status: "completed"},
+		}, nil)
+
+	// Neither progress nor finish is written for a cancelled sweep
+	if err := service.UpdateSweepProgress(context.Background(), 1); err != nil {
+		t.Fatalf("UpdateSweepProgress failed: %v", err)
+	}
+}
+
+func TestSweepService_CancelSweep_Finished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, NewMockSimulationServicer(ctrl), logger)
+
+	mockQueries.EXPECT().
+		GetSweepJob(gomock.Any(), int64(1)).
+		Return(sweep_execution.SweepJob{ID: 1, Status: "completed", FinishedAt: sql.NullString{String: "2025-01-01T00:00:00Z", Valid: true}}, nil)
+	mockQueries.EXPECT().
+		GetSweepJob(gomock.Any(), int64(2)).
+		Return(sweep_execution.SweepJob{}, sql.ErrNoRows)
+
+	if _, err := service.CancelSweep(context.Background(), 1); !errors.Is(err, ErrSweepNotCancellable) {
+		t.Errorf("expected ErrSweepNotCancellable, got %v", err)
+	}
+	if _, err := service.CancelSweep(context.Background(), 2); !errors.Is(err, ErrSweepNotFound) {
+		t.Errorf("expected ErrSweepNotFound, got %v", err)
+	}
+}
+
+func TestSweepService_FindBest_Cached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := sweepmock.NewMockQuerier(ctrl)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	service := NewSweepService(mockQueries, nil, NewMockSimulationServicer(ctrl), logger)
+
+	mockQueries.EXPECT().
+		GetSweepJob(gomock.Any(), int64(1)).
+		Return(sweep_execution.SweepJob{
+			ID:         1,
+			Status:     "completed",
+			BestMetric: sql.NullString{String: "quina_rate", Valid: true},
+			BestJson:   sql.NullString{String: `{"sweep_id":1,"simulation_id":101,"rank":1}`, Valid: true},
+		}, nil)
+	mockQueries.EXPECT().
+		GetSweepSimulationDetails(gomock.Any(), int64(1)).
+		Return(nil, nil)
+
+	// The simulation service is not consulted for a cached result
+	best, err := service.FindBest(context.Background(), 1, "quina_rate")
+	if err != nil {
+		t.Fatalf("FindBest failed: %v", err)
+	}
+	if best.SimulationID != 101 {
+		t.Errorf("Expected cached best simulation 101, got %d", best.SimulationID)
+	}
+}
+
+func TestSweepService_SetBestMetric_Invalid(t *testing.T) {
+	service := &SweepService{}
+	if err := service.SetBestMetric("luck"); err == nil {
+		t.Fatal("Expected error for invalid metric")
+	}
+	if err := service.SetBestMetric(""); err != nil {
+		t.Errorf("Expected empty metric to disable caching, got %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/pkg/predictor"
+)
+
+// ErrInvalidTicket is returned when a ticket request fails validation.
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+const (
+	minTicketNumbers = 5
+	maxTicketNumbers = 15
+	maxQuinaNumber   = 80
+)
+
+// prizeTiers are the Quina prize tiers, best first. Placed tickets and
+// checked tickets are paid by the same tiers.
+var prizeTiers = []struct {
+	name string
+	hits int
+}{
+	{"quina", 5},
+	{"quadra", 4},
+	{"terno", 3},
+	{"duque", 2},
+}
+
+type TicketServicer interface {
+	CreateTicket(ctx context.Context, req CreateTicketRequest) (*finances.Ticket, error)
+	GetTicket(ctx context.Context, id int64) (*TicketDetail, error)
+	ListTickets(ctx context.Context, owner string, limit, offset int) ([]finances.Ticket, error)
+	CheckContest(ctx context.Context, contest int) (int, error)
+}
+
+// TicketService records tickets placed on real contests and settles them as
+// the corresponding draws are imported.
+type TicketService struct {
+	financesQueries finances.Querier
+	financesDB      *sql.DB
+	resultsQueries  results.Querier
+	financial       *FinancialService
+	scorer          *predictor.ScorerImpl
+	logger          *slog.Logger
+}
+
+func NewTicketService(
+	financesQueries finances.Querier,
+	financesDB *sql.DB,
+	resultsQueries results.Querier,
+	logger *slog.Logger,
+) *TicketService {
+	return &TicketService{
+		financesQueries: financesQueries,
+		financesDB:      financesDB,
+		resultsQueries:  resultsQueries,
+		financial:       NewFinancialService(financesQueries, financesDB, logger),
+		scorer:          predictor.NewScorer(),
+		logger:          logger,
+	}
+}
+
+type CreateTicketRequest struct {
+	Owner        string `json:"owner"`
+	Numbers      []int  `json:"numbers"`
+	FirstContest int    `json:"first_contest"`
+	LastContest  int    `json:"last_contest"` // Defaults to FirstContest
+	CostCents    int64  `json:"cost_cents"`   // Defaults to the official bet cost
+	PredictionID int64  `json:"prediction_id"`
+	Notes        string `json:"notes"`
+}
+
+// TicketDetail is a ticket together with its per-contest results.
+type TicketDetail struct {
+	Ticket finances.Ticket        `json:"ticket"`
+	Checks []finances.TicketCheck `json:"checks"`
+}
+
+func (s *TicketService) CreateTicket(ctx context.Context, req CreateTicketRequest) (*finances.Ticket, error) {
+	if req.LastContest == 0 {
+		req.LastContest = req.FirstContest
+	}
+	if err := validateTicketRequest(&req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTicket, err)
+	}
+
+	contests := int64(req.LastContest - req.FirstContest + 1)
+	cost := req.CostCents
+	if cost == 0 {
+		betCost, err := s.financial.GetBetCost(ctx, time.Now(), minTicketNumbers, "BR")
+		if err != nil {
+			return nil, fmt.Errorf("get bet cost: %w", err)
+		}
+		cost = betCost * binomial(len(req.Numbers), minTicketNumbers) * contests
+	}
+
+	numbersJSON, err := json.Marshal(req.Numbers)
+	if err != nil {
+		return nil, fmt.Errorf("marshal numbers: %w", err)
+	}
+
+	ticket, err := s.financesQueries.CreateTicket(ctx, finances.CreateTicketParams{
+		Owner:        req.Owner,
+		Numbers:      string(numbersJSON),
+		FirstContest: int64(req.FirstContest),
+		LastContest:  int64(req.LastContest),
+		CostCents:    cost,
+		PredictionID: sql.NullInt64{Int64: req.PredictionID, Valid: req.PredictionID != 0},
+		Notes:        sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create ticket: %w", err)
+	}
+
+	// Settle right away any contest that has already been drawn
+	for contest := req.FirstContest; contest <= req.LastContest; contest++ {
+		draw, err := s.resultsQueries.GetDraw(ctx, int64(contest))
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get draw: %w", err)
+		}
+		if err := s.checkTicket(ctx, ticket, draw); err != nil {
+			return nil, err
+		}
+	}
+
+	if ticket, err = s.financesQueries.GetTicket(ctx, ticket.ID); err != nil {
+		return nil, fmt.Errorf("reload ticket: %w", err)
+	}
+
+	return &ticket, nil
+}
+
+func validateTicketRequest(req *CreateTicketRequest) error {
+	if req.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if err := validateTicketNumbers(req.Numbers); err != nil {
+		return err
+	}
+	sort.Ints(req.Numbers)
+	if req.FirstContest <= 0 {
+		return fmt.Errorf("first_contest must be positive")
+	}
+	if req.LastContest < req.FirstContest {
+		return fmt.Errorf("last_contest must not be before first_contest")
+	}
+	if req.CostCents < 0 {
+		return fmt.Errorf("cost_cents must be non-negative")
+	}
+	return nil
+}
+
+// validateTicketNumbers checks that numbers form a playable Quina ticket.
+func validateTicketNumbers(numbers []int) error {
+	if len(numbers) < minTicketNumbers || len(numbers) > maxTicketNumbers {
+		return fmt.Errorf("a ticket must have between %d and %d numbers", minTicketNumbers, maxTicketNumbers)
+	}
+	seen := make(map[int]bool, len(numbers))
+	for _, n := range numbers {
+		if n < 1 || n > maxQuinaNumber {
+			return fmt.Errorf("number %d out of range 1-%d", n, maxQuinaNumber)
+		}
+		if seen[n] {
+			return fmt.Errorf("duplicate number %d", n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+func (s *TicketService) GetTicket(ctx context.Context, id int64) (*TicketDetail, error) {
+	ticket, err := s.financesQueries.GetTicket(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	checks, err := s.financesQueries.GetTicketChecks(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket checks: %w", err)
+	}
+
+	return &TicketDetail{Ticket: ticket, Checks: checks}, nil
+}
+
+func (s *TicketService) ListTickets(ctx context.Context, owner string, limit, offset int) ([]finances.Ticket, error) {
+	if owner != "" {
+		return s.financesQueries.ListTicketsByOwner(ctx, finances.ListTicketsByOwnerParams{
+			Owner:  owner,
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	}
+	return s.financesQueries.ListTickets(ctx, finances.ListTicketsParams{
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+}
+
+// CheckContest settles every pending ticket covering contest and returns how
+// many tickets were checked. A ticket that fails does not stop the others;
+// the failures are returned together.
+func (s *TicketService) CheckContest(ctx context.Context, contest int) (int, error) {
+	pending, err := s.financesQueries.ListPendingTicketsForContest(ctx, finances.ListPendingTicketsForContestParams{
+		FirstContest: int64(contest),
+		LastContest:  int64(contest),
+		Contest:      int64(contest),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list pending tickets: %w", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	draw, err := s.resultsQueries.GetDraw(ctx, int64(contest))
+	if err != nil {
+		return 0, fmt.Errorf("get draw: %w", err)
+	}
+
+	checked := 0
+	var errs []error
+	for _, ticket := range pending {
+		if err := s.checkTicket(ctx, ticket, draw); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		checked++
+	}
+
+	s.logger.Info("tickets checked", "contest", contest, "count", checked, "failed", len(errs))
+	return checked, errors.Join(errs...)
+}
+
+// OnDrawsImported implements DrawObserver. Every contest is checked, even
+// after one fails.
+func (s *TicketService) OnDrawsImported(ctx context.Context, contests []int) error {
+	var errs []error
+	for _, contest := range contests {
+		if _, err := s.CheckContest(ctx, contest); err != nil {
+			errs = append(errs, fmt.Errorf("check contest %d: %w", contest, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkTicket scores a ticket against one draw and, in a single transaction,
+// records the result, accumulates the prize and writes the ledger entries.
+func (s *TicketService) checkTicket(ctx context.Context, ticket finances.Ticket, draw results.Draw) error {
+	var numbers []int
+	if err := json.Unmarshal([]byte(ticket.Numbers), &numbers); err != nil {
+		return fmt.Errorf("unmarshal ticket %d numbers: %w", ticket.ID, err)
+	}
+
+	contest := int(draw.Contest)
+	actual := []int{int(draw.Bola1), int(draw.Bola2), int(draw.Bola3), int(draw.Bola4), int(draw.Bola5)}
+	score := s.scorer.ScorePredictions([]predictor.Prediction{{Numbers: numbers}}, actual)
+	matched := matchedNumbers(numbers, actual)
+
+	prizeType, prizeCents, err := s.ticketPrize(ctx, contest, len(numbers), score.BestHits)
+	if err != nil {
+		return err
+	}
+
+	matchedJSON, _ := json.Marshal(matched)
+	metadataJSON, _ := json.Marshal(map[string]any{"ticket_id": ticket.ID, "owner": ticket.Owner})
+	date := draw.DrawDate
+	if date == "" {
+		date = time.Now().Format(time.RFC3339)
+	}
+
+	tx, err := s.financesDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	txQueries := finances.New(tx)
+
+	if _, err := txQueries.CreateTicketCheck(ctx, finances.CreateTicketCheckParams{
+		TicketID:       ticket.ID,
+		Contest:        int64(contest),
+		Hits:           int64(score.BestHits),
+		MatchedNumbers: string(matchedJSON),
+		PrizeType:      sql.NullString{String: prizeType, Valid: prizeType != ""},
+		PrizeCents:     prizeCents,
+	}); err != nil {
+		return fmt.Errorf("create ticket check: %w", err)
+	}
+
+	if err := txQueries.AddTicketPrize(ctx, finances.AddTicketPrizeParams{
+		TotalPrizeCents: prizeCents,
+		ID:              ticket.ID,
+	}); err != nil {
+		return fmt.Errorf("add ticket prize: %w", err)
+	}
+
+	if _, err := txQueries.CreateLedgerEntry(ctx, finances.CreateLedgerEntryParams{
+		TransactionDate: date,
+		TransactionType: "bet",
+		AmountCents:     -ticketContestCost(ticket, contest),
+		Contest:         sql.NullInt64{Int64: int64(contest), Valid: true},
+		Description:     sql.NullString{String: fmt.Sprintf("Ticket %d for contest %d", ticket.ID, contest), Valid: true},
+		MetadataJson:    sql.NullString{String: string(metadataJSON), Valid: true},
+	}); err != nil {
+		return fmt.Errorf("create bet ledger entry: %w", err)
+	}
+
+	if prizeCents > 0 {
+		if _, err := txQueries.CreateLedgerEntry(ctx, finances.CreateLedgerEntryParams{
+			TransactionDate: date,
+			TransactionType: "prize",
+			AmountCents:     prizeCents,
+			Contest:         sql.NullInt64{Int64: int64(contest), Valid: true},
+			Description:     sql.NullString{String: fmt.Sprintf("%s win for ticket %d in contest %d", prizeType, ticket.ID, contest), Valid: true},
+			MetadataJson:    sql.NullString{String: string(metadataJSON), Valid: true},
+		}); err != nil {
+			return fmt.Errorf("create prize ledger entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	s.logger.Info("ticket checked", "ticket_id", ticket.ID, "contest", contest, "hits", score.BestHits, "prize_cents", prizeCents)
+	return nil
+}
+
+// ticketPrize returns the best prize tier and the total prize of a ticket with
+// size numbers and the given hits. Tickets with more than five numbers play
+// every five-number combination, so each combination is paid separately.
+func (s *TicketService) ticketPrize(ctx context.Context, contest, size, hits int) (string, int64, error) {
+	var bestTier string
+	var total int64
+	for _, tier := range prizeTiers {
+		if hits < tier.hits {
+			continue
+		}
+		combos := winningCombinations(size, hits, tier.hits)
+		if combos == 0 {
+			continue
+		}
+		amount, err := s.financial.GetPrize(ctx, contest, tier.name)
+		if err != nil {
+			return "", 0, fmt.Errorf("get prize: %w", err)
+		}
+		if bestTier == "" {
+			bestTier = tier.name
+		}
+		total += combos * amount
+	}
+
+	return bestTier, total, nil
+}
+
+// ticketContestCost splits the ticket cost evenly across its contests, adding
+// any remainder to the first contest.
+func ticketContestCost(ticket finances.Ticket, contest int) int64 {
+	contests := ticket.LastContest - ticket.FirstContest + 1
+	share := ticket.CostCents / contests
+	if int64(contest) == ticket.FirstContest {
+		share += ticket.CostCents % contests
+	}
+	return share
+}
+
+func matchedNumbers(numbers, actual []int) []int {
+	drawn := make(map[int]bool, len(actual))
+	for _, n := range actual {
+		drawn[n] = true
+	}
+	matched := []int{}
+	for _, n := range numbers {
+		if drawn[n] {
+			matched = append(matched, n)
+		}
+	}
+	return matched
+}
+
+// winningCombinations returns how many five-number combinations of a ticket
+// with size numbers and the given hits match exactly tierHits numbers.
+func winningCombinations(size, hits, tierHits int) int64 {
+	return binomial(hits, tierHits) * binomial(size-hits, minTicketNumbers-tierHits)
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) int64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := int64(1)
+	for i := 1; i <= k; i++ {
+		result = result * int64(n-k+i) / int64(i)
+	}
+	return result
+}
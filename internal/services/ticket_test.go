@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+	financesmock "github.com/garnizeh/luckyfive/internal/store/finances/mock"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	resultsmock "github.com/garnizeh/luckyfive/internal/store/results/mock"
+)
+
+func TestTicketService_CreateTicket_Validation(t *testing.T) {
+	svc := NewTicketService(nil, nil, nil, createTestLogger())
+
+	tests := []struct {
+		name string
+		req  CreateTicketRequest
+	}{
+		{"missing owner", CreateTicketRequest{Numbers: []int{1, 2, 3, 4, 5}, FirstContest: 10}},
+		{"too few numbers", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4}, FirstContest: 10}},
+		{"too many numbers", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, FirstContest: 10}},
+		{"out of range", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4, 81}, FirstContest: 10}},
+		{"duplicate", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4, 4}, FirstContest: 10}},
+		{"missing contest", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4, 5}}},
+		{"reversed contests", CreateTicketRequest{Owner: "a", Numbers: []int{1, 2, 3, 4, 5}, FirstContest: 10, LastContest: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateTicket(context.Background(), tt.req)
+			if !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("expected ErrInvalidTicket, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTicketService_CheckContest_NoPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFinances := financesmock.NewMockQuerier(ctrl)
+	svc := NewTicketService(mockFinances, nil, nil, createTestLogger())
+
+	mockFinances.EXPECT().ListPendingTicketsForContest(gomock.Any(), finances.ListPendingTicketsForContestParams{
+		FirstContest: 42, LastContest: 42, Contest: 42,
+	}).Return(nil, nil)
+
+	n, err := svc.CheckContest(context.Background(), 42)
+	if err != nil || n != 0 {
+		t.Errorf("expected 0 tickets checked, got %d (%v)", n, err)
+	}
+}
+
+func TestTicketService_CheckContest_ChecksEveryTicket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFinances := financesmock.NewMockQuerier(ctrl)
+	mockResults := resultsmock.NewMockQuerier(ctrl)
+	svc := NewTicketService(mockFinances, nil, mockResults, createTestLogger())
+
+	mockFinances.EXPECT().ListPendingTicketsForContest(gomock.Any(), gomock.Any()).Return([]finances.Ticket{
+		{ID: 1, Numbers: "broken"},
+		{ID: 2, Numbers: "[1,2"},
+	}, nil)
+	mockResults.EXPECT().GetDraw(gomock.Any(), int64(42)).Return(results.Draw{Contest: 42}, nil)
+
+	n, err := svc.CheckContest(context.Background(), 42)
+	if n != 0 || err == nil {
+		t.Fatalf("expected no ticket checked and an error, got %d (%v)", n, err)
+	}
+	for _, want := range []string{"ticket 1", "ticket 2"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the failure of %s in %q", want, err)
+		}
+	}
+}
+
+func TestTicketService_OnDrawsImported_ChecksEveryContest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFinances := financesmock.NewMockQuerier(ctrl)
+	svc := NewTicketService(mockFinances, nil, nil, createTestLogger())
+
+	listErr := errors.New("database is locked")
+	mockFinances.EXPECT().ListPendingTicketsForContest(gomock.Any(), finances.ListPendingTicketsForContestParams{
+		FirstContest: 41, LastContest: 41, Contest: 41,
+	}).Return(nil, listErr)
+	mockFinances.EXPECT().ListPendingTicketsForContest(gomock.Any(), finances.ListPendingTicketsForContestParams{
+		FirstContest: 42, LastContest: 42, Contest: 42,
+	}).Return(nil, nil)
+
+	err := svc.OnDrawsImported(context.Background(), []int{41, 42})
+	if !errors.Is(err, listErr) || !strings.Contains(err.Error(), "contest 41") {
+		t.Errorf("expected the failure of contest 41, got %v", err)
+	}
+}
+
+func TestTicketService_TicketPrize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFinances := financesmock.NewMockQuerier(ctrl)
+	svc := NewTicketService(mockFinances, nil, nil, createTestLogger())
+
+	for _, tier := range []struct {
+		name   string
+		amount int64
+	}{{"quina", 1000000}, {"quadra", 10000}, {"terno", 100}, {"duque", 5}} {
+		mockFinances.EXPECT().GetPrizeRule(gomock.Any(), finances.GetPrizeRuleParams{Contest: 7, PrizeType: tier.name}).
+			Return(finances.PrizeRule{AmountCents: tier.amount}, nil).AnyTimes()
+	}
+
+	tests := []struct {
+		size, hits int
+		wantType   string
+		wantPrize  int64
+	}{
+		{5, 1, "", 0},
+		{5, 2, "duque", 5},
+		{5, 3, "terno", 100},
+		{5, 5, "quina", 1000000},
+		// Seven numbers with four hits: 3 quadras, 4*3=12 ternos and 6 duques
+		{7, 4, "quadra", 3*10000 + 12*100 + 6*5},
+		// Fifteen numbers with five hits: 1 quina, 50 quadras, 450 ternos and
+		// 10*120=1200 duques
+		{15, 5, "quina", 1000000 + 50*10000 + 450*100 + 1200*5},
+	}
+
+	for _, tt := range tests {
+		prizeType, prize, err := svc.ticketPrize(context.Background(), 7, tt.size, tt.hits)
+		if err != nil {
+			t.Fatalf("ticketPrize(%d, %d) failed: %v", tt.size, tt.hits, err)
+		}
+		if prizeType != tt.wantType || prize != tt.wantPrize {
+			t.Errorf("ticketPrize(%d, %d) = %q, %d; want %q, %d", tt.size, tt.hits, prizeType, prize, tt.wantType, tt.wantPrize)
+		}
+	}
+}
+
+func TestTicketContestCost(t *testing.T) {
+	ticket := finances.Ticket{FirstContest: 10, LastContest: 12, CostCents: 1000}
+
+	var total int64
+	for c := 10; c <= 12; c++ {
+		total += ticketContestCost(ticket, c)
+	}
+	if total != 1000 {
+		t.Errorf("expected shares to add up to 1000, got %d", total)
+	}
+	if ticketContestCost(ticket, 10) != 334 || ticketContestCost(ticket, 11) != 333 {
+		t.Errorf("expected remainder on the first contest")
+	}
+}
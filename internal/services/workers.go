@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+// Workers refresh their registry entry every WorkerHeartbeatInterval and are
+// reported dead after missing three heartbeats.
+const (
+	WorkerHeartbeatInterval = 30 * time.Second
+	workerDeadAfter         = 3 * WorkerHeartbeatInterval
+)
+
+// ThroughputWindow is the period over which worker throughput is measured.
+const ThroughputWindow = 15 * time.Minute
+
+// Worker states reported by the fleet status.
+const (
+	WorkerStatusLive    = "live"
+	WorkerStatusDead    = "dead"
+	WorkerStatusStopped = "stopped"
+)
+
+// WorkerStatus describes a registered worker process.
+type WorkerStatus struct {
+	ID          string           `json:"id"`
+	Host        string           `json:"host"`
+	Version     string           `json:"version"`
+	Status      string           `json:"status"`
+	Concurrency int              `json:"concurrency"`
+	ActiveJobs  int              `json:"active_jobs"`
+	CurrentJobs []int64          `json:"current_jobs"` // Simulations the worker is running
+	StartedAt   string           `json:"started_at"`
+	HeartbeatAt string           `json:"heartbeat_at"`
+	StoppedAt   *string          `json:"stopped_at,omitempty"`
+	Throughput  WorkerThroughput `json:"throughput"`
+}
+
+// WorkerThroughput counts the runs a worker finished over the throughput
+// window, or since it started when that is more recent.
+type WorkerThroughput struct {
+	WindowMinutes     float64 `json:"window_minutes"`
+	JobsCompleted     int64   `json:"jobs_completed"`
+	JobsFailed        int64   `json:"jobs_failed"`
+	ContestsCompleted int64   `json:"contests_completed"`
+	JobsPerMinute     float64 `json:"jobs_per_minute"`
+	ContestsPerMinute float64 `json:"contests_per_minute"`
+}
+
+type WorkerService struct {
+	simulationsQueries simulations.Querier
+	logger             *slog.Logger
+}
+
+type WorkerServicer interface {
+	ListWorkers(ctx context.Context) ([]WorkerStatus, error)
+}
+
+func NewWorkerService(simulationsQueries simulations.Querier, logger *slog.Logger) *WorkerService {
+	return &WorkerService{
+		simulationsQueries: simulationsQueries,
+		logger:             logger,
+	}
+}
+
+// ListWorkers returns live, dead and stopped workers with their current jobs
+// and throughput.
+func (s *WorkerService) ListWorkers(ctx context.Context) ([]WorkerStatus, error) {
+	// Registry timestamps have second precision
+	now := time.Now().UTC().Truncate(time.Second)
+
+	rows, err := s.simulationsQueries.ListWorkers(ctx, now.Add(-ThroughputWindow).Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("list workers: %w", err)
+	}
+
+	running, err := s.simulationsQueries.ListRunningSimulationsByWorker(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list running simulations: %w", err)
+	}
+	jobs := make(map[string][]int64)
+	for _, r := range running {
+		jobs[r.WorkerID.String] = append(jobs[r.WorkerID.String], r.ID)
+	}
+
+	statuses := make([]WorkerStatus, 0, len(rows))
+	for _, row := range rows {
+		status := WorkerStatus{
+			ID:          row.ID,
+			Host:        row.Host,
+			Version:     row.Version,
+			Status:      workerState(row, now),
+			Concurrency: int(row.Concurrency),
+			ActiveJobs:  int(row.ActiveJobs),
+			CurrentJobs: jobs[row.ID],
+			StartedAt:   row.StartedAt,
+			HeartbeatAt: row.HeartbeatAt,
+			Throughput:  workerThroughput(row, now),
+		}
+		if status.CurrentJobs == nil {
+			status.CurrentJobs = []int64{}
+		}
+		if row.StoppedAt.Valid {
+			status.StoppedAt = &row.StoppedAt.String
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func workerState(row simulations.ListWorkersRow, now time.Time) string {
+	if row.StoppedAt.Valid {
+		return WorkerStatusStopped
+	}
+	heartbeat, err := time.Parse(time.RFC3339, row.HeartbeatAt)
+	if err != nil || now.Sub(heartbeat) > workerDeadAfter {
+		return WorkerStatusDead
+	}
+	return WorkerStatusLive
+}
+
+func workerThroughput(row simulations.ListWorkersRow, now time.Time) WorkerThroughput {
+	window := ThroughputWindow
+	if started, err := time.Parse(time.RFC3339, row.StartedAt); err == nil && now.Sub(started) < window {
+		window = max(now.Sub(started), time.Minute)
+	}
+	minutes := window.Minutes()
+
+	return WorkerThroughput{
+		WindowMinutes:     minutes,
+		JobsCompleted:     row.JobsCompleted,
+		JobsFailed:        row.JobsFailed,
+		ContestsCompleted: row.ContestsCompleted,
+		JobsPerMinute:     float64(row.JobsCompleted) / minutes,
+		ContestsPerMinute: float64(row.ContestsCompleted) / minutes,
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	simulationsmock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
+)
+
+func TestWorkerService_ListWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewWorkerService(mockQueries, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Now().UTC()
+	ts := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	mockQueries.EXPECT().
+		ListWorkers(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, since string) ([]simulations.ListWorkersRow, error) {
+			if since > ts(-ThroughputWindow) || since < ts(-ThroughputWindow-time.Minute) {
+				t.Errorf("expected throughput since %s, got %s", ts(-ThroughputWindow), since)
+			}
+			return []simulations.ListWorkersRow{
+				{ID: "live", Host: "a", Version: "v1", Concurrency: 4, ActiveJobs: 2, StartedAt: ts(-time.Hour), HeartbeatAt: ts(-10 * time.Second), JobsCompleted: 30, JobsFailed: 1, ContestsCompleted: 3000},
+				{ID: "young", Host: "b", Version: "v1", Concurrency: 1, StartedAt: ts(-5 * time.Minute), HeartbeatAt: ts(0), JobsCompleted: 10, ContestsCompleted: 500},
+				{ID: "dead", Host: "c", Version: "v0", Concurrency: 2, StartedAt: ts(-time.Hour), HeartbeatAt: ts(-10 * time.Minute)},
+				{ID: "stopped", Host: "d", Version: "v0", Concurrency: 2, StartedAt: ts(-time.Hour), HeartbeatAt: ts(-time.Minute), StoppedAt: sql.NullString{String: ts(-time.Minute), Valid: true}},
+			}, nil
+		})
+	mockQueries.EXPECT().
+		ListRunningSimulationsByWorker(gomock.Any()).
+		Return([]simulations.ListRunningSimulationsByWorkerRow{
+			{ID: 3, WorkerID: sql.NullString{String: "dead", Valid: true}},
+			{ID: 5, WorkerID: sql.NullString{String: "live", Valid: true}},
+			{ID: 8, WorkerID: sql.NullString{String: "live", Valid: true}},
+		}, nil)
+
+	workers, err := service.ListWorkers(context.Background())
+	if err != nil {
+		t.Fatalf("ListWorkers failed: %v", err)
+	}
+	if len(workers) != 4 {
+		t.Fatalf("expected 4 workers, got %d", len(workers))
+	}
+
+	live := workers[0]
+	if live.Status != WorkerStatusLive || live.ActiveJobs != 2 || len(live.CurrentJobs) != 2 || live.CurrentJobs[0] != 5 || live.CurrentJobs[1] != 8 {
+		t.Errorf("unexpected live worker: %+v", live)
+	}
+	if live.Throughput.WindowMinutes != 15 || live.Throughput.JobsPerMinute != 2 || live.Throughput.ContestsPerMinute != 200 || live.Throughput.JobsFailed != 1 {
+		t.Errorf("unexpected live throughput: %+v", live.Throughput)
+	}
+
+	// Throughput of workers started within the window is measured since start
+	young := workers[1]
+	if young.Status != WorkerStatusLive || math.Abs(young.Throughput.WindowMinutes-5) > 0.1 || math.Abs(young.Throughput.JobsPerMinute-2) > 0.1 {
+		t.Errorf("unexpected young worker: %+v", young)
+	}
+	if young.CurrentJobs == nil || len(young.CurrentJobs) != 0 {
+		t.Errorf("expected empty current jobs, got %v", young.CurrentJobs)
+	}
+
+	if dead := workers[2]; dead.Status != WorkerStatusDead || len(dead.CurrentJobs) != 1 {
+		t.Errorf("unexpected dead worker: %+v", dead)
+	}
+	if stopped := workers[3]; stopped.Status != WorkerStatusStopped || stopped.StoppedAt == nil {
+		t.Errorf("unexpected stopped worker: %+v", stopped)
+	}
+}
+
+func TestWorkerService_ListWorkersError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := simulationsmock.NewMockQuerier(ctrl)
+	service := NewWorkerService(mockQueries, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	dbErr := errors.New("database is closed")
+	mockQueries.EXPECT().ListWorkers(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	if _, err := service.ListWorkers(context.Background()); !errors.Is(err, dbErr) {
+		t.Errorf("expected %v, got %v", dbErr, err)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/garnizeh/luckyfive/internal/store/comparisons"
 	"github.com/garnizeh/luckyfive/internal/store/configs"
 	"github.com/garnizeh/luckyfive/internal/store/finances"
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+	"github.com/garnizeh/luckyfive/internal/store/predictions"
 	"github.com/garnizeh/luckyfive/internal/store/results"
 	"github.com/garnizeh/luckyfive/internal/store/simulations"
 	sweepExecution "github.com/garnizeh/luckyfive/internal/store/sweep_execution"
@@ -32,6 +34,8 @@ type DB struct {
 	Sweeps         sweeps.Querier
 	SweepExecution sweepExecution.Querier
 	Comparisons    comparisons.Querier
+	Predictions    predictions.Querier
+	Jobs           jobs.Querier
 }
 
 // Config holds the paths for each database.
@@ -74,6 +78,8 @@ func Open(cfg Config) (*DB, error) {
 	db.Simulations = simulations.New(simulationsDB)
 	db.Comparisons = comparisons.New(simulationsDB)
 	db.SweepExecution = sweepExecution.New(simulationsDB)
+	db.Predictions = predictions.New(simulationsDB)
+	db.Jobs = jobs.New(simulationsDB)
 
 	// Open Configs DB
 	configsDB, err := sql.Open("sqlite", cfg.ConfigsPath)
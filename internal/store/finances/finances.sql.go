@@ -10,6 +10,29 @@ import (
 	"database/sql"
 )
 
+const addTicketPrize = `-- name: AddTicketPrize :exec
+UPDATE tickets
+SET total_prize_cents = total_prize_cents + ?,
+    status = CASE WHEN (
+        SELECT COUNT(*) FROM ticket_checks c
+        WHERE c.ticket_id = tickets.id
+            AND c.contest BETWEEN tickets.first_contest AND tickets.last_contest
+    ) >= last_contest - first_contest + 1 THEN 'settled' ELSE status END
+WHERE id = ?
+`
+
+type AddTicketPrizeParams struct {
+	TotalPrizeCents int64 `json:"total_prize_cents"`
+	ID              int64 `json:"id"`
+}
+
+// Settles the ticket once every contest it covers has been checked, however
+// out of order the draws were imported.
+func (q *Queries) AddTicketPrize(ctx context.Context, arg AddTicketPrizeParams) error {
+	_, err := q.db.ExecContext(ctx, addTicketPrize, arg.TotalPrizeCents, arg.ID)
+	return err
+}
+
 const allocateBudget = `-- name: AllocateBudget :one
 INSERT INTO budget_allocations (
     budget_id, simulation_id, sweep_id, allocated_cents
@@ -261,6 +284,90 @@ func (q *Queries) CreateSimulationFinances(ctx context.Context, arg CreateSimula
 	return i, err
 }
 
+const createTicket = `-- name: CreateTicket :one
+INSERT INTO tickets (
+    owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes, status, total_prize_cents, created_at
+`
+
+type CreateTicketParams struct {
+	Owner        string         `json:"owner"`
+	Numbers      string         `json:"numbers"`
+	FirstContest int64          `json:"first_contest"`
+	LastContest  int64          `json:"last_contest"`
+	CostCents    int64          `json:"cost_cents"`
+	PredictionID sql.NullInt64  `json:"prediction_id"`
+	Notes        sql.NullString `json:"notes"`
+}
+
+// Tickets
+func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (Ticket, error) {
+	row := q.db.QueryRowContext(ctx, createTicket,
+		arg.Owner,
+		arg.Numbers,
+		arg.FirstContest,
+		arg.LastContest,
+		arg.CostCents,
+		arg.PredictionID,
+		arg.Notes,
+	)
+	var i Ticket
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Numbers,
+		&i.FirstContest,
+		&i.LastContest,
+		&i.CostCents,
+		&i.PredictionID,
+		&i.Notes,
+		&i.Status,
+		&i.TotalPrizeCents,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTicketCheck = `-- name: CreateTicketCheck :one
+INSERT INTO ticket_checks (
+    ticket_id, contest, hits, matched_numbers, prize_type, prize_cents
+) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, ticket_id, contest, hits, matched_numbers, prize_type, prize_cents, checked_at
+`
+
+type CreateTicketCheckParams struct {
+	TicketID       int64          `json:"ticket_id"`
+	Contest        int64          `json:"contest"`
+	Hits           int64          `json:"hits"`
+	MatchedNumbers string         `json:"matched_numbers"`
+	PrizeType      sql.NullString `json:"prize_type"`
+	PrizeCents     int64          `json:"prize_cents"`
+}
+
+func (q *Queries) CreateTicketCheck(ctx context.Context, arg CreateTicketCheckParams) (TicketCheck, error) {
+	row := q.db.QueryRowContext(ctx, createTicketCheck,
+		arg.TicketID,
+		arg.Contest,
+		arg.Hits,
+		arg.MatchedNumbers,
+		arg.PrizeType,
+		arg.PrizeCents,
+	)
+	var i TicketCheck
+	err := row.Scan(
+		&i.ID,
+		&i.TicketID,
+		&i.Contest,
+		&i.Hits,
+		&i.MatchedNumbers,
+		&i.PrizeType,
+		&i.PrizeCents,
+		&i.CheckedAt,
+	)
+	return i, err
+}
+
 const getActiveBetCost = `-- name: GetActiveBetCost :one
 SELECT id, effective_from, effective_to, cost_cents, numbers_count, region, notes FROM bet_costs
 WHERE effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)
@@ -579,6 +686,118 @@ func (q *Queries) GetSimulationFinances(ctx context.Context, simulationID int64)
 	return i, err
 }
 
+const getTicket = `-- name: GetTicket :one
+SELECT id, owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes, status, total_prize_cents, created_at FROM tickets WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetTicket(ctx context.Context, id int64) (Ticket, error) {
+	row := q.db.QueryRowContext(ctx, getTicket, id)
+	var i Ticket
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Numbers,
+		&i.FirstContest,
+		&i.LastContest,
+		&i.CostCents,
+		&i.PredictionID,
+		&i.Notes,
+		&i.Status,
+		&i.TotalPrizeCents,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTicketChecks = `-- name: GetTicketChecks :many
+SELECT id, ticket_id, contest, hits, matched_numbers, prize_type, prize_cents, checked_at FROM ticket_checks
+WHERE ticket_id = ?
+ORDER BY contest ASC
+`
+
+func (q *Queries) GetTicketChecks(ctx context.Context, ticketID int64) ([]TicketCheck, error) {
+	rows, err := q.db.QueryContext(ctx, getTicketChecks, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TicketCheck
+	for rows.Next() {
+		var i TicketCheck
+		if err := rows.Scan(
+			&i.ID,
+			&i.TicketID,
+			&i.Contest,
+			&i.Hits,
+			&i.MatchedNumbers,
+			&i.PrizeType,
+			&i.PrizeCents,
+			&i.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingTicketsForContest = `-- name: ListPendingTicketsForContest :many
+SELECT t.id, t.owner, t.numbers, t.first_contest, t.last_contest, t.cost_cents, t.prediction_id, t.notes, t.status, t.total_prize_cents, t.created_at FROM tickets t
+WHERE t.first_contest <= ? AND t.last_contest >= ?
+    AND NOT EXISTS (
+        SELECT 1 FROM ticket_checks c
+        WHERE c.ticket_id = t.id AND c.contest = ?
+    )
+ORDER BY t.id ASC
+`
+
+type ListPendingTicketsForContestParams struct {
+	FirstContest int64 `json:"first_contest"`
+	LastContest  int64 `json:"last_contest"`
+	Contest      int64 `json:"contest"`
+}
+
+func (q *Queries) ListPendingTicketsForContest(ctx context.Context, arg ListPendingTicketsForContestParams) ([]Ticket, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingTicketsForContest, arg.FirstContest, arg.LastContest, arg.Contest)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Ticket
+	for rows.Next() {
+		var i Ticket
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Numbers,
+			&i.FirstContest,
+			&i.LastContest,
+			&i.CostCents,
+			&i.PredictionID,
+			&i.Notes,
+			&i.Status,
+			&i.TotalPrizeCents,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPrizeRules = `-- name: ListPrizeRules :many
 SELECT id, contest, prize_type, amount_cents, winners, total_collected_cents, notes FROM prize_rules
 WHERE contest >= ? AND contest <= ?
@@ -621,6 +840,100 @@ func (q *Queries) ListPrizeRules(ctx context.Context, arg ListPrizeRulesParams)
 	return items, nil
 }
 
+const listTickets = `-- name: ListTickets :many
+SELECT id, owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes, status, total_prize_cents, created_at FROM tickets
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListTicketsParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+func (q *Queries) ListTickets(ctx context.Context, arg ListTicketsParams) ([]Ticket, error) {
+	rows, err := q.db.QueryContext(ctx, listTickets, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Ticket
+	for rows.Next() {
+		var i Ticket
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Numbers,
+			&i.FirstContest,
+			&i.LastContest,
+			&i.CostCents,
+			&i.PredictionID,
+			&i.Notes,
+			&i.Status,
+			&i.TotalPrizeCents,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTicketsByOwner = `-- name: ListTicketsByOwner :many
+SELECT id, owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes, status, total_prize_cents, created_at FROM tickets
+WHERE owner = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListTicketsByOwnerParams struct {
+	Owner  string `json:"owner"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+func (q *Queries) ListTicketsByOwner(ctx context.Context, arg ListTicketsByOwnerParams) ([]Ticket, error) {
+	rows, err := q.db.QueryContext(ctx, listTicketsByOwner, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Ticket
+	for rows.Next() {
+		var i Ticket
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Numbers,
+			&i.FirstContest,
+			&i.LastContest,
+			&i.CostCents,
+			&i.PredictionID,
+			&i.Notes,
+			&i.Status,
+			&i.TotalPrizeCents,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTopSimulationsByROI = `-- name: ListTopSimulationsByROI :many
 SELECT sf.id, sf.simulation_id, sf.total_bets, sf.total_cost_cents, sf.total_prizes_cents, sf.net_profit_cents, sf.roi_percentage, sf.break_even_contest, sf.best_prize_cents, sf.best_prize_contest, sf.quina_wins, sf.quadra_wins, sf.terno_wins, sf.updated_at, s.recipe_name, s.mode
 FROM simulation_finances sf
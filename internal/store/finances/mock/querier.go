@@ -36,6 +36,20 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// AddTicketPrize mocks base method.
+func (m *MockQuerier) AddTicketPrize(ctx context.Context, arg finances.AddTicketPrizeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTicketPrize", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTicketPrize indicates an expected call of AddTicketPrize.
+func (mr *MockQuerierMockRecorder) AddTicketPrize(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTicketPrize", reflect.TypeOf((*MockQuerier)(nil).AddTicketPrize), ctx, arg)
+}
+
 // AllocateBudget mocks base method.
 func (m *MockQuerier) AllocateBudget(ctx context.Context, arg finances.AllocateBudgetParams) (finances.BudgetAllocation, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +140,36 @@ func (mr *MockQuerierMockRecorder) CreateSimulationFinances(ctx, arg interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSimulationFinances", reflect.TypeOf((*MockQuerier)(nil).CreateSimulationFinances), ctx, arg)
 }
 
+// CreateTicket mocks base method.
+func (m *MockQuerier) CreateTicket(ctx context.Context, arg finances.CreateTicketParams) (finances.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicket", ctx, arg)
+	ret0, _ := ret[0].(finances.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTicket indicates an expected call of CreateTicket.
+func (mr *MockQuerierMockRecorder) CreateTicket(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicket", reflect.TypeOf((*MockQuerier)(nil).CreateTicket), ctx, arg)
+}
+
+// CreateTicketCheck mocks base method.
+func (m *MockQuerier) CreateTicketCheck(ctx context.Context, arg finances.CreateTicketCheckParams) (finances.TicketCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicketCheck", ctx, arg)
+	ret0, _ := ret[0].(finances.TicketCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTicketCheck indicates an expected call of CreateTicketCheck.
+func (mr *MockQuerierMockRecorder) CreateTicketCheck(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicketCheck", reflect.TypeOf((*MockQuerier)(nil).CreateTicketCheck), ctx, arg)
+}
+
 // GetActiveBetCost mocks base method.
 func (m *MockQuerier) GetActiveBetCost(ctx context.Context, arg finances.GetActiveBetCostParams) (finances.BetCost, error) {
 	m.ctrl.T.Helper()
@@ -276,6 +320,51 @@ func (mr *MockQuerierMockRecorder) GetSimulationFinances(ctx, simulationID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulationFinances", reflect.TypeOf((*MockQuerier)(nil).GetSimulationFinances), ctx, simulationID)
 }
 
+// GetTicket mocks base method.
+func (m *MockQuerier) GetTicket(ctx context.Context, id int64) (finances.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicket", ctx, id)
+	ret0, _ := ret[0].(finances.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicket indicates an expected call of GetTicket.
+func (mr *MockQuerierMockRecorder) GetTicket(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicket", reflect.TypeOf((*MockQuerier)(nil).GetTicket), ctx, id)
+}
+
+// GetTicketChecks mocks base method.
+func (m *MockQuerier) GetTicketChecks(ctx context.Context, ticketID int64) ([]finances.TicketCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketChecks", ctx, ticketID)
+	ret0, _ := ret[0].([]finances.TicketCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketChecks indicates an expected call of GetTicketChecks.
+func (mr *MockQuerierMockRecorder) GetTicketChecks(ctx, ticketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketChecks", reflect.TypeOf((*MockQuerier)(nil).GetTicketChecks), ctx, ticketID)
+}
+
+// ListPendingTicketsForContest mocks base method.
+func (m *MockQuerier) ListPendingTicketsForContest(ctx context.Context, arg finances.ListPendingTicketsForContestParams) ([]finances.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingTicketsForContest", ctx, arg)
+	ret0, _ := ret[0].([]finances.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTicketsForContest indicates an expected call of ListPendingTicketsForContest.
+func (mr *MockQuerierMockRecorder) ListPendingTicketsForContest(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTicketsForContest", reflect.TypeOf((*MockQuerier)(nil).ListPendingTicketsForContest), ctx, arg)
+}
+
 // ListPrizeRules mocks base method.
 func (m *MockQuerier) ListPrizeRules(ctx context.Context, arg finances.ListPrizeRulesParams) ([]finances.PrizeRule, error) {
 	m.ctrl.T.Helper()
@@ -291,6 +380,36 @@ func (mr *MockQuerierMockRecorder) ListPrizeRules(ctx, arg interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrizeRules", reflect.TypeOf((*MockQuerier)(nil).ListPrizeRules), ctx, arg)
 }
 
+// ListTickets mocks base method.
+func (m *MockQuerier) ListTickets(ctx context.Context, arg finances.ListTicketsParams) ([]finances.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTickets", ctx, arg)
+	ret0, _ := ret[0].([]finances.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTickets indicates an expected call of ListTickets.
+func (mr *MockQuerierMockRecorder) ListTickets(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTickets", reflect.TypeOf((*MockQuerier)(nil).ListTickets), ctx, arg)
+}
+
+// ListTicketsByOwner mocks base method.
+func (m *MockQuerier) ListTicketsByOwner(ctx context.Context, arg finances.ListTicketsByOwnerParams) ([]finances.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTicketsByOwner", ctx, arg)
+	ret0, _ := ret[0].([]finances.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTicketsByOwner indicates an expected call of ListTicketsByOwner.
+func (mr *MockQuerierMockRecorder) ListTicketsByOwner(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTicketsByOwner", reflect.TypeOf((*MockQuerier)(nil).ListTicketsByOwner), ctx, arg)
+}
+
 // ListTopSimulationsByROI mocks base method.
 func (m *MockQuerier) ListTopSimulationsByROI(ctx context.Context, arg finances.ListTopSimulationsByROIParams) ([]finances.ListTopSimulationsByROIRow, error) {
 	m.ctrl.T.Helper()
@@ -143,3 +143,28 @@ type SimulationFinance struct {
 	TernoWins        sql.NullInt64  `json:"terno_wins"`
 	UpdatedAt        sql.NullString `json:"updated_at"`
 }
+
+type Ticket struct {
+	ID              int64          `json:"id"`
+	Owner           string         `json:"owner"`
+	Numbers         string         `json:"numbers"`
+	FirstContest    int64          `json:"first_contest"`
+	LastContest     int64          `json:"last_contest"`
+	CostCents       int64          `json:"cost_cents"`
+	PredictionID    sql.NullInt64  `json:"prediction_id"`
+	Notes           sql.NullString `json:"notes"`
+	Status          string         `json:"status"`
+	TotalPrizeCents int64          `json:"total_prize_cents"`
+	CreatedAt       sql.NullString `json:"created_at"`
+}
+
+type TicketCheck struct {
+	ID             int64          `json:"id"`
+	TicketID       int64          `json:"ticket_id"`
+	Contest        int64          `json:"contest"`
+	Hits           int64          `json:"hits"`
+	MatchedNumbers string         `json:"matched_numbers"`
+	PrizeType      sql.NullString `json:"prize_type"`
+	PrizeCents     int64          `json:"prize_cents"`
+	CheckedAt      sql.NullString `json:"checked_at"`
+}
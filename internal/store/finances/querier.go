@@ -10,6 +10,9 @@ import (
 )
 
 type Querier interface {
+	// Settles the ticket once every contest it covers has been checked, however
+	// out of order the draws were imported.
+	AddTicketPrize(ctx context.Context, arg AddTicketPrizeParams) error
 	AllocateBudget(ctx context.Context, arg AllocateBudgetParams) (BudgetAllocation, error)
 	// Bet costs
 	CreateBetCost(ctx context.Context, arg CreateBetCostParams) (BetCost, error)
@@ -23,6 +26,9 @@ type Querier interface {
 	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error)
 	// Simulation finances
 	CreateSimulationFinances(ctx context.Context, arg CreateSimulationFinancesParams) (SimulationFinance, error)
+	// Tickets
+	CreateTicket(ctx context.Context, arg CreateTicketParams) (Ticket, error)
+	CreateTicketCheck(ctx context.Context, arg CreateTicketCheckParams) (TicketCheck, error)
 	GetActiveBetCost(ctx context.Context, arg GetActiveBetCostParams) (BetCost, error)
 	GetBudget(ctx context.Context, id int64) (Budget, error)
 	GetBudgetAllocations(ctx context.Context, budgetID int64) ([]BudgetAllocation, error)
@@ -33,7 +39,12 @@ type Querier interface {
 	GetLedgerEntries(ctx context.Context, arg GetLedgerEntriesParams) ([]LedgerEntry, error)
 	GetPrizeRule(ctx context.Context, arg GetPrizeRuleParams) (PrizeRule, error)
 	GetSimulationFinances(ctx context.Context, simulationID int64) (SimulationFinance, error)
+	GetTicket(ctx context.Context, id int64) (Ticket, error)
+	GetTicketChecks(ctx context.Context, ticketID int64) ([]TicketCheck, error)
+	ListPendingTicketsForContest(ctx context.Context, arg ListPendingTicketsForContestParams) ([]Ticket, error)
 	ListPrizeRules(ctx context.Context, arg ListPrizeRulesParams) ([]PrizeRule, error)
+	ListTickets(ctx context.Context, arg ListTicketsParams) ([]Ticket, error)
+	ListTicketsByOwner(ctx context.Context, arg ListTicketsByOwnerParams) ([]Ticket, error)
 	ListTopSimulationsByROI(ctx context.Context, arg ListTopSimulationsByROIParams) ([]ListTopSimulationsByROIRow, error)
 	UpdateAllocationSpent(ctx context.Context, arg UpdateAllocationSpentParams) error
 	UpdateBudgetSpent(ctx context.Context, arg UpdateBudgetSpentParams) error
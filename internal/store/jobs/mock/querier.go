@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/store/jobs/querier.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jobs "github.com/garnizeh/luckyfive/internal/store/jobs"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuerier is a mock of Querier interface.
+type MockQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuerierMockRecorder
+}
+
+// MockQuerierMockRecorder is the mock recorder for MockQuerier.
+type MockQuerierMockRecorder struct {
+	mock *MockQuerier
+}
+
+// NewMockQuerier creates a new mock instance.
+func NewMockQuerier(ctrl *gomock.Controller) *MockQuerier {
+	mock := &MockQuerier{ctrl: ctrl}
+	mock.recorder = &MockQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
+	return m.recorder
+}
+
+// CancelAnalysisJob mocks base method.
+func (m *MockQuerier) CancelAnalysisJob(ctx context.Context, arg jobs.CancelAnalysisJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelAnalysisJob indicates an expected call of CancelAnalysisJob.
+func (mr *MockQuerierMockRecorder) CancelAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).CancelAnalysisJob), ctx, arg)
+}
+
+// ClaimPendingAnalysisJob mocks base method.
+func (m *MockQuerier) ClaimPendingAnalysisJob(ctx context.Context, arg jobs.ClaimPendingAnalysisJobParams) (jobs.AnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(jobs.AnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingAnalysisJob indicates an expected call of ClaimPendingAnalysisJob.
+func (mr *MockQuerierMockRecorder) ClaimPendingAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).ClaimPendingAnalysisJob), ctx, arg)
+}
+
+// CompleteAnalysisJob mocks base method.
+func (m *MockQuerier) CompleteAnalysisJob(ctx context.Context, arg jobs.CompleteAnalysisJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteAnalysisJob indicates an expected call of CompleteAnalysisJob.
+func (mr *MockQuerierMockRecorder) CompleteAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).CompleteAnalysisJob), ctx, arg)
+}
+
+// CreateAnalysisJob mocks base method.
+func (m *MockQuerier) CreateAnalysisJob(ctx context.Context, arg jobs.CreateAnalysisJobParams) (jobs.AnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(jobs.AnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAnalysisJob indicates an expected call of CreateAnalysisJob.
+func (mr *MockQuerierMockRecorder) CreateAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).CreateAnalysisJob), ctx, arg)
+}
+
+// FailAnalysisJob mocks base method.
+func (m *MockQuerier) FailAnalysisJob(ctx context.Context, arg jobs.FailAnalysisJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailAnalysisJob indicates an expected call of FailAnalysisJob.
+func (mr *MockQuerierMockRecorder) FailAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).FailAnalysisJob), ctx, arg)
+}
+
+// FailExpiredAnalysisJobs mocks base method.
+func (m *MockQuerier) FailExpiredAnalysisJobs(ctx context.Context, arg jobs.FailExpiredAnalysisJobsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExpiredAnalysisJobs", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailExpiredAnalysisJobs indicates an expected call of FailExpiredAnalysisJobs.
+func (mr *MockQuerierMockRecorder) FailExpiredAnalysisJobs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExpiredAnalysisJobs", reflect.TypeOf((*MockQuerier)(nil).FailExpiredAnalysisJobs), ctx, arg)
+}
+
+// GetAnalysisJob mocks base method.
+func (m *MockQuerier) GetAnalysisJob(ctx context.Context, id int64) (jobs.AnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisJob", ctx, id)
+	ret0, _ := ret[0].(jobs.AnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisJob indicates an expected call of GetAnalysisJob.
+func (mr *MockQuerierMockRecorder) GetAnalysisJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).GetAnalysisJob), ctx, id)
+}
+
+// GetAnalysisJobReport mocks base method.
+func (m *MockQuerier) GetAnalysisJobReport(ctx context.Context, id int64) (jobs.GetAnalysisJobReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisJobReport", ctx, id)
+	ret0, _ := ret[0].(jobs.GetAnalysisJobReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisJobReport indicates an expected call of GetAnalysisJobReport.
+func (mr *MockQuerierMockRecorder) GetAnalysisJobReport(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisJobReport", reflect.TypeOf((*MockQuerier)(nil).GetAnalysisJobReport), ctx, id)
+}
+
+// HeartbeatAnalysisJob mocks base method.
+func (m *MockQuerier) HeartbeatAnalysisJob(ctx context.Context, arg jobs.HeartbeatAnalysisJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeartbeatAnalysisJob indicates an expected call of HeartbeatAnalysisJob.
+func (mr *MockQuerierMockRecorder) HeartbeatAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).HeartbeatAnalysisJob), ctx, arg)
+}
+
+// ListAnalysisJobs mocks base method.
+func (m *MockQuerier) ListAnalysisJobs(ctx context.Context, arg jobs.ListAnalysisJobsParams) ([]jobs.ListAnalysisJobsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnalysisJobs", ctx, arg)
+	ret0, _ := ret[0].([]jobs.ListAnalysisJobsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnalysisJobs indicates an expected call of ListAnalysisJobs.
+func (mr *MockQuerierMockRecorder) ListAnalysisJobs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnalysisJobs", reflect.TypeOf((*MockQuerier)(nil).ListAnalysisJobs), ctx, arg)
+}
+
+// ReleaseAnalysisJob mocks base method.
+func (m *MockQuerier) ReleaseAnalysisJob(ctx context.Context, arg jobs.ReleaseAnalysisJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseAnalysisJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseAnalysisJob indicates an expected call of ReleaseAnalysisJob.
+func (mr *MockQuerierMockRecorder) ReleaseAnalysisJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAnalysisJob", reflect.TypeOf((*MockQuerier)(nil).ReleaseAnalysisJob), ctx, arg)
+}
+
+// RequeueExpiredAnalysisJobs mocks base method.
+func (m *MockQuerier) RequeueExpiredAnalysisJobs(ctx context.Context, arg jobs.RequeueExpiredAnalysisJobsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueExpiredAnalysisJobs", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueExpiredAnalysisJobs indicates an expected call of RequeueExpiredAnalysisJobs.
+func (mr *MockQuerierMockRecorder) RequeueExpiredAnalysisJobs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueExpiredAnalysisJobs", reflect.TypeOf((*MockQuerier)(nil).RequeueExpiredAnalysisJobs), ctx, arg)
+}
+
+// UpdateAnalysisJobProgress mocks base method.
+func (m *MockQuerier) UpdateAnalysisJobProgress(ctx context.Context, arg jobs.UpdateAnalysisJobProgressParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAnalysisJobProgress", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAnalysisJobProgress indicates an expected call of UpdateAnalysisJobProgress.
+func (mr *MockQuerierMockRecorder) UpdateAnalysisJobProgress(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnalysisJobProgress", reflect.TypeOf((*MockQuerier)(nil).UpdateAnalysisJobProgress), ctx, arg)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/store/predictions/querier.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	predictions "github.com/garnizeh/luckyfive/internal/store/predictions"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuerier is a mock of Querier interface.
+type MockQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuerierMockRecorder
+}
+
+// MockQuerierMockRecorder is the mock recorder for MockQuerier.
+type MockQuerierMockRecorder struct {
+	mock *MockQuerier
+}
+
+// NewMockQuerier creates a new mock instance.
+func NewMockQuerier(ctrl *gomock.Controller) *MockQuerier {
+	mock := &MockQuerier{ctrl: ctrl}
+	mock.recorder = &MockQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
+	return m.recorder
+}
+
+// CreatePrediction mocks base method.
+func (m *MockQuerier) CreatePrediction(ctx context.Context, arg predictions.CreatePredictionParams) (predictions.Prediction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrediction", ctx, arg)
+	ret0, _ := ret[0].(predictions.Prediction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePrediction indicates an expected call of CreatePrediction.
+func (mr *MockQuerierMockRecorder) CreatePrediction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrediction", reflect.TypeOf((*MockQuerier)(nil).CreatePrediction), ctx, arg)
+}
+
+// GetPrediction mocks base method.
+func (m *MockQuerier) GetPrediction(ctx context.Context, id int64) (predictions.Prediction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrediction", ctx, id)
+	ret0, _ := ret[0].(predictions.Prediction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrediction indicates an expected call of GetPrediction.
+func (mr *MockQuerierMockRecorder) GetPrediction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrediction", reflect.TypeOf((*MockQuerier)(nil).GetPrediction), ctx, id)
+}
+
+// ListPendingPredictionsByContest mocks base method.
+func (m *MockQuerier) ListPendingPredictionsByContest(ctx context.Context, targetContest int64) ([]predictions.Prediction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingPredictionsByContest", ctx, targetContest)
+	ret0, _ := ret[0].([]predictions.Prediction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingPredictionsByContest indicates an expected call of ListPendingPredictionsByContest.
+func (mr *MockQuerierMockRecorder) ListPendingPredictionsByContest(ctx, targetContest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingPredictionsByContest", reflect.TypeOf((*MockQuerier)(nil).ListPendingPredictionsByContest), ctx, targetContest)
+}
+
+// ListPredictions mocks base method.
+func (m *MockQuerier) ListPredictions(ctx context.Context, arg predictions.ListPredictionsParams) ([]predictions.Prediction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPredictions", ctx, arg)
+	ret0, _ := ret[0].([]predictions.Prediction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPredictions indicates an expected call of ListPredictions.
+func (mr *MockQuerierMockRecorder) ListPredictions(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPredictions", reflect.TypeOf((*MockQuerier)(nil).ListPredictions), ctx, arg)
+}
+
+// MarkPredictionChecked mocks base method.
+func (m *MockQuerier) MarkPredictionChecked(ctx context.Context, arg predictions.MarkPredictionCheckedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPredictionChecked", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPredictionChecked indicates an expected call of MarkPredictionChecked.
+func (mr *MockQuerierMockRecorder) MarkPredictionChecked(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPredictionChecked", reflect.TypeOf((*MockQuerier)(nil).MarkPredictionChecked), ctx, arg)
+}
@@ -155,3 +155,56 @@ WHERE id = ?;
 -- name: GetBudgetAllocations :many
 SELECT * FROM budget_allocations
 WHERE budget_id = ?;
+
+-- Tickets
+-- name: CreateTicket :one
+INSERT INTO tickets (
+    owner, numbers, first_contest, last_contest, cost_cents, prediction_id, notes
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetTicket :one
+SELECT * FROM tickets WHERE id = ? LIMIT 1;
+
+-- name: ListTickets :many
+SELECT * FROM tickets
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListTicketsByOwner :many
+SELECT * FROM tickets
+WHERE owner = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListPendingTicketsForContest :many
+SELECT * FROM tickets t
+WHERE t.first_contest <= ? AND t.last_contest >= ?
+    AND NOT EXISTS (
+        SELECT 1 FROM ticket_checks c
+        WHERE c.ticket_id = t.id AND c.contest = ?
+    )
+ORDER BY t.id ASC;
+
+-- name: AddTicketPrize :exec
+-- Settles the ticket once every contest it covers has been checked, however
+-- out of order the draws were imported.
+UPDATE tickets
+SET total_prize_cents = total_prize_cents + ?,
+    status = CASE WHEN (
+        SELECT COUNT(*) FROM ticket_checks c
+        WHERE c.ticket_id = tickets.id
+            AND c.contest BETWEEN tickets.first_contest AND tickets.last_contest
+    ) >= last_contest - first_contest + 1 THEN 'settled' ELSE status END
+WHERE id = ?;
+
+-- name: CreateTicketCheck :one
+INSERT INTO ticket_checks (
+    ticket_id, contest, hits, matched_numbers, prize_type, prize_cents
+) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetTicketChecks :many
+SELECT * FROM ticket_checks
+WHERE ticket_id = ?
+ORDER BY contest ASC;
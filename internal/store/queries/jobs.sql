@@ -0,0 +1,117 @@
+-- name: CreateAnalysisJob :one
+INSERT INTO analysis_jobs (job_type, config_json, created_by)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetAnalysisJob :one
+SELECT * FROM analysis_jobs
+WHERE id = ?;
+
+-- name: ListAnalysisJobs :many
+-- Lists jobs newest first without their reports. Empty filters match all.
+SELECT id, created_at, started_at, finished_at, status, job_type, created_by,
+    config_json, progress_done, progress_total, result_json, report_name,
+    error_message, worker_id, attempts
+FROM analysis_jobs
+WHERE (sqlc.arg('job_type') = '' OR job_type = sqlc.arg('job_type'))
+    AND (sqlc.arg('status') = '' OR status = sqlc.arg('status'))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetAnalysisJobReport :one
+SELECT report_name, report_blob FROM analysis_jobs
+WHERE id = ?;
+
+-- name: ClaimPendingAnalysisJob :one
+-- Claims the oldest pending job.
+UPDATE analysis_jobs
+SET status = 'running',
+    started_at = sqlc.arg('started_at'),
+    heartbeat_at = sqlc.arg('started_at'),
+    worker_id = sqlc.arg('worker_id'),
+    lease_expires_at = sqlc.arg('lease_expires_at'),
+    attempts = attempts + 1
+WHERE id = (
+    SELECT id FROM analysis_jobs
+    WHERE status = 'pending'
+    ORDER BY id ASC
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: HeartbeatAnalysisJob :execrows
+-- Extends the lease of a running job. No rows are affected when the worker
+-- no longer owns it (reaped or cancelled).
+UPDATE analysis_jobs
+SET heartbeat_at = ?,
+    lease_expires_at = ?
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: UpdateAnalysisJobProgress :execrows
+UPDATE analysis_jobs
+SET progress_done = ?,
+    progress_total = ?
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: CompleteAnalysisJob :execrows
+UPDATE analysis_jobs
+SET status = 'completed',
+    finished_at = ?,
+    result_json = ?,
+    report_blob = ?,
+    report_name = ?,
+    progress_done = progress_total,
+    lease_expires_at = NULL
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: FailAnalysisJob :execrows
+UPDATE analysis_jobs
+SET status = 'failed',
+    finished_at = ?,
+    error_message = ?,
+    lease_expires_at = NULL
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: CancelAnalysisJob :execrows
+-- Cancels a pending or running job. A running job stops at its next
+-- heartbeat, when the worker finds it no longer owns the job.
+UPDATE analysis_jobs
+SET status = 'cancelled',
+    finished_at = ?,
+    lease_expires_at = NULL
+WHERE id = ? AND status IN ('pending', 'running');
+
+-- name: ReleaseAnalysisJob :execrows
+-- Returns a job interrupted by the shutdown of its worker to the queue. The
+-- interrupted run does not count as an attempt.
+UPDATE analysis_jobs
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    progress_done = 0,
+    attempts = MAX(attempts - 1, 0)
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: RequeueExpiredAnalysisJobs :many
+UPDATE analysis_jobs
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    progress_done = 0
+WHERE status = 'running'
+    AND lease_expires_at < sqlc.arg('now')
+    AND attempts < sqlc.arg('max_attempts')
+RETURNING id;
+
+-- name: FailExpiredAnalysisJobs :many
+UPDATE analysis_jobs
+SET status = 'failed',
+    finished_at = sqlc.arg('now'),
+    error_message = 'worker lease expired after ' || attempts || ' attempts',
+    lease_expires_at = NULL
+WHERE status = 'running'
+    AND lease_expires_at < sqlc.arg('now')
+    AND attempts >= sqlc.arg('max_attempts')
+RETURNING id;
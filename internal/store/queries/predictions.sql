@@ -0,0 +1,28 @@
+-- name: CreatePrediction :one
+INSERT INTO predictions (
+    config_id, recipe_name, recipe_json, recipe_hash, target_contest,
+    based_on_contest, seed, predictions_json, created_by
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetPrediction :one
+SELECT * FROM predictions WHERE id = ? LIMIT 1;
+
+-- name: ListPredictions :many
+SELECT * FROM predictions
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListPendingPredictionsByContest :many
+SELECT * FROM predictions
+WHERE target_contest = ? AND status = 'pending'
+ORDER BY id ASC;
+
+-- name: MarkPredictionChecked :exec
+UPDATE predictions
+SET status = 'checked',
+    actual_numbers = ?,
+    best_hits = ?,
+    hits_json = ?,
+    checked_at = ?
+WHERE id = ?;
@@ -1,7 +1,7 @@
 -- name: CreateSimulation :one
 INSERT INTO simulations (
-    recipe_name, recipe_json, mode, start_contest, end_contest, created_by
-) VALUES (?, ?, ?, ?, ?, ?)
+    recipe_name, recipe_json, mode, start_contest, end_contest, created_by, priority
+) VALUES (?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetSimulation :one
@@ -25,7 +25,9 @@ UPDATE simulations
 SET status = ?, started_at = ?, worker_id = ?
 WHERE id = ? AND status = 'pending';
 
--- name: CompleteSimulation :exec
+-- name: CompleteSimulation :execrows
+-- Completes a running simulation. No rows are affected when the worker no
+-- longer owns it (reaped or cancelled).
 UPDATE simulations
 SET status = 'completed',
     finished_at = ?,
@@ -33,14 +35,21 @@ SET status = 'completed',
     summary_json = ?,
     output_blob = ?,
     output_name = ?
-WHERE id = ?;
+WHERE id = ? AND worker_id = ? AND status = 'running';
 
--- name: FailSimulation :exec
+-- name: FailSimulation :execrows
+-- Fails a running simulation. No rows are affected when the worker no longer
+-- owns it (reaped or cancelled).
 UPDATE simulations
 SET status = 'failed',
     finished_at = ?,
     error_message = ?,
     error_stack = ?
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: SaveSimulationLog :exec
+UPDATE simulations
+SET log_blob = ?
 WHERE id = ?;
 
 -- name: CancelSimulation :exec
@@ -49,19 +58,108 @@ SET status = 'cancelled',
     finished_at = ?
 WHERE id = ? AND status IN ('pending', 'running');
 
+-- name: UpdateQueuedSimulation :one
+-- Reprioritises, pauses or resumes a simulation that is still waiting in the
+-- queue. NULL arguments leave the current value unchanged.
+UPDATE simulations
+SET priority = COALESCE(sqlc.narg('priority'), priority),
+    paused = COALESCE(sqlc.narg('paused'), paused)
+WHERE id = sqlc.arg('id') AND status = 'pending'
+RETURNING *;
+
 -- name: ClaimPendingSimulation :one
+-- Claims the next unpaused pending simulation with the highest priority.
+-- Simulations waiting for a retry are skipped until their next attempt.
+-- Within a priority, users take turns: the one with the fewest running jobs
+-- and the least recent claim goes first, so one large sweep cannot starve
+-- everyone else's simulations.
 UPDATE simulations
 SET status = 'running',
-    started_at = ?,
-    worker_id = ?
+    started_at = sqlc.arg('started_at'),
+    heartbeat_at = sqlc.arg('started_at'),
+    worker_id = sqlc.arg('worker_id'),
+    lease_expires_at = sqlc.arg('lease_expires_at'),
+    next_attempt_at = NULL,
+    attempts = attempts + 1
 WHERE id = (
-    SELECT id FROM simulations
-    WHERE status = 'pending'
-    ORDER BY created_at ASC
+    SELECT p.id FROM simulations p
+    WHERE p.status = 'pending' AND p.paused = 0
+        AND (p.next_attempt_at IS NULL OR p.next_attempt_at <= sqlc.arg('started_at'))
+    ORDER BY p.priority DESC,
+        (SELECT COUNT(*) FROM simulations r
+         WHERE r.status = 'running' AND r.created_by IS p.created_by) ASC,
+        (SELECT MAX(c.started_at) FROM simulations c
+         WHERE c.created_by IS p.created_by) ASC,
+        p.created_at ASC,
+        p.id ASC
     LIMIT 1
 )
 RETURNING *;
 
+-- name: RetrySimulation :execrows
+-- Returns a running simulation to the queue after a transient failure. No
+-- rows are affected when the worker no longer owns it (reaped, reclaimed by
+-- another worker or cancelled).
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    next_attempt_at = ?,
+    error_message = ?
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: ReleaseSimulation :execrows
+-- Returns a simulation interrupted by the shutdown of its worker to the
+-- queue. The interrupted run does not count as an attempt.
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    attempts = MAX(attempts - 1, 0)
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: RecordSimulationAttempt :exec
+INSERT INTO simulation_attempts (
+    simulation_id, attempt, worker_id, started_at, finished_at, outcome, error_message, transient, retry_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListSimulationAttempts :many
+SELECT * FROM simulation_attempts
+WHERE simulation_id = ?
+ORDER BY attempt ASC, id ASC;
+
+-- name: HeartbeatSimulation :execrows
+-- Extends the lease of a running simulation. No rows are affected when the
+-- worker no longer owns it (reaped or cancelled).
+UPDATE simulations
+SET heartbeat_at = ?,
+    lease_expires_at = ?
+WHERE id = ? AND worker_id = ? AND status = 'running';
+
+-- name: RequeueExpiredSimulations :many
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL
+WHERE status = 'running'
+    AND lease_expires_at < sqlc.arg('now')
+    AND attempts < sqlc.arg('max_attempts')
+RETURNING id;
+
+-- name: FailExpiredSimulations :many
+UPDATE simulations
+SET status = 'failed',
+    finished_at = sqlc.arg('now'),
+    error_message = 'worker lease expired after ' || attempts || ' attempts',
+    lease_expires_at = NULL
+WHERE status = 'running'
+    AND lease_expires_at < sqlc.arg('now')
+    AND attempts >= sqlc.arg('max_attempts')
+RETURNING id;
+
 -- name: InsertContestResult :exec
 INSERT INTO simulation_contest_results (
     simulation_id, contest, actual_numbers, best_hits,
@@ -82,6 +180,134 @@ WHERE simulation_id = ? AND best_hits >= ?
 ORDER BY best_hits DESC, contest ASC
 LIMIT ? OFFSET ?;
 
+-- name: ListContestResults :many
+-- Filters are skipped when NULL.
+SELECT id, simulation_id, contest, actual_numbers, best_hits, best_prediction_index, best_prediction_numbers, predictions_json, processed_at
+FROM simulation_contest_results
+WHERE simulation_id = sqlc.arg('simulation_id')
+    AND (sqlc.narg('min_hits') IS NULL OR best_hits >= sqlc.narg('min_hits'))
+    AND (sqlc.narg('max_hits') IS NULL OR best_hits <= sqlc.narg('max_hits'))
+    AND (sqlc.narg('contest_from') IS NULL OR contest >= sqlc.narg('contest_from'))
+    AND (sqlc.narg('contest_to') IS NULL OR contest <= sqlc.narg('contest_to'))
+ORDER BY CASE WHEN sqlc.arg('sort_by_hits') THEN best_hits END DESC, contest ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountContestResults :one
+SELECT COUNT(*) FROM simulation_contest_results
+WHERE simulation_id = sqlc.arg('simulation_id')
+    AND (sqlc.narg('min_hits') IS NULL OR best_hits >= sqlc.narg('min_hits'))
+    AND (sqlc.narg('max_hits') IS NULL OR best_hits <= sqlc.narg('max_hits'))
+    AND (sqlc.narg('contest_from') IS NULL OR contest >= sqlc.narg('contest_from'))
+    AND (sqlc.narg('contest_to') IS NULL OR contest <= sqlc.narg('contest_to'));
+
 -- name: CountSimulationsByStatus :one
 SELECT COUNT(*) FROM simulations
 WHERE status = ?;
+
+-- name: SearchSimulations :many
+-- Filters are skipped when NULL. metric_path and sort_path are JSON paths
+-- into summary_json; tags is a JSON array and all of its tags must match.
+SELECT s.* FROM simulations s
+WHERE (sqlc.narg('status') IS NULL OR s.status = sqlc.narg('status'))
+    AND (sqlc.narg('mode') IS NULL OR s.mode = sqlc.narg('mode'))
+    AND (sqlc.narg('recipe_prefix') IS NULL OR (s.recipe_name >= sqlc.narg('recipe_prefix') AND s.recipe_name < sqlc.narg('recipe_prefix') || char(1114111)))
+    AND (sqlc.narg('created_by') IS NULL OR s.created_by = sqlc.narg('created_by'))
+    AND (sqlc.narg('contest_from') IS NULL OR s.end_contest >= sqlc.narg('contest_from'))
+    AND (sqlc.narg('contest_to') IS NULL OR s.start_contest <= sqlc.narg('contest_to'))
+    AND (sqlc.narg('created_from') IS NULL OR s.created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to') IS NULL OR s.created_at < sqlc.narg('created_to'))
+    AND (sqlc.narg('metric_path') IS NULL OR CASE sqlc.narg('metric_op')
+        WHEN '>' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) > sqlc.narg('metric_value')
+        WHEN '>=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) >= sqlc.narg('metric_value')
+        WHEN '<' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) < sqlc.narg('metric_value')
+        WHEN '<=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) <= sqlc.narg('metric_value')
+        WHEN '=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) = sqlc.narg('metric_value')
+        ELSE 0 END)
+    AND (sqlc.narg('tags') IS NULL OR (
+        SELECT COUNT(DISTINCT t.tag) FROM simulation_tags t
+        WHERE t.simulation_id = s.id AND t.tag IN (SELECT value FROM json_each(sqlc.narg('tags')))
+    ) = json_array_length(sqlc.narg('tags')))
+ORDER BY
+    CASE WHEN sqlc.arg('sort_desc') THEN json_extract(s.summary_json, sqlc.narg('sort_path')) END DESC NULLS LAST,
+    CASE WHEN NOT sqlc.arg('sort_desc') THEN json_extract(s.summary_json, sqlc.narg('sort_path')) END ASC NULLS LAST,
+    s.created_at DESC, s.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchSimulations :one
+SELECT COUNT(*) FROM simulations s
+WHERE (sqlc.narg('status') IS NULL OR s.status = sqlc.narg('status'))
+    AND (sqlc.narg('mode') IS NULL OR s.mode = sqlc.narg('mode'))
+    AND (sqlc.narg('recipe_prefix') IS NULL OR (s.recipe_name >= sqlc.narg('recipe_prefix') AND s.recipe_name < sqlc.narg('recipe_prefix') || char(1114111)))
+    AND (sqlc.narg('created_by') IS NULL OR s.created_by = sqlc.narg('created_by'))
+    AND (sqlc.narg('contest_from') IS NULL OR s.end_contest >= sqlc.narg('contest_from'))
+    AND (sqlc.narg('contest_to') IS NULL OR s.start_contest <= sqlc.narg('contest_to'))
+    AND (sqlc.narg('created_from') IS NULL OR s.created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to') IS NULL OR s.created_at < sqlc.narg('created_to'))
+    AND (sqlc.narg('metric_path') IS NULL OR CASE sqlc.narg('metric_op')
+        WHEN '>' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) > sqlc.narg('metric_value')
+        WHEN '>=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) >= sqlc.narg('metric_value')
+        WHEN '<' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) < sqlc.narg('metric_value')
+        WHEN '<=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) <= sqlc.narg('metric_value')
+        WHEN '=' THEN json_extract(s.summary_json, sqlc.narg('metric_path')) = sqlc.narg('metric_value')
+        ELSE 0 END)
+    AND (sqlc.narg('tags') IS NULL OR (
+        SELECT COUNT(DISTINCT t.tag) FROM simulation_tags t
+        WHERE t.simulation_id = s.id AND t.tag IN (SELECT value FROM json_each(sqlc.narg('tags')))
+    ) = json_array_length(sqlc.narg('tags')));
+
+-- name: AddSimulationTag :exec
+INSERT OR IGNORE INTO simulation_tags (simulation_id, tag)
+VALUES (?, ?);
+
+-- name: DeleteSimulationTags :exec
+DELETE FROM simulation_tags
+WHERE simulation_id = ?;
+
+-- name: ListSimulationTags :many
+SELECT tag FROM simulation_tags
+WHERE simulation_id = ?
+ORDER BY tag ASC;
+
+-- name: RegisterWorker :exec
+-- Registers a worker process. Restarting with the same ID resets the entry.
+INSERT INTO workers (id, host, version, concurrency, active_jobs, started_at, heartbeat_at)
+VALUES (?, ?, ?, ?, 0, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+    host = excluded.host,
+    version = excluded.version,
+    concurrency = excluded.concurrency,
+    active_jobs = 0,
+    started_at = excluded.started_at,
+    heartbeat_at = excluded.heartbeat_at,
+    stopped_at = NULL;
+
+-- name: HeartbeatWorker :execrows
+UPDATE workers
+SET heartbeat_at = ?, active_jobs = ?
+WHERE id = ?;
+
+-- name: StopWorker :exec
+UPDATE workers
+SET stopped_at = ?, active_jobs = 0
+WHERE id = ?;
+
+-- name: ListWorkers :many
+-- Lists registered workers with the runs they finished since the given time.
+SELECT
+    w.id, w.host, w.version, w.concurrency, w.active_jobs, w.started_at, w.heartbeat_at, w.stopped_at,
+    (SELECT COUNT(*) FROM simulation_attempts a
+     WHERE a.worker_id = w.id AND a.outcome = 'completed' AND a.finished_at >= sqlc.arg('since')) AS jobs_completed,
+    (SELECT COUNT(*) FROM simulation_attempts a
+     WHERE a.worker_id = w.id AND a.outcome != 'completed' AND a.finished_at >= sqlc.arg('since')) AS jobs_failed,
+    CAST((SELECT COALESCE(SUM(s.end_contest - s.start_contest + 1), 0)
+     FROM simulation_attempts a
+     JOIN simulations s ON s.id = a.simulation_id
+     WHERE a.worker_id = w.id AND a.outcome = 'completed' AND a.finished_at >= sqlc.arg('since')) AS INTEGER) AS contests_completed
+FROM workers w
+ORDER BY w.stopped_at IS NOT NULL, w.started_at DESC, w.id;
+
+-- name: ListRunningSimulationsByWorker :many
+SELECT id, worker_id
+FROM simulations
+WHERE status = 'running' AND worker_id IS NOT NULL
+ORDER BY worker_id, id;
@@ -14,19 +14,58 @@ ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
 
 -- name: UpdateSweepJobProgress :exec
+-- Sets started_at the first time the sweep leaves pending. Finished and
+-- cancelled sweeps are left alone.
 UPDATE sweep_jobs
-SET completed_simulations = ?,
-    failed_simulations = ?,
-    status = ?
-WHERE id = ?;
+SET completed_simulations = sqlc.arg('completed_simulations'),
+    failed_simulations = sqlc.arg('failed_simulations'),
+    status = sqlc.arg('status'),
+    started_at = COALESCE(started_at, sqlc.narg('started_at'))
+WHERE id = sqlc.arg('id') AND finished_at IS NULL AND status != 'cancelled';
 
 -- name: FinishSweepJob :exec
 UPDATE sweep_jobs
 SET status = ?,
+    completed_simulations = ?,
+    failed_simulations = ?,
     finished_at = ?,
     run_duration_ms = ?
+WHERE id = ? AND finished_at IS NULL AND status != 'cancelled';
+
+-- name: CancelSweepJob :execrows
+-- Marks a sweep cancelled. No rows are affected when it already finished or
+-- was cancelled.
+UPDATE sweep_jobs
+SET status = 'cancelled',
+    finished_at = ?,
+    run_duration_ms = ?
+WHERE id = ? AND finished_at IS NULL AND status != 'cancelled';
+
+-- name: CancelSweepSimulations :many
+-- Cancels the simulations of a sweep that have the given status. Running
+-- simulations stop at their next heartbeat, when the worker finds it no
+-- longer owns them.
+UPDATE simulations
+SET status = 'cancelled',
+    finished_at = sqlc.arg('finished_at')
+WHERE status = sqlc.arg('status')
+    AND id IN (
+        SELECT simulation_id FROM sweep_simulations
+        WHERE sweep_job_id = sqlc.arg('sweep_job_id')
+    )
+RETURNING id;
+
+-- name: CacheSweepBest :exec
+UPDATE sweep_jobs
+SET best_metric = ?,
+    best_json = ?
 WHERE id = ?;
 
+-- name: GetSweepJobIDBySimulation :one
+SELECT sweep_job_id FROM sweep_simulations
+WHERE simulation_id = ?
+LIMIT 1;
+
 -- name: CreateSweepSimulation :exec
 INSERT INTO sweep_simulations (
     sweep_job_id, simulation_id, variation_index, variation_params
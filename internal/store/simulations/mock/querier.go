@@ -35,6 +35,20 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// AddSimulationTag mocks base method.
+func (m *MockQuerier) AddSimulationTag(ctx context.Context, arg simulations.AddSimulationTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSimulationTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSimulationTag indicates an expected call of AddSimulationTag.
+func (mr *MockQuerierMockRecorder) AddSimulationTag(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSimulationTag", reflect.TypeOf((*MockQuerier)(nil).AddSimulationTag), ctx, arg)
+}
+
 // CancelSimulation mocks base method.
 func (m *MockQuerier) CancelSimulation(ctx context.Context, arg simulations.CancelSimulationParams) error {
 	m.ctrl.T.Helper()
@@ -65,11 +79,12 @@ func (mr *MockQuerierMockRecorder) ClaimPendingSimulation(ctx, arg interface{})
 }
 
 // CompleteSimulation mocks base method.
-func (m *MockQuerier) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) error {
+func (m *MockQuerier) CompleteSimulation(ctx context.Context, arg simulations.CompleteSimulationParams) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CompleteSimulation", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CompleteSimulation indicates an expected call of CompleteSimulation.
@@ -78,6 +93,36 @@ func (mr *MockQuerierMockRecorder) CompleteSimulation(ctx, arg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSimulation", reflect.TypeOf((*MockQuerier)(nil).CompleteSimulation), ctx, arg)
 }
 
+// CountContestResults mocks base method.
+func (m *MockQuerier) CountContestResults(ctx context.Context, arg simulations.CountContestResultsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountContestResults", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountContestResults indicates an expected call of CountContestResults.
+func (mr *MockQuerierMockRecorder) CountContestResults(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountContestResults", reflect.TypeOf((*MockQuerier)(nil).CountContestResults), ctx, arg)
+}
+
+// CountSearchSimulations mocks base method.
+func (m *MockQuerier) CountSearchSimulations(ctx context.Context, arg simulations.CountSearchSimulationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchSimulations", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchSimulations indicates an expected call of CountSearchSimulations.
+func (mr *MockQuerierMockRecorder) CountSearchSimulations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchSimulations", reflect.TypeOf((*MockQuerier)(nil).CountSearchSimulations), ctx, arg)
+}
+
 // CountSimulationsByStatus mocks base method.
 func (m *MockQuerier) CountSimulationsByStatus(ctx context.Context, status string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -108,14 +153,44 @@ func (mr *MockQuerierMockRecorder) CreateSimulation(ctx, arg interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSimulation", reflect.TypeOf((*MockQuerier)(nil).CreateSimulation), ctx, arg)
 }
 
-// FailSimulation mocks base method.
-func (m *MockQuerier) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) error {
+// DeleteSimulationTags mocks base method.
+func (m *MockQuerier) DeleteSimulationTags(ctx context.Context, simulationID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FailSimulation", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteSimulationTags", ctx, simulationID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteSimulationTags indicates an expected call of DeleteSimulationTags.
+func (mr *MockQuerierMockRecorder) DeleteSimulationTags(ctx, simulationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSimulationTags", reflect.TypeOf((*MockQuerier)(nil).DeleteSimulationTags), ctx, simulationID)
+}
+
+// FailExpiredSimulations mocks base method.
+func (m *MockQuerier) FailExpiredSimulations(ctx context.Context, arg simulations.FailExpiredSimulationsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExpiredSimulations", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailExpiredSimulations indicates an expected call of FailExpiredSimulations.
+func (mr *MockQuerierMockRecorder) FailExpiredSimulations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExpiredSimulations", reflect.TypeOf((*MockQuerier)(nil).FailExpiredSimulations), ctx, arg)
+}
+
+// FailSimulation mocks base method.
+func (m *MockQuerier) FailSimulation(ctx context.Context, arg simulations.FailSimulationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailSimulation", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // FailSimulation indicates an expected call of FailSimulation.
 func (mr *MockQuerierMockRecorder) FailSimulation(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -167,6 +242,36 @@ func (mr *MockQuerierMockRecorder) GetSimulation(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulation", reflect.TypeOf((*MockQuerier)(nil).GetSimulation), ctx, id)
 }
 
+// HeartbeatSimulation mocks base method.
+func (m *MockQuerier) HeartbeatSimulation(ctx context.Context, arg simulations.HeartbeatSimulationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatSimulation", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeartbeatSimulation indicates an expected call of HeartbeatSimulation.
+func (mr *MockQuerierMockRecorder) HeartbeatSimulation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatSimulation", reflect.TypeOf((*MockQuerier)(nil).HeartbeatSimulation), ctx, arg)
+}
+
+// HeartbeatWorker mocks base method.
+func (m *MockQuerier) HeartbeatWorker(ctx context.Context, arg simulations.HeartbeatWorkerParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatWorker", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeartbeatWorker indicates an expected call of HeartbeatWorker.
+func (mr *MockQuerierMockRecorder) HeartbeatWorker(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatWorker", reflect.TypeOf((*MockQuerier)(nil).HeartbeatWorker), ctx, arg)
+}
+
 // InsertContestResult mocks base method.
 func (m *MockQuerier) InsertContestResult(ctx context.Context, arg simulations.InsertContestResultParams) error {
 	m.ctrl.T.Helper()
@@ -181,6 +286,66 @@ func (mr *MockQuerierMockRecorder) InsertContestResult(ctx, arg interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertContestResult", reflect.TypeOf((*MockQuerier)(nil).InsertContestResult), ctx, arg)
 }
 
+// ListContestResults mocks base method.
+func (m *MockQuerier) ListContestResults(ctx context.Context, arg simulations.ListContestResultsParams) ([]simulations.SimulationContestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContestResults", ctx, arg)
+	ret0, _ := ret[0].([]simulations.SimulationContestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContestResults indicates an expected call of ListContestResults.
+func (mr *MockQuerierMockRecorder) ListContestResults(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContestResults", reflect.TypeOf((*MockQuerier)(nil).ListContestResults), ctx, arg)
+}
+
+// ListRunningSimulationsByWorker mocks base method.
+func (m *MockQuerier) ListRunningSimulationsByWorker(ctx context.Context) ([]simulations.ListRunningSimulationsByWorkerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRunningSimulationsByWorker", ctx)
+	ret0, _ := ret[0].([]simulations.ListRunningSimulationsByWorkerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRunningSimulationsByWorker indicates an expected call of ListRunningSimulationsByWorker.
+func (mr *MockQuerierMockRecorder) ListRunningSimulationsByWorker(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunningSimulationsByWorker", reflect.TypeOf((*MockQuerier)(nil).ListRunningSimulationsByWorker), ctx)
+}
+
+// ListSimulationAttempts mocks base method.
+func (m *MockQuerier) ListSimulationAttempts(ctx context.Context, simulationID int64) ([]simulations.SimulationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSimulationAttempts", ctx, simulationID)
+	ret0, _ := ret[0].([]simulations.SimulationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSimulationAttempts indicates an expected call of ListSimulationAttempts.
+func (mr *MockQuerierMockRecorder) ListSimulationAttempts(ctx, simulationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSimulationAttempts", reflect.TypeOf((*MockQuerier)(nil).ListSimulationAttempts), ctx, simulationID)
+}
+
+// ListSimulationTags mocks base method.
+func (m *MockQuerier) ListSimulationTags(ctx context.Context, simulationID int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSimulationTags", ctx, simulationID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSimulationTags indicates an expected call of ListSimulationTags.
+func (mr *MockQuerierMockRecorder) ListSimulationTags(ctx, simulationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSimulationTags", reflect.TypeOf((*MockQuerier)(nil).ListSimulationTags), ctx, simulationID)
+}
+
 // ListSimulations mocks base method.
 func (m *MockQuerier) ListSimulations(ctx context.Context, arg simulations.ListSimulationsParams) ([]simulations.Simulation, error) {
 	m.ctrl.T.Helper()
@@ -211,6 +376,152 @@ func (mr *MockQuerierMockRecorder) ListSimulationsByStatus(ctx, arg interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSimulationsByStatus", reflect.TypeOf((*MockQuerier)(nil).ListSimulationsByStatus), ctx, arg)
 }
 
+// ListWorkers mocks base method.
+func (m *MockQuerier) ListWorkers(ctx context.Context, since string) ([]simulations.ListWorkersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkers", ctx, since)
+	ret0, _ := ret[0].([]simulations.ListWorkersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkers indicates an expected call of ListWorkers.
+func (mr *MockQuerierMockRecorder) ListWorkers(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkers", reflect.TypeOf((*MockQuerier)(nil).ListWorkers), ctx, since)
+}
+
+// RecordSimulationAttempt mocks base method.
+func (m *MockQuerier) RecordSimulationAttempt(ctx context.Context, arg simulations.RecordSimulationAttemptParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSimulationAttempt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSimulationAttempt indicates an expected call of RecordSimulationAttempt.
+func (mr *MockQuerierMockRecorder) RecordSimulationAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSimulationAttempt", reflect.TypeOf((*MockQuerier)(nil).RecordSimulationAttempt), ctx, arg)
+}
+
+// RegisterWorker mocks base method.
+func (m *MockQuerier) RegisterWorker(ctx context.Context, arg simulations.RegisterWorkerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWorker", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterWorker indicates an expected call of RegisterWorker.
+func (mr *MockQuerierMockRecorder) RegisterWorker(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorker", reflect.TypeOf((*MockQuerier)(nil).RegisterWorker), ctx, arg)
+}
+
+// ReleaseSimulation mocks base method.
+func (m *MockQuerier) ReleaseSimulation(ctx context.Context, arg simulations.ReleaseSimulationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSimulation", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseSimulation indicates an expected call of ReleaseSimulation.
+func (mr *MockQuerierMockRecorder) ReleaseSimulation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSimulation", reflect.TypeOf((*MockQuerier)(nil).ReleaseSimulation), ctx, arg)
+}
+
+// RequeueExpiredSimulations mocks base method.
+func (m *MockQuerier) RequeueExpiredSimulations(ctx context.Context, arg simulations.RequeueExpiredSimulationsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueExpiredSimulations", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueExpiredSimulations indicates an expected call of RequeueExpiredSimulations.
+func (mr *MockQuerierMockRecorder) RequeueExpiredSimulations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueExpiredSimulations", reflect.TypeOf((*MockQuerier)(nil).RequeueExpiredSimulations), ctx, arg)
+}
+
+// RetrySimulation mocks base method.
+func (m *MockQuerier) RetrySimulation(ctx context.Context, arg simulations.RetrySimulationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrySimulation", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrySimulation indicates an expected call of RetrySimulation.
+func (mr *MockQuerierMockRecorder) RetrySimulation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrySimulation", reflect.TypeOf((*MockQuerier)(nil).RetrySimulation), ctx, arg)
+}
+
+// SaveSimulationLog mocks base method.
+func (m *MockQuerier) SaveSimulationLog(ctx context.Context, arg simulations.SaveSimulationLogParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSimulationLog", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSimulationLog indicates an expected call of SaveSimulationLog.
+func (mr *MockQuerierMockRecorder) SaveSimulationLog(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSimulationLog", reflect.TypeOf((*MockQuerier)(nil).SaveSimulationLog), ctx, arg)
+}
+
+// SearchSimulations mocks base method.
+func (m *MockQuerier) SearchSimulations(ctx context.Context, arg simulations.SearchSimulationsParams) ([]simulations.Simulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSimulations", ctx, arg)
+	ret0, _ := ret[0].([]simulations.Simulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSimulations indicates an expected call of SearchSimulations.
+func (mr *MockQuerierMockRecorder) SearchSimulations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSimulations", reflect.TypeOf((*MockQuerier)(nil).SearchSimulations), ctx, arg)
+}
+
+// StopWorker mocks base method.
+func (m *MockQuerier) StopWorker(ctx context.Context, arg simulations.StopWorkerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopWorker", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopWorker indicates an expected call of StopWorker.
+func (mr *MockQuerierMockRecorder) StopWorker(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWorker", reflect.TypeOf((*MockQuerier)(nil).StopWorker), ctx, arg)
+}
+
+// UpdateQueuedSimulation mocks base method.
+func (m *MockQuerier) UpdateQueuedSimulation(ctx context.Context, arg simulations.UpdateQueuedSimulationParams) (simulations.Simulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQueuedSimulation", ctx, arg)
+	ret0, _ := ret[0].(simulations.Simulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQueuedSimulation indicates an expected call of UpdateQueuedSimulation.
+func (mr *MockQuerierMockRecorder) UpdateQueuedSimulation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueuedSimulation", reflect.TypeOf((*MockQuerier)(nil).UpdateQueuedSimulation), ctx, arg)
+}
+
 // UpdateSimulationStatus mocks base method.
 func (m *MockQuerier) UpdateSimulationStatus(ctx context.Context, arg simulations.UpdateSimulationStatusParams) error {
 	m.ctrl.T.Helper()
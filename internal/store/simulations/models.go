@@ -26,25 +26,44 @@ type AnalysisJob struct {
 }
 
 type Simulation struct {
-	ID            int64          `json:"id"`
-	CreatedAt     string         `json:"created_at"`
-	StartedAt     sql.NullString `json:"started_at"`
-	FinishedAt    sql.NullString `json:"finished_at"`
-	Status        string         `json:"status"`
-	RecipeName    sql.NullString `json:"recipe_name"`
-	RecipeJson    string         `json:"recipe_json"`
-	Mode          string         `json:"mode"`
-	StartContest  int64          `json:"start_contest"`
-	EndContest    int64          `json:"end_contest"`
-	WorkerID      sql.NullString `json:"worker_id"`
-	RunDurationMs sql.NullInt64  `json:"run_duration_ms"`
-	SummaryJson   sql.NullString `json:"summary_json"`
-	OutputBlob    []byte         `json:"output_blob"`
-	OutputName    sql.NullString `json:"output_name"`
-	LogBlob       []byte         `json:"log_blob"`
-	ErrorMessage  sql.NullString `json:"error_message"`
-	ErrorStack    sql.NullString `json:"error_stack"`
-	CreatedBy     sql.NullString `json:"created_by"`
+	ID             int64          `json:"id"`
+	CreatedAt      string         `json:"created_at"`
+	StartedAt      sql.NullString `json:"started_at"`
+	FinishedAt     sql.NullString `json:"finished_at"`
+	Status         string         `json:"status"`
+	RecipeName     sql.NullString `json:"recipe_name"`
+	RecipeJson     string         `json:"recipe_json"`
+	Mode           string         `json:"mode"`
+	StartContest   int64          `json:"start_contest"`
+	EndContest     int64          `json:"end_contest"`
+	WorkerID       sql.NullString `json:"worker_id"`
+	RunDurationMs  sql.NullInt64  `json:"run_duration_ms"`
+	SummaryJson    sql.NullString `json:"summary_json"`
+	OutputBlob     []byte         `json:"output_blob"`
+	OutputName     sql.NullString `json:"output_name"`
+	LogBlob        []byte         `json:"log_blob"`
+	ErrorMessage   sql.NullString `json:"error_message"`
+	ErrorStack     sql.NullString `json:"error_stack"`
+	CreatedBy      sql.NullString `json:"created_by"`
+	Attempts       int64          `json:"attempts"`
+	HeartbeatAt    sql.NullString `json:"heartbeat_at"`
+	LeaseExpiresAt sql.NullString `json:"lease_expires_at"`
+	Priority       int64          `json:"priority"`
+	Paused         int64          `json:"paused"`
+	NextAttemptAt  sql.NullString `json:"next_attempt_at"`
+}
+
+type SimulationAttempt struct {
+	ID           int64          `json:"id"`
+	SimulationID int64          `json:"simulation_id"`
+	Attempt      int64          `json:"attempt"`
+	WorkerID     sql.NullString `json:"worker_id"`
+	StartedAt    sql.NullString `json:"started_at"`
+	FinishedAt   string         `json:"finished_at"`
+	Outcome      string         `json:"outcome"`
+	ErrorMessage sql.NullString `json:"error_message"`
+	Transient    int64          `json:"transient"`
+	RetryAt      sql.NullString `json:"retry_at"`
 }
 
 type SimulationContestResult struct {
@@ -58,3 +77,19 @@ type SimulationContestResult struct {
 	PredictionsJson       string         `json:"predictions_json"`
 	ProcessedAt           string         `json:"processed_at"`
 }
+
+type SimulationTag struct {
+	SimulationID int64  `json:"simulation_id"`
+	Tag          string `json:"tag"`
+}
+
+type Worker struct {
+	ID          string         `json:"id"`
+	Host        string         `json:"host"`
+	Version     string         `json:"version"`
+	Concurrency int64          `json:"concurrency"`
+	ActiveJobs  int64          `json:"active_jobs"`
+	StartedAt   string         `json:"started_at"`
+	HeartbeatAt string         `json:"heartbeat_at"`
+	StoppedAt   sql.NullString `json:"stopped_at"`
+}
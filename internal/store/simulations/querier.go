@@ -9,18 +9,62 @@ import (
 )
 
 type Querier interface {
+	AddSimulationTag(ctx context.Context, arg AddSimulationTagParams) error
 	CancelSimulation(ctx context.Context, arg CancelSimulationParams) error
+	// Claims the next unpaused pending simulation with the highest priority.
+	// Simulations waiting for a retry are skipped until their next attempt.
+	// Within a priority, users take turns: the one with the fewest running jobs
+	// and the least recent claim goes first, so one large sweep cannot starve
+	// everyone else's simulations.
 	ClaimPendingSimulation(ctx context.Context, arg ClaimPendingSimulationParams) (Simulation, error)
-	CompleteSimulation(ctx context.Context, arg CompleteSimulationParams) error
+	// Completes a running simulation. No rows are affected when the worker no
+	// longer owns it (reaped or cancelled).
+	CompleteSimulation(ctx context.Context, arg CompleteSimulationParams) (int64, error)
+	CountContestResults(ctx context.Context, arg CountContestResultsParams) (int64, error)
+	CountSearchSimulations(ctx context.Context, arg CountSearchSimulationsParams) (int64, error)
 	CountSimulationsByStatus(ctx context.Context, status string) (int64, error)
 	CreateSimulation(ctx context.Context, arg CreateSimulationParams) (Simulation, error)
-	FailSimulation(ctx context.Context, arg FailSimulationParams) error
+	DeleteSimulationTags(ctx context.Context, simulationID int64) error
+	FailExpiredSimulations(ctx context.Context, arg FailExpiredSimulationsParams) ([]int64, error)
+	// Fails a running simulation. No rows are affected when the worker no longer
+	// owns it (reaped or cancelled).
+	FailSimulation(ctx context.Context, arg FailSimulationParams) (int64, error)
 	GetContestResults(ctx context.Context, arg GetContestResultsParams) ([]SimulationContestResult, error)
 	GetContestResultsByMinHits(ctx context.Context, arg GetContestResultsByMinHitsParams) ([]SimulationContestResult, error)
 	GetSimulation(ctx context.Context, id int64) (Simulation, error)
+	// Extends the lease of a running simulation. No rows are affected when the
+	// worker no longer owns it (reaped or cancelled).
+	HeartbeatSimulation(ctx context.Context, arg HeartbeatSimulationParams) (int64, error)
+	HeartbeatWorker(ctx context.Context, arg HeartbeatWorkerParams) (int64, error)
 	InsertContestResult(ctx context.Context, arg InsertContestResultParams) error
+	// Filters are skipped when NULL.
+	ListContestResults(ctx context.Context, arg ListContestResultsParams) ([]SimulationContestResult, error)
+	ListRunningSimulationsByWorker(ctx context.Context) ([]ListRunningSimulationsByWorkerRow, error)
+	ListSimulationAttempts(ctx context.Context, simulationID int64) ([]SimulationAttempt, error)
+	ListSimulationTags(ctx context.Context, simulationID int64) ([]string, error)
 	ListSimulations(ctx context.Context, arg ListSimulationsParams) ([]Simulation, error)
 	ListSimulationsByStatus(ctx context.Context, arg ListSimulationsByStatusParams) ([]Simulation, error)
+	// Lists registered workers with the runs they finished since the given time.
+	ListWorkers(ctx context.Context, since string) ([]ListWorkersRow, error)
+	RecordSimulationAttempt(ctx context.Context, arg RecordSimulationAttemptParams) error
+	// Registers a worker process. Restarting with the same ID resets the entry.
+	RegisterWorker(ctx context.Context, arg RegisterWorkerParams) error
+	// Returns a simulation interrupted by the shutdown of its worker to the
+	// queue. The interrupted run does not count as an attempt.
+	ReleaseSimulation(ctx context.Context, arg ReleaseSimulationParams) (int64, error)
+	RequeueExpiredSimulations(ctx context.Context, arg RequeueExpiredSimulationsParams) ([]int64, error)
+	// Returns a running simulation to the queue after a transient failure. No
+	// rows are affected when the worker no longer owns it (reaped, reclaimed by
+	// another worker or cancelled).
+	RetrySimulation(ctx context.Context, arg RetrySimulationParams) (int64, error)
+	SaveSimulationLog(ctx context.Context, arg SaveSimulationLogParams) error
+	// Filters are skipped when NULL. metric_path and sort_path are JSON paths
+	// into summary_json; tags is a JSON array and all of its tags must match.
+	SearchSimulations(ctx context.Context, arg SearchSimulationsParams) ([]Simulation, error)
+	StopWorker(ctx context.Context, arg StopWorkerParams) error
+	// Reprioritises, pauses or resumes a simulation that is still waiting in the
+	// queue. NULL arguments leave the current value unchanged.
+	UpdateQueuedSimulation(ctx context.Context, arg UpdateQueuedSimulationParams) (Simulation, error)
 	UpdateSimulationStatus(ctx context.Context, arg UpdateSimulationStatusParams) error
 }
 
@@ -10,6 +10,21 @@ import (
 	"database/sql"
 )
 
+const addSimulationTag = `-- name: AddSimulationTag :exec
+INSERT OR IGNORE INTO simulation_tags (simulation_id, tag)
+VALUES (?, ?)
+`
+
+type AddSimulationTagParams struct {
+	SimulationID int64  `json:"simulation_id"`
+	Tag          string `json:"tag"`
+}
+
+func (q *Queries) AddSimulationTag(ctx context.Context, arg AddSimulationTagParams) error {
+	_, err := q.db.ExecContext(ctx, addSimulationTag, arg.SimulationID, arg.Tag)
+	return err
+}
+
 const cancelSimulation = `-- name: CancelSimulation :exec
 UPDATE simulations
 SET status = 'cancelled',
@@ -30,24 +45,41 @@ func (q *Queries) CancelSimulation(ctx context.Context, arg CancelSimulationPara
 const claimPendingSimulation = `-- name: ClaimPendingSimulation :one
 UPDATE simulations
 SET status = 'running',
-    started_at = ?,
-    worker_id = ?
+    started_at = ?1,
+    heartbeat_at = ?1,
+    worker_id = ?2,
+    lease_expires_at = ?3,
+    next_attempt_at = NULL,
+    attempts = attempts + 1
 WHERE id = (
-    SELECT id FROM simulations
-    WHERE status = 'pending'
-    ORDER BY created_at ASC
+    SELECT p.id FROM simulations p
+    WHERE p.status = 'pending' AND p.paused = 0
+        AND (p.next_attempt_at IS NULL OR p.next_attempt_at <= ?1)
+    ORDER BY p.priority DESC,
+        (SELECT COUNT(*) FROM simulations r
+         WHERE r.status = 'running' AND r.created_by IS p.created_by) ASC,
+        (SELECT MAX(c.started_at) FROM simulations c
+         WHERE c.created_by IS p.created_by) ASC,
+        p.created_at ASC,
+        p.id ASC
     LIMIT 1
 )
-RETURNING id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by
+RETURNING id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at
 `
 
 type ClaimPendingSimulationParams struct {
-	StartedAt sql.NullString `json:"started_at"`
-	WorkerID  sql.NullString `json:"worker_id"`
+	StartedAt      sql.NullString `json:"started_at"`
+	WorkerID       sql.NullString `json:"worker_id"`
+	LeaseExpiresAt sql.NullString `json:"lease_expires_at"`
 }
 
+// Claims the next unpaused pending simulation with the highest priority.
+// Simulations waiting for a retry are skipped until their next attempt.
+// Within a priority, users take turns: the one with the fewest running jobs
+// and the least recent claim goes first, so one large sweep cannot starve
+// everyone else's simulations.
 func (q *Queries) ClaimPendingSimulation(ctx context.Context, arg ClaimPendingSimulationParams) (Simulation, error) {
-	row := q.db.QueryRowContext(ctx, claimPendingSimulation, arg.StartedAt, arg.WorkerID)
+	row := q.db.QueryRowContext(ctx, claimPendingSimulation, arg.StartedAt, arg.WorkerID, arg.LeaseExpiresAt)
 	var i Simulation
 	err := row.Scan(
 		&i.ID,
@@ -69,11 +101,17 @@ func (q *Queries) ClaimPendingSimulation(ctx context.Context, arg ClaimPendingSi
 		&i.ErrorMessage,
 		&i.ErrorStack,
 		&i.CreatedBy,
+		&i.Attempts,
+		&i.HeartbeatAt,
+		&i.LeaseExpiresAt,
+		&i.Priority,
+		&i.Paused,
+		&i.NextAttemptAt,
 	)
 	return i, err
 }
 
-const completeSimulation = `-- name: CompleteSimulation :exec
+const completeSimulation = `-- name: CompleteSimulation :execrows
 UPDATE simulations
 SET status = 'completed',
     finished_at = ?,
@@ -81,7 +119,7 @@ SET status = 'completed',
     summary_json = ?,
     output_blob = ?,
     output_name = ?
-WHERE id = ?
+WHERE id = ? AND worker_id = ? AND status = 'running'
 `
 
 type CompleteSimulationParams struct {
@@ -91,18 +129,113 @@ type CompleteSimulationParams struct {
 	OutputBlob    []byte         `json:"output_blob"`
 	OutputName    sql.NullString `json:"output_name"`
 	ID            int64          `json:"id"`
+	WorkerID      sql.NullString `json:"worker_id"`
 }
 
-func (q *Queries) CompleteSimulation(ctx context.Context, arg CompleteSimulationParams) error {
-	_, err := q.db.ExecContext(ctx, completeSimulation,
+// Completes a running simulation. No rows are affected when the worker no
+// longer owns it (reaped or cancelled).
+func (q *Queries) CompleteSimulation(ctx context.Context, arg CompleteSimulationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeSimulation,
 		arg.FinishedAt,
 		arg.RunDurationMs,
 		arg.SummaryJson,
 		arg.OutputBlob,
 		arg.OutputName,
 		arg.ID,
+		arg.WorkerID,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countContestResults = `-- name: CountContestResults :one
+SELECT COUNT(*) FROM simulation_contest_results
+WHERE simulation_id = ?1
+    AND (?2 IS NULL OR best_hits >= ?2)
+    AND (?3 IS NULL OR best_hits <= ?3)
+    AND (?4 IS NULL OR contest >= ?4)
+    AND (?5 IS NULL OR contest <= ?5)
+`
+
+type CountContestResultsParams struct {
+	SimulationID int64         `json:"simulation_id"`
+	MinHits      sql.NullInt64 `json:"min_hits"`
+	MaxHits      sql.NullInt64 `json:"max_hits"`
+	ContestFrom  sql.NullInt64 `json:"contest_from"`
+	ContestTo    sql.NullInt64 `json:"contest_to"`
+}
+
+func (q *Queries) CountContestResults(ctx context.Context, arg CountContestResultsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countContestResults,
+		arg.SimulationID,
+		arg.MinHits,
+		arg.MaxHits,
+		arg.ContestFrom,
+		arg.ContestTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchSimulations = `-- name: CountSearchSimulations :one
+SELECT COUNT(*) FROM simulations s
+WHERE (?1 IS NULL OR s.status = ?1)
+    AND (?2 IS NULL OR s.mode = ?2)
+    AND (?3 IS NULL OR (s.recipe_name >= ?3 AND s.recipe_name < ?3 || char(1114111)))
+    AND (?4 IS NULL OR s.created_by = ?4)
+    AND (?5 IS NULL OR s.end_contest >= ?5)
+    AND (?6 IS NULL OR s.start_contest <= ?6)
+    AND (?7 IS NULL OR s.created_at >= ?7)
+    AND (?8 IS NULL OR s.created_at < ?8)
+    AND (?9 IS NULL OR CASE ?10
+        WHEN '>' THEN json_extract(s.summary_json, ?9) > ?11
+        WHEN '>=' THEN json_extract(s.summary_json, ?9) >= ?11
+        WHEN '<' THEN json_extract(s.summary_json, ?9) < ?11
+        WHEN '<=' THEN json_extract(s.summary_json, ?9) <= ?11
+        WHEN '=' THEN json_extract(s.summary_json, ?9) = ?11
+        ELSE 0 END)
+    AND (?12 IS NULL OR (
+        SELECT COUNT(DISTINCT t.tag) FROM simulation_tags t
+        WHERE t.simulation_id = s.id AND t.tag IN (SELECT value FROM json_each(?12))
+    ) = json_array_length(?12))
+`
+
+type CountSearchSimulationsParams struct {
+	Status       sql.NullString  `json:"status"`
+	Mode         sql.NullString  `json:"mode"`
+	RecipePrefix sql.NullString  `json:"recipe_prefix"`
+	CreatedBy    sql.NullString  `json:"created_by"`
+	ContestFrom  sql.NullInt64   `json:"contest_from"`
+	ContestTo    sql.NullInt64   `json:"contest_to"`
+	CreatedFrom  sql.NullString  `json:"created_from"`
+	CreatedTo    sql.NullString  `json:"created_to"`
+	MetricPath   sql.NullString  `json:"metric_path"`
+	MetricOp     sql.NullString  `json:"metric_op"`
+	MetricValue  sql.NullFloat64 `json:"metric_value"`
+	Tags         sql.NullString  `json:"tags"`
+}
+
+func (q *Queries) CountSearchSimulations(ctx context.Context, arg CountSearchSimulationsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchSimulations,
+		arg.Status,
+		arg.Mode,
+		arg.RecipePrefix,
+		arg.CreatedBy,
+		arg.ContestFrom,
+		arg.ContestTo,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MetricPath,
+		arg.MetricOp,
+		arg.MetricValue,
+		arg.Tags,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
 const countSimulationsByStatus = `-- name: CountSimulationsByStatus :one
@@ -119,9 +252,9 @@ func (q *Queries) CountSimulationsByStatus(ctx context.Context, status string) (
 
 const createSimulation = `-- name: CreateSimulation :one
 INSERT INTO simulations (
-    recipe_name, recipe_json, mode, start_contest, end_contest, created_by
-) VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by
+    recipe_name, recipe_json, mode, start_contest, end_contest, created_by, priority
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at
 `
 
 type CreateSimulationParams struct {
@@ -131,6 +264,7 @@ type CreateSimulationParams struct {
 	StartContest int64          `json:"start_contest"`
 	EndContest   int64          `json:"end_contest"`
 	CreatedBy    sql.NullString `json:"created_by"`
+	Priority     int64          `json:"priority"`
 }
 
 func (q *Queries) CreateSimulation(ctx context.Context, arg CreateSimulationParams) (Simulation, error) {
@@ -141,6 +275,7 @@ func (q *Queries) CreateSimulation(ctx context.Context, arg CreateSimulationPara
 		arg.StartContest,
 		arg.EndContest,
 		arg.CreatedBy,
+		arg.Priority,
 	)
 	var i Simulation
 	err := row.Scan(
@@ -163,17 +298,73 @@ func (q *Queries) CreateSimulation(ctx context.Context, arg CreateSimulationPara
 		&i.ErrorMessage,
 		&i.ErrorStack,
 		&i.CreatedBy,
+		&i.Attempts,
+		&i.HeartbeatAt,
+		&i.LeaseExpiresAt,
+		&i.Priority,
+		&i.Paused,
+		&i.NextAttemptAt,
 	)
 	return i, err
 }
 
-const failSimulation = `-- name: FailSimulation :exec
+const deleteSimulationTags = `-- name: DeleteSimulationTags :exec
+DELETE FROM simulation_tags
+WHERE simulation_id = ?
+`
+
+func (q *Queries) DeleteSimulationTags(ctx context.Context, simulationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSimulationTags, simulationID)
+	return err
+}
+
+const failExpiredSimulations = `-- name: FailExpiredSimulations :many
+UPDATE simulations
+SET status = 'failed',
+    finished_at = ?1,
+    error_message = 'worker lease expired after ' || attempts || ' attempts',
+    lease_expires_at = NULL
+WHERE status = 'running'
+    AND lease_expires_at < ?1
+    AND attempts >= ?2
+RETURNING id
+`
+
+type FailExpiredSimulationsParams struct {
+	Now         sql.NullString `json:"now"`
+	MaxAttempts int64          `json:"max_attempts"`
+}
+
+func (q *Queries) FailExpiredSimulations(ctx context.Context, arg FailExpiredSimulationsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, failExpiredSimulations, arg.Now, arg.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failSimulation = `-- name: FailSimulation :execrows
 UPDATE simulations
 SET status = 'failed',
     finished_at = ?,
     error_message = ?,
     error_stack = ?
-WHERE id = ?
+WHERE id = ? AND worker_id = ? AND status = 'running'
 `
 
 type FailSimulationParams struct {
@@ -181,16 +372,23 @@ type FailSimulationParams struct {
 	ErrorMessage sql.NullString `json:"error_message"`
 	ErrorStack   sql.NullString `json:"error_stack"`
 	ID           int64          `json:"id"`
+	WorkerID     sql.NullString `json:"worker_id"`
 }
 
-func (q *Queries) FailSimulation(ctx context.Context, arg FailSimulationParams) error {
-	_, err := q.db.ExecContext(ctx, failSimulation,
+// Fails a running simulation. No rows are affected when the worker no longer
+// owns it (reaped or cancelled).
+func (q *Queries) FailSimulation(ctx context.Context, arg FailSimulationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failSimulation,
 		arg.FinishedAt,
 		arg.ErrorMessage,
 		arg.ErrorStack,
 		arg.ID,
+		arg.WorkerID,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getContestResults = `-- name: GetContestResults :many
@@ -294,7 +492,7 @@ func (q *Queries) GetContestResultsByMinHits(ctx context.Context, arg GetContest
 }
 
 const getSimulation = `-- name: GetSimulation :one
-SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by FROM simulations
+SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at FROM simulations
 WHERE id = ?
 LIMIT 1
 `
@@ -322,10 +520,65 @@ func (q *Queries) GetSimulation(ctx context.Context, id int64) (Simulation, erro
 		&i.ErrorMessage,
 		&i.ErrorStack,
 		&i.CreatedBy,
+		&i.Attempts,
+		&i.HeartbeatAt,
+		&i.LeaseExpiresAt,
+		&i.Priority,
+		&i.Paused,
+		&i.NextAttemptAt,
 	)
 	return i, err
 }
 
+const heartbeatSimulation = `-- name: HeartbeatSimulation :execrows
+UPDATE simulations
+SET heartbeat_at = ?,
+    lease_expires_at = ?
+WHERE id = ? AND worker_id = ? AND status = 'running'
+`
+
+type HeartbeatSimulationParams struct {
+	HeartbeatAt    sql.NullString `json:"heartbeat_at"`
+	LeaseExpiresAt sql.NullString `json:"lease_expires_at"`
+	ID             int64          `json:"id"`
+	WorkerID       sql.NullString `json:"worker_id"`
+}
+
+// Extends the lease of a running simulation. No rows are affected when the
+// worker no longer owns it (reaped or cancelled).
+func (q *Queries) HeartbeatSimulation(ctx context.Context, arg HeartbeatSimulationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, heartbeatSimulation,
+		arg.HeartbeatAt,
+		arg.LeaseExpiresAt,
+		arg.ID,
+		arg.WorkerID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const heartbeatWorker = `-- name: HeartbeatWorker :execrows
+UPDATE workers
+SET heartbeat_at = ?, active_jobs = ?
+WHERE id = ?
+`
+
+type HeartbeatWorkerParams struct {
+	HeartbeatAt string `json:"heartbeat_at"`
+	ActiveJobs  int64  `json:"active_jobs"`
+	ID          string `json:"id"`
+}
+
+func (q *Queries) HeartbeatWorker(ctx context.Context, arg HeartbeatWorkerParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, heartbeatWorker, arg.HeartbeatAt, arg.ActiveJobs, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const insertContestResult = `-- name: InsertContestResult :exec
 INSERT INTO simulation_contest_results (
     simulation_id, contest, actual_numbers, best_hits,
@@ -356,8 +609,181 @@ func (q *Queries) InsertContestResult(ctx context.Context, arg InsertContestResu
 	return err
 }
 
+const listContestResults = `-- name: ListContestResults :many
+SELECT id, simulation_id, contest, actual_numbers, best_hits, best_prediction_index, best_prediction_numbers, predictions_json, processed_at
+FROM simulation_contest_results
+WHERE simulation_id = ?1
+    AND (?2 IS NULL OR best_hits >= ?2)
+    AND (?3 IS NULL OR best_hits <= ?3)
+    AND (?4 IS NULL OR contest >= ?4)
+    AND (?5 IS NULL OR contest <= ?5)
+ORDER BY CASE WHEN ?6 THEN best_hits END DESC, contest ASC
+LIMIT ?7 OFFSET ?8
+`
+
+type ListContestResultsParams struct {
+	SimulationID int64         `json:"simulation_id"`
+	MinHits      sql.NullInt64 `json:"min_hits"`
+	MaxHits      sql.NullInt64 `json:"max_hits"`
+	ContestFrom  sql.NullInt64 `json:"contest_from"`
+	ContestTo    sql.NullInt64 `json:"contest_to"`
+	SortByHits   bool          `json:"sort_by_hits"`
+	Limit        int64         `json:"limit"`
+	Offset       int64         `json:"offset"`
+}
+
+// Filters are skipped when NULL.
+func (q *Queries) ListContestResults(ctx context.Context, arg ListContestResultsParams) ([]SimulationContestResult, error) {
+	rows, err := q.db.QueryContext(ctx, listContestResults,
+		arg.SimulationID,
+		arg.MinHits,
+		arg.MaxHits,
+		arg.ContestFrom,
+		arg.ContestTo,
+		arg.SortByHits,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SimulationContestResult
+	for rows.Next() {
+		var i SimulationContestResult
+		if err := rows.Scan(
+			&i.ID,
+			&i.SimulationID,
+			&i.Contest,
+			&i.ActualNumbers,
+			&i.BestHits,
+			&i.BestPredictionIndex,
+			&i.BestPredictionNumbers,
+			&i.PredictionsJson,
+			&i.ProcessedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRunningSimulationsByWorker = `-- name: ListRunningSimulationsByWorker :many
+SELECT id, worker_id
+FROM simulations
+WHERE status = 'running' AND worker_id IS NOT NULL
+ORDER BY worker_id, id
+`
+
+type ListRunningSimulationsByWorkerRow struct {
+	ID       int64          `json:"id"`
+	WorkerID sql.NullString `json:"worker_id"`
+}
+
+func (q *Queries) ListRunningSimulationsByWorker(ctx context.Context) ([]ListRunningSimulationsByWorkerRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRunningSimulationsByWorker)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRunningSimulationsByWorkerRow
+	for rows.Next() {
+		var i ListRunningSimulationsByWorkerRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSimulationAttempts = `-- name: ListSimulationAttempts :many
+SELECT id, simulation_id, attempt, worker_id, started_at, finished_at, outcome, error_message, transient, retry_at FROM simulation_attempts
+WHERE simulation_id = ?
+ORDER BY attempt ASC, id ASC
+`
+
+func (q *Queries) ListSimulationAttempts(ctx context.Context, simulationID int64) ([]SimulationAttempt, error) {
+	rows, err := q.db.QueryContext(ctx, listSimulationAttempts, simulationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SimulationAttempt
+	for rows.Next() {
+		var i SimulationAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.SimulationID,
+			&i.Attempt,
+			&i.WorkerID,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Outcome,
+			&i.ErrorMessage,
+			&i.Transient,
+			&i.RetryAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSimulationTags = `-- name: ListSimulationTags :many
+SELECT tag FROM simulation_tags
+WHERE simulation_id = ?
+ORDER BY tag ASC
+`
+
+func (q *Queries) ListSimulationTags(ctx context.Context, simulationID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listSimulationTags, simulationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSimulations = `-- name: ListSimulations :many
-SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by FROM simulations
+SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at FROM simulations
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -396,6 +822,12 @@ func (q *Queries) ListSimulations(ctx context.Context, arg ListSimulationsParams
 			&i.ErrorMessage,
 			&i.ErrorStack,
 			&i.CreatedBy,
+			&i.Attempts,
+			&i.HeartbeatAt,
+			&i.LeaseExpiresAt,
+			&i.Priority,
+			&i.Paused,
+			&i.NextAttemptAt,
 		); err != nil {
 			return nil, err
 		}
@@ -411,7 +843,7 @@ func (q *Queries) ListSimulations(ctx context.Context, arg ListSimulationsParams
 }
 
 const listSimulationsByStatus = `-- name: ListSimulationsByStatus :many
-SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by FROM simulations
+SELECT id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at FROM simulations
 WHERE status = ?
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
@@ -452,6 +884,77 @@ func (q *Queries) ListSimulationsByStatus(ctx context.Context, arg ListSimulatio
 			&i.ErrorMessage,
 			&i.ErrorStack,
 			&i.CreatedBy,
+			&i.Attempts,
+			&i.HeartbeatAt,
+			&i.LeaseExpiresAt,
+			&i.Priority,
+			&i.Paused,
+			&i.NextAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkers = `-- name: ListWorkers :many
+SELECT
+    w.id, w.host, w.version, w.concurrency, w.active_jobs, w.started_at, w.heartbeat_at, w.stopped_at,
+    (SELECT COUNT(*) FROM simulation_attempts a
+     WHERE a.worker_id = w.id AND a.outcome = 'completed' AND a.finished_at >= ?1) AS jobs_completed,
+    (SELECT COUNT(*) FROM simulation_attempts a
+     WHERE a.worker_id = w.id AND a.outcome != 'completed' AND a.finished_at >= ?1) AS jobs_failed,
+    CAST((SELECT COALESCE(SUM(s.end_contest - s.start_contest + 1), 0)
+     FROM simulation_attempts a
+     JOIN simulations s ON s.id = a.simulation_id
+     WHERE a.worker_id = w.id AND a.outcome = 'completed' AND a.finished_at >= ?1) AS INTEGER) AS contests_completed
+FROM workers w
+ORDER BY w.stopped_at IS NOT NULL, w.started_at DESC, w.id
+`
+
+type ListWorkersRow struct {
+	ID                string         `json:"id"`
+	Host              string         `json:"host"`
+	Version           string         `json:"version"`
+	Concurrency       int64          `json:"concurrency"`
+	ActiveJobs        int64          `json:"active_jobs"`
+	StartedAt         string         `json:"started_at"`
+	HeartbeatAt       string         `json:"heartbeat_at"`
+	StoppedAt         sql.NullString `json:"stopped_at"`
+	JobsCompleted     int64          `json:"jobs_completed"`
+	JobsFailed        int64          `json:"jobs_failed"`
+	ContestsCompleted int64          `json:"contests_completed"`
+}
+
+// Lists registered workers with the runs they finished since the given time.
+func (q *Queries) ListWorkers(ctx context.Context, since string) ([]ListWorkersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkers, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorkersRow
+	for rows.Next() {
+		var i ListWorkersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Host,
+			&i.Version,
+			&i.Concurrency,
+			&i.ActiveJobs,
+			&i.StartedAt,
+			&i.HeartbeatAt,
+			&i.StoppedAt,
+			&i.JobsCompleted,
+			&i.JobsFailed,
+			&i.ContestsCompleted,
 		); err != nil {
 			return nil, err
 		}
@@ -466,6 +969,364 @@ func (q *Queries) ListSimulationsByStatus(ctx context.Context, arg ListSimulatio
 	return items, nil
 }
 
+const recordSimulationAttempt = `-- name: RecordSimulationAttempt :exec
+INSERT INTO simulation_attempts (
+    simulation_id, attempt, worker_id, started_at, finished_at, outcome, error_message, transient, retry_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type RecordSimulationAttemptParams struct {
+	SimulationID int64          `json:"simulation_id"`
+	Attempt      int64          `json:"attempt"`
+	WorkerID     sql.NullString `json:"worker_id"`
+	StartedAt    sql.NullString `json:"started_at"`
+	FinishedAt   string         `json:"finished_at"`
+	Outcome      string         `json:"outcome"`
+	ErrorMessage sql.NullString `json:"error_message"`
+	Transient    int64          `json:"transient"`
+	RetryAt      sql.NullString `json:"retry_at"`
+}
+
+func (q *Queries) RecordSimulationAttempt(ctx context.Context, arg RecordSimulationAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordSimulationAttempt,
+		arg.SimulationID,
+		arg.Attempt,
+		arg.WorkerID,
+		arg.StartedAt,
+		arg.FinishedAt,
+		arg.Outcome,
+		arg.ErrorMessage,
+		arg.Transient,
+		arg.RetryAt,
+	)
+	return err
+}
+
+const registerWorker = `-- name: RegisterWorker :exec
+INSERT INTO workers (id, host, version, concurrency, active_jobs, started_at, heartbeat_at)
+VALUES (?, ?, ?, ?, 0, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+    host = excluded.host,
+    version = excluded.version,
+    concurrency = excluded.concurrency,
+    active_jobs = 0,
+    started_at = excluded.started_at,
+    heartbeat_at = excluded.heartbeat_at,
+    stopped_at = NULL
+`
+
+type RegisterWorkerParams struct {
+	ID          string `json:"id"`
+	Host        string `json:"host"`
+	Version     string `json:"version"`
+	Concurrency int64  `json:"concurrency"`
+	StartedAt   string `json:"started_at"`
+	HeartbeatAt string `json:"heartbeat_at"`
+}
+
+// Registers a worker process. Restarting with the same ID resets the entry.
+func (q *Queries) RegisterWorker(ctx context.Context, arg RegisterWorkerParams) error {
+	_, err := q.db.ExecContext(ctx, registerWorker,
+		arg.ID,
+		arg.Host,
+		arg.Version,
+		arg.Concurrency,
+		arg.StartedAt,
+		arg.HeartbeatAt,
+	)
+	return err
+}
+
+const releaseSimulation = `-- name: ReleaseSimulation :execrows
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    attempts = MAX(attempts - 1, 0)
+WHERE id = ? AND worker_id = ? AND status = 'running'
+`
+
+type ReleaseSimulationParams struct {
+	ID       int64          `json:"id"`
+	WorkerID sql.NullString `json:"worker_id"`
+}
+
+// Returns a simulation interrupted by the shutdown of its worker to the
+// queue. The interrupted run does not count as an attempt.
+func (q *Queries) ReleaseSimulation(ctx context.Context, arg ReleaseSimulationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseSimulation, arg.ID, arg.WorkerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requeueExpiredSimulations = `-- name: RequeueExpiredSimulations :many
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL
+WHERE status = 'running'
+    AND lease_expires_at < ?1
+    AND attempts < ?2
+RETURNING id
+`
+
+type RequeueExpiredSimulationsParams struct {
+	Now         sql.NullString `json:"now"`
+	MaxAttempts int64          `json:"max_attempts"`
+}
+
+func (q *Queries) RequeueExpiredSimulations(ctx context.Context, arg RequeueExpiredSimulationsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, requeueExpiredSimulations, arg.Now, arg.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retrySimulation = `-- name: RetrySimulation :execrows
+UPDATE simulations
+SET status = 'pending',
+    started_at = NULL,
+    worker_id = NULL,
+    lease_expires_at = NULL,
+    next_attempt_at = ?,
+    error_message = ?
+WHERE id = ? AND worker_id = ? AND status = 'running'
+`
+
+type RetrySimulationParams struct {
+	NextAttemptAt sql.NullString `json:"next_attempt_at"`
+	ErrorMessage  sql.NullString `json:"error_message"`
+	ID            int64          `json:"id"`
+	WorkerID      sql.NullString `json:"worker_id"`
+}
+
+// Returns a running simulation to the queue after a transient failure. No
+// rows are affected when the worker no longer owns it (reaped, reclaimed by
+// another worker or cancelled).
+func (q *Queries) RetrySimulation(ctx context.Context, arg RetrySimulationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, retrySimulation, arg.NextAttemptAt, arg.ErrorMessage, arg.ID, arg.WorkerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const saveSimulationLog = `-- name: SaveSimulationLog :exec
+UPDATE simulations
+SET log_blob = ?
+WHERE id = ?
+`
+
+type SaveSimulationLogParams struct {
+	LogBlob []byte `json:"log_blob"`
+	ID      int64  `json:"id"`
+}
+
+func (q *Queries) SaveSimulationLog(ctx context.Context, arg SaveSimulationLogParams) error {
+	_, err := q.db.ExecContext(ctx, saveSimulationLog, arg.LogBlob, arg.ID)
+	return err
+}
+
+const searchSimulations = `-- name: SearchSimulations :many
+SELECT s.id, s.created_at, s.started_at, s.finished_at, s.status, s.recipe_name, s.recipe_json, s.mode, s.start_contest, s.end_contest, s.worker_id, s.run_duration_ms, s.summary_json, s.output_blob, s.output_name, s.log_blob, s.error_message, s.error_stack, s.created_by, s.attempts, s.heartbeat_at, s.lease_expires_at, s.priority, s.paused, s.next_attempt_at FROM simulations s
+WHERE (?1 IS NULL OR s.status = ?1)
+    AND (?2 IS NULL OR s.mode = ?2)
+    AND (?3 IS NULL OR (s.recipe_name >= ?3 AND s.recipe_name < ?3 || char(1114111)))
+    AND (?4 IS NULL OR s.created_by = ?4)
+    AND (?5 IS NULL OR s.end_contest >= ?5)
+    AND (?6 IS NULL OR s.start_contest <= ?6)
+    AND (?7 IS NULL OR s.created_at >= ?7)
+    AND (?8 IS NULL OR s.created_at < ?8)
+    AND (?9 IS NULL OR CASE ?10
+        WHEN '>' THEN json_extract(s.summary_json, ?9) > ?11
+        WHEN '>=' THEN json_extract(s.summary_json, ?9) >= ?11
+        WHEN '<' THEN json_extract(s.summary_json, ?9) < ?11
+        WHEN '<=' THEN json_extract(s.summary_json, ?9) <= ?11
+        WHEN '=' THEN json_extract(s.summary_json, ?9) = ?11
+        ELSE 0 END)
+    AND (?12 IS NULL OR (
+        SELECT COUNT(DISTINCT t.tag) FROM simulation_tags t
+        WHERE t.simulation_id = s.id AND t.tag IN (SELECT value FROM json_each(?12))
+    ) = json_array_length(?12))
+ORDER BY
+    CASE WHEN ?13 THEN json_extract(s.summary_json, ?14) END DESC NULLS LAST,
+    CASE WHEN NOT ?13 THEN json_extract(s.summary_json, ?14) END ASC NULLS LAST,
+    s.created_at DESC, s.id DESC
+LIMIT ?15 OFFSET ?16
+`
+
+type SearchSimulationsParams struct {
+	Status       sql.NullString  `json:"status"`
+	Mode         sql.NullString  `json:"mode"`
+	RecipePrefix sql.NullString  `json:"recipe_prefix"`
+	CreatedBy    sql.NullString  `json:"created_by"`
+	ContestFrom  sql.NullInt64   `json:"contest_from"`
+	ContestTo    sql.NullInt64   `json:"contest_to"`
+	CreatedFrom  sql.NullString  `json:"created_from"`
+	CreatedTo    sql.NullString  `json:"created_to"`
+	MetricPath   sql.NullString  `json:"metric_path"`
+	MetricOp     sql.NullString  `json:"metric_op"`
+	MetricValue  sql.NullFloat64 `json:"metric_value"`
+	Tags         sql.NullString  `json:"tags"`
+	SortDesc     bool            `json:"sort_desc"`
+	SortPath     sql.NullString  `json:"sort_path"`
+	Limit        int64           `json:"limit"`
+	Offset       int64           `json:"offset"`
+}
+
+// Filters are skipped when NULL. metric_path and sort_path are JSON paths
+// into summary_json; tags is a JSON array and all of its tags must match.
+func (q *Queries) SearchSimulations(ctx context.Context, arg SearchSimulationsParams) ([]Simulation, error) {
+	rows, err := q.db.QueryContext(ctx, searchSimulations,
+		arg.Status,
+		arg.Mode,
+		arg.RecipePrefix,
+		arg.CreatedBy,
+		arg.ContestFrom,
+		arg.ContestTo,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MetricPath,
+		arg.MetricOp,
+		arg.MetricValue,
+		arg.Tags,
+		arg.SortDesc,
+		arg.SortPath,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Simulation
+	for rows.Next() {
+		var i Simulation
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Status,
+			&i.RecipeName,
+			&i.RecipeJson,
+			&i.Mode,
+			&i.StartContest,
+			&i.EndContest,
+			&i.WorkerID,
+			&i.RunDurationMs,
+			&i.SummaryJson,
+			&i.OutputBlob,
+			&i.OutputName,
+			&i.LogBlob,
+			&i.ErrorMessage,
+			&i.ErrorStack,
+			&i.CreatedBy,
+			&i.Attempts,
+			&i.HeartbeatAt,
+			&i.LeaseExpiresAt,
+			&i.Priority,
+			&i.Paused,
+			&i.NextAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const stopWorker = `-- name: StopWorker :exec
+UPDATE workers
+SET stopped_at = ?, active_jobs = 0
+WHERE id = ?
+`
+
+type StopWorkerParams struct {
+	StoppedAt sql.NullString `json:"stopped_at"`
+	ID        string         `json:"id"`
+}
+
+func (q *Queries) StopWorker(ctx context.Context, arg StopWorkerParams) error {
+	_, err := q.db.ExecContext(ctx, stopWorker, arg.StoppedAt, arg.ID)
+	return err
+}
+
+const updateQueuedSimulation = `-- name: UpdateQueuedSimulation :one
+UPDATE simulations
+SET priority = COALESCE(?1, priority),
+    paused = COALESCE(?2, paused)
+WHERE id = ?3 AND status = 'pending'
+RETURNING id, created_at, started_at, finished_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, worker_id, run_duration_ms, summary_json, output_blob, output_name, log_blob, error_message, error_stack, created_by, attempts, heartbeat_at, lease_expires_at, priority, paused, next_attempt_at
+`
+
+type UpdateQueuedSimulationParams struct {
+	Priority sql.NullInt64 `json:"priority"`
+	Paused   sql.NullInt64 `json:"paused"`
+	ID       int64         `json:"id"`
+}
+
+// Reprioritises, pauses or resumes a simulation that is still waiting in the
+// queue. NULL arguments leave the current value unchanged.
+func (q *Queries) UpdateQueuedSimulation(ctx context.Context, arg UpdateQueuedSimulationParams) (Simulation, error) {
+	row := q.db.QueryRowContext(ctx, updateQueuedSimulation, arg.Priority, arg.Paused, arg.ID)
+	var i Simulation
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Status,
+		&i.RecipeName,
+		&i.RecipeJson,
+		&i.Mode,
+		&i.StartContest,
+		&i.EndContest,
+		&i.WorkerID,
+		&i.RunDurationMs,
+		&i.SummaryJson,
+		&i.OutputBlob,
+		&i.OutputName,
+		&i.LogBlob,
+		&i.ErrorMessage,
+		&i.ErrorStack,
+		&i.CreatedBy,
+		&i.Attempts,
+		&i.HeartbeatAt,
+		&i.LeaseExpiresAt,
+		&i.Priority,
+		&i.Paused,
+		&i.NextAttemptAt,
+	)
+	return i, err
+}
+
 const updateSimulationStatus = `-- name: UpdateSimulationStatus :exec
 UPDATE simulations
 SET status = ?, started_at = ?, worker_id = ?
@@ -0,0 +1,285 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/store/sweep_execution/querier.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sweep_execution "github.com/garnizeh/luckyfive/internal/store/sweep_execution"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuerier is a mock of Querier interface.
+type MockQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuerierMockRecorder
+}
+
+// MockQuerierMockRecorder is the mock recorder for MockQuerier.
+type MockQuerierMockRecorder struct {
+	mock *MockQuerier
+}
+
+// NewMockQuerier creates a new mock instance.
+func NewMockQuerier(ctrl *gomock.Controller) *MockQuerier {
+	mock := &MockQuerier{ctrl: ctrl}
+	mock.recorder = &MockQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
+	return m.recorder
+}
+
+// CacheSweepBest mocks base method.
+func (m *MockQuerier) CacheSweepBest(ctx context.Context, arg sweep_execution.CacheSweepBestParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheSweepBest", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CacheSweepBest indicates an expected call of CacheSweepBest.
+func (mr *MockQuerierMockRecorder) CacheSweepBest(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheSweepBest", reflect.TypeOf((*MockQuerier)(nil).CacheSweepBest), ctx, arg)
+}
+
+// CancelSweepJob mocks base method.
+func (m *MockQuerier) CancelSweepJob(ctx context.Context, arg sweep_execution.CancelSweepJobParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSweepJob", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSweepJob indicates an expected call of CancelSweepJob.
+func (mr *MockQuerierMockRecorder) CancelSweepJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSweepJob", reflect.TypeOf((*MockQuerier)(nil).CancelSweepJob), ctx, arg)
+}
+
+// CancelSweepSimulations mocks base method.
+func (m *MockQuerier) CancelSweepSimulations(ctx context.Context, arg sweep_execution.CancelSweepSimulationsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSweepSimulations", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSweepSimulations indicates an expected call of CancelSweepSimulations.
+func (mr *MockQuerierMockRecorder) CancelSweepSimulations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSweepSimulations", reflect.TypeOf((*MockQuerier)(nil).CancelSweepSimulations), ctx, arg)
+}
+
+// CreateComparison mocks base method.
+func (m *MockQuerier) CreateComparison(ctx context.Context, arg sweep_execution.CreateComparisonParams) (sweep_execution.Comparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComparison", ctx, arg)
+	ret0, _ := ret[0].(sweep_execution.Comparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComparison indicates an expected call of CreateComparison.
+func (mr *MockQuerierMockRecorder) CreateComparison(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComparison", reflect.TypeOf((*MockQuerier)(nil).CreateComparison), ctx, arg)
+}
+
+// CreateSweepJob mocks base method.
+func (m *MockQuerier) CreateSweepJob(ctx context.Context, arg sweep_execution.CreateSweepJobParams) (sweep_execution.SweepJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSweepJob", ctx, arg)
+	ret0, _ := ret[0].(sweep_execution.SweepJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSweepJob indicates an expected call of CreateSweepJob.
+func (mr *MockQuerierMockRecorder) CreateSweepJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSweepJob", reflect.TypeOf((*MockQuerier)(nil).CreateSweepJob), ctx, arg)
+}
+
+// CreateSweepSimulation mocks base method.
+func (m *MockQuerier) CreateSweepSimulation(ctx context.Context, arg sweep_execution.CreateSweepSimulationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSweepSimulation", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSweepSimulation indicates an expected call of CreateSweepSimulation.
+func (mr *MockQuerierMockRecorder) CreateSweepSimulation(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSweepSimulation", reflect.TypeOf((*MockQuerier)(nil).CreateSweepSimulation), ctx, arg)
+}
+
+// FinishSweepJob mocks base method.
+func (m *MockQuerier) FinishSweepJob(ctx context.Context, arg sweep_execution.FinishSweepJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSweepJob", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishSweepJob indicates an expected call of FinishSweepJob.
+func (mr *MockQuerierMockRecorder) FinishSweepJob(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSweepJob", reflect.TypeOf((*MockQuerier)(nil).FinishSweepJob), ctx, arg)
+}
+
+// GetComparison mocks base method.
+func (m *MockQuerier) GetComparison(ctx context.Context, id int64) (sweep_execution.Comparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComparison", ctx, id)
+	ret0, _ := ret[0].(sweep_execution.Comparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComparison indicates an expected call of GetComparison.
+func (mr *MockQuerierMockRecorder) GetComparison(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComparison", reflect.TypeOf((*MockQuerier)(nil).GetComparison), ctx, id)
+}
+
+// GetComparisonMetrics mocks base method.
+func (m *MockQuerier) GetComparisonMetrics(ctx context.Context, comparisonID int64) ([]sweep_execution.ComparisonMetric, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComparisonMetrics", ctx, comparisonID)
+	ret0, _ := ret[0].([]sweep_execution.ComparisonMetric)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComparisonMetrics indicates an expected call of GetComparisonMetrics.
+func (mr *MockQuerierMockRecorder) GetComparisonMetrics(ctx, comparisonID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComparisonMetrics", reflect.TypeOf((*MockQuerier)(nil).GetComparisonMetrics), ctx, comparisonID)
+}
+
+// GetSweepJob mocks base method.
+func (m *MockQuerier) GetSweepJob(ctx context.Context, id int64) (sweep_execution.SweepJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweepJob", ctx, id)
+	ret0, _ := ret[0].(sweep_execution.SweepJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweepJob indicates an expected call of GetSweepJob.
+func (mr *MockQuerierMockRecorder) GetSweepJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweepJob", reflect.TypeOf((*MockQuerier)(nil).GetSweepJob), ctx, id)
+}
+
+// GetSweepJobIDBySimulation mocks base method.
+func (m *MockQuerier) GetSweepJobIDBySimulation(ctx context.Context, simulationID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweepJobIDBySimulation", ctx, simulationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweepJobIDBySimulation indicates an expected call of GetSweepJobIDBySimulation.
+func (mr *MockQuerierMockRecorder) GetSweepJobIDBySimulation(ctx, simulationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweepJobIDBySimulation", reflect.TypeOf((*MockQuerier)(nil).GetSweepJobIDBySimulation), ctx, simulationID)
+}
+
+// GetSweepSimulationDetails mocks base method.
+func (m *MockQuerier) GetSweepSimulationDetails(ctx context.Context, sweepJobID int64) ([]sweep_execution.GetSweepSimulationDetailsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweepSimulationDetails", ctx, sweepJobID)
+	ret0, _ := ret[0].([]sweep_execution.GetSweepSimulationDetailsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweepSimulationDetails indicates an expected call of GetSweepSimulationDetails.
+func (mr *MockQuerierMockRecorder) GetSweepSimulationDetails(ctx, sweepJobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweepSimulationDetails", reflect.TypeOf((*MockQuerier)(nil).GetSweepSimulationDetails), ctx, sweepJobID)
+}
+
+// GetSweepSimulations mocks base method.
+func (m *MockQuerier) GetSweepSimulations(ctx context.Context, sweepJobID int64) ([]sweep_execution.SweepSimulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweepSimulations", ctx, sweepJobID)
+	ret0, _ := ret[0].([]sweep_execution.SweepSimulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweepSimulations indicates an expected call of GetSweepSimulations.
+func (mr *MockQuerierMockRecorder) GetSweepSimulations(ctx, sweepJobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweepSimulations", reflect.TypeOf((*MockQuerier)(nil).GetSweepSimulations), ctx, sweepJobID)
+}
+
+// InsertComparisonMetric mocks base method.
+func (m *MockQuerier) InsertComparisonMetric(ctx context.Context, arg sweep_execution.InsertComparisonMetricParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertComparisonMetric", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertComparisonMetric indicates an expected call of InsertComparisonMetric.
+func (mr *MockQuerierMockRecorder) InsertComparisonMetric(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertComparisonMetric", reflect.TypeOf((*MockQuerier)(nil).InsertComparisonMetric), ctx, arg)
+}
+
+// ListSweepJobs mocks base method.
+func (m *MockQuerier) ListSweepJobs(ctx context.Context, arg sweep_execution.ListSweepJobsParams) ([]sweep_execution.SweepJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSweepJobs", ctx, arg)
+	ret0, _ := ret[0].([]sweep_execution.SweepJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSweepJobs indicates an expected call of ListSweepJobs.
+func (mr *MockQuerierMockRecorder) ListSweepJobs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSweepJobs", reflect.TypeOf((*MockQuerier)(nil).ListSweepJobs), ctx, arg)
+}
+
+// UpdateComparisonResult mocks base method.
+func (m *MockQuerier) UpdateComparisonResult(ctx context.Context, arg sweep_execution.UpdateComparisonResultParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComparisonResult", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComparisonResult indicates an expected call of UpdateComparisonResult.
+func (mr *MockQuerierMockRecorder) UpdateComparisonResult(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComparisonResult", reflect.TypeOf((*MockQuerier)(nil).UpdateComparisonResult), ctx, arg)
+}
+
+// UpdateSweepJobProgress mocks base method.
+func (m *MockQuerier) UpdateSweepJobProgress(ctx context.Context, arg sweep_execution.UpdateSweepJobProgressParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSweepJobProgress", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSweepJobProgress indicates an expected call of UpdateSweepJobProgress.
+func (mr *MockQuerierMockRecorder) UpdateSweepJobProgress(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSweepJobProgress", reflect.TypeOf((*MockQuerier)(nil).UpdateSweepJobProgress), ctx, arg)
+}
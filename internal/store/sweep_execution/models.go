@@ -94,6 +94,8 @@ type SweepJob struct {
 	FinishedAt           sql.NullString `json:"finished_at"`
 	RunDurationMs        sql.NullInt64  `json:"run_duration_ms"`
 	CreatedBy            sql.NullString `json:"created_by"`
+	BestMetric           sql.NullString `json:"best_metric"`
+	BestJson             sql.NullString `json:"best_json"`
 }
 
 type SweepSimulation struct {
@@ -9,6 +9,14 @@ import (
 )
 
 type Querier interface {
+	CacheSweepBest(ctx context.Context, arg CacheSweepBestParams) error
+	// Marks a sweep cancelled. No rows are affected when it already finished or
+	// was cancelled.
+	CancelSweepJob(ctx context.Context, arg CancelSweepJobParams) (int64, error)
+	// Cancels the simulations of a sweep that have the given status. Running
+	// simulations stop at their next heartbeat, when the worker finds it no
+	// longer owns them.
+	CancelSweepSimulations(ctx context.Context, arg CancelSweepSimulationsParams) ([]int64, error)
 	CreateComparison(ctx context.Context, arg CreateComparisonParams) (Comparison, error)
 	CreateSweepJob(ctx context.Context, arg CreateSweepJobParams) (SweepJob, error)
 	CreateSweepSimulation(ctx context.Context, arg CreateSweepSimulationParams) error
@@ -16,11 +24,14 @@ type Querier interface {
 	GetComparison(ctx context.Context, id int64) (Comparison, error)
 	GetComparisonMetrics(ctx context.Context, comparisonID int64) ([]ComparisonMetric, error)
 	GetSweepJob(ctx context.Context, id int64) (SweepJob, error)
+	GetSweepJobIDBySimulation(ctx context.Context, simulationID int64) (int64, error)
 	GetSweepSimulationDetails(ctx context.Context, sweepJobID int64) ([]GetSweepSimulationDetailsRow, error)
 	GetSweepSimulations(ctx context.Context, sweepJobID int64) ([]SweepSimulation, error)
 	InsertComparisonMetric(ctx context.Context, arg InsertComparisonMetricParams) error
 	ListSweepJobs(ctx context.Context, arg ListSweepJobsParams) ([]SweepJob, error)
 	UpdateComparisonResult(ctx context.Context, arg UpdateComparisonResultParams) error
+	// Sets started_at the first time the sweep leaves pending. Finished and
+	// cancelled sweeps are left alone.
 	UpdateSweepJobProgress(ctx context.Context, arg UpdateSweepJobProgressParams) error
 }
 
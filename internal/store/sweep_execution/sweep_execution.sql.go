@@ -10,6 +10,92 @@ import (
 	"database/sql"
 )
 
+const cacheSweepBest = `-- name: CacheSweepBest :exec
+UPDATE sweep_jobs
+SET best_metric = ?,
+    best_json = ?
+WHERE id = ?
+`
+
+type CacheSweepBestParams struct {
+	BestMetric sql.NullString `json:"best_metric"`
+	BestJson   sql.NullString `json:"best_json"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) CacheSweepBest(ctx context.Context, arg CacheSweepBestParams) error {
+	_, err := q.db.ExecContext(ctx, cacheSweepBest, arg.BestMetric, arg.BestJson, arg.ID)
+	return err
+}
+
+const cancelSweepJob = `-- name: CancelSweepJob :execrows
+UPDATE sweep_jobs
+SET status = 'cancelled',
+    finished_at = ?,
+    run_duration_ms = ?
+WHERE id = ? AND finished_at IS NULL AND status != 'cancelled'
+`
+
+type CancelSweepJobParams struct {
+	FinishedAt    sql.NullString `json:"finished_at"`
+	RunDurationMs sql.NullInt64  `json:"run_duration_ms"`
+	ID            int64          `json:"id"`
+}
+
+// Marks a sweep cancelled. No rows are affected when it already finished or
+// was cancelled.
+func (q *Queries) CancelSweepJob(ctx context.Context, arg CancelSweepJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelSweepJob, arg.FinishedAt, arg.RunDurationMs, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const cancelSweepSimulations = `-- name: CancelSweepSimulations :many
+UPDATE simulations
+SET status = 'cancelled',
+    finished_at = ?1
+WHERE status = ?2
+    AND id IN (
+        SELECT simulation_id FROM sweep_simulations
+        WHERE sweep_job_id = ?3
+    )
+RETURNING id
+`
+
+type CancelSweepSimulationsParams struct {
+	FinishedAt sql.NullString `json:"finished_at"`
+	Status     string         `json:"status"`
+	SweepJobID int64          `json:"sweep_job_id"`
+}
+
+// Cancels the simulations of a sweep that have the given status. Running
+// simulations stop at their next heartbeat, when the worker finds it no
+// longer owns them.
+func (q *Queries) CancelSweepSimulations(ctx context.Context, arg CancelSweepSimulationsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, cancelSweepSimulations, arg.FinishedAt, arg.Status, arg.SweepJobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createComparison = `-- name: CreateComparison :one
 INSERT INTO comparisons (
     name, description, simulation_ids, metric
@@ -49,7 +135,7 @@ INSERT INTO sweep_jobs (
     name, description, sweep_config_json, base_contest_range,
     total_combinations, created_by
 ) VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by
+RETURNING id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by, best_metric, best_json
 `
 
 type CreateSweepJobParams struct {
@@ -86,6 +172,8 @@ func (q *Queries) CreateSweepJob(ctx context.Context, arg CreateSweepJobParams)
 		&i.FinishedAt,
 		&i.RunDurationMs,
 		&i.CreatedBy,
+		&i.BestMetric,
+		&i.BestJson,
 	)
 	return i, err
 }
@@ -116,21 +204,27 @@ func (q *Queries) CreateSweepSimulation(ctx context.Context, arg CreateSweepSimu
 const finishSweepJob = `-- name: FinishSweepJob :exec
 UPDATE sweep_jobs
 SET status = ?,
+    completed_simulations = ?,
+    failed_simulations = ?,
     finished_at = ?,
     run_duration_ms = ?
-WHERE id = ?
+WHERE id = ? AND finished_at IS NULL AND status != 'cancelled'
 `
 
 type FinishSweepJobParams struct {
-	Status        string         `json:"status"`
-	FinishedAt    sql.NullString `json:"finished_at"`
-	RunDurationMs sql.NullInt64  `json:"run_duration_ms"`
-	ID            int64          `json:"id"`
+	Status               string         `json:"status"`
+	CompletedSimulations sql.NullInt64  `json:"completed_simulations"`
+	FailedSimulations    sql.NullInt64  `json:"failed_simulations"`
+	FinishedAt           sql.NullString `json:"finished_at"`
+	RunDurationMs        sql.NullInt64  `json:"run_duration_ms"`
+	ID                   int64          `json:"id"`
 }
 
 func (q *Queries) FinishSweepJob(ctx context.Context, arg FinishSweepJobParams) error {
 	_, err := q.db.ExecContext(ctx, finishSweepJob,
 		arg.Status,
+		arg.CompletedSimulations,
+		arg.FailedSimulations,
 		arg.FinishedAt,
 		arg.RunDurationMs,
 		arg.ID,
@@ -195,7 +289,7 @@ func (q *Queries) GetComparisonMetrics(ctx context.Context, comparisonID int64)
 }
 
 const getSweepJob = `-- name: GetSweepJob :one
-SELECT id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by FROM sweep_jobs WHERE id = ? LIMIT 1
+SELECT id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by, best_metric, best_json FROM sweep_jobs WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetSweepJob(ctx context.Context, id int64) (SweepJob, error) {
@@ -216,10 +310,25 @@ func (q *Queries) GetSweepJob(ctx context.Context, id int64) (SweepJob, error) {
 		&i.FinishedAt,
 		&i.RunDurationMs,
 		&i.CreatedBy,
+		&i.BestMetric,
+		&i.BestJson,
 	)
 	return i, err
 }
 
+const getSweepJobIDBySimulation = `-- name: GetSweepJobIDBySimulation :one
+SELECT sweep_job_id FROM sweep_simulations
+WHERE simulation_id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetSweepJobIDBySimulation(ctx context.Context, simulationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSweepJobIDBySimulation, simulationID)
+	var sweep_job_id int64
+	err := row.Scan(&sweep_job_id)
+	return sweep_job_id, err
+}
+
 const getSweepSimulationDetails = `-- name: GetSweepSimulationDetails :many
 SELECT
     ss.id, ss.sweep_job_id, ss.simulation_id, ss.variation_index, ss.variation_params,
@@ -338,7 +447,7 @@ func (q *Queries) InsertComparisonMetric(ctx context.Context, arg InsertComparis
 }
 
 const listSweepJobs = `-- name: ListSweepJobs :many
-SELECT id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by FROM sweep_jobs
+SELECT id, name, description, sweep_config_json, base_contest_range, status, total_combinations, completed_simulations, failed_simulations, created_at, started_at, finished_at, run_duration_ms, created_by, best_metric, best_json FROM sweep_jobs
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -372,6 +481,8 @@ func (q *Queries) ListSweepJobs(ctx context.Context, arg ListSweepJobsParams) ([
 			&i.FinishedAt,
 			&i.RunDurationMs,
 			&i.CreatedBy,
+			&i.BestMetric,
+			&i.BestJson,
 		); err != nil {
 			return nil, err
 		}
@@ -404,24 +515,29 @@ func (q *Queries) UpdateComparisonResult(ctx context.Context, arg UpdateComparis
 
 const updateSweepJobProgress = `-- name: UpdateSweepJobProgress :exec
 UPDATE sweep_jobs
-SET completed_simulations = ?,
-    failed_simulations = ?,
-    status = ?
-WHERE id = ?
+SET completed_simulations = ?1,
+    failed_simulations = ?2,
+    status = ?3,
+    started_at = COALESCE(started_at, ?4)
+WHERE id = ?5 AND finished_at IS NULL AND status != 'cancelled'
 `
 
 type UpdateSweepJobProgressParams struct {
-	CompletedSimulations sql.NullInt64 `json:"completed_simulations"`
-	FailedSimulations    sql.NullInt64 `json:"failed_simulations"`
-	Status               string        `json:"status"`
-	ID                   int64         `json:"id"`
+	CompletedSimulations sql.NullInt64  `json:"completed_simulations"`
+	FailedSimulations    sql.NullInt64  `json:"failed_simulations"`
+	Status               string         `json:"status"`
+	StartedAt            sql.NullString `json:"started_at"`
+	ID                   int64          `json:"id"`
 }
 
+// Sets started_at the first time the sweep leaves pending. Finished and
+// cancelled sweeps are left alone.
 func (q *Queries) UpdateSweepJobProgress(ctx context.Context, arg UpdateSweepJobProgressParams) error {
 	_, err := q.db.ExecContext(ctx, updateSweepJobProgress,
 		arg.CompletedSimulations,
 		arg.FailedSimulations,
 		arg.Status,
+		arg.StartedAt,
 		arg.ID,
 	)
 	return err
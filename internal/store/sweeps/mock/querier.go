@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/store/sweeps/querier.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sweeps "github.com/garnizeh/luckyfive/internal/store/sweeps"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuerier is a mock of Querier interface.
+type MockQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuerierMockRecorder
+}
+
+// MockQuerierMockRecorder is the mock recorder for MockQuerier.
+type MockQuerierMockRecorder struct {
+	mock *MockQuerier
+}
+
+// NewMockQuerier creates a new mock instance.
+func NewMockQuerier(ctrl *gomock.Controller) *MockQuerier {
+	mock := &MockQuerier{ctrl: ctrl}
+	mock.recorder = &MockQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
+	return m.recorder
+}
+
+// CreateSweep mocks base method.
+func (m *MockQuerier) CreateSweep(ctx context.Context, arg sweeps.CreateSweepParams) (sweeps.Sweep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSweep", ctx, arg)
+	ret0, _ := ret[0].(sweeps.Sweep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSweep indicates an expected call of CreateSweep.
+func (mr *MockQuerierMockRecorder) CreateSweep(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSweep", reflect.TypeOf((*MockQuerier)(nil).CreateSweep), ctx, arg)
+}
+
+// DeleteSweep mocks base method.
+func (m *MockQuerier) DeleteSweep(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSweep", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSweep indicates an expected call of DeleteSweep.
+func (mr *MockQuerierMockRecorder) DeleteSweep(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSweep", reflect.TypeOf((*MockQuerier)(nil).DeleteSweep), ctx, id)
+}
+
+// GetSweep mocks base method.
+func (m *MockQuerier) GetSweep(ctx context.Context, id int64) (sweeps.Sweep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweep", ctx, id)
+	ret0, _ := ret[0].(sweeps.Sweep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweep indicates an expected call of GetSweep.
+func (mr *MockQuerierMockRecorder) GetSweep(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweep", reflect.TypeOf((*MockQuerier)(nil).GetSweep), ctx, id)
+}
+
+// GetSweepByName mocks base method.
+func (m *MockQuerier) GetSweepByName(ctx context.Context, name string) (sweeps.Sweep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSweepByName", ctx, name)
+	ret0, _ := ret[0].(sweeps.Sweep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSweepByName indicates an expected call of GetSweepByName.
+func (mr *MockQuerierMockRecorder) GetSweepByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSweepByName", reflect.TypeOf((*MockQuerier)(nil).GetSweepByName), ctx, name)
+}
+
+// IncrementSweepUsage mocks base method.
+func (m *MockQuerier) IncrementSweepUsage(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementSweepUsage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementSweepUsage indicates an expected call of IncrementSweepUsage.
+func (mr *MockQuerierMockRecorder) IncrementSweepUsage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementSweepUsage", reflect.TypeOf((*MockQuerier)(nil).IncrementSweepUsage), ctx, id)
+}
+
+// ListSweeps mocks base method.
+func (m *MockQuerier) ListSweeps(ctx context.Context, arg sweeps.ListSweepsParams) ([]sweeps.Sweep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSweeps", ctx, arg)
+	ret0, _ := ret[0].([]sweeps.Sweep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSweeps indicates an expected call of ListSweeps.
+func (mr *MockQuerierMockRecorder) ListSweeps(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSweeps", reflect.TypeOf((*MockQuerier)(nil).ListSweeps), ctx, arg)
+}
+
+// UpdateSweep mocks base method.
+func (m *MockQuerier) UpdateSweep(ctx context.Context, arg sweeps.UpdateSweepParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSweep", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSweep indicates an expected call of UpdateSweep.
+func (mr *MockQuerierMockRecorder) UpdateSweep(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSweep", reflect.TypeOf((*MockQuerier)(nil).UpdateSweep), ctx, arg)
+}
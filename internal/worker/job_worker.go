@@ -3,21 +3,44 @@ package worker
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
 	"github.com/garnizeh/luckyfive/internal/store/simulations"
 )
 
+const (
+	defaultLeaseDuration = 2 * time.Minute
+	defaultMaxAttempts   = 3
+	defaultDrainTimeout  = 30 * time.Second
+	releaseTimeout       = 5 * time.Second
+	defaultVersion       = "dev"
+)
+
 type JobWorker struct {
-	simulationsQueries simulations.Querier
-	simulationService  services.SimulationServicer
-	workerID           string
-	pollInterval       time.Duration
-	maxConcurrent      int
-	logger             *slog.Logger
-	shutdown           chan struct{}
+	queue         simulationQueue
+	jobsQueries   jobs.Querier         // Nil without analysis jobs
+	jobService    services.JobServicer // Nil without analysis jobs
+	workerID      string
+	host          string
+	version       string
+	pollInterval  time.Duration
+	maxConcurrent int
+	leaseDuration time.Duration
+	maxAttempts   int
+	drainTimeout  time.Duration
+	registryEvery time.Duration // Interval of the registry heartbeats
+	logger        *slog.Logger
+	shutdown      chan struct{}
+	wake          <-chan struct{} // Nil without a notifier
+	jobs          sync.WaitGroup
+	activeJobs    atomic.Int64
 }
 
 func NewJobWorker(
@@ -28,61 +51,437 @@ func NewJobWorker(
 	maxConcurrent int,
 	logger *slog.Logger,
 ) *JobWorker {
+	return newJobWorker(&localQueue{queries: simulationsQueries, service: simulationService}, workerID, pollInterval, maxConcurrent, logger)
+}
+
+// NewRemoteJobWorker creates a worker that claims and runs simulations
+// through the job-lease API of another process instead of the simulations
+// database, under the worker ID of client. Remote workers do not run
+// analysis jobs.
+func NewRemoteJobWorker(
+	client *RemoteClient,
+	pollInterval time.Duration,
+	maxConcurrent int,
+	logger *slog.Logger,
+) *JobWorker {
+	return newJobWorker(client, client.workerID, pollInterval, maxConcurrent, logger)
+}
+
+func newJobWorker(queue simulationQueue, workerID string, pollInterval time.Duration, maxConcurrent int, logger *slog.Logger) *JobWorker {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
 	return &JobWorker{
-		simulationsQueries: simulationsQueries,
-		simulationService:  simulationService,
-		workerID:           workerID,
-		pollInterval:       pollInterval,
-		maxConcurrent:      maxConcurrent,
-		logger:             logger,
-		shutdown:           make(chan struct{}),
+		queue:         queue,
+		workerID:      workerID,
+		host:          host,
+		version:       defaultVersion,
+		pollInterval:  pollInterval,
+		maxConcurrent: maxConcurrent,
+		leaseDuration: defaultLeaseDuration,
+		maxAttempts:   defaultMaxAttempts,
+		drainTimeout:  defaultDrainTimeout,
+		registryEvery: services.WorkerHeartbeatInterval,
+		logger:        logger,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// SetLease configures how long a claimed job stays owned without a heartbeat
+// and how many times an expired job is claimed again before it is failed.
+// Heartbeats are sent every third of the lease. Call before Start.
+func (w *JobWorker) SetLease(duration time.Duration, maxAttempts int) {
+	if duration > 0 {
+		w.leaseDuration = duration
+	}
+	if maxAttempts > 0 {
+		w.maxAttempts = maxAttempts
+	}
+}
+
+// SetDrainTimeout configures how long in-flight jobs may keep running after
+// shutdown starts. Jobs still running at the deadline are cancelled and
+// returned to the queue. Call before Start.
+func (w *JobWorker) SetDrainTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		w.drainTimeout = timeout
 	}
 }
 
+// SetVersion sets the build version reported in the worker registry. Call
+// before Start.
+func (w *JobWorker) SetVersion(version string) {
+	if version != "" {
+		w.version = version
+	}
+}
+
+// SetNotifier makes the worker claim jobs as soon as n is notified instead of
+// waiting for the next poll. Polling continues for jobs queued by other
+// processes. Call before Start.
+func (w *JobWorker) SetNotifier(n *Notifier) {
+	w.wake = n.wake
+}
+
+// SetJobService makes the worker run analysis jobs of the types registered
+// with jobService besides simulations. Analysis jobs are claimed first since
+// they are usually short and users wait for them. Call before Start.
+func (w *JobWorker) SetJobService(jobsQueries jobs.Querier, jobService services.JobServicer) {
+	w.jobsQueries = jobsQueries
+	w.jobService = jobService
+}
+
+// Start claims and runs jobs until ctx is cancelled or Stop is called. It
+// then stops claiming and drains the in-flight jobs before returning.
 func (w *JobWorker) Start(ctx context.Context) error {
-	w.logger.Info("worker starting", "worker_id", w.workerID)
+	w.logger.Info("worker starting", "worker_id", w.workerID, "lease", w.leaseDuration, "max_attempts", w.maxAttempts, "drain_timeout", w.drainTimeout)
 
 	ticker := time.NewTicker(w.pollInterval)
 	defer ticker.Stop()
 
+	// Jobs of crashed workers are recovered on start and then every half lease
+	reaper := time.NewTicker(w.leaseDuration / 2)
+	defer reaper.Stop()
+	w.reapExpiredLeases(ctx)
+
+	// The registry entry shows the worker in the fleet status until it stops
+	w.register(ctx)
+	defer w.deregister()
+	registry := time.NewTicker(w.registryEvery)
+	defer registry.Stop()
+
 	// Semaphore for concurrency control
 	sem := make(chan struct{}, w.maxConcurrent)
 
+	// In-flight jobs outlive ctx so that they can finish while draining
+	jobsCtx, cancelJobs := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer cancelJobs(nil)
+
 	for {
 		select {
 		case <-ctx.Done():
 			w.logger.Info("worker shutting down", "worker_id", w.workerID)
+			w.drain(context.WithoutCancel(ctx), cancelJobs)
 			return ctx.Err()
 		case <-w.shutdown:
+			w.drain(ctx, cancelJobs)
 			w.logger.Info("worker stopped", "worker_id", w.workerID)
 			return nil
+		case <-reaper.C:
+			w.reapExpiredLeases(ctx)
+		case <-registry.C:
+			w.heartbeatRegistry(ctx)
 		case <-ticker.C:
-			// Try to claim a job
-			job, err := w.simulationsQueries.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
-				StartedAt: sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
-				WorkerID:  sql.NullString{String: w.workerID, Valid: true},
-			})
+			w.claimJobs(ctx, jobsCtx, sem)
+		case <-w.wake:
+			w.claimJobs(ctx, jobsCtx, sem)
+		}
+	}
+}
+
+// claimJobs claims and starts jobs while slots are free and jobs are pending.
+// A slot is taken before claiming, so that no claimed job waits without
+// heartbeats.
+func (w *JobWorker) claimJobs(ctx, jobsCtx context.Context, sem chan struct{}) {
+	for ctx.Err() == nil {
+		select {
+		case sem <- struct{}{}:
+		default:
+			return
+		}
+
+		if job, ok := w.claimAnalysisJob(ctx); ok {
+			w.jobs.Add(1)
+			go func() {
+				defer w.jobs.Done()
+				defer func() { <-sem }()
+				w.runAnalysisJob(jobsCtx, job)
+			}()
+			continue
+		}
+
+		jobID, attempt, err := w.queue.claim(ctx, w.workerID, w.leaseDuration)
+		if err != nil {
+			<-sem
+			if err != sql.ErrNoRows {
+				w.logger.Error("claim job failed", "error", err, "worker_id", w.workerID)
+			}
+			return
+		}
+
+		// Execute in goroutine
+		w.jobs.Add(1)
+		go func(jobID int64, attempt int64) {
+			defer w.jobs.Done()
+			defer func() { <-sem }()
+			w.runJob(jobsCtx, jobID, attempt)
+		}(jobID, attempt)
+	}
+}
+
+func (w *JobWorker) runJob(ctx context.Context, jobID, attempt int64) {
+	w.logger.Info("processing job", "job_id", jobID, "attempt", attempt, "worker_id", w.workerID)
+
+	w.activeJobs.Add(1)
+	defer w.activeJobs.Add(-1)
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go w.heartbeat(jobCtx, jobID, cancel)
+
+	err := w.queue.execute(jobCtx, jobID)
+	switch {
+	case err == nil:
+		w.logger.Info("job completed", "job_id", jobID, "worker_id", w.workerID)
+	case errors.Is(context.Cause(jobCtx), services.ErrWorkerShutdown):
+		w.releaseJob(jobID)
+	case errors.Is(err, services.ErrRetryScheduled):
+		w.logger.Warn("job failed, retry scheduled", "job_id", jobID, "attempt", attempt, "error", err, "worker_id", w.workerID)
+	case errors.Is(err, services.ErrLeaseLost):
+		w.logger.Warn("job cancelled or lease lost", "job_id", jobID, "worker_id", w.workerID)
+	default:
+		w.logger.Error("job execution failed", "job_id", jobID, "error", err, "worker_id", w.workerID)
+	}
+}
+
+// claimAnalysisJob claims the next pending analysis job, if the worker runs
+// analysis jobs.
+func (w *JobWorker) claimAnalysisJob(ctx context.Context) (jobs.AnalysisJob, bool) {
+	if w.jobService == nil {
+		return jobs.AnalysisJob{}, false
+	}
+
+	now := time.Now().UTC()
+	job, err := w.jobsQueries.ClaimPendingAnalysisJob(ctx, jobs.ClaimPendingAnalysisJobParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: w.workerID, Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(w.leaseDuration).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil {
+		if err != sql.ErrNoRows {
+			w.logger.Error("claim analysis job failed", "error", err, "worker_id", w.workerID)
+		}
+		return jobs.AnalysisJob{}, false
+	}
+	return job, true
+}
+
+func (w *JobWorker) runAnalysisJob(ctx context.Context, job jobs.AnalysisJob) {
+	w.logger.Info("processing analysis job", "analysis_job_id", job.ID, "type", job.JobType, "attempt", job.Attempts, "worker_id", w.workerID)
+
+	w.activeJobs.Add(1)
+	defer w.activeJobs.Add(-1)
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go w.keepLease(jobCtx, job.ID, cancel, func(now time.Time) (bool, error) {
+		n, err := w.jobsQueries.HeartbeatAnalysisJob(jobCtx, jobs.HeartbeatAnalysisJobParams{
+			HeartbeatAt:    sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+			LeaseExpiresAt: sql.NullString{String: now.Add(w.leaseDuration).Format(time.RFC3339), Valid: true},
+			ID:             job.ID,
+			WorkerID:       sql.NullString{String: w.workerID, Valid: true},
+		})
+		return n > 0, err
+	})
+
+	err := w.jobService.ExecuteJob(jobCtx, job)
+	switch {
+	case err == nil:
+		w.logger.Info("analysis job completed", "analysis_job_id", job.ID, "worker_id", w.workerID)
+	case errors.Is(context.Cause(jobCtx), services.ErrWorkerShutdown):
+		w.releaseAnalysisJob(job.ID)
+	case errors.Is(err, services.ErrLeaseLost):
+		w.logger.Warn("analysis job cancelled or lease lost", "analysis_job_id", job.ID, "worker_id", w.workerID)
+	default:
+		w.logger.Error("analysis job failed", "analysis_job_id", job.ID, "error", err, "worker_id", w.workerID)
+	}
+}
+
+// drain waits for the in-flight jobs up to the drain timeout. Jobs still
+// running then are cancelled with services.ErrWorkerShutdown and drain waits
+// for them to be returned to the queue. The registry entry keeps its
+// heartbeats meanwhile, so that a long drain does not show the worker dead.
+func (w *JobWorker) drain(ctx context.Context, cancelJobs context.CancelCauseFunc) {
+	done := make(chan struct{})
+	go func() {
+		w.jobs.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(w.drainTimeout)
+	defer timer.Stop()
+	registry := time.NewTicker(w.registryEvery)
+	defer registry.Stop()
+
+	w.logger.Info("draining in-flight jobs", "timeout", w.drainTimeout, "worker_id", w.workerID)
+	for {
+		select {
+		case <-done:
+			return
+		case <-registry.C:
+			w.heartbeatRegistry(ctx)
+		case <-timer.C:
+			w.logger.Warn("drain timeout reached, cancelling in-flight jobs", "worker_id", w.workerID)
+			cancelJobs(services.ErrWorkerShutdown)
+			<-done
+			return
+		}
+	}
+}
+
+// releaseJob returns a job interrupted by shutdown to pending without
+// counting the interrupted run as an attempt.
+func (w *JobWorker) releaseJob(jobID int64) {
+	// The job context is cancelled already
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	released, err := w.queue.release(ctx, w.workerID, jobID)
+	if err != nil {
+		w.logger.Error("release job failed", "job_id", jobID, "error", err, "worker_id", w.workerID)
+		return
+	}
+	if !released {
+		w.logger.Warn("job not released, no longer owned", "job_id", jobID, "worker_id", w.workerID)
+		return
+	}
+	w.logger.Info("job returned to queue on shutdown", "job_id", jobID, "worker_id", w.workerID)
+}
+
+// releaseAnalysisJob returns an analysis job interrupted by shutdown to
+// pending without counting the interrupted run as an attempt.
+func (w *JobWorker) releaseAnalysisJob(jobID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	n, err := w.jobsQueries.ReleaseAnalysisJob(ctx, jobs.ReleaseAnalysisJobParams{
+		ID:       jobID,
+		WorkerID: sql.NullString{String: w.workerID, Valid: true},
+	})
+	if err != nil {
+		w.logger.Error("release analysis job failed", "analysis_job_id", jobID, "error", err, "worker_id", w.workerID)
+		return
+	}
+	if n == 0 {
+		w.logger.Warn("analysis job not released, no longer owned", "analysis_job_id", jobID, "worker_id", w.workerID)
+		return
+	}
+	w.logger.Info("analysis job returned to queue on shutdown", "analysis_job_id", jobID, "worker_id", w.workerID)
+}
+
+// heartbeat extends the lease of a simulation until ctx is done.
+func (w *JobWorker) heartbeat(ctx context.Context, jobID int64, cancel context.CancelCauseFunc) {
+	w.keepLease(ctx, jobID, cancel, func(now time.Time) (bool, error) {
+		return w.queue.heartbeat(ctx, w.workerID, jobID, w.leaseDuration)
+	})
+}
+
+// keepLease calls extend every third of the lease until ctx is done. When
+// extend returns false the worker no longer owns the job, and the job is
+// cancelled with services.ErrLeaseLost.
+func (w *JobWorker) keepLease(ctx context.Context, jobID int64, cancel context.CancelCauseFunc, extend func(now time.Time) (bool, error)) {
+	ticker := time.NewTicker(w.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			owned, err := extend(time.Now().UTC())
 			if err != nil {
-				if err != sql.ErrNoRows {
-					w.logger.Error("claim job failed", "error", err, "worker_id", w.workerID)
+				if ctx.Err() == nil {
+					w.logger.Warn("heartbeat failed", "job_id", jobID, "error", err, "worker_id", w.workerID)
 				}
 				continue
 			}
+			if !owned {
+				w.logger.Warn("job lease lost", "job_id", jobID, "worker_id", w.workerID)
+				cancel(services.ErrLeaseLost)
+				return
+			}
+		}
+	}
+}
 
-			// Execute in goroutine
-			sem <- struct{}{}
-			go func(jobID int64) {
-				defer func() { <-sem }()
+// reapExpiredLeases fails expired jobs that used all their attempts and
+// returns the others to pending.
+func (w *JobWorker) reapExpiredLeases(ctx context.Context) {
+	failed, requeued, err := w.queue.reap(ctx, w.maxAttempts)
+	if err != nil {
+		w.logger.Error("reap expired jobs failed", "error", err, "worker_id", w.workerID)
+	}
+	for _, id := range failed {
+		w.logger.Warn("job failed after lease expired", "job_id", id, "max_attempts", w.maxAttempts, "worker_id", w.workerID)
+	}
+	for _, id := range requeued {
+		w.logger.Warn("job requeued after lease expired", "job_id", id, "worker_id", w.workerID)
+	}
 
-				w.logger.Info("processing job", "job_id", jobID, "worker_id", w.workerID)
+	if w.jobService != nil {
+		w.reapExpiredAnalysisJobs(ctx)
+	}
+}
 
-				if err := w.simulationService.ExecuteSimulation(ctx, jobID); err != nil {
-					w.logger.Error("job execution failed", "job_id", jobID, "error", err, "worker_id", w.workerID)
-				} else {
-					w.logger.Info("job completed", "job_id", jobID, "worker_id", w.workerID)
-				}
-			}(job.ID)
-		}
+func (w *JobWorker) reapExpiredAnalysisJobs(ctx context.Context) {
+	now := sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true}
+
+	failed, err := w.jobsQueries.FailExpiredAnalysisJobs(ctx, jobs.FailExpiredAnalysisJobsParams{
+		Now:         now,
+		MaxAttempts: int64(w.maxAttempts),
+	})
+	if err != nil {
+		w.logger.Error("fail expired analysis jobs failed", "error", err, "worker_id", w.workerID)
+	}
+	for _, id := range failed {
+		w.logger.Warn("analysis job failed after lease expired", "analysis_job_id", id, "max_attempts", w.maxAttempts, "worker_id", w.workerID)
+	}
+
+	requeued, err := w.jobsQueries.RequeueExpiredAnalysisJobs(ctx, jobs.RequeueExpiredAnalysisJobsParams{
+		Now:         now,
+		MaxAttempts: int64(w.maxAttempts),
+	})
+	if err != nil {
+		w.logger.Error("requeue expired analysis jobs failed", "error", err, "worker_id", w.workerID)
+	}
+	for _, id := range requeued {
+		w.logger.Warn("analysis job requeued after lease expired", "analysis_job_id", id, "worker_id", w.workerID)
+	}
+}
+
+func (w *JobWorker) register(ctx context.Context) {
+	err := w.queue.register(ctx, w.workerID, services.WorkerRegistration{
+		Host:        w.host,
+		Version:     w.version,
+		Concurrency: w.maxConcurrent,
+	})
+	if err != nil {
+		w.logger.Error("register worker failed", "error", err, "worker_id", w.workerID)
+	}
+}
+
+func (w *JobWorker) heartbeatRegistry(ctx context.Context) {
+	registered, err := w.queue.heartbeatWorker(ctx, w.workerID, w.activeJobs.Load())
+	if err != nil {
+		w.logger.Warn("worker heartbeat failed", "error", err, "worker_id", w.workerID)
+		return
+	}
+	if !registered {
+		// The entry was removed or registration failed on start
+		w.register(ctx)
+	}
+}
+
+// deregister marks the worker stopped once it drained its jobs.
+func (w *JobWorker) deregister() {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	err := w.queue.deregister(ctx, w.workerID)
+	if err != nil {
+		w.logger.Error("deregister worker failed", "error", err, "worker_id", w.workerID)
 	}
 }
 
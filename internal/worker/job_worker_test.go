@@ -3,26 +3,40 @@ package worker
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	servicemock "github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+	jobsmock "github.com/garnizeh/luckyfive/internal/store/jobs/mock"
 	"github.com/garnizeh/luckyfive/internal/store/simulations"
 	storemock "github.com/garnizeh/luckyfive/internal/store/simulations/mock"
 	"github.com/golang/mock/gomock"
 )
 
+// allowRegistry accepts the worker registry updates made by Start.
+func allowRegistry(q *storemock.MockQuerier) {
+	q.EXPECT().RegisterWorker(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	q.EXPECT().HeartbeatWorker(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	q.EXPECT().StopWorker(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
 func TestJobWorker_Start_Stop(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
 	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
 
 	// Expect no calls since no jobs are available
 	mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes()
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	// Create a discard logger for testing
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -66,9 +80,12 @@ func TestJobWorker_GracefulShutdown(t *testing.T) {
 
 	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
 	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
 
 	// Expect no calls since no jobs are available
 	mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes()
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	// Create a discard logger for testing
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -106,3 +123,557 @@ func TestJobWorker_GracefulShutdown(t *testing.T) {
 		t.Error("Worker did not stop gracefully within timeout")
 	}
 }
+
+func TestJobWorker_ReapExpiredLeases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", time.Second, 1, logger)
+	worker.SetLease(time.Minute, 5)
+
+	gomock.InOrder(
+		mockQuerier.EXPECT().
+			FailExpiredSimulations(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg simulations.FailExpiredSimulationsParams) ([]int64, error) {
+				if arg.MaxAttempts != 5 || !arg.Now.Valid {
+					t.Errorf("unexpected params: %+v", arg)
+				}
+				return []int64{1}, nil
+			}),
+		mockQuerier.EXPECT().
+			RequeueExpiredSimulations(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg simulations.RequeueExpiredSimulationsParams) ([]int64, error) {
+				if arg.MaxAttempts != 5 || !arg.Now.Valid {
+					t.Errorf("unexpected params: %+v", arg)
+				}
+				return []int64{2, 3}, nil
+			}),
+	)
+	// Observers such as sweeps learn about simulations failed by the reaper
+	mockSimSvc.EXPECT().NotifySimulationFinished(gomock.Any(), int64(1))
+
+	worker.reapExpiredLeases(context.Background())
+}
+
+func TestJobWorker_HeartbeatsWhileRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	gomock.InOrder(
+		mockQuerier.EXPECT().
+			ClaimPendingSimulation(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg simulations.ClaimPendingSimulationParams) (simulations.Simulation, error) {
+				if !arg.LeaseExpiresAt.Valid || arg.LeaseExpiresAt.String < arg.StartedAt.String {
+					t.Errorf("expected lease after start, got %+v", arg)
+				}
+				return simulations.Simulation{ID: 7, Attempts: 1}, nil
+			}),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+
+	heartbeats := make(chan simulations.HeartbeatSimulationParams, 100)
+	mockQuerier.EXPECT().
+		HeartbeatSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.HeartbeatSimulationParams) (int64, error) {
+			heartbeats <- arg
+			return 1, nil
+		}).
+		AnyTimes()
+
+	done := make(chan struct{})
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			defer close(done)
+			time.Sleep(150 * time.Millisecond)
+			return ctx.Err()
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetLease(60*time.Millisecond, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not finish")
+	}
+	worker.Stop()
+
+	if len(heartbeats) == 0 {
+		t.Fatal("expected heartbeats while the job was running")
+	}
+	hb := <-heartbeats
+	if hb.ID != 7 || hb.WorkerID.String != "test-worker" || !hb.LeaseExpiresAt.Valid {
+		t.Errorf("unexpected heartbeat: %+v", hb)
+	}
+}
+
+func TestJobWorker_LostLeaseCancelsJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	gomock.InOrder(
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 7, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+	// Another worker took over or the simulation was cancelled
+	mockQuerier.EXPECT().HeartbeatSimulation(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	cause := make(chan error, 1)
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			<-ctx.Done()
+			cause <- context.Cause(ctx)
+			return ctx.Err()
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetLease(60*time.Millisecond, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+
+	select {
+	case err := <-cause:
+		if !errors.Is(err, servicemock.ErrLeaseLost) {
+			t.Errorf("expected ErrLeaseLost, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not cancelled")
+	}
+	worker.Stop()
+}
+
+func TestJobWorker_DrainWaitsForInFlightJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().HeartbeatSimulation(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	gomock.InOrder(
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 7, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			close(started)
+			time.Sleep(150 * time.Millisecond)
+			defer close(finished)
+			// Shutdown must not cancel a job that finishes within the deadline
+			return context.Cause(ctx)
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetDrainTimeout(2 * time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- worker.Start(ctx)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not start")
+	}
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != nil && err != context.Canceled {
+			t.Errorf("expected no error or context canceled, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("worker did not stop")
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("worker stopped before the in-flight job finished")
+	}
+}
+
+func TestJobWorker_HeartbeatsRegistryWhileDraining(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().RegisterWorker(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockQuerier.EXPECT().StopWorker(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().HeartbeatSimulation(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	gomock.InOrder(
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 7, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+
+	var draining atomic.Bool
+	var drainHeartbeats atomic.Int64
+	mockQuerier.EXPECT().
+		HeartbeatWorker(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg simulations.HeartbeatWorkerParams) (int64, error) {
+			if draining.Load() {
+				if ctx.Err() != nil {
+					t.Errorf("registry heartbeat sent with a cancelled context")
+				}
+				if arg.ActiveJobs != 1 {
+					t.Errorf("expected the in-flight job in the heartbeat, got %d", arg.ActiveJobs)
+				}
+				drainHeartbeats.Add(1)
+			}
+			return 1, nil
+		}).AnyTimes()
+
+	started := make(chan struct{})
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetDrainTimeout(2 * time.Second)
+	worker.registryEvery = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- worker.Start(ctx)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not start")
+	}
+	draining.Store(true)
+	cancel()
+
+	select {
+	case <-errCh:
+	case <-time.After(3 * time.Second):
+		t.Fatal("worker did not stop")
+	}
+	if drainHeartbeats.Load() == 0 {
+		t.Error("expected registry heartbeats while draining")
+	}
+}
+
+func TestJobWorker_DrainTimeoutReleasesJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().HeartbeatSimulation(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	gomock.InOrder(
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 7, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+	mockQuerier.EXPECT().
+		ReleaseSimulation(gomock.Any(), simulations.ReleaseSimulationParams{
+			ID:       7,
+			WorkerID: sql.NullString{String: "test-worker", Valid: true},
+		}).
+		Return(int64(1), nil)
+
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			close(started)
+			<-ctx.Done()
+			cause <- context.Cause(ctx)
+			return ctx.Err()
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetDrainTimeout(50 * time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- worker.Start(context.Background())
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not start")
+	}
+	worker.Stop()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not stop after the drain timeout")
+	}
+	if err := <-cause; !errors.Is(err, servicemock.ErrWorkerShutdown) {
+		t.Errorf("expected ErrWorkerShutdown, got %v", err)
+	}
+}
+
+func TestJobWorker_RegistersInFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+
+	mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes()
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	registered := make(chan simulations.RegisterWorkerParams, 1)
+	gomock.InOrder(
+		mockQuerier.EXPECT().
+			RegisterWorker(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg simulations.RegisterWorkerParams) error {
+				registered <- arg
+				return nil
+			}),
+		mockQuerier.EXPECT().
+			StopWorker(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg simulations.StopWorkerParams) error {
+				if arg.ID != "test-worker" || !arg.StoppedAt.Valid {
+					t.Errorf("unexpected stop: %+v", arg)
+				}
+				return nil
+			}),
+	)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 2, logger)
+	worker.SetVersion("v1.2.3")
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- worker.Start(context.Background())
+	}()
+
+	select {
+	case arg := <-registered:
+		if arg.ID != "test-worker" || arg.Version != "v1.2.3" || arg.Concurrency != 2 || arg.Host == "" || arg.StartedAt == "" {
+			t.Errorf("unexpected registration: %+v", arg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not register")
+	}
+	worker.Stop()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not stop")
+	}
+}
+
+func TestJobWorker_NotifierWakesWorker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().HeartbeatSimulation(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	// One wakeup claims every pending job while slots are free
+	gomock.InOrder(
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 7, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{ID: 8, Attempts: 1}, nil),
+		mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes(),
+	)
+
+	executed := make(chan int64, 2)
+	mockSimSvc.EXPECT().
+		ExecuteSimulation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, simID int64) error {
+			executed <- simID
+			return nil
+		}).
+		Times(2)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	// The poll interval is too long to claim anything during the test
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", time.Hour, 2, logger)
+	notifier := NewNotifier()
+	worker.SetNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+
+	notifier.NotifyJobQueued()
+	notifier.NotifyJobQueued() // Coalesced, never blocks
+
+	for range 2 {
+		select {
+		case <-executed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("notified jobs were not claimed")
+		}
+	}
+	worker.Stop()
+}
+
+func TestJobWorker_RunsAnalysisJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockJobSvc := servicemock.NewMockJobServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	mockJobs := jobsmock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().ClaimPendingSimulation(gomock.Any(), gomock.Any()).Return(simulations.Simulation{}, sql.ErrNoRows).AnyTimes()
+	mockJobs.EXPECT().FailExpiredAnalysisJobs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockJobs.EXPECT().RequeueExpiredAnalysisJobs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockJobs.EXPECT().HeartbeatAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	gomock.InOrder(
+		mockJobs.EXPECT().ClaimPendingAnalysisJob(gomock.Any(), gomock.Any()).Return(jobs.AnalysisJob{ID: 3, JobType: "sweep", Attempts: 1}, nil),
+		mockJobs.EXPECT().ClaimPendingAnalysisJob(gomock.Any(), gomock.Any()).Return(jobs.AnalysisJob{}, sql.ErrNoRows).AnyTimes(),
+	)
+
+	done := make(chan struct{})
+	mockJobSvc.EXPECT().
+		ExecuteJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job jobs.AnalysisJob) error {
+			if job.ID != 3 || job.JobType != "sweep" {
+				t.Errorf("unexpected job %+v", job)
+			}
+			close(done)
+			return nil
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetJobService(mockJobs, mockJobSvc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("analysis job was not run")
+	}
+	worker.Stop()
+}
+
+func TestJobWorker_DrainTimeoutReleasesAnalysisJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSimSvc := servicemock.NewMockSimulationServicer(ctrl)
+	mockJobSvc := servicemock.NewMockJobServicer(ctrl)
+	mockQuerier := storemock.NewMockQuerier(ctrl)
+	mockJobs := jobsmock.NewMockQuerier(ctrl)
+	allowRegistry(mockQuerier)
+
+	mockQuerier.EXPECT().FailExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQuerier.EXPECT().RequeueExpiredSimulations(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockJobs.EXPECT().FailExpiredAnalysisJobs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockJobs.EXPECT().RequeueExpiredAnalysisJobs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockJobs.EXPECT().HeartbeatAnalysisJob(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	mockJobs.EXPECT().ClaimPendingAnalysisJob(gomock.Any(), gomock.Any()).Return(jobs.AnalysisJob{ID: 3, JobType: "comparison", Attempts: 1}, nil)
+	mockJobs.EXPECT().
+		ReleaseAnalysisJob(gomock.Any(), jobs.ReleaseAnalysisJobParams{
+			ID:       3,
+			WorkerID: sql.NullString{String: "test-worker", Valid: true},
+		}).
+		Return(int64(1), nil)
+
+	started := make(chan struct{})
+	mockJobSvc.EXPECT().
+		ExecuteJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job jobs.AnalysisJob) error {
+			close(started)
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewJobWorker(mockQuerier, mockSimSvc, "test-worker", 10*time.Millisecond, 1, logger)
+	worker.SetJobService(mockJobs, mockJobSvc)
+	worker.SetDrainTimeout(50 * time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- worker.Start(context.Background())
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("analysis job did not start")
+	}
+	worker.Stop()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not stop after the drain timeout")
+	}
+}
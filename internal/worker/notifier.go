@@ -0,0 +1,24 @@
+package worker
+
+import "github.com/garnizeh/luckyfive/internal/services"
+
+var _ services.JobNotifier = (*Notifier)(nil)
+
+// Notifier wakes a JobWorker running in the same process when simulations
+// are queued. Notifications sent while the worker is busy are coalesced into
+// one wakeup.
+type Notifier struct {
+	wake chan struct{}
+}
+
+func NewNotifier() *Notifier {
+	return &Notifier{wake: make(chan struct{}, 1)}
+}
+
+// NotifyJobQueued wakes the worker without blocking.
+func (n *Notifier) NotifyJobQueued() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+// simulationQueue is where a JobWorker claims and runs simulations: the
+// simulations database for local workers, the job-lease API of cmd/api for
+// remote ones.
+type simulationQueue interface {
+	// claim leases the next pending simulation, or fails with sql.ErrNoRows.
+	claim(ctx context.Context, workerID string, lease time.Duration) (id, attempt int64, err error)
+	// heartbeat extends a lease. It returns false once the worker no longer
+	// owns the simulation.
+	heartbeat(ctx context.Context, workerID string, id int64, lease time.Duration) (bool, error)
+	execute(ctx context.Context, id int64) error
+	// release returns a simulation interrupted by shutdown to the queue. It
+	// returns false when the worker no longer owned it.
+	release(ctx context.Context, workerID string, id int64) (bool, error)
+	// reap recovers the simulations of crashed workers.
+	reap(ctx context.Context, maxAttempts int) (failed, requeued []int64, err error)
+
+	register(ctx context.Context, workerID string, registration services.WorkerRegistration) error
+	// heartbeatWorker refreshes the registry entry. It returns false when
+	// the worker is not registered.
+	heartbeatWorker(ctx context.Context, workerID string, activeJobs int64) (bool, error)
+	deregister(ctx context.Context, workerID string) error
+}
+
+// localQueue runs simulations against the simulations database.
+type localQueue struct {
+	queries simulations.Querier
+	service services.SimulationServicer
+}
+
+func (q *localQueue) claim(ctx context.Context, workerID string, lease time.Duration) (int64, int64, error) {
+	now := time.Now().UTC()
+	sim, err := q.queries.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: workerID, Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(lease).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return sim.ID, sim.Attempts, nil
+}
+
+func (q *localQueue) heartbeat(ctx context.Context, workerID string, id int64, lease time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	n, err := q.queries.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(lease).Format(time.RFC3339), Valid: true},
+		ID:             id,
+		WorkerID:       sql.NullString{String: workerID, Valid: true},
+	})
+	return n > 0, err
+}
+
+func (q *localQueue) execute(ctx context.Context, id int64) error {
+	return q.service.ExecuteSimulation(ctx, id)
+}
+
+func (q *localQueue) release(ctx context.Context, workerID string, id int64) (bool, error) {
+	n, err := q.queries.ReleaseSimulation(ctx, simulations.ReleaseSimulationParams{
+		ID:       id,
+		WorkerID: sql.NullString{String: workerID, Valid: true},
+	})
+	return n > 0, err
+}
+
+func (q *localQueue) reap(ctx context.Context, maxAttempts int) ([]int64, []int64, error) {
+	now := sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true}
+
+	failed, failErr := q.queries.FailExpiredSimulations(ctx, simulations.FailExpiredSimulationsParams{
+		Now:         now,
+		MaxAttempts: int64(maxAttempts),
+	})
+	for _, id := range failed {
+		q.service.NotifySimulationFinished(ctx, id)
+	}
+
+	requeued, requeueErr := q.queries.RequeueExpiredSimulations(ctx, simulations.RequeueExpiredSimulationsParams{
+		Now:         now,
+		MaxAttempts: int64(maxAttempts),
+	})
+	return failed, requeued, errors.Join(failErr, requeueErr)
+}
+
+func (q *localQueue) register(ctx context.Context, workerID string, registration services.WorkerRegistration) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return q.queries.RegisterWorker(ctx, simulations.RegisterWorkerParams{
+		ID:          workerID,
+		Host:        registration.Host,
+		Version:     registration.Version,
+		Concurrency: int64(registration.Concurrency),
+		StartedAt:   now,
+		HeartbeatAt: now,
+	})
+}
+
+func (q *localQueue) heartbeatWorker(ctx context.Context, workerID string, activeJobs int64) (bool, error) {
+	n, err := q.queries.HeartbeatWorker(ctx, simulations.HeartbeatWorkerParams{
+		HeartbeatAt: time.Now().UTC().Format(time.RFC3339),
+		ActiveJobs:  activeJobs,
+		ID:          workerID,
+	})
+	return n > 0, err
+}
+
+func (q *localQueue) deregister(ctx context.Context, workerID string) error {
+	return q.queries.StopWorker(ctx, simulations.StopWorkerParams{
+		StoppedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:        workerID,
+	})
+}
@@ -0,0 +1,242 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+)
+
+// remoteRequestTimeout bounds each call to the API, including uploads of
+// large results.
+const remoteRequestTimeout = 2 * time.Minute
+
+var (
+	_ simulationQueue      = (*RemoteClient)(nil)
+	_ services.DrawFetcher = (*RemoteClient)(nil)
+)
+
+// RemoteClient talks to the job-lease API of a cmd/api process, so that a
+// worker can run simulations on a machine that does not share the API's
+// filesystem. Leases, draws, results and failures all go over HTTP; the API
+// keeps applying the retry and limit policies.
+type RemoteClient struct {
+	baseURL  string
+	workerID string
+	client   *http.Client
+	engine   services.EngineServicer
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	leases map[int64]services.SimulationLease // Claimed, not yet run
+}
+
+// NewRemoteClient creates a client for the API at baseURL, e.g.
+// http://api.internal:8080, identifying itself as workerID.
+func NewRemoteClient(baseURL, workerID string, logger *slog.Logger) *RemoteClient {
+	c := &RemoteClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		workerID: workerID,
+		client:   &http.Client{Timeout: remoteRequestTimeout},
+		logger:   logger,
+		leases:   make(map[int64]services.SimulationLease),
+	}
+	c.engine = services.NewEngineService(c, logger)
+	return c
+}
+
+// apiError is an error response of the API. Server errors are transient.
+type apiError struct {
+	status int
+	code   string
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("api: %s: %s (HTTP %d)", e.code, e.msg, e.status)
+}
+
+func (e *apiError) Is(target error) bool {
+	return target == services.ErrTransient && e.status >= http.StatusInternalServerError
+}
+
+// do sends a JSON request and decodes a 200 response into out. Other
+// successful responses return their status code with no body decoded.
+// Network failures wrap services.ErrTransient.
+func (c *RemoteClient) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", services.ErrTransient, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr models.APIError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+			apiErr.Code = "unknown"
+			apiErr.Message = resp.Status
+		}
+		return resp.StatusCode, &apiError{status: resp.StatusCode, code: apiErr.Code, msg: apiErr.Message}
+	}
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%w: decode response: %v", services.ErrTransient, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func (c *RemoteClient) workerPath(workerID string) string {
+	return "/api/v1/workers/" + url.PathEscape(workerID)
+}
+
+func (c *RemoteClient) simulationPath(workerID string, id int64) string {
+	return fmt.Sprintf("%s/simulations/%d", c.workerPath(workerID), id)
+}
+
+// leaseSeconds rounds a lease up to the whole seconds of the API.
+func leaseSeconds(lease time.Duration) int {
+	return max(int(math.Ceil(lease.Seconds())), 1)
+}
+
+func (c *RemoteClient) claim(ctx context.Context, workerID string, lease time.Duration) (int64, int64, error) {
+	var leased services.SimulationLease
+	status, err := c.do(ctx, http.MethodPost, c.workerPath(workerID)+"/claim", map[string]int{"lease_seconds": leaseSeconds(lease)}, &leased)
+	if err != nil {
+		return 0, 0, err
+	}
+	if status == http.StatusNoContent {
+		return 0, 0, sql.ErrNoRows
+	}
+
+	c.mu.Lock()
+	c.leases[leased.SimulationID] = leased
+	c.mu.Unlock()
+	return leased.SimulationID, leased.Attempt, nil
+}
+
+func (c *RemoteClient) heartbeat(ctx context.Context, workerID string, id int64, lease time.Duration) (bool, error) {
+	status, err := c.do(ctx, http.MethodPost, c.simulationPath(workerID, id)+"/heartbeat", map[string]int{"lease_seconds": leaseSeconds(lease)}, nil)
+	if status == http.StatusConflict {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// execute runs a leased simulation and reports the outcome to the API. Runs
+// interrupted by the worker are not reported; the worker releases them or
+// leaves them to their new owner.
+func (c *RemoteClient) execute(ctx context.Context, id int64) error {
+	c.mu.Lock()
+	lease, ok := c.leases[id]
+	delete(c.leases, id)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("simulation %d is not leased", id)
+	}
+
+	report := services.RunSimulationLease(ctx, c.engine, lease, c.logger)
+	if cause := context.Cause(ctx); errors.Is(cause, services.ErrLeaseLost) || errors.Is(cause, services.ErrWorkerShutdown) {
+		return cause
+	}
+
+	// The report is sent even if the run outlived its context
+	ctx = context.WithoutCancel(ctx)
+	path := c.simulationPath(c.workerID, id)
+	if report.Result != nil {
+		if _, err := c.do(ctx, http.MethodPost, path+"/complete", report, nil); err != nil {
+			return fmt.Errorf("submit result: %w", err)
+		}
+		return nil
+	}
+
+	var resp struct {
+		RetryScheduled bool `json:"retry_scheduled"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, path+"/fail", report, &resp); err != nil {
+		return fmt.Errorf("report failure: %w", err)
+	}
+	if resp.RetryScheduled {
+		return fmt.Errorf("%w: %s", services.ErrRetryScheduled, report.Error)
+	}
+	return errors.New(report.Error)
+}
+
+func (c *RemoteClient) release(ctx context.Context, workerID string, id int64) (bool, error) {
+	status, err := c.do(ctx, http.MethodPost, c.simulationPath(workerID, id)+"/release", nil, nil)
+	if status == http.StatusConflict {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// reap ignores maxAttempts: the API reaps with its own retry policy.
+func (c *RemoteClient) reap(ctx context.Context, _ int) ([]int64, []int64, error) {
+	var result services.ReapResult
+	if _, err := c.do(ctx, http.MethodPost, c.workerPath(c.workerID)+"/reap", nil, &result); err != nil {
+		return nil, nil, err
+	}
+	return result.Failed, result.Requeued, nil
+}
+
+func (c *RemoteClient) register(ctx context.Context, workerID string, registration services.WorkerRegistration) error {
+	_, err := c.do(ctx, http.MethodPut, c.workerPath(workerID), registration, nil)
+	return err
+}
+
+func (c *RemoteClient) heartbeatWorker(ctx context.Context, workerID string, activeJobs int64) (bool, error) {
+	status, err := c.do(ctx, http.MethodPost, c.workerPath(workerID)+"/heartbeat", map[string]int64{"active_jobs": activeJobs}, nil)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *RemoteClient) deregister(ctx context.Context, workerID string) error {
+	_, err := c.do(ctx, http.MethodPost, c.workerPath(workerID)+"/stop", nil, nil)
+	return err
+}
+
+// ListDrawsByContestRange fetches the draws of a contest range from the API
+// for the simulation engine.
+func (c *RemoteClient) ListDrawsByContestRange(ctx context.Context, arg results.ListDrawsByContestRangeParams) ([]results.Draw, error) {
+	var resp struct {
+		Draws []results.Draw `json:"draws"`
+	}
+	path := fmt.Sprintf("%s/draws?from=%d&to=%d", c.workerPath(c.workerID), arg.FromContest, arg.ToContest)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Draws, nil
+}
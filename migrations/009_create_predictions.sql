@@ -0,0 +1,34 @@
+-- Migration: 009_create_predictions.sql
+-- Creates the table storing ticket sets predicted for upcoming contests
+
+-- Up migration
+
+-- Table: predictions
+CREATE TABLE IF NOT EXISTS predictions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  config_id INTEGER,                -- Saved config used, NULL for inline recipes
+  recipe_name TEXT,
+  recipe_json TEXT NOT NULL,
+  recipe_hash TEXT NOT NULL,        -- SHA-256 of the canonical recipe JSON
+  target_contest INTEGER NOT NULL,  -- Contest the tickets are meant for
+  based_on_contest INTEGER NOT NULL, -- Latest contest available when predicting
+  seed INTEGER NOT NULL,
+  predictions_json TEXT NOT NULL,   -- JSON array of predicted tickets
+  status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'checked')),
+  actual_numbers TEXT,              -- JSON array, filled when the draw arrives
+  best_hits INTEGER,
+  hits_json TEXT,                   -- JSON array with hits per ticket
+  created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+  checked_at TEXT,
+  created_by TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_predictions_target ON predictions(target_contest, status);
+CREATE INDEX IF NOT EXISTS idx_predictions_recipe_hash ON predictions(recipe_hash);
+CREATE INDEX IF NOT EXISTS idx_predictions_created ON predictions(created_at DESC);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_predictions_created;
+-- DROP INDEX IF EXISTS idx_predictions_recipe_hash;
+-- DROP INDEX IF EXISTS idx_predictions_target;
+-- DROP TABLE IF EXISTS predictions;
@@ -0,0 +1,45 @@
+-- Migration: 010_create_tickets.sql
+-- Adds tracking of real tickets placed on upcoming contests (finances.db)
+
+-- Up migration
+
+-- Table: tickets (one row per placed bet, possibly covering several contests)
+CREATE TABLE IF NOT EXISTS tickets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner TEXT NOT NULL,
+    numbers TEXT NOT NULL,           -- JSON array with 5 to 15 numbers
+    first_contest INTEGER NOT NULL,
+    last_contest INTEGER NOT NULL,   -- Same as first_contest for single-contest bets
+    cost_cents INTEGER NOT NULL,     -- Total cost across all contests
+    prediction_id INTEGER,           -- Prediction the numbers came from, if any
+    notes TEXT,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'settled')),
+    total_prize_cents INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    CHECK(last_contest >= first_contest)
+);
+
+-- Table: ticket_checks (result of a ticket on each drawn contest)
+CREATE TABLE IF NOT EXISTS ticket_checks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_id INTEGER NOT NULL,
+    contest INTEGER NOT NULL,
+    hits INTEGER NOT NULL,
+    matched_numbers TEXT NOT NULL,   -- JSON array
+    prize_type TEXT,                 -- 'quina', 'quadra', 'terno', null
+    prize_cents INTEGER NOT NULL DEFAULT 0,
+    checked_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    UNIQUE(ticket_id, contest)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tickets_contests ON tickets(first_contest, last_contest);
+CREATE INDEX IF NOT EXISTS idx_tickets_owner ON tickets(owner);
+CREATE INDEX IF NOT EXISTS idx_ticket_checks_ticket_id ON ticket_checks(ticket_id);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_ticket_checks_ticket_id;
+-- DROP INDEX IF EXISTS idx_tickets_owner;
+-- DROP INDEX IF EXISTS idx_tickets_contests;
+-- DROP TABLE IF EXISTS ticket_checks;
+-- DROP TABLE IF EXISTS tickets;
@@ -0,0 +1,19 @@
+-- Migration: 011_add_simulation_search.sql
+-- Adds simulation tags and the indexes used by simulation search (simulations.db)
+
+-- Up migration
+
+-- Table: simulation_tags (free-form labels, many per simulation)
+CREATE TABLE IF NOT EXISTS simulation_tags (
+    simulation_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY(simulation_id, tag),
+    FOREIGN KEY(simulation_id) REFERENCES simulations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sim_tags_tag ON simulation_tags(tag, simulation_id);
+
+-- Search filters not covered by 002
+CREATE INDEX IF NOT EXISTS idx_sim_created_by ON simulations(created_by, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sim_mode_created ON simulations(mode, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sim_end_contest ON simulations(end_contest);
@@ -0,0 +1,17 @@
+-- Migration: 012_add_simulation_leases.sql
+-- Adds worker leases to simulations so jobs of crashed workers can be recovered (simulations.db)
+
+-- Up migration
+
+-- Claims increment attempts; workers extend the lease with heartbeats until the job finishes
+ALTER TABLE simulations ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE simulations ADD COLUMN heartbeat_at TEXT;
+ALTER TABLE simulations ADD COLUMN lease_expires_at TEXT;  -- UTC RFC3339
+
+CREATE INDEX IF NOT EXISTS idx_sim_lease ON simulations(status, lease_expires_at);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_sim_lease;
+-- ALTER TABLE simulations DROP COLUMN lease_expires_at;
+-- ALTER TABLE simulations DROP COLUMN heartbeat_at;
+-- ALTER TABLE simulations DROP COLUMN attempts;
@@ -0,0 +1,15 @@
+-- Migration: 013_add_simulation_priority.sql
+-- Adds queue priorities and pausing to simulations for fair job scheduling (simulations.db)
+
+-- Up migration
+
+-- Workers claim the highest priority first; paused jobs stay pending but are never claimed
+ALTER TABLE simulations ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE simulations ADD COLUMN paused INTEGER NOT NULL DEFAULT 0 CHECK(paused IN (0, 1));
+
+CREATE INDEX IF NOT EXISTS idx_sim_queue ON simulations(status, paused, priority DESC, created_at);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_sim_queue;
+-- ALTER TABLE simulations DROP COLUMN paused;
+-- ALTER TABLE simulations DROP COLUMN priority;
@@ -0,0 +1,29 @@
+-- Migration: 014_add_simulation_retries.sql
+-- Adds retry backoff and the attempts history of simulations (simulations.db)
+
+-- Up migration
+
+-- Simulations scheduled for a retry are not claimed before next_attempt_at
+ALTER TABLE simulations ADD COLUMN next_attempt_at TEXT;  -- UTC RFC3339
+
+-- Table: simulation_attempts (one row per finished run of a simulation)
+CREATE TABLE IF NOT EXISTS simulation_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    simulation_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL,
+    worker_id TEXT,
+    started_at TEXT,
+    finished_at TEXT NOT NULL,
+    outcome TEXT NOT NULL CHECK(outcome IN ('completed', 'failed', 'retrying')),
+    error_message TEXT,
+    transient INTEGER NOT NULL DEFAULT 0 CHECK(transient IN (0, 1)),
+    retry_at TEXT,
+    FOREIGN KEY(simulation_id) REFERENCES simulations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sim_attempts_sim ON simulation_attempts(simulation_id, attempt);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_sim_attempts_sim;
+-- DROP TABLE IF EXISTS simulation_attempts;
+-- ALTER TABLE simulations DROP COLUMN next_attempt_at;
@@ -0,0 +1,23 @@
+-- Migration: 015_create_workers.sql
+-- Adds the registry of worker processes (simulations.db)
+
+-- Up migration
+
+-- Table: workers (one row per worker ID, kept after the worker stops)
+CREATE TABLE IF NOT EXISTS workers (
+    id TEXT PRIMARY KEY,
+    host TEXT NOT NULL,
+    version TEXT NOT NULL,
+    concurrency INTEGER NOT NULL,
+    active_jobs INTEGER NOT NULL DEFAULT 0,
+    started_at TEXT NOT NULL,    -- UTC RFC3339
+    heartbeat_at TEXT NOT NULL,  -- UTC RFC3339
+    stopped_at TEXT              -- UTC RFC3339, NULL while running
+);
+
+-- Throughput is computed from the attempts finished by each worker
+CREATE INDEX IF NOT EXISTS idx_sim_attempts_worker ON simulation_attempts(worker_id, finished_at);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_sim_attempts_worker;
+-- DROP TABLE IF EXISTS workers;
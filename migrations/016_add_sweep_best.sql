@@ -0,0 +1,12 @@
+-- Migration: 016_add_sweep_best.sql
+-- Caches the best configuration of finished sweeps (simulations.db)
+
+-- Up migration
+
+-- Computed for SWEEP_BEST_METRIC when the last simulation of a sweep finishes
+ALTER TABLE sweep_jobs ADD COLUMN best_metric TEXT;
+ALTER TABLE sweep_jobs ADD COLUMN best_json TEXT;  -- JSON of the best configuration
+
+-- Down migration
+-- ALTER TABLE sweep_jobs DROP COLUMN best_json;
+-- ALTER TABLE sweep_jobs DROP COLUMN best_metric;
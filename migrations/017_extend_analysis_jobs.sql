@@ -0,0 +1,75 @@
+-- Migration: 017_extend_analysis_jobs.sql
+-- Turns analysis_jobs into a generic job queue run by workers (simulations.db)
+
+-- Up migration
+
+-- SQLite cannot drop the job_type CHECK in place, so the table is rebuilt.
+-- Job types are validated by the registry of the services instead.
+CREATE TABLE analysis_jobs_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  started_at TEXT,
+  finished_at TEXT,
+  status TEXT NOT NULL DEFAULT 'pending'
+    CHECK(status IN ('pending', 'running', 'completed', 'failed', 'cancelled')),
+
+  job_type TEXT NOT NULL,
+  created_by TEXT,
+
+  -- Configuration
+  config_json TEXT NOT NULL,  -- JSON payload of the job type
+
+  -- Progress, in units chosen by the job type
+  progress_done INTEGER NOT NULL DEFAULT 0,
+  progress_total INTEGER NOT NULL DEFAULT 0,
+
+  -- Results
+  total_simulations INTEGER,
+  completed_simulations INTEGER,
+  failed_simulations INTEGER,
+  result_json TEXT,
+
+  -- Best configs found
+  top_configs_json TEXT,
+
+  -- Artifacts
+  report_blob BLOB,
+  report_name TEXT,
+
+  error_message TEXT,
+
+  -- Worker leases, as for simulations
+  worker_id TEXT,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  heartbeat_at TEXT,
+  lease_expires_at TEXT  -- UTC RFC3339
+);
+
+INSERT INTO analysis_jobs_new (
+  id, created_at, started_at, finished_at, status, job_type, config_json,
+  total_simulations, completed_simulations, failed_simulations,
+  top_configs_json, report_blob, report_name, error_message
+)
+SELECT
+  id, created_at, started_at, finished_at, status, job_type, config_json,
+  total_simulations, completed_simulations, failed_simulations,
+  top_configs_json, report_blob, report_name, error_message
+FROM analysis_jobs;
+
+DROP TABLE analysis_jobs;
+ALTER TABLE analysis_jobs_new RENAME TO analysis_jobs;
+
+CREATE INDEX IF NOT EXISTS idx_analysis_status ON analysis_jobs(status);
+CREATE INDEX IF NOT EXISTS idx_analysis_type ON analysis_jobs(job_type);
+CREATE INDEX IF NOT EXISTS idx_analysis_lease ON analysis_jobs(status, lease_expires_at);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_analysis_lease;
+-- ALTER TABLE analysis_jobs DROP COLUMN lease_expires_at;
+-- ALTER TABLE analysis_jobs DROP COLUMN heartbeat_at;
+-- ALTER TABLE analysis_jobs DROP COLUMN attempts;
+-- ALTER TABLE analysis_jobs DROP COLUMN worker_id;
+-- ALTER TABLE analysis_jobs DROP COLUMN result_json;
+-- ALTER TABLE analysis_jobs DROP COLUMN progress_total;
+-- ALTER TABLE analysis_jobs DROP COLUMN progress_done;
+-- ALTER TABLE analysis_jobs DROP COLUMN created_by;
@@ -0,0 +1,18 @@
+-- Migration: 018_unique_contest_results.sql
+-- Stores the result of each contest of a simulation only once (simulations.db)
+
+-- Up migration
+
+-- Runs that completed twice before completions were guarded by their worker
+-- left duplicate rows behind; the first one is kept.
+DELETE FROM simulation_contest_results
+WHERE id NOT IN (
+  SELECT MIN(id) FROM simulation_contest_results
+  GROUP BY simulation_id, contest
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scr_simulation_contest
+  ON simulation_contest_results(simulation_id, contest);
+
+-- Down migration
+-- DROP INDEX IF EXISTS idx_scr_simulation_contest;
@@ -88,6 +88,9 @@ func (p *AdvancedPredictor) GeneratePredictions(ctx context.Context, params Pred
 			return nil, ctx.Err()
 		default:
 		}
+		if err := params.Budget.Spend(1); err != nil {
+			return nil, err
+		}
 
 		// sample first seed by marginal probs
 		first := sampleByWeight(p.rng, probs, maxNum)
@@ -143,6 +146,9 @@ func (p *AdvancedPredictor) GeneratePredictions(ctx context.Context, params Pred
 	for i := 0; i < seedsCount; i++ {
 		pop = append(pop, candidates[i].nums)
 	}
+	if err := params.Budget.Spend(int64(len(pop))); err != nil {
+		return nil, err
+	}
 	evolved := evolvePopulation(pop, cond, freq, nil, posFreqSum, 40, 0.15, 10, p.rng)
 	for _, e := range evolved {
 		refined := hillClimbRefine(e, cond, freq, nil, posFreqSum, p.rng, 60)
@@ -2,6 +2,7 @@ package predictor
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -150,3 +151,33 @@ func TestAdvancedPredictor_LargeHistory(t *testing.T) {
 		t.Fatalf("expected 5 predictions, got %d", len(res))
 	}
 }
+
+func TestAdvancedPredictor_Budget(t *testing.T) {
+	params := PredictionParams{
+		HistoricalDraws: []Draw{
+			{Contest: 1, Numbers: []int{1, 2, 3, 4, 5}},
+			{Contest: 2, Numbers: []int{2, 3, 4, 5, 6}},
+		},
+		NumPredictions: 3,
+		Seed:           7,
+	}
+
+	// 50 generated candidates plus 3 evolved ones
+	params.Budget = NewBudget(53)
+	if _, err := NewAdvancedPredictor(7).GeneratePredictions(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used := params.Budget.Used(); used != 53 {
+		t.Errorf("expected 53 evaluations, got %d", used)
+	}
+
+	// The same budget cannot cover a second run
+	if _, err := NewAdvancedPredictor(7).GeneratePredictions(context.Background(), params); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+
+	params.Budget = nil
+	if _, err := NewAdvancedPredictor(7).GeneratePredictions(context.Background(), params); err != nil {
+		t.Errorf("expected nil budget to be unlimited, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package predictor
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBudgetExhausted is returned when generating predictions would evaluate
+// more candidates than the budget allows.
+var ErrBudgetExhausted = errors.New("candidate evaluation budget exhausted")
+
+// Budget counts candidate evaluations across prediction runs, e.g. all the
+// contests of a simulation. A nil Budget is unlimited.
+type Budget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// NewBudget creates a budget of max evaluations. max <= 0 is unlimited.
+func NewBudget(max int64) *Budget {
+	return &Budget{max: max}
+}
+
+// Spend records n evaluations. It fails once the budget is exceeded.
+func (b *Budget) Spend(n int64) error {
+	if b == nil {
+		return nil
+	}
+	used := b.used.Add(n)
+	if b.max > 0 && used > b.max {
+		return fmt.Errorf("%w: %d evaluations allowed", ErrBudgetExhausted, b.max)
+	}
+	return nil
+}
+
+// Used returns the evaluations spent so far.
+func (b *Budget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.used.Load()
+}
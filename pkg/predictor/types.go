@@ -18,7 +18,8 @@ type PredictionParams struct {
 	MaxHistory      int // sim_prev_max
 	NumPredictions  int // sim_preds
 	Weights         Weights
-	Seed            int64 // deterministic seed
+	Seed            int64   // deterministic seed
+	Budget          *Budget // Optional, bounds candidate evaluations
 }
 
 // Weights contains optional algorithm weights that can be tuned or evolved.
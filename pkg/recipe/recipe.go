@@ -0,0 +1,232 @@
+// Package recipe keeps the versioned schema of simulation recipes and
+// upgrades stored recipes to the current version.
+package recipe
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// CurrentVersion is the recipe version written by the services.
+const CurrentVersion = "1.2"
+
+// unversioned is the registry key of recipes stored without a version field.
+const unversioned = "0"
+
+// ErrUnknownVersion is returned for recipes whose version is not registered.
+var ErrUnknownVersion = errors.New("unknown recipe version")
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// UpgradeFunc rewrites a decoded recipe of one version into the next one.
+type UpgradeFunc func(doc map[string]any) (map[string]any, error)
+
+// Version describes one registered recipe version.
+type Version struct {
+	Name    string
+	Schema  *Schema
+	Next    string      // Empty for the current version
+	Upgrade UpgradeFunc // Converts a recipe of this version into Next
+}
+
+// Registry holds the known recipe versions and how to upgrade between them.
+type Registry struct {
+	versions map[string]*Version
+	current  string
+}
+
+// NewRegistry creates an empty registry whose recipes are upgraded to current.
+func NewRegistry(current string) *Registry {
+	return &Registry{
+		versions: make(map[string]*Version),
+		current:  current,
+	}
+}
+
+// Register adds a version. Upgrade and next may be nil and empty only for the
+// current version.
+func (r *Registry) Register(name string, schema []byte, next string, upgrade UpgradeFunc) error {
+	s, err := ParseSchema(schema)
+	if err != nil {
+		return fmt.Errorf("version %s: %w", name, err)
+	}
+	if name != r.current && (next == "" || upgrade == nil) {
+		return fmt.Errorf("version %s: an upgrade to a newer version is required", name)
+	}
+	r.versions[name] = &Version{Name: name, Schema: s, Next: next, Upgrade: upgrade}
+	return nil
+}
+
+// Versions returns the registered version names in upgrade order.
+func (r *Registry) Versions() []string {
+	var names []string
+	for name := range r.versions {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return r.distance(b) - r.distance(a)
+	})
+	return names
+}
+
+// distance is the number of upgrades needed to reach the current version.
+func (r *Registry) distance(name string) int {
+	n := 0
+	for v := r.versions[name]; v != nil && v.Name != r.current; v = r.versions[v.Next] {
+		n++
+	}
+	return n
+}
+
+// Schema returns the schema of a version.
+func (r *Registry) Schema(version string) (*Schema, bool) {
+	v, ok := r.versions[version]
+	if !ok {
+		return nil, false
+	}
+	return v.Schema, true
+}
+
+// Normalize validates a recipe against the schema of its own version, upgrades
+// it step by step to the current version and validates the result. Unknown
+// keys are rejected at every step. The returned JSON is in the current version.
+func (r *Registry) Normalize(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode recipe: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("decode recipe: must be a JSON object")
+	}
+
+	version := unversioned
+	if raw, ok := doc["version"]; ok {
+		s, isString := raw.(string)
+		if !isString {
+			return nil, fmt.Errorf("version: must be a string")
+		}
+		version = s
+	}
+
+	for {
+		v, ok := r.versions[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownVersion, version)
+		}
+		if err := v.Schema.Validate(doc); err != nil {
+			return nil, fmt.Errorf("recipe %s: %w", version, err)
+		}
+		if version == r.current {
+			break
+		}
+
+		upgraded, err := v.Upgrade(doc)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade recipe %s to %s: %w", version, v.Next, err)
+		}
+		upgraded["version"] = v.Next
+		doc, version = upgraded, v.Next
+	}
+
+	return json.Marshal(doc)
+}
+
+// defaultRegistry holds the versions known to this build.
+//
+// Version 1.0 predates the registry: it is the recipe clients wrote before
+// schemas existed, i.e. the original engine parameters plus seeds,
+// confidence_level and fixed_tickets, which were added while recipes still
+// carried "1.0". Recipes stored with those keys keep validating as 1.0.
+// Every key added since gets a new version with its own upgrade.
+var defaultRegistry = mustDefaultRegistry()
+
+func mustDefaultRegistry() *Registry {
+	r := NewRegistry(CurrentVersion)
+	for _, v := range []struct {
+		name, next string
+		upgrade    UpgradeFunc
+	}{
+		{unversioned, "1.0", upgradeUnversioned},
+		{"1.0", "1.1", upgrade10},
+		{"1.1", "1.2", upgrade11},
+		{"1.2", "", nil},
+	} {
+		schema, err := schemaFiles.ReadFile("schemas/" + v.name + ".json")
+		if err != nil {
+			panic(err)
+		}
+		if err := r.Register(v.name, schema, v.next, v.upgrade); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Normalize upgrades a recipe to CurrentVersion using the built-in registry.
+func Normalize(data []byte) ([]byte, error) {
+	return defaultRegistry.Normalize(data)
+}
+
+// Versions lists the built-in recipe versions, oldest first.
+func Versions() []string {
+	return defaultRegistry.Versions()
+}
+
+// IsParameter reports whether name is a parameter of the current recipe
+// version.
+func IsParameter(name string) bool {
+	_, ok := ParameterType(name)
+	return ok
+}
+
+// ParameterType returns the JSON Schema type ("number", "integer",
+// "boolean", ...) of a parameter of the current recipe version.
+func ParameterType(name string) (string, bool) {
+	schema, ok := defaultRegistry.Schema(CurrentVersion)
+	if !ok {
+		return "", false
+	}
+	params, ok := schema.Properties["parameters"]
+	if !ok {
+		return "", false
+	}
+	param, ok := params.Properties[name]
+	if !ok {
+		return "", false
+	}
+	return param.Type, true
+}
+
+// upgradeUnversioned nests the flat parameters of the first presets under
+// "parameters".
+func upgradeUnversioned(doc map[string]any) (map[string]any, error) {
+	params := make(map[string]any, len(doc))
+	out := map[string]any{"parameters": params}
+	for key, value := range doc {
+		if key == "name" {
+			out[key] = value
+			continue
+		}
+		params[key] = value
+	}
+	return out, nil
+}
+
+// upgrade10 drops the keys 1.0 accepted but the engine never read.
+func upgrade10(doc map[string]any) (map[string]any, error) {
+	delete(doc, "algorithm")
+	if params, ok := doc["parameters"].(map[string]any); ok {
+		delete(params, "sim_count")
+		delete(params, "scorer_type")
+	}
+	return doc, nil
+}
+
+// upgrade11 keeps the recipe as is; 1.2 only adds the optional limits.
+func upgrade11(doc map[string]any) (map[string]any, error) {
+	return doc, nil
+}
@@ -0,0 +1,150 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize_UpgradesUnversionedPreset(t *testing.T) {
+	out, err := Normalize([]byte(`{"alpha":0.3,"beta":0.25,"gamma":0.25,"delta":0.2,"sim_prev_max":500,"sim_preds":20}`))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := map[string]any{
+		"version": CurrentVersion,
+		"parameters": map[string]any{
+			"alpha": 0.3, "beta": 0.25, "gamma": 0.25, "delta": 0.2,
+			"sim_prev_max": 500.0, "sim_preds": 20.0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNormalize_DropsLegacySweepKeys(t *testing.T) {
+	out, err := Normalize([]byte(`{"version":"1.0","name":"advanced","algorithm":"advanced","parameters":{"alpha":0.1,"sim_prev_max":100,"sim_count":1000,"scorer_type":"frequency"}}`))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := map[string]any{
+		"version":    CurrentVersion,
+		"name":       "advanced",
+		"parameters": map[string]any{"alpha": 0.1, "sim_prev_max": 100.0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNormalize_Version10KeepsPreSchemaParameters(t *testing.T) {
+	// seeds, confidence_level and fixed_tickets were written into 1.0
+	// recipes before versioning, so 1.0 accepts them
+	out, err := Normalize([]byte(`{"version":"1.0","parameters":{"seeds":3,"confidence_level":0.9,"fixed_tickets":[[1,2,3,4,5]]}}`))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	params := got["parameters"].(map[string]any)
+	if params["seeds"] != 3.0 || params["confidence_level"] != 0.9 || params["fixed_tickets"] == nil {
+		t.Errorf("expected pre-schema parameters to be kept, got %v", params)
+	}
+}
+
+func TestNormalize_CurrentVersionUnchanged(t *testing.T) {
+	in := `{"limits":{"timeout_seconds":60},"name":"x","parameters":{"fixed_tickets":[[1,2,3,4,5]],"seeds":3},"version":"1.2"}`
+	out, err := Normalize([]byte(in))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if string(out) != in {
+		t.Errorf("expected %s, got %s", in, out)
+	}
+}
+
+func TestNormalize_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipe  string
+		wantErr string
+	}{
+		{"unknown top-level key", `{"version":"1.1","parameters":{},"extra":1}`, "extra: unknown key"},
+		{"unknown parameter", `{"version":"1.1","parameters":{"sim_count":10}}`, "parameters.sim_count: unknown key"},
+		{"unknown unversioned key", `{"alpha":1,"foo":2}`, "foo: unknown key"},
+		{"wrong type", `{"version":"1.1","parameters":{"sim_preds":"ten"}}`, "parameters.sim_preds: must be an integer"},
+		{"fractional integer", `{"version":"1.1","parameters":{"sim_prev_max":10.5}}`, "parameters.sim_prev_max: must be an integer"},
+		{"negative weight", `{"version":"1.0","parameters":{"alpha":-1}}`, "parameters.alpha: must be >= 0"},
+		{"confidence level", `{"version":"1.1","parameters":{"confidence_level":1}}`, "parameters.confidence_level: must be < 1"},
+		{"short ticket", `{"version":"1.1","parameters":{"fixed_tickets":[[1,2,3]]}}`, "parameters.fixed_tickets[0]: must have at least 5 items"},
+		{"duplicate number", `{"version":"1.1","parameters":{"fixed_tickets":[[1,1,2,3,4]]}}`, "parameters.fixed_tickets[0]: items must be unique"},
+		{"missing parameters", `{"version":"1.1"}`, "parameters: is required"},
+		{"unknown limit", `{"version":"1.2","parameters":{},"limits":{"max_seeds":2}}`, "limits.max_seeds: unknown key"},
+		{"negative limit", `{"version":"1.2","parameters":{},"limits":{"timeout_seconds":-1}}`, "limits.timeout_seconds: must be >= 0"},
+		{"limits before 1.2", `{"version":"1.1","parameters":{},"limits":{"timeout_seconds":60}}`, "recipe 1.1: limits: unknown key"},
+		{"limits in 1.0", `{"version":"1.0","parameters":{},"limits":{"timeout_seconds":60}}`, "recipe 1.0: limits: unknown key"},
+		{"not an object", `[1,2]`, "decode recipe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Normalize([]byte(tt.recipe))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNormalize_UnknownVersion(t *testing.T) {
+	_, err := Normalize([]byte(`{"version":"9.0","parameters":{}}`))
+	if !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	want := []string{"0", "1.0", "1.1", CurrentVersion}
+	if got := Versions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParameterType(t *testing.T) {
+	for name, want := range map[string]string{"alpha": "number", "sim_preds": "integer", "enableEvolutionary": "boolean"} {
+		if got, ok := ParameterType(name); !ok || got != want {
+			t.Errorf("%s: expected %s, got %q (%v)", name, want, got, ok)
+		}
+	}
+	if _, ok := ParameterType("sim_count"); ok {
+		t.Error("expected sim_count not to be a parameter")
+	}
+}
+
+func TestRegistry_RegisterRequiresUpgrade(t *testing.T) {
+	r := NewRegistry("2")
+	if err := r.Register("1", []byte(`{"type":"object"}`), "", nil); err == nil {
+		t.Error("expected error registering an old version without upgrade")
+	}
+	if err := r.Register("2", []byte(`{"type":"object"}`), "", nil); err != nil {
+		t.Errorf("unexpected error registering current version: %v", err)
+	}
+}
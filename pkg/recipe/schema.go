@@ -0,0 +1,174 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used by recipe schemas: type,
+// properties, required, additionalProperties, items and numeric and array
+// bounds. Annotation keywords ($schema, $id, title, description) are ignored.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+}
+
+// ParseSchema decodes a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Validate checks a decoded JSON value (as produced by json.Unmarshal into
+// any) against the schema.
+func (s *Schema) Validate(v any) error {
+	return s.validate(v, "")
+}
+
+func (s *Schema) validate(v any, path string) error {
+	if err := s.validateType(v, path); err != nil {
+		return err
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		return s.validateObject(val, path)
+	case []any:
+		return s.validateArray(val, path)
+	case float64:
+		return s.validateNumber(val, path)
+	}
+	return nil
+}
+
+func (s *Schema) validateType(v any, path string) error {
+	ok := true
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		_, ok = v.(map[string]any)
+	case "array":
+		_, ok = v.([]any)
+	case "string":
+		_, ok = v.(string)
+	case "boolean":
+		_, ok = v.(bool)
+	case "number":
+		_, ok = v.(float64)
+	case "integer":
+		f, isNumber := v.(float64)
+		ok = isNumber && f == math.Trunc(f)
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", displayPath(path), s.Type)
+	}
+	if !ok {
+		return fmt.Errorf("%s: must be %s", displayPath(path), article(s.Type))
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(obj map[string]any, path string) error {
+	for _, key := range s.Required {
+		if _, ok := obj[key]; !ok {
+			return fmt.Errorf("%s: is required", joinPath(path, key))
+		}
+	}
+
+	// Walk keys in order so the reported error is deterministic
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop, ok := s.Properties[key]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("%s: unknown key", joinPath(path, key))
+			}
+			continue
+		}
+		if err := prop.validate(obj[key], joinPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(arr []any, path string) error {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		return fmt.Errorf("%s: must have at least %d items", displayPath(path), *s.MinItems)
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		return fmt.Errorf("%s: must have at most %d items", displayPath(path), *s.MaxItems)
+	}
+	if s.UniqueItems {
+		seen := make(map[string]bool, len(arr))
+		for _, item := range arr {
+			key, _ := json.Marshal(item)
+			if seen[string(key)] {
+				return fmt.Errorf("%s: items must be unique", displayPath(path))
+			}
+			seen[string(key)] = true
+		}
+	}
+	if s.Items != nil {
+		for i, item := range arr {
+			if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(f float64, path string) error {
+	if s.Minimum != nil && f < *s.Minimum {
+		return fmt.Errorf("%s: must be >= %v", displayPath(path), *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		return fmt.Errorf("%s: must be <= %v", displayPath(path), *s.Maximum)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		return fmt.Errorf("%s: must be < %v", displayPath(path), *s.ExclusiveMaximum)
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "recipe"
+	}
+	return path
+}
+
+func article(typ string) string {
+	if strings.IndexAny(typ, "aeiou") == 0 {
+		return "an " + typ
+	}
+	return "a " + typ
+}
@@ -0,0 +1,108 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// mutationScale is the standard deviation of a mutation of a range or
+// exponential parameter, as a fraction of its interval.
+const mutationScale = 0.1
+
+// RandomValues draws a value of every parameter over its whole domain, as
+// random sampling does.
+func (g *Generator) RandomValues(rng *rand.Rand, params []ParameterSweep) (map[string]float64, error) {
+	values := make(map[string]float64, len(params))
+	for _, param := range params {
+		value, err := sampleParameter(param, rng.Float64())
+		if err != nil {
+			return nil, fmt.Errorf("sample parameter %s: %w", param.Name, err)
+		}
+		values[param.Name] = value
+	}
+	return values, nil
+}
+
+// Crossover takes the value of every parameter from either parent with equal
+// probability, so the child stays within the domains of its parents.
+func (g *Generator) Crossover(rng *rand.Rand, params []ParameterSweep, a, b map[string]float64) map[string]float64 {
+	child := make(map[string]float64, len(params))
+	for _, param := range params {
+		if rng.Intn(2) == 0 {
+			child[param.Name] = a[param.Name]
+		} else {
+			child[param.Name] = b[param.Name]
+		}
+	}
+	return child
+}
+
+// Mutate mutates every parameter with probability rate and returns the
+// mutated values and how many parameters changed. Range and exponential
+// parameters move by a normal step clamped to their interval, in log space
+// for exponential ones, and int_range parameters by a step rounded to their
+// steps; discrete and categorical parameters take another of their values
+// and booleans flip.
+func (g *Generator) Mutate(rng *rand.Rand, params []ParameterSweep, values map[string]float64, rate float64) (map[string]float64, int, error) {
+	mutated := make(map[string]float64, len(values))
+	for name, value := range values {
+		mutated[name] = value
+	}
+
+	changed := 0
+	for _, param := range params {
+		if rng.Float64() >= rate {
+			continue
+		}
+		value := values[param.Name]
+		switch v := param.Values.(type) {
+		case RangeValues:
+			value = math.Min(math.Max(value+rng.NormFloat64()*mutationScale*(v.Max-v.Min), v.Min), v.Max)
+		case DiscreteValues:
+			others := make([]float64, 0, len(v.Values))
+			for _, candidate := range v.Values {
+				if candidate != value {
+					others = append(others, candidate)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			value = others[rng.Intn(len(others))]
+		case ExponentialValues:
+			exponent := math.Log(value)/math.Log(v.Base) + rng.NormFloat64()*mutationScale*float64(v.End-v.Start)
+			value = math.Pow(v.Base, math.Min(math.Max(exponent, float64(v.Start)), float64(v.End)))
+		case IntRangeValues:
+			// Snap to the steps of the range
+			steps := (v.Max - v.Min) / v.Step
+			step := math.Round((value - float64(v.Min) + rng.NormFloat64()*mutationScale*float64(v.Max-v.Min)) / float64(v.Step))
+			value = float64(v.Min + min(max(int(step), 0), steps)*v.Step)
+		case BoolValues:
+			value = 1 - value
+		case CategoricalValues:
+			if len(v.Values) < 2 {
+				continue
+			}
+			// Move to another index
+			other := rng.Intn(len(v.Values) - 1)
+			if other >= int(value) {
+				other++
+			}
+			value = float64(other)
+		default:
+			return nil, 0, fmt.Errorf("mutate parameter %s: unknown parameter type: %s", param.Name, param.Type)
+		}
+		if value != values[param.Name] {
+			changed++
+		}
+		mutated[param.Name] = value
+	}
+	return mutated, changed, nil
+}
+
+// BuildRecipe creates the recipe of the index-th combination of values of
+// params over the base recipe.
+func (g *Generator) BuildRecipe(baseRecipe Recipe, params []ParameterSweep, values map[string]float64, index int) GeneratedRecipe {
+	return g.buildRecipe(baseRecipe, params, values, index)
+}
@@ -0,0 +1,170 @@
+package sweep
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func evolveParams() []ParameterSweep {
+	return []ParameterSweep{
+		{Name: "alpha", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.5}},
+		{Name: "sim_preds", Type: "discrete", Values: DiscreteValues{Values: []float64{5, 10, 20}}},
+		{Name: "mutation_rate", Type: "exponential", Values: ExponentialValues{Base: 10, Start: -3, End: -1}},
+	}
+}
+
+// assertInDomain checks that values holds a value of every parameter domain.
+func assertInDomain(t *testing.T, values map[string]float64) {
+	t.Helper()
+	if alpha := values["alpha"]; alpha < 0 || alpha > 1 {
+		t.Errorf("alpha %f outside its range", alpha)
+	}
+	if preds := values["sim_preds"]; !slices.Contains([]float64{5, 10, 20}, preds) {
+		t.Errorf("sim_preds %f is not one of the values", preds)
+	}
+	if rate := values["mutation_rate"]; rate < 0.001-1e-12 || rate > 0.1+1e-12 {
+		t.Errorf("mutation_rate %f outside its range", rate)
+	}
+}
+
+func TestGenerator_RandomValues(t *testing.T) {
+	gen := NewGenerator()
+	rng := rand.New(rand.NewSource(1))
+
+	for range 50 {
+		values, err := gen.RandomValues(rng, evolveParams())
+		if err != nil {
+			t.Fatalf("RandomValues failed: %v", err)
+		}
+		assertInDomain(t, values)
+	}
+}
+
+func TestGenerator_Crossover(t *testing.T) {
+	gen := NewGenerator()
+	rng := rand.New(rand.NewSource(2))
+	a := map[string]float64{"alpha": 0.1, "sim_preds": 5, "mutation_rate": 0.001}
+	b := map[string]float64{"alpha": 0.9, "sim_preds": 20, "mutation_rate": 0.1}
+
+	mixed := false
+	for range 20 {
+		child := gen.Crossover(rng, evolveParams(), a, b)
+		fromA := 0
+		for name, value := range child {
+			switch value {
+			case a[name]:
+				fromA++
+			case b[name]:
+			default:
+				t.Fatalf("%s: %f comes from neither parent", name, value)
+			}
+		}
+		if fromA > 0 && fromA < len(child) {
+			mixed = true
+		}
+	}
+	if !mixed {
+		t.Error("expected some children to mix both parents")
+	}
+}
+
+func TestGenerator_Mutate(t *testing.T) {
+	gen := NewGenerator()
+	rng := rand.New(rand.NewSource(3))
+	values := map[string]float64{"alpha": 1, "sim_preds": 10, "mutation_rate": 0.1, "beta": 0.5}
+
+	unchanged, changed, err := gen.Mutate(rng, evolveParams(), values, 0)
+	if err != nil {
+		t.Fatalf("Mutate failed: %v", err)
+	}
+	if changed != 0 || unchanged["alpha"] != 1 {
+		t.Errorf("expected no mutation at rate 0, got %d changes", changed)
+	}
+
+	for range 50 {
+		mutated, changed, err := gen.Mutate(rng, evolveParams(), values, 1)
+		if err != nil {
+			t.Fatalf("Mutate failed: %v", err)
+		}
+		assertInDomain(t, mutated)
+		if mutated["sim_preds"] == 10 {
+			t.Error("expected a mutated discrete parameter to take another value")
+		}
+		if mutated["beta"] != 0.5 {
+			t.Error("expected parameters that are not swept to be kept")
+		}
+		if changed == 0 {
+			t.Error("expected mutations at rate 1")
+		}
+	}
+	if values["sim_preds"] != 10 {
+		t.Error("expected Mutate to leave its input alone")
+	}
+}
+
+func TestGenerator_ConstraintChecker(t *testing.T) {
+	gen := NewGenerator()
+	cfg := SweepConfig{
+		Parameters: []ParameterSweep{
+			{Name: "alpha", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.1}},
+			{Name: "beta", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.1}},
+		},
+		Constraints: []Constraint{
+			{Type: "sum", Parameters: []string{"alpha", "beta"}, Value: 1},
+			{Type: "expression", Expression: "alpha >= beta"},
+		},
+	}
+
+	satisfies, err := gen.ConstraintChecker(cfg)
+	if err != nil {
+		t.Fatalf("ConstraintChecker failed: %v", err)
+	}
+	if !satisfies(map[string]float64{"alpha": 0.7, "beta": 0.3}) {
+		t.Error("expected 0.7 and 0.3 to satisfy the constraints")
+	}
+	if satisfies(map[string]float64{"alpha": 0.3, "beta": 0.7}) {
+		t.Error("expected alpha < beta to violate the expression")
+	}
+	if satisfies(map[string]float64{"alpha": 0.3, "beta": 0.3}) {
+		t.Error("expected values summing to 0.6 to violate the sum")
+	}
+
+	none, err := gen.ConstraintChecker(SweepConfig{})
+	if err != nil || !none(map[string]float64{"alpha": math.Pi}) {
+		t.Errorf("expected no constraints to be satisfied, got error %v", err)
+	}
+}
+
+func TestGenerator_Mutate_TypedParameters(t *testing.T) {
+	gen := NewGenerator()
+	rng := rand.New(rand.NewSource(4))
+	params := []ParameterSweep{
+		{Name: "sim_preds", Type: "int_range", Values: IntRangeValues{Min: 5, Max: 50, Step: 5}},
+		{Name: "enableEvolutionary", Type: "bool", Values: BoolValues{}},
+		{Name: "predictor", Type: "categorical", Values: CategoricalValues{Values: []string{"frequency", "markov", "neural"}}},
+	}
+	values := map[string]float64{"sim_preds": 25, "enableEvolutionary": 0, "predictor": 1}
+
+	for range 50 {
+		mutated, _, err := gen.Mutate(rng, params, values, 1)
+		if err != nil {
+			t.Fatalf("Mutate failed: %v", err)
+		}
+		if preds := mutated["sim_preds"]; preds < 5 || preds > 50 || math.Mod(preds, 5) != 0 {
+			t.Errorf("sim_preds %f is not on the steps of its range", preds)
+		}
+		if mutated["enableEvolutionary"] != 1 {
+			t.Error("expected a mutated boolean to flip")
+		}
+		if predictor := mutated["predictor"]; predictor == 1 || (predictor != 0 && predictor != 2) {
+			t.Errorf("expected another predictor index, got %f", predictor)
+		}
+
+		recipe := gen.BuildRecipe(Recipe{Name: "base"}, params, mutated, 0)
+		if _, ok := recipe.Parameters["predictor"].(string); !ok {
+			t.Errorf("expected a predictor name, got %#v", recipe.Parameters["predictor"])
+		}
+	}
+}
@@ -0,0 +1,586 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression constraints are boolean expressions over parameter names, such
+// as "alpha + beta <= 1" or "enableEvolutionary || generations == 0". They
+// support number, string and boolean literals, parentheses, the arithmetic
+// operators + - * / %, the comparisons < <= > >= == !=, the logical
+// operators ! && || and the functions abs, min and max.
+//
+// Names refer to swept parameters, or else to parameters of the base recipe,
+// which are constants. range, discrete, exponential and int_range parameters
+// are numbers, bool parameters booleans and categorical parameters strings,
+// which only compare for equality. Numbers compare equal within a relative
+// tolerance of 1e-9, so that 0.1 + 0.2 == 0.3 holds and 0.1 + 0.2 > 0.3 does
+// not.
+
+// ExprError is an error at a column (1-based) of an expression.
+type ExprError struct {
+	Column int
+	Msg    string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+func exprErrorf(pos int, format string, args ...any) error {
+	return &ExprError{Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Tokens
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// exprOperators lists the operators, two-character ones first.
+var exprOperators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ","}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			// Exponent, as in 1e-3
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				j := i + 1
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				if j < len(src) && unicode.IsDigit(rune(src[j])) {
+					for i = j; i < len(src) && unicode.IsDigit(rune(src[i])); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{tokenNumber, src[start:i], start})
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, src[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, exprErrorf(start, "unterminated string")
+			}
+			i += end + 2
+			tokens = append(tokens, token{tokenString, src[start+1 : i-1], start})
+		default:
+			op := ""
+			for _, candidate := range exprOperators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, exprErrorf(i, "unexpected character %q", c)
+			}
+			tokens = append(tokens, token{tokenOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(src)}), nil
+}
+
+// Syntax tree
+
+type exprNode interface {
+	position() int
+}
+
+type (
+	numberNode struct {
+		pos   int
+		value float64
+	}
+	stringNode struct {
+		pos   int
+		value string
+	}
+	boolNode struct {
+		pos   int
+		value bool
+	}
+	identNode struct {
+		pos  int
+		name string
+	}
+	unaryNode struct {
+		pos int
+		op  string
+		x   exprNode
+	}
+	binaryNode struct {
+		pos  int
+		op   string
+		x, y exprNode
+	}
+	callNode struct {
+		pos  int
+		name string
+		args []exprNode
+	}
+)
+
+func (n *numberNode) position() int { return n.pos }
+func (n *stringNode) position() int { return n.pos }
+func (n *boolNode) position() int   { return n.pos }
+func (n *identNode) position() int  { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.pos }
+func (n *callNode) position() int   { return n.pos }
+
+// exprPrecedence is the binding power of the binary operators.
+var exprPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+type exprParser struct {
+	tokens []token
+	next   int
+}
+
+// parseExpression parses src into a syntax tree.
+func parseExpression(src string) (exprNode, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, exprErrorf(0, "empty expression")
+	}
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, exprErrorf(t.pos, "unexpected %q", t.text)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *exprParser) peekOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokenOp && t.text == op
+}
+
+func (p *exprParser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// parseBinary parses operators binding at least as tightly as minPrecedence.
+// Comparisons do not chain.
+func (p *exprParser) parseBinary(minPrecedence int) (exprNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		precedence, ok := exprPrecedence[t.text]
+		if t.kind != tokenOp || !ok || precedence < minPrecedence {
+			return x, nil
+		}
+		p.advance()
+		y, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryNode{pos: t.pos, op: t.text, x: x, y: y}
+		if precedence == exprPrecedence["=="] {
+			if next := p.peek(); exprPrecedence[next.text] == precedence && next.kind == tokenOp {
+				return nil, exprErrorf(next.pos, "comparisons cannot be chained")
+			}
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	t := p.peek()
+	if t.kind == tokenOp && (t.text == "!" || t.text == "-") {
+		p.advance()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: t.pos, op: t.text, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, exprErrorf(t.pos, "invalid number %q", t.text)
+		}
+		return &numberNode{pos: t.pos, value: value}, nil
+	case tokenString:
+		return &stringNode{pos: t.pos, value: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &boolNode{pos: t.pos, value: t.text == "true"}, nil
+		}
+		if p.peekOp("(") {
+			return p.parseCall(t)
+		}
+		return &identNode{pos: t.pos, name: t.text}, nil
+	case tokenOp:
+		if t.text == "(" {
+			x, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			if closing := p.advance(); closing.kind != tokenOp || closing.text != ")" {
+				return nil, exprErrorf(closing.pos, "expected )")
+			}
+			return x, nil
+		}
+		return nil, exprErrorf(t.pos, "unexpected %q", t.text)
+	default:
+		return nil, exprErrorf(t.pos, "unexpected end of expression")
+	}
+}
+
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	p.advance() // (
+	call := &callNode{pos: name.pos, name: name.text}
+	if p.peekOp(")") {
+		p.advance()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		switch t := p.advance(); {
+		case t.kind == tokenOp && t.text == ",":
+		case t.kind == tokenOp && t.text == ")":
+			return call, nil
+		default:
+			return nil, exprErrorf(t.pos, "expected , or )")
+		}
+	}
+}
+
+// Type checking and compilation
+
+type exprType int
+
+const (
+	exprNumber exprType = iota
+	exprBool
+	exprString
+)
+
+func (t exprType) String() string {
+	switch t {
+	case exprNumber:
+		return "number"
+	case exprBool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// compiledExpr is a type-checked expression, evaluated over the values of a
+// combination by the function of its type.
+type compiledExpr struct {
+	typ  exprType
+	num  func(map[string]float64) float64
+	cond func(map[string]float64) bool
+	str  func(map[string]float64) string
+	// Values of a categorical parameter, to check the literals it is
+	// compared with
+	categories []string
+}
+
+// exprScope maps the names of an expression to swept parameters and base
+// recipe constants.
+type exprScope struct {
+	params    map[string]ParameterSweep
+	constants map[string]any
+}
+
+func newExprScope(cfg SweepConfig) *exprScope {
+	scope := &exprScope{
+		params:    make(map[string]ParameterSweep, len(cfg.Parameters)),
+		constants: cfg.BaseRecipe.Parameters,
+	}
+	for _, param := range cfg.Parameters {
+		scope.params[param.Name] = param
+	}
+	return scope
+}
+
+// compileExpression parses and type-checks a boolean expression.
+func compileExpression(src string, scope *exprScope) (func(map[string]float64) bool, error) {
+	node, err := parseExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := scope.compile(node)
+	if err != nil {
+		return nil, err
+	}
+	if expr.typ != exprBool {
+		return nil, exprErrorf(node.position(), "constraint must be a boolean expression, got a %s", expr.typ)
+	}
+	return expr.cond, nil
+}
+
+func (s *exprScope) compile(node exprNode) (compiledExpr, error) {
+	switch n := node.(type) {
+	case *numberNode:
+		return numberExpr(func(map[string]float64) float64 { return n.value }), nil
+	case *stringNode:
+		return compiledExpr{typ: exprString, str: func(map[string]float64) string { return n.value }}, nil
+	case *boolNode:
+		return boolExpr(func(map[string]float64) bool { return n.value }), nil
+	case *identNode:
+		return s.compileIdent(n)
+	case *unaryNode:
+		x, err := s.compile(n.x)
+		if err != nil {
+			return compiledExpr{}, err
+		}
+		if n.op == "!" {
+			if x.typ != exprBool {
+				return compiledExpr{}, exprErrorf(n.pos, "! needs a boolean, got a %s", x.typ)
+			}
+			return boolExpr(func(v map[string]float64) bool { return !x.cond(v) }), nil
+		}
+		if x.typ != exprNumber {
+			return compiledExpr{}, exprErrorf(n.pos, "- needs a number, got a %s", x.typ)
+		}
+		return numberExpr(func(v map[string]float64) float64 { return -x.num(v) }), nil
+	case *binaryNode:
+		return s.compileBinary(n)
+	case *callNode:
+		return s.compileCall(n)
+	default:
+		return compiledExpr{}, exprErrorf(node.position(), "unsupported expression")
+	}
+}
+
+func (s *exprScope) compileIdent(n *identNode) (compiledExpr, error) {
+	name := n.name
+	if param, ok := s.params[name]; ok {
+		switch v := param.Values.(type) {
+		case BoolValues:
+			return boolExpr(func(values map[string]float64) bool { return values[name] != 0 }), nil
+		case CategoricalValues:
+			categories := v.Values
+			return compiledExpr{
+				typ:        exprString,
+				str:        func(values map[string]float64) string { return categories[int(values[name])] },
+				categories: categories,
+			}, nil
+		default:
+			return numberExpr(func(values map[string]float64) float64 { return values[name] }), nil
+		}
+	}
+
+	constant, ok := s.constants[name]
+	if !ok {
+		return compiledExpr{}, exprErrorf(n.pos, "unknown parameter %s", name)
+	}
+	switch c := constant.(type) {
+	case float64:
+		return numberExpr(func(map[string]float64) float64 { return c }), nil
+	case int:
+		return numberExpr(func(map[string]float64) float64 { return float64(c) }), nil
+	case bool:
+		return boolExpr(func(map[string]float64) bool { return c }), nil
+	case string:
+		return compiledExpr{typ: exprString, str: func(map[string]float64) string { return c }}, nil
+	default:
+		return compiledExpr{}, exprErrorf(n.pos, "parameter %s is not a number, boolean or string", name)
+	}
+}
+
+func (s *exprScope) compileBinary(n *binaryNode) (compiledExpr, error) {
+	x, err := s.compile(n.x)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+	y, err := s.compile(n.y)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		if x.typ != exprBool || y.typ != exprBool {
+			return compiledExpr{}, exprErrorf(n.pos, "%s needs booleans, got a %s and a %s", n.op, x.typ, y.typ)
+		}
+		if n.op == "&&" {
+			return boolExpr(func(v map[string]float64) bool { return x.cond(v) && y.cond(v) }), nil
+		}
+		return boolExpr(func(v map[string]float64) bool { return x.cond(v) || y.cond(v) }), nil
+
+	case "==", "!=":
+		if x.typ != y.typ {
+			return compiledExpr{}, exprErrorf(n.pos, "cannot compare a %s with a %s", x.typ, y.typ)
+		}
+		if err := checkCategory(x, n.y); err != nil {
+			return compiledExpr{}, err
+		}
+		if err := checkCategory(y, n.x); err != nil {
+			return compiledExpr{}, err
+		}
+		var equal func(map[string]float64) bool
+		switch x.typ {
+		case exprNumber:
+			equal = func(v map[string]float64) bool { return numbersEqual(x.num(v), y.num(v)) }
+		case exprBool:
+			equal = func(v map[string]float64) bool { return x.cond(v) == y.cond(v) }
+		default:
+			equal = func(v map[string]float64) bool { return x.str(v) == y.str(v) }
+		}
+		if n.op == "==" {
+			return boolExpr(equal), nil
+		}
+		return boolExpr(func(v map[string]float64) bool { return !equal(v) }), nil
+	}
+
+	if x.typ != exprNumber || y.typ != exprNumber {
+		return compiledExpr{}, exprErrorf(n.pos, "%s needs numbers, got a %s and a %s", n.op, x.typ, y.typ)
+	}
+	switch n.op {
+	case "<":
+		return boolExpr(func(v map[string]float64) bool { a, b := x.num(v), y.num(v); return a < b && !numbersEqual(a, b) }), nil
+	case "<=":
+		return boolExpr(func(v map[string]float64) bool { a, b := x.num(v), y.num(v); return a < b || numbersEqual(a, b) }), nil
+	case ">":
+		return boolExpr(func(v map[string]float64) bool { a, b := x.num(v), y.num(v); return a > b && !numbersEqual(a, b) }), nil
+	case ">=":
+		return boolExpr(func(v map[string]float64) bool { a, b := x.num(v), y.num(v); return a > b || numbersEqual(a, b) }), nil
+	case "+":
+		return numberExpr(func(v map[string]float64) float64 { return x.num(v) + y.num(v) }), nil
+	case "-":
+		return numberExpr(func(v map[string]float64) float64 { return x.num(v) - y.num(v) }), nil
+	case "*":
+		return numberExpr(func(v map[string]float64) float64 { return x.num(v) * y.num(v) }), nil
+	case "/":
+		return numberExpr(func(v map[string]float64) float64 { return x.num(v) / y.num(v) }), nil
+	default: // %
+		return numberExpr(func(v map[string]float64) float64 { return math.Mod(x.num(v), y.num(v)) }), nil
+	}
+}
+
+// checkCategory rejects comparing a categorical parameter with a string
+// literal that is not one of its values.
+func checkCategory(param compiledExpr, other exprNode) error {
+	literal, ok := other.(*stringNode)
+	if !ok || param.categories == nil {
+		return nil
+	}
+	for _, category := range param.categories {
+		if category == literal.value {
+			return nil
+		}
+	}
+	return exprErrorf(literal.pos, "%q is not one of the values %s", literal.value, strings.Join(param.categories, ", "))
+}
+
+func (s *exprScope) compileCall(n *callNode) (compiledExpr, error) {
+	args := make([]compiledExpr, len(n.args))
+	for i, arg := range n.args {
+		compiled, err := s.compile(arg)
+		if err != nil {
+			return compiledExpr{}, err
+		}
+		if compiled.typ != exprNumber {
+			return compiledExpr{}, exprErrorf(arg.position(), "%s needs numbers, got a %s", n.name, compiled.typ)
+		}
+		args[i] = compiled
+	}
+
+	switch n.name {
+	case "abs":
+		if len(args) != 1 {
+			return compiledExpr{}, exprErrorf(n.pos, "abs takes 1 argument, got %d", len(args))
+		}
+		return numberExpr(func(v map[string]float64) float64 { return math.Abs(args[0].num(v)) }), nil
+	case "min", "max":
+		if len(args) < 2 {
+			return compiledExpr{}, exprErrorf(n.pos, "%s takes at least 2 arguments, got %d", n.name, len(args))
+		}
+		pick := math.Min
+		if n.name == "max" {
+			pick = math.Max
+		}
+		return numberExpr(func(v map[string]float64) float64 {
+			result := args[0].num(v)
+			for _, arg := range args[1:] {
+				result = pick(result, arg.num(v))
+			}
+			return result
+		}), nil
+	default:
+		return compiledExpr{}, exprErrorf(n.pos, "unknown function %s", n.name)
+	}
+}
+
+func numberExpr(f func(map[string]float64) float64) compiledExpr {
+	return compiledExpr{typ: exprNumber, num: f}
+}
+
+func boolExpr(f func(map[string]float64) bool) compiledExpr {
+	return compiledExpr{typ: exprBool, cond: f}
+}
+
+// numbersEqual compares numbers within a relative tolerance.
+func numbersEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
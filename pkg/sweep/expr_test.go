@@ -0,0 +1,164 @@
+package sweep
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func exprConfig() SweepConfig {
+	return SweepConfig{
+		Name: "expr",
+		BaseRecipe: Recipe{
+			Version:    "1.1",
+			Name:       "base",
+			Parameters: map[string]any{"sim_prev_max": 10.0, "generations": 0, "scorer": "frequency"},
+		},
+		Parameters: []ParameterSweep{
+			{Name: "alpha", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.1}},
+			{Name: "beta", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.1}},
+			{Name: "sim_preds", Type: "int_range", Values: IntRangeValues{Min: 5, Max: 50, Step: 5}},
+			{Name: "enableEvolutionary", Type: "bool", Values: BoolValues{}},
+			{Name: "predictor", Type: "categorical", Values: CategoricalValues{Values: []string{"frequency", "markov"}}},
+		},
+	}
+}
+
+func TestCompileExpression(t *testing.T) {
+	scope := newExprScope(exprConfig())
+	values := map[string]float64{"alpha": 0.1, "beta": 0.2, "sim_preds": 20, "enableEvolutionary": 0, "predictor": 1}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"alpha + beta <= 1", true},
+		{"alpha + beta == 0.3", true},
+		{"alpha + beta > 0.3", false},
+		{"sim_preds * sim_prev_max < 200", false},
+		{"sim_preds * sim_prev_max <= 200", true},
+		{"enableEvolutionary || generations == 0", true},
+		{"enableEvolutionary && generations == 0", false},
+		{"!enableEvolutionary", true},
+		{"enableEvolutionary == false", true},
+		{"predictor == 'markov'", true},
+		{`predictor != "markov" || scorer == "frequency"`, true},
+		{"-alpha < 0 && abs(-alpha) == alpha", true},
+		{"max(alpha, beta, 0.15) == beta && min(alpha, beta) == 0.1", true},
+		{"sim_preds % 15 == 5", true},
+		{"(alpha + beta) * 10 >= 3", true},
+		{"1e-1 == alpha", true},
+		{"alpha < beta && beta < 1 || false", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			check, err := compileExpression(tt.expr, scope)
+			if err != nil {
+				t.Fatalf("compileExpression failed: %v", err)
+			}
+			if got := check(values); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	scope := newExprScope(exprConfig())
+
+	tests := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{"", 1, "empty expression"},
+		{"alpha + gama <= 1", 9, "unknown parameter gama"},
+		{"alpha + beta", 7, "must be a boolean expression"},
+		{"alpha <= beta <= 1", 15, "cannot be chained"},
+		{"alpha + * beta", 9, `unexpected "*"`},
+		{"(alpha < 1", 11, "expected )"},
+		{"alpha < 1)", 10, `unexpected ")"`},
+		{"alpha # 1", 7, "unexpected character"},
+		{"predictor == 'neural'", 14, `"neural" is not one of the values frequency, markov`},
+		{"predictor < 'markov'", 11, "< needs numbers"},
+		{"enableEvolutionary + 1 > 0", 20, "+ needs numbers"},
+		{"alpha && beta", 7, "&& needs booleans"},
+		{"alpha == true", 7, "cannot compare a number with a boolean"},
+		{"!alpha", 1, "! needs a boolean"},
+		{"sqrt(alpha) < 1", 1, "unknown function sqrt"},
+		{"abs(alpha, beta) < 1", 1, "abs takes 1 argument"},
+		{"alpha == 'x", 10, "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := compileExpression(tt.expr, scope)
+			var exprErr *ExprError
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("expected an ExprError, got %v", err)
+			}
+			if exprErr.Column != tt.column || !strings.Contains(exprErr.Msg, tt.msg) {
+				t.Errorf("expected %q at column %d, got %q at column %d", tt.msg, tt.column, exprErr.Msg, exprErr.Column)
+			}
+		})
+	}
+}
+
+func TestSweepConfig_Validate_ExpressionConstraints(t *testing.T) {
+	cfg := exprConfig()
+	cfg.Constraints = []Constraint{{Type: "expression", Expression: "alpha + beta <= 1"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid expression constraint, got %v", err)
+	}
+
+	cfg.Constraints = []Constraint{{Type: "expression", Expression: "alpha + gamma <= 1"}}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "constraint 0: expression: column 9: unknown parameter gamma") {
+		t.Errorf("expected the position of the unknown parameter, got %v", err)
+	}
+
+	// Standalone constraints only check the syntax
+	bad := Constraint{Type: "expression", Expression: "alpha +"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected a syntax error")
+	}
+	mixed := Constraint{Type: "expression", Expression: "alpha < 1", Parameters: []string{"alpha"}}
+	if err := mixed.Validate(); err == nil {
+		t.Error("expected parameters to be rejected on expression constraints")
+	}
+}
+
+func TestGenerator_Generate_ExpressionConstraint(t *testing.T) {
+	gen := NewGenerator()
+	cfg := exprConfig()
+	cfg.Parameters = cfg.Parameters[:2]
+	cfg.Parameters[0].Values = RangeValues{Min: 0, Max: 1, Step: 0.5}
+	cfg.Parameters[1].Values = RangeValues{Min: 0, Max: 1, Step: 0.5}
+	cfg.Constraints = []Constraint{{Type: "expression", Expression: "alpha + beta <= 1"}}
+
+	recipes, err := gen.Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	// (0,0) (0,.5) (0,1) (.5,0) (.5,.5) (1,0)
+	if len(recipes) != 6 {
+		t.Fatalf("expected 6 recipes, got %d", len(recipes))
+	}
+	for i, recipe := range recipes {
+		if sum := recipe.Values["alpha"] + recipe.Values["beta"]; sum > 1 {
+			t.Errorf("recipe %d: alpha + beta = %f violates the constraint", i, sum)
+		}
+	}
+}
+
+func BenchmarkExpressionConstraint(b *testing.B) {
+	check, err := compileExpression("alpha + beta <= 1 && (enableEvolutionary || sim_preds * sim_prev_max < 200)", newExprScope(exprConfig()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := map[string]float64{"alpha": 0.1, "beta": 0.2, "sim_preds": 20, "enableEvolutionary": 0}
+	for b.Loop() {
+		check(values)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"maps"
 	"math"
 	"sort"
+
+	"github.com/garnizeh/luckyfive/pkg/recipe"
 )
 
 // Generator handles the generation of parameter combinations for sweeps.
@@ -16,51 +18,71 @@ func NewGenerator() *Generator {
 }
 
 // GeneratedRecipe represents a single generated recipe from a sweep.
+// Parameters holds swept values with their type: float64 for range,
+// discrete and exponential parameters, int for int_range, bool and string.
+// Values holds them all as numbers, booleans as 0 and 1 and categoricals as
+// the index of their value, which is how combinations, constraints and the
+// evolution operators see them.
 type GeneratedRecipe struct {
 	ID          string
 	Name        string
 	Parameters  map[string]any
+	Values      map[string]float64
 	ParentSweep string
 }
 
 // ToServiceRecipe converts a GeneratedRecipe to a service-compatible Recipe.
 func (gr *GeneratedRecipe) ToServiceRecipe() Recipe {
 	return Recipe{
-		Version:    "1.0",
+		Version:    recipe.CurrentVersion,
 		Name:       gr.Name,
 		Parameters: gr.Parameters,
 	}
 }
 
-// Generate creates all valid parameter combinations for a sweep configuration.
+// Generate creates all valid parameter combinations for a sweep configuration,
+// or the valid samples of a sampled sweep.
 func (g *Generator) Generate(cfg SweepConfig) ([]GeneratedRecipe, error) {
 	// Validate the configuration first
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid sweep config: %w", err)
 	}
 
-	// Parse parameter ranges into value sets
-	paramSets := make(map[string][]float64)
-	for _, param := range cfg.Parameters {
-		values, err := g.expandParameter(param)
+	var combinations []map[string]float64
+	if cfg.Sampling.IsGrid() {
+		// Parse parameter ranges into value sets
+		paramSets := make(map[string][]float64)
+		for _, param := range cfg.Parameters {
+			values, err := g.expandParameter(param)
+			if err != nil {
+				return nil, fmt.Errorf("expand parameter %s: %w", param.Name, err)
+			}
+			paramSets[param.Name] = values
+		}
+
+		// Generate cartesian product
+		combinations = g.cartesianProduct(paramSets)
+	} else {
+		var err error
+		combinations, err = g.sample(cfg.Parameters, cfg.Sampling)
 		if err != nil {
-			return nil, fmt.Errorf("expand parameter %s: %w", param.Name, err)
+			return nil, err
 		}
-		paramSets[param.Name] = values
 	}
 
-	// Generate cartesian product
-	combinations := g.cartesianProduct(paramSets)
-
 	// Apply constraints if any
 	if len(cfg.Constraints) > 0 {
-		combinations = g.applyConstraints(combinations, cfg.Constraints)
+		check, err := g.ConstraintChecker(cfg)
+		if err != nil {
+			return nil, err
+		}
+		combinations = g.applyConstraints(combinations, check)
 	}
 
 	// Create recipes from combinations
 	recipes := make([]GeneratedRecipe, 0, len(combinations))
 	for i, combo := range combinations {
-		recipe := g.buildRecipe(cfg.BaseRecipe, combo, i)
+		recipe := g.buildRecipe(cfg.BaseRecipe, cfg.Parameters, combo, i)
 		recipes = append(recipes, recipe)
 	}
 
@@ -76,6 +98,12 @@ func (g *Generator) expandParameter(param ParameterSweep) ([]float64, error) {
 		return g.expandDiscrete(param.Values)
 	case "exponential":
 		return g.expandExponential(param.Values)
+	case "int_range":
+		return g.expandIntRange(param.Values)
+	case "bool":
+		return []float64{0, 1}, nil
+	case "categorical":
+		return g.expandCategorical(param.Values)
 	default:
 		return nil, fmt.Errorf("unknown parameter type: %s", param.Type)
 	}
@@ -119,6 +147,58 @@ func (g *Generator) expandExponential(values any) ([]float64, error) {
 	return result, nil
 }
 
+// expandIntRange expands an IntRangeValues into a slice of values.
+func (g *Generator) expandIntRange(values any) ([]float64, error) {
+	iv, ok := values.(IntRangeValues)
+	if !ok {
+		return nil, fmt.Errorf("values must be IntRangeValues for int_range type")
+	}
+
+	var result []float64
+	for v := iv.Min; v <= iv.Max; v += iv.Step {
+		result = append(result, float64(v))
+	}
+	return result, nil
+}
+
+// expandCategorical expands a CategoricalValues into the indices of its
+// values.
+func (g *Generator) expandCategorical(values any) ([]float64, error) {
+	cv, ok := values.(CategoricalValues)
+	if !ok {
+		return nil, fmt.Errorf("values must be CategoricalValues for categorical type")
+	}
+
+	result := make([]float64, len(cv.Values))
+	for i := range cv.Values {
+		result[i] = float64(i)
+	}
+	return result, nil
+}
+
+// sample draws the combinations of a sampled sweep, one per point of the
+// sampling strategy.
+func (g *Generator) sample(params []ParameterSweep, sampling *Sampling) ([]map[string]float64, error) {
+	points, err := sampling.points(len(params))
+	if err != nil {
+		return nil, err
+	}
+
+	combinations := make([]map[string]float64, len(points))
+	for i, point := range points {
+		combo := make(map[string]float64, len(params))
+		for j, param := range params {
+			value, err := sampleParameter(param, point[j])
+			if err != nil {
+				return nil, fmt.Errorf("sample parameter %s: %w", param.Name, err)
+			}
+			combo[param.Name] = value
+		}
+		combinations[i] = combo
+	}
+	return combinations, nil
+}
+
 // cartesianProduct generates all combinations of parameter values.
 func (g *Generator) cartesianProduct(paramSets map[string][]float64) []map[string]float64 {
 	// Get parameter names in stable order for consistent results
@@ -162,19 +242,12 @@ func (g *Generator) cartesianRecursive(
 // applyConstraints filters combinations based on constraints.
 func (g *Generator) applyConstraints(
 	combinations []map[string]float64,
-	constraints []Constraint,
+	check func(map[string]float64) bool,
 ) []map[string]float64 {
 	var filtered []map[string]float64
 
 	for _, combo := range combinations {
-		valid := true
-		for _, constraint := range constraints {
-			if !g.checkConstraint(combo, constraint) {
-				valid = false
-				break
-			}
-		}
-		if valid {
+		if check(combo) {
 			filtered = append(filtered, combo)
 		}
 	}
@@ -182,6 +255,35 @@ func (g *Generator) applyConstraints(
 	return filtered
 }
 
+// ConstraintChecker compiles the constraints of cfg, parsing expressions
+// once, into a check of whether values satisfy them all.
+func (g *Generator) ConstraintChecker(cfg SweepConfig) (func(map[string]float64) bool, error) {
+	scope := newExprScope(cfg)
+	checks := make([]func(map[string]float64) bool, 0, len(cfg.Constraints))
+	for i, constraint := range cfg.Constraints {
+		if constraint.Type == "expression" {
+			check, err := compileExpression(constraint.Expression, scope)
+			if err != nil {
+				return nil, fmt.Errorf("constraint %d: expression: %w", i, err)
+			}
+			checks = append(checks, check)
+			continue
+		}
+		checks = append(checks, func(combo map[string]float64) bool {
+			return g.checkConstraint(combo, constraint)
+		})
+	}
+
+	return func(values map[string]float64) bool {
+		for _, check := range checks {
+			if !check(values) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
 // checkConstraint validates a single constraint against a parameter combination.
 func (g *Generator) checkConstraint(combo map[string]float64, c Constraint) bool {
 	switch c.Type {
@@ -220,12 +322,12 @@ func (g *Generator) checkConstraint(combo map[string]float64, c Constraint) bool
 		return true
 
 	default:
-		return true // Unknown constraints are ignored (pass through)
+		return false // Unknown constraints reject every combination
 	}
 }
 
 // buildRecipe creates a GeneratedRecipe from base recipe and parameter combination.
-func (g *Generator) buildRecipe(baseRecipe Recipe, combo map[string]float64, index int) GeneratedRecipe {
+func (g *Generator) buildRecipe(baseRecipe Recipe, sweeps []ParameterSweep, combo map[string]float64, index int) GeneratedRecipe {
 	// Copy base parameters
 	params := make(map[string]any)
 	for k, v := range baseRecipe.Parameters {
@@ -233,14 +335,33 @@ func (g *Generator) buildRecipe(baseRecipe Recipe, combo map[string]float64, ind
 	}
 
 	// Override with sweep values
-	for paramName, value := range combo {
-		params[paramName] = value
+	values := make(map[string]float64, len(combo))
+	for _, param := range sweeps {
+		if value, ok := combo[param.Name]; ok {
+			params[param.Name] = typedValue(param, value)
+			values[param.Name] = value
+		}
 	}
 
 	return GeneratedRecipe{
 		ID:          fmt.Sprintf("sweep_var_%d", index),
 		Name:        fmt.Sprintf("%s_var_%d", baseRecipe.Name, index),
 		Parameters:  params,
+		Values:      values,
 		ParentSweep: "", // Will be set by caller
 	}
 }
+
+// typedValue converts the number of a combination to the type of param.
+func typedValue(param ParameterSweep, value float64) any {
+	switch v := param.Values.(type) {
+	case IntRangeValues:
+		return int(math.Round(value))
+	case BoolValues:
+		return value != 0
+	case CategoricalValues:
+		return v.Values[min(max(int(value), 0), len(v.Values)-1)]
+	default:
+		return value
+	}
+}
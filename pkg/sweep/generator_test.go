@@ -1,6 +1,7 @@
 package sweep
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -349,8 +350,8 @@ func TestGeneratedRecipe_ToServiceRecipe(t *testing.T) {
 
 	recipe := gr.ToServiceRecipe()
 
-	if recipe.Version != "1.0" {
-		t.Errorf("Expected version 1.0, got %s", recipe.Version)
+	if recipe.Version != "1.2" {
+		t.Errorf("Expected version 1.2, got %s", recipe.Version)
 	}
 
 	if recipe.Name != gr.Name {
@@ -535,3 +536,82 @@ func TestGenerator_expandExponential_TypeError(t *testing.T) {
 		t.Error("Expected error for invalid type")
 	}
 }
+
+func typedConfig() SweepConfig {
+	return SweepConfig{
+		Name: "typed",
+		BaseRecipe: Recipe{
+			Version:    "1.1",
+			Name:       "base",
+			Parameters: map[string]any{"alpha": 0.1},
+		},
+		Parameters: []ParameterSweep{
+			{Name: "sim_preds", Type: "int_range", Values: IntRangeValues{Min: 5, Max: 15, Step: 5}},
+			{Name: "enableEvolutionary", Type: "bool", Values: BoolValues{}},
+			{Name: "predictor", Type: "categorical", Values: CategoricalValues{Values: []string{"frequency", "markov"}}},
+		},
+	}
+}
+
+func TestGenerator_Generate_TypedParameters(t *testing.T) {
+	gen := NewGenerator()
+
+	recipes, err := gen.Generate(typedConfig())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(recipes) != 3*2*2 {
+		t.Fatalf("expected 12 recipes, got %d", len(recipes))
+	}
+
+	seen := make(map[string]bool)
+	for i, recipe := range recipes {
+		preds, ok := recipe.Parameters["sim_preds"].(int)
+		if !ok || preds%5 != 0 || preds < 5 || preds > 15 {
+			t.Errorf("recipe %d: expected an int sim_preds, got %#v", i, recipe.Parameters["sim_preds"])
+		}
+		evolutionary, ok := recipe.Parameters["enableEvolutionary"].(bool)
+		if !ok {
+			t.Errorf("recipe %d: expected a bool enableEvolutionary, got %#v", i, recipe.Parameters["enableEvolutionary"])
+		}
+		predictor, ok := recipe.Parameters["predictor"].(string)
+		if !ok || (predictor != "frequency" && predictor != "markov") {
+			t.Errorf("recipe %d: expected a predictor name, got %#v", i, recipe.Parameters["predictor"])
+		}
+		if recipe.Parameters["alpha"] != 0.1 {
+			t.Errorf("recipe %d: expected the base alpha, got %v", i, recipe.Parameters["alpha"])
+		}
+		if recipe.Values["predictor"] != 0 && recipe.Values["predictor"] != 1 {
+			t.Errorf("recipe %d: expected the predictor index in Values, got %v", i, recipe.Values["predictor"])
+		}
+		seen[fmt.Sprint(preds, evolutionary, predictor)] = true
+	}
+	if len(seen) != 12 {
+		t.Errorf("expected 12 distinct combinations, got %d", len(seen))
+	}
+}
+
+func TestGenerator_Generate_TypedParametersSampled(t *testing.T) {
+	gen := NewGenerator()
+	cfg := typedConfig()
+	cfg.Sampling = &Sampling{Strategy: SamplingRandom, Samples: 40, Seed: 5}
+
+	recipes, err := gen.Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	values := make(map[any]bool)
+	for i, recipe := range recipes {
+		preds, ok := recipe.Parameters["sim_preds"].(int)
+		if !ok || preds%5 != 0 || preds < 5 || preds > 15 {
+			t.Errorf("recipe %d: expected sim_preds on the steps, got %#v", i, recipe.Parameters["sim_preds"])
+		}
+		values[recipe.Parameters["enableEvolutionary"]] = true
+		values[recipe.Parameters["predictor"]] = true
+	}
+	for _, want := range []any{true, false, "frequency", "markov"} {
+		if !values[want] {
+			t.Errorf("expected %v to be sampled", want)
+		}
+	}
+}
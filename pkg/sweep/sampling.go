@@ -0,0 +1,215 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Sampling strategies of a sweep.
+const (
+	SamplingGrid           = "grid"
+	SamplingRandom         = "random"
+	SamplingLatinHypercube = "latin_hypercube"
+	SamplingSobol          = "sobol"
+)
+
+// MaxSamples bounds the simulations a sampled sweep can create.
+const MaxSamples = 10000
+
+// Sampling chooses the parameter combinations a sweep runs. Grid runs every
+// combination of the expanded values; the other strategies draw Samples
+// points, sampling range and exponential parameters over their whole
+// interval and discrete parameters from their values. The same Seed draws
+// the same points.
+type Sampling struct {
+	Strategy string `json:"strategy"`
+	Samples  int    `json:"samples,omitempty"`
+	Seed     int64  `json:"seed,omitempty"`
+}
+
+// IsGrid reports whether s runs the full cartesian product. A nil sampling
+// is a grid.
+func (s *Sampling) IsGrid() bool {
+	return s == nil || s.Strategy == "" || s.Strategy == SamplingGrid
+}
+
+// Validate validates the Sampling
+func (s *Sampling) Validate() error {
+	switch s.Strategy {
+	case "", SamplingGrid:
+		if s.Samples != 0 {
+			return errors.New("samples is not used by grid sampling")
+		}
+		return nil
+	case SamplingRandom, SamplingLatinHypercube, SamplingSobol:
+		// Valid strategies
+	default:
+		return fmt.Errorf("unknown strategy: %s", s.Strategy)
+	}
+
+	if s.Samples <= 0 {
+		return errors.New("samples must be positive")
+	}
+	if s.Samples > MaxSamples {
+		return fmt.Errorf("samples must be at most %d", MaxSamples)
+	}
+	return nil
+}
+
+// points draws the sample of s as points of the unit hypercube with one
+// coordinate in [0, 1) per dimension.
+func (s *Sampling) points(dims int) ([][]float64, error) {
+	rng := rand.New(rand.NewSource(s.Seed))
+	switch s.Strategy {
+	case SamplingRandom:
+		return randomPoints(rng, s.Samples, dims), nil
+	case SamplingLatinHypercube:
+		return latinHypercubePoints(rng, s.Samples, dims), nil
+	case SamplingSobol:
+		return sobolPoints(rng, s.Samples, dims)
+	default:
+		return nil, fmt.Errorf("unknown strategy: %s", s.Strategy)
+	}
+}
+
+func randomPoints(rng *rand.Rand, n, dims int) [][]float64 {
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, dims)
+		for j := range points[i] {
+			points[i][j] = rng.Float64()
+		}
+	}
+	return points
+}
+
+// latinHypercubePoints splits every dimension into n equal strata and puts
+// exactly one point in each stratum of each dimension.
+func latinHypercubePoints(rng *rand.Rand, n, dims int) [][]float64 {
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, dims)
+	}
+	for j := range dims {
+		for i, stratum := range rng.Perm(n) {
+			points[i][j] = (float64(stratum) + rng.Float64()) / float64(n)
+		}
+	}
+	return points
+}
+
+// sobolDirections holds the primitive polynomial degree s, coefficients a
+// and initial direction numbers m of Sobol dimensions 2 and up, from Joe and
+// Kuo's new-joe-kuo-6.21201 table. Dimension 1 is the van der Corput
+// sequence.
+var sobolDirections = []struct {
+	s, a int
+	m    []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
+
+// sobolBits is the precision of Sobol points.
+const sobolBits = 32
+
+// sobolPoints draws the first n points of the Sobol sequence with a random
+// digital shift from rng. The shift keeps the stratification of the
+// sequence, and avoids the all-zero first point.
+func sobolPoints(rng *rand.Rand, n, dims int) ([][]float64, error) {
+	if dims > len(sobolDirections)+1 {
+		return nil, fmt.Errorf("sobol sampling supports at most %d parameters", len(sobolDirections)+1)
+	}
+
+	directions := make([][sobolBits]uint32, dims)
+	for k := range sobolBits {
+		directions[0][k] = 1 << (sobolBits - 1 - k)
+	}
+	for j := 1; j < dims; j++ {
+		d := sobolDirections[j-1]
+		v := &directions[j]
+		for k := range sobolBits {
+			if k < d.s {
+				v[k] = d.m[k] << (sobolBits - 1 - k)
+				continue
+			}
+			v[k] = v[k-d.s] ^ (v[k-d.s] >> d.s)
+			for i := 1; i < d.s; i++ {
+				if (d.a>>(d.s-1-i))&1 == 1 {
+					v[k] ^= v[k-i]
+				}
+			}
+		}
+	}
+
+	shift := make([]uint32, dims)
+	for j := range shift {
+		shift[j] = rng.Uint32()
+	}
+
+	points := make([][]float64, n)
+	x := make([]uint32, dims)
+	for i := range points {
+		if i > 0 {
+			// Gray code order: flip the direction of the lowest zero bit of i-1
+			c := 0
+			for (i-1)>>c&1 == 1 {
+				c++
+			}
+			for j := range x {
+				x[j] ^= directions[j][c]
+			}
+		}
+		points[i] = make([]float64, dims)
+		for j := range x {
+			points[i][j] = float64(x[j]^shift[j]) / math.Exp2(sobolBits)
+		}
+	}
+	return points, nil
+}
+
+// sampleParameter maps u in [0, 1) to a value of param, as a number of a
+// combination.
+func sampleParameter(param ParameterSweep, u float64) (float64, error) {
+	switch v := param.Values.(type) {
+	case RangeValues:
+		return v.Min + u*(v.Max-v.Min), nil
+	case DiscreteValues:
+		return v.Values[min(int(u*float64(len(v.Values))), len(v.Values)-1)], nil
+	case ExponentialValues:
+		// Log-uniform, so every order of magnitude is sampled equally
+		return math.Pow(v.Base, float64(v.Start)+u*float64(v.End-v.Start)), nil
+	case IntRangeValues:
+		steps := (v.Max-v.Min)/v.Step + 1
+		return float64(v.Min + min(int(u*float64(steps)), steps-1)*v.Step), nil
+	case BoolValues:
+		if u < 0.5 {
+			return 0, nil
+		}
+		return 1, nil
+	case CategoricalValues:
+		return float64(min(int(u*float64(len(v.Values))), len(v.Values)-1)), nil
+	default:
+		return 0, fmt.Errorf("unknown parameter type: %s", param.Type)
+	}
+}
@@ -0,0 +1,211 @@
+package sweep
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSampling_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampling Sampling
+		wantErr  bool
+	}{
+		{"grid", Sampling{Strategy: SamplingGrid}, false},
+		{"empty strategy is grid", Sampling{}, false},
+		{"grid with samples", Sampling{Strategy: SamplingGrid, Samples: 10}, true},
+		{"random", Sampling{Strategy: SamplingRandom, Samples: 10, Seed: 1}, false},
+		{"latin hypercube", Sampling{Strategy: SamplingLatinHypercube, Samples: 10}, false},
+		{"sobol", Sampling{Strategy: SamplingSobol, Samples: MaxSamples}, false},
+		{"no samples", Sampling{Strategy: SamplingRandom}, true},
+		{"too many samples", Sampling{Strategy: SamplingSobol, Samples: MaxSamples + 1}, true},
+		{"unknown strategy", Sampling{Strategy: "halton", Samples: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sampling.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// assertStratified checks that every dimension has exactly one point in each
+// of len(points) equal strata.
+func assertStratified(t *testing.T, points [][]float64) {
+	t.Helper()
+	n := len(points)
+	for j := range points[0] {
+		seen := make([]bool, n)
+		for i, p := range points {
+			if p[j] < 0 || p[j] >= 1 {
+				t.Fatalf("point %d dimension %d: %f outside [0, 1)", i, j, p[j])
+			}
+			stratum := int(p[j] * float64(n))
+			if seen[stratum] {
+				t.Fatalf("dimension %d: stratum %d sampled twice", j, stratum)
+			}
+			seen[stratum] = true
+		}
+	}
+}
+
+func TestLatinHypercubePoints_Stratified(t *testing.T) {
+	points := latinHypercubePoints(rand.New(rand.NewSource(7)), 25, 4)
+	if len(points) != 25 {
+		t.Fatalf("expected 25 points, got %d", len(points))
+	}
+	assertStratified(t, points)
+}
+
+func TestSobolPoints_Stratified(t *testing.T) {
+	points, err := sobolPoints(rand.New(rand.NewSource(7)), 64, len(sobolDirections)+1)
+	if err != nil {
+		t.Fatalf("sobolPoints failed: %v", err)
+	}
+	assertStratified(t, points)
+
+	// The first two dimensions also put one point in each cell of an 8x8 grid
+	cells := make(map[[2]int]bool)
+	for _, p := range points {
+		cells[[2]int{int(p[0] * 8), int(p[1] * 8)}] = true
+	}
+	if len(cells) != 64 {
+		t.Errorf("expected 64 distinct cells, got %d", len(cells))
+	}
+}
+
+func TestSobolPoints_TooManyDimensions(t *testing.T) {
+	if _, err := sobolPoints(rand.New(rand.NewSource(1)), 4, len(sobolDirections)+2); err == nil {
+		t.Error("expected error for too many dimensions")
+	}
+}
+
+func sampledConfig(strategy string, samples int, seed int64) SweepConfig {
+	return SweepConfig{
+		Name: "sampled",
+		BaseRecipe: Recipe{
+			Version:    "1.0",
+			Name:       "base",
+			Parameters: map[string]any{"alpha": 0.1},
+		},
+		Parameters: []ParameterSweep{
+			{Name: "alpha", Type: "range", Values: RangeValues{Min: 0, Max: 1, Step: 0.5}},
+			{Name: "sim_preds", Type: "discrete", Values: DiscreteValues{Values: []float64{5, 10, 20}}},
+			{Name: "mutation_rate", Type: "exponential", Values: ExponentialValues{Base: 10, Start: -3, End: -1}},
+		},
+		Sampling: &Sampling{Strategy: strategy, Samples: samples, Seed: seed},
+	}
+}
+
+func TestGenerator_Generate_Sampled(t *testing.T) {
+	gen := NewGenerator()
+
+	for _, strategy := range []string{SamplingRandom, SamplingLatinHypercube, SamplingSobol} {
+		t.Run(strategy, func(t *testing.T) {
+			recipes, err := gen.Generate(sampledConfig(strategy, 30, 42))
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if len(recipes) != 30 {
+				t.Fatalf("expected 30 recipes, got %d", len(recipes))
+			}
+
+			for i, recipe := range recipes {
+				alpha := recipe.Parameters["alpha"].(float64)
+				if alpha < 0 || alpha > 1 {
+					t.Errorf("recipe %d: alpha %f outside its range", i, alpha)
+				}
+				if preds := recipe.Parameters["sim_preds"].(float64); !slices.Contains([]float64{5, 10, 20}, preds) {
+					t.Errorf("recipe %d: sim_preds %f is not one of the values", i, preds)
+				}
+				if rate := recipe.Parameters["mutation_rate"].(float64); rate < 0.001 || rate > 0.1 {
+					t.Errorf("recipe %d: mutation_rate %f outside its range", i, rate)
+				}
+			}
+
+			// Continuous parameters are not limited to the grid steps
+			offGrid := 0
+			for _, recipe := range recipes {
+				alpha := recipe.Parameters["alpha"].(float64)
+				if math.Mod(alpha, 0.5) != 0 {
+					offGrid++
+				}
+			}
+			if offGrid == 0 {
+				t.Error("expected alpha values between the grid steps")
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_SampledReproducible(t *testing.T) {
+	gen := NewGenerator()
+
+	first, err := gen.Generate(sampledConfig(SamplingLatinHypercube, 10, 1))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	again, err := gen.Generate(sampledConfig(SamplingLatinHypercube, 10, 1))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	other, err := gen.Generate(sampledConfig(SamplingLatinHypercube, 10, 2))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(first, again) {
+		t.Error("expected the same seed to draw the same sample")
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Error("expected another seed to draw another sample")
+	}
+}
+
+func TestGenerator_Generate_SampledWithConstraints(t *testing.T) {
+	gen := NewGenerator()
+
+	cfg := sampledConfig(SamplingRandom, 50, 3)
+	cfg.Constraints = []Constraint{{Type: "max", Parameters: []string{"alpha"}, Value: 0.5}}
+
+	recipes, err := gen.Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(recipes) == 0 || len(recipes) == 50 {
+		t.Fatalf("expected constraints to drop some samples, got %d", len(recipes))
+	}
+	for i, recipe := range recipes {
+		if alpha := recipe.Parameters["alpha"].(float64); alpha > 0.5 {
+			t.Errorf("recipe %d: alpha %f violates the constraint", i, alpha)
+		}
+	}
+}
+
+func TestSweepConfig_SamplingJSON(t *testing.T) {
+	cfg := sampledConfig(SamplingSobol, 16, 9)
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded SweepConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Sampling, cfg.Sampling) {
+		t.Errorf("expected sampling %+v, got %+v", cfg.Sampling, decoded.Sampling)
+	}
+
+	cfg.Sampling = &Sampling{Strategy: SamplingRandom}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected invalid sampling to fail validation")
+	}
+}
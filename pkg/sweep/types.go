@@ -13,12 +13,13 @@ type SweepConfig struct {
 	BaseRecipe  Recipe           `json:"base_recipe"`
 	Parameters  []ParameterSweep `json:"parameters"`
 	Constraints []Constraint     `json:"constraints,omitempty"`
+	Sampling    *Sampling        `json:"sampling,omitempty"` // Grid when nil
 }
 
 // ParameterSweep defines how to sweep a single parameter
 type ParameterSweep struct {
 	Name   string `json:"name"` // e.g., "alpha", "sim_prev_max"
-	Type   string `json:"type"` // "range", "discrete", "exponential"
+	Type   string `json:"type"` // "range", "discrete", "exponential", "int_range", "bool", "categorical"
 	Values any    `json:"values"`
 }
 
@@ -57,6 +58,30 @@ func (p ParameterSweep) MarshalJSON() ([]byte, error) {
 			}
 			aux.Values = values
 		}
+	case "int_range":
+		if iv, ok := p.Values.(IntRangeValues); ok {
+			values, err := json.Marshal(iv)
+			if err != nil {
+				return nil, err
+			}
+			aux.Values = values
+		}
+	case "bool":
+		if bv, ok := p.Values.(BoolValues); ok {
+			values, err := json.Marshal(bv)
+			if err != nil {
+				return nil, err
+			}
+			aux.Values = values
+		}
+	case "categorical":
+		if cv, ok := p.Values.(CategoricalValues); ok {
+			values, err := json.Marshal(cv)
+			if err != nil {
+				return nil, err
+			}
+			aux.Values = values
+		}
 	}
 
 	return json.Marshal(aux)
@@ -96,6 +121,27 @@ func (p *ParameterSweep) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		p.Values = ev
+	case "int_range":
+		var iv IntRangeValues
+		if err := json.Unmarshal(aux.Values, &iv); err != nil {
+			return err
+		}
+		p.Values = iv
+	case "bool":
+		// A boolean sweep needs no values
+		var bv BoolValues
+		if len(aux.Values) > 0 {
+			if err := json.Unmarshal(aux.Values, &bv); err != nil {
+				return err
+			}
+		}
+		p.Values = bv
+	case "categorical":
+		var cv CategoricalValues
+		if err := json.Unmarshal(aux.Values, &cv); err != nil {
+			return err
+		}
+		p.Values = cv
 	default:
 		return fmt.Errorf("unknown parameter type: %s", p.Type)
 	}
@@ -122,11 +168,29 @@ type ExponentialValues struct {
 	End   int     `json:"end"`   // e.g., 2
 }
 
-// Constraint defines a constraint on parameter combinations
+// IntRangeValues defines a range of integer values for a parameter
+type IntRangeValues struct {
+	Min  int `json:"min"`
+	Max  int `json:"max"`
+	Step int `json:"step"`
+}
+
+// BoolValues sweeps a boolean parameter over false and true
+type BoolValues struct{}
+
+// CategoricalValues defines a set of string values for a parameter
+type CategoricalValues struct {
+	Values []string `json:"values"`
+}
+
+// Constraint defines a constraint on parameter combinations. Expression
+// constraints hold a boolean Expression over parameter names instead of
+// Parameters and Value.
 type Constraint struct {
-	Type       string   `json:"type"` // "sum", "ratio", "min", "max"
-	Parameters []string `json:"parameters"`
-	Value      float64  `json:"value"`
+	Type       string   `json:"type"` // "sum", "ratio", "min", "max", "expression"
+	Parameters []string `json:"parameters,omitempty"`
+	Value      float64  `json:"value,omitempty"`
+	Expression string   `json:"expression,omitempty"`
 }
 
 // Recipe defines a simulation recipe
@@ -158,11 +222,34 @@ func (s *SweepConfig) Validate() error {
 		}
 	}
 
+	if s.Sampling != nil {
+		if err := s.Sampling.Validate(); err != nil {
+			return fmt.Errorf("sampling: %w", err)
+		}
+	}
+
 	// Validate constraints
+	categorical := make(map[string]bool)
+	for _, param := range s.Parameters {
+		categorical[param.Name] = param.Type == "categorical"
+	}
+	scope := newExprScope(*s)
 	for i, constraint := range s.Constraints {
 		if err := constraint.Validate(); err != nil {
 			return fmt.Errorf("constraint %d: %w", i, err)
 		}
+		if constraint.Type == "expression" {
+			// Type-check the expression against the parameters
+			if _, err := compileExpression(constraint.Expression, scope); err != nil {
+				return fmt.Errorf("constraint %d: expression: %w", i, err)
+			}
+			continue
+		}
+		for _, name := range constraint.Parameters {
+			if categorical[name] {
+				return fmt.Errorf("constraint %d: categorical parameter %s cannot be constrained", i, name)
+			}
+		}
 	}
 
 	return nil
@@ -181,6 +268,15 @@ func (p *ParameterSweep) Validate() error {
 		return p.validateDiscreteValues()
 	case "exponential":
 		return p.validateExponentialValues()
+	case "int_range":
+		return p.validateIntRangeValues()
+	case "bool":
+		if _, ok := p.Values.(BoolValues); !ok {
+			return errors.New("values must be BoolValues for type 'bool'")
+		}
+		return nil
+	case "categorical":
+		return p.validateCategoricalValues()
 	default:
 		return fmt.Errorf("unknown type: %s", p.Type)
 	}
@@ -233,8 +329,61 @@ func (p *ParameterSweep) validateExponentialValues() error {
 	return nil
 }
 
-// Validate validates the Constraint
+func (p *ParameterSweep) validateIntRangeValues() error {
+	iv, ok := p.Values.(IntRangeValues)
+	if !ok {
+		return errors.New("values must be IntRangeValues for type 'int_range'")
+	}
+
+	if iv.Min >= iv.Max {
+		return errors.New("min must be less than max")
+	}
+
+	if iv.Step <= 0 {
+		return errors.New("step must be positive")
+	}
+
+	return nil
+}
+
+func (p *ParameterSweep) validateCategoricalValues() error {
+	cv, ok := p.Values.(CategoricalValues)
+	if !ok {
+		return errors.New("values must be CategoricalValues for type 'categorical'")
+	}
+
+	if len(cv.Values) == 0 {
+		return errors.New("at least one value is required")
+	}
+
+	seen := make(map[string]bool, len(cv.Values))
+	for _, value := range cv.Values {
+		if seen[value] {
+			return fmt.Errorf("duplicate value: %s", value)
+		}
+		seen[value] = true
+	}
+
+	return nil
+}
+
+// Validate validates the Constraint. The names and types of an expression
+// are only checked by SweepConfig.Validate.
 func (c *Constraint) Validate() error {
+	if c.Type == "expression" {
+		if len(c.Parameters) != 0 {
+			return errors.New("parameters is not used by expression constraints")
+		}
+		if _, err := parseExpression(c.Expression); err != nil {
+			return fmt.Errorf("expression: %w", err)
+		}
+		return nil
+	}
+
+	if c.Expression != "" {
+		return fmt.Errorf("expression is not used by %s constraints", c.Type)
+	}
+
 	if len(c.Parameters) == 0 {
 		return errors.New("at least one parameter is required")
 	}
@@ -2,6 +2,7 @@ package sweep
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -243,6 +244,36 @@ func TestParameterSweep_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "valid int range",
+			param:   ParameterSweep{Name: "sim_preds", Type: "int_range", Values: IntRangeValues{Min: 5, Max: 20, Step: 5}},
+			wantErr: false,
+		},
+		{
+			name:    "int range zero step",
+			param:   ParameterSweep{Name: "sim_preds", Type: "int_range", Values: IntRangeValues{Min: 5, Max: 20}},
+			wantErr: true,
+		},
+		{
+			name:    "valid bool",
+			param:   ParameterSweep{Name: "enableEvolutionary", Type: "bool", Values: BoolValues{}},
+			wantErr: false,
+		},
+		{
+			name:    "valid categorical",
+			param:   ParameterSweep{Name: "predictor", Type: "categorical", Values: CategoricalValues{Values: []string{"frequency", "markov"}}},
+			wantErr: false,
+		},
+		{
+			name:    "categorical duplicate value",
+			param:   ParameterSweep{Name: "predictor", Type: "categorical", Values: CategoricalValues{Values: []string{"markov", "markov"}}},
+			wantErr: true,
+		},
+		{
+			name:    "categorical wrong values type",
+			param:   ParameterSweep{Name: "predictor", Type: "categorical", Values: DiscreteValues{Values: []float64{1}}},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -432,6 +463,54 @@ func TestJSONMarshaling(t *testing.T) {
 	}
 }
 
+func TestJSONMarshaling_TypedParameters(t *testing.T) {
+	data := []byte(`{
+		"name": "typed",
+		"base_recipe": {"version": "1.1", "name": "base", "parameters": {"alpha": 0.1}},
+		"parameters": [
+			{"name": "sim_preds", "type": "int_range", "values": {"min": 5, "max": 15, "step": 5}},
+			{"name": "enableEvolutionary", "type": "bool"},
+			{"name": "predictor", "type": "categorical", "values": {"values": ["frequency", "markov"]}}
+		]
+	}`)
+
+	var config SweepConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Unmarshaled config is invalid: %v", err)
+	}
+	if v, ok := config.Parameters[0].Values.(IntRangeValues); !ok || v.Step != 5 {
+		t.Errorf("expected IntRangeValues, got %#v", config.Parameters[0].Values)
+	}
+	if _, ok := config.Parameters[1].Values.(BoolValues); !ok {
+		t.Errorf("expected BoolValues, got %#v", config.Parameters[1].Values)
+	}
+	if v, ok := config.Parameters[2].Values.(CategoricalValues); !ok || len(v.Values) != 2 {
+		t.Errorf("expected CategoricalValues, got %#v", config.Parameters[2].Values)
+	}
+
+	// Round trip
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	var again SweepConfig
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Failed to unmarshal marshaled config: %v", err)
+	}
+	if !reflect.DeepEqual(again.Parameters, config.Parameters) {
+		t.Errorf("expected %+v after a round trip, got %+v", config.Parameters, again.Parameters)
+	}
+
+	// Categorical parameters have no numeric value to constrain
+	config.Constraints = []Constraint{{Type: "max", Parameters: []string{"predictor"}, Value: 1}}
+	if err := config.Validate(); err == nil {
+		t.Error("expected a constraint on a categorical parameter to fail validation")
+	}
+}
+
 func TestJSONMarshaling_InvalidRangeValues(t *testing.T) {
 	// Test marshaling with invalid range values (min >= max)
 	config := SweepConfig{
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/jobs"
+)
+
+func TestContestReportJob(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	ctx := context.Background()
+	q := jobs.New(db.SimulationsDB)
+	jobSvc := services.NewJobService(q, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	jobSvc.RegisterJobType(simSvc.ContestReportJobType())
+
+	sim, err := simSvc.CreateSimulation(ctx, services.CreateSimulationRequest{
+		Mode:         "simple",
+		RecipeName:   "test_preset",
+		Recipe:       services.Recipe{Version: "1.0", Name: "test", Parameters: services.RecipeParameters{SimPrevMax: 10, SimPreds: 5}},
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+
+	if _, err := jobSvc.EnqueueJob(ctx, services.JobTypeContestReport, services.ContestReportRequest{}, ""); !errors.Is(err, services.ErrInvalidJobPayload) {
+		t.Fatalf("expected missing simulation_id to be rejected, got %v", err)
+	}
+
+	queued, err := jobSvc.EnqueueJob(ctx, services.JobTypeContestReport, services.ContestReportRequest{SimulationID: sim.ID}, "alice")
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	if queued.Status != "pending" || queued.CreatedBy != "alice" {
+		t.Fatalf("unexpected queued job %+v", queued)
+	}
+
+	now := time.Now().UTC()
+	claimed, err := q.ClaimPendingAnalysisJob(ctx, jobs.ClaimPendingAnalysisJobParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("ClaimPendingAnalysisJob failed: %v", err)
+	}
+	if claimed.ID != queued.ID || claimed.Status != "running" || claimed.Attempts != 1 {
+		t.Fatalf("unexpected claimed job %+v", claimed)
+	}
+
+	if err := jobSvc.ExecuteJob(ctx, claimed); err != nil {
+		t.Fatalf("ExecuteJob failed: %v", err)
+	}
+
+	job, err := jobSvc.GetJob(ctx, queued.ID)
+	if err != nil {
+		t.Fatalf("GetJob failed: %v", err)
+	}
+	if job.Status != "completed" || job.FinishedAt == nil || job.ReportName == "" {
+		t.Fatalf("unexpected finished job %+v", job)
+	}
+	var result struct {
+		SimulationID int64 `json:"simulation_id"`
+		SizeBytes    int   `json:"size_bytes"`
+	}
+	if err := json.Unmarshal(job.Result, &result); err != nil || result.SimulationID != sim.ID || result.SizeBytes == 0 {
+		t.Fatalf("unexpected result %s: %v", job.Result, err)
+	}
+
+	name, report, err := jobSvc.GetJobReport(ctx, queued.ID)
+	if err != nil {
+		t.Fatalf("GetJobReport failed: %v", err)
+	}
+	if !strings.HasSuffix(name, ".csv") || len(report) != result.SizeBytes {
+		t.Errorf("unexpected report %q with %d bytes", name, len(report))
+	}
+	// Header plus contests 1001-1003
+	if lines := strings.Count(string(report), "\n"); lines != 4 {
+		t.Errorf("expected 4 CSV lines, got %d:\n%s", lines, report)
+	}
+
+	if err := jobSvc.CancelJob(ctx, queued.ID); !errors.Is(err, services.ErrJobNotCancellable) {
+		t.Errorf("expected finished job not to be cancellable, got %v", err)
+	}
+
+	list, err := jobSvc.ListJobs(ctx, services.JobFilter{Type: services.JobTypeContestReport, Status: "completed", Limit: 10})
+	if err != nil || len(list) != 1 || list[0].ID != queued.ID {
+		t.Errorf("unexpected job list %+v, %v", list, err)
+	}
+}
+
+func TestAnalysisJobLeases(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	ctx := context.Background()
+	q := jobs.New(db.SimulationsDB)
+
+	create := func() int64 {
+		t.Helper()
+		job, err := q.CreateAnalysisJob(ctx, jobs.CreateAnalysisJobParams{JobType: "sweep", ConfigJson: "{}"})
+		if err != nil {
+			t.Fatalf("CreateAnalysisJob failed: %v", err)
+		}
+		return job.ID
+	}
+	first := create()
+	second := create()
+
+	start := time.Now().UTC().Add(-time.Hour)
+	at := func(d time.Duration) sql.NullString {
+		return sql.NullString{String: start.Add(d).Format(time.RFC3339), Valid: true}
+	}
+	worker := sql.NullString{String: "worker-a", Valid: true}
+
+	claimed, err := q.ClaimPendingAnalysisJob(ctx, jobs.ClaimPendingAnalysisJobParams{StartedAt: at(0), WorkerID: worker, LeaseExpiresAt: at(time.Minute)})
+	if err != nil || claimed.ID != first {
+		t.Fatalf("expected oldest job %d to be claimed, got %d: %v", first, claimed.ID, err)
+	}
+
+	// The lease expired an hour ago; the job is requeued for another attempt
+	now := sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true}
+	requeued, err := q.RequeueExpiredAnalysisJobs(ctx, jobs.RequeueExpiredAnalysisJobsParams{Now: now, MaxAttempts: 1})
+	if err != nil || len(requeued) != 0 {
+		t.Fatalf("expected no requeue at max attempts, got %v: %v", requeued, err)
+	}
+	failed, err := q.FailExpiredAnalysisJobs(ctx, jobs.FailExpiredAnalysisJobsParams{Now: now, MaxAttempts: 1})
+	if err != nil || len(failed) != 1 || failed[0] != first {
+		t.Fatalf("expected job %d to fail, got %v: %v", first, failed, err)
+	}
+
+	claimed, err = q.ClaimPendingAnalysisJob(ctx, jobs.ClaimPendingAnalysisJobParams{StartedAt: at(0), WorkerID: worker, LeaseExpiresAt: at(time.Minute)})
+	if err != nil || claimed.ID != second {
+		t.Fatalf("expected job %d to be claimed, got %d: %v", second, claimed.ID, err)
+	}
+	requeued, err = q.RequeueExpiredAnalysisJobs(ctx, jobs.RequeueExpiredAnalysisJobsParams{Now: now, MaxAttempts: 3})
+	if err != nil || len(requeued) != 1 || requeued[0] != second {
+		t.Fatalf("expected job %d to be requeued, got %v: %v", second, requeued, err)
+	}
+
+	job, err := q.GetAnalysisJob(ctx, second)
+	if err != nil || job.Status != "pending" || job.WorkerID.Valid {
+		t.Fatalf("unexpected requeued job %+v: %v", job, err)
+	}
+}
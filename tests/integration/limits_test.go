@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+func TestSimulationExecutionLimits(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	err := simSvc.SetLimitPolicy(services.LimitPolicy{
+		Defaults: services.ExecutionLimits{Timeout: time.Minute, MaxContests: 2},
+		Ceiling:  services.ExecutionLimits{Timeout: time.Hour, MaxContests: 3, MaxCandidateEvaluations: 1000000},
+	})
+	if err != nil {
+		t.Fatalf("SetLimitPolicy failed: %v", err)
+	}
+	simSvc.SetRetryPolicy(services.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour})
+
+	ctx := context.Background()
+	recipe := services.Recipe{Version: "1.2", Name: "test", Parameters: services.RecipeParameters{SimPrevMax: 10, SimPreds: 5}}
+	create := func(limits *services.RecipeLimits, end int) (*simulations.Simulation, error) {
+		r := recipe
+		r.Limits = limits
+		return simSvc.CreateSimulation(ctx, services.CreateSimulationRequest{
+			Mode:         "simple",
+			RecipeName:   "test_preset",
+			Recipe:       r,
+			StartContest: 1001,
+			EndContest:   end,
+			Async:        true,
+		})
+	}
+
+	// A run that exhausts its budget fails for good instead of being retried
+	sim, err := create(&services.RecipeLimits{MaxCandidateEvaluations: 1}, 1002)
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+	q := simulations.New(db.SimulationsDB)
+	now := time.Now().UTC()
+	claimed, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil || claimed.ID != sim.ID {
+		t.Fatalf("expected simulation %d to be claimed, got %d: %v", sim.ID, claimed.ID, err)
+	}
+	if err := simSvc.ExecuteSimulation(ctx, sim.ID); errors.Is(err, services.ErrRetryScheduled) {
+		t.Fatalf("expected no retry after exceeding a limit, got %v", err)
+	}
+
+	got, err := q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "failed" || !strings.Contains(got.ErrorMessage.String, "limit exceeded") {
+		t.Errorf("expected simulation to fail on its limit, got %q: %q", got.Status, got.ErrorMessage.String)
+	}
+
+	// The default allows two contests; the recipe may raise it up to the ceiling
+	if _, err := create(nil, 1003); !errors.Is(err, services.ErrLimitExceeded) {
+		t.Errorf("expected three contests to exceed the default, got %v", err)
+	}
+	if _, err := create(&services.RecipeLimits{MaxContests: 4}, 1003); !errors.Is(err, services.ErrLimitExceeded) {
+		t.Errorf("expected override above the ceiling to be rejected, got %v", err)
+	}
+	if _, err := create(&services.RecipeLimits{MaxContests: 3}, 1003); err != nil {
+		t.Errorf("expected override within the ceiling, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	"github.com/garnizeh/luckyfive/internal/worker"
+	"github.com/garnizeh/luckyfive/pkg/sweep"
+)
+
+func optimizationRequest(method string, budget int) services.OptimizationRequest {
+	return services.OptimizationRequest{
+		Name: "search",
+		SweepConfig: sweep.SweepConfig{
+			Name: "search",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.0",
+				Name:       "base",
+				Parameters: map[string]any{"sim_prev_max": 10, "sim_preds": 5, "gamma": 0.5, "delta": 0.5},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "alpha", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{0.1, 0.5, 0.9}}},
+				{Name: "beta", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{0.1, 0.5, 0.9}}},
+			},
+		},
+		Method:       method,
+		Metric:       "avg_hits",
+		StartContest: 1001,
+		EndContest:   1004,
+		Budget:       budget,
+		Seed:         1,
+	}
+}
+
+func TestOptimizeSuccessiveHalving(t *testing.T) {
+	db := setupSweepTestDB(t)
+	_, sweepSvc, _ := setupSweepServices(t, db)
+	ctx := context.Background()
+
+	var progress []int64
+	result, err := sweepSvc.Optimize(ctx, optimizationRequest(services.OptimizationSuccessiveHalving, 100), func(done, total int64) {
+		progress = append(progress, done)
+	})
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	// 9 candidates on the last contest, 3 on the last contest again, 1 on all 4
+	if result.Stopped != services.OptimizationStopCompleted || result.Candidates != 9 {
+		t.Errorf("Expected a completed search of 9 candidates, got %+v", result)
+	}
+	if len(result.Trials) != 13 || result.ContestsUsed != 16 {
+		t.Fatalf("Expected 13 trials using 16 contests, got %d using %d", len(result.Trials), result.ContestsUsed)
+	}
+	rounds := make(map[int]int)
+	for _, trial := range result.Trials {
+		if trial.Error != "" || trial.SimulationID == 0 {
+			t.Errorf("Expected a successful run, got %+v", trial)
+		}
+		rounds[trial.Round]++
+	}
+	if rounds[0] != 9 || rounds[1] != 3 || rounds[2] != 1 {
+		t.Errorf("Expected rounds of 9, 3 and 1 trials, got %v", rounds)
+	}
+	last := result.Trials[len(result.Trials)-1]
+	if last.StartContest != 1001 || last.EndContest != 1004 {
+		t.Errorf("Expected the last round on the whole range, got %d-%d", last.StartContest, last.EndContest)
+	}
+	if result.Best == nil || result.Best.Candidate != last.Candidate {
+		t.Errorf("Expected the survivor to be the best, got %+v", result.Best)
+	}
+	if len(progress) != 13 || progress[12] != 16 {
+		t.Errorf("Expected progress after every trial, got %v", progress)
+	}
+}
+
+func TestOptimizeStopsOnBudget(t *testing.T) {
+	db := setupSweepTestDB(t)
+	_, sweepSvc, _ := setupSweepServices(t, db)
+
+	result, err := sweepSvc.Optimize(context.Background(), optimizationRequest(services.OptimizationSuccessiveHalving, 10), nil)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if result.Stopped != services.OptimizationStopBudget || result.ContestsUsed != 10 || len(result.Trials) != 10 {
+		t.Errorf("Expected 10 trials before the budget ran out, got %d using %d (%s)", len(result.Trials), result.ContestsUsed, result.Stopped)
+	}
+}
+
+func TestOptimizeBayesian(t *testing.T) {
+	db := setupSweepTestDB(t)
+	_, sweepSvc, _ := setupSweepServices(t, db)
+
+	req := optimizationRequest(services.OptimizationBayesian, 100)
+	req.InitialPoints = 3
+	req.MaxEvaluations = 6
+	result, err := sweepSvc.Optimize(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.Stopped != services.OptimizationStopCompleted || len(result.Trials) != 6 || result.ContestsUsed != 24 {
+		t.Fatalf("Expected 6 trials using 24 contests, got %d using %d (%s)", len(result.Trials), result.ContestsUsed, result.Stopped)
+	}
+	seen := make(map[int]bool)
+	bestScore := 0.0
+	for i, trial := range result.Trials {
+		if seen[trial.Candidate] {
+			t.Errorf("Trial %d: candidate %d ran twice", i, trial.Candidate)
+		}
+		seen[trial.Candidate] = true
+		if trial.BestScore < bestScore {
+			t.Errorf("Trial %d: best score went down from %f to %f", i, bestScore, trial.BestScore)
+		}
+		bestScore = trial.BestScore
+	}
+	if result.Best == nil || result.Best.Score != bestScore {
+		t.Errorf("Expected the best trial to score %f, got %+v", bestScore, result.Best)
+	}
+}
+
+func TestOptimizationJobTypeValidate(t *testing.T) {
+	db := setupSweepTestDB(t)
+	_, sweepSvc, _ := setupSweepServices(t, db)
+	jobType := sweepSvc.OptimizationJobType()
+
+	valid, _ := json.Marshal(optimizationRequest(services.OptimizationBayesian, 100))
+	if err := jobType.Validate(valid); err != nil {
+		t.Errorf("Expected a valid request, got %v", err)
+	}
+
+	for name, mutate := range map[string]func(*services.OptimizationRequest){
+		"unknown method": func(r *services.OptimizationRequest) { r.Method = "grid" },
+		"unknown metric": func(r *services.OptimizationRequest) { r.Metric = "luck" },
+		"no budget":      func(r *services.OptimizationRequest) { r.Budget = 0 },
+		"eta of 1":       func(r *services.OptimizationRequest) { r.Eta = 1 },
+		"bad range":      func(r *services.OptimizationRequest) { r.EndContest = 1000 },
+	} {
+		req := optimizationRequest(services.OptimizationSuccessiveHalving, 100)
+		mutate(&req)
+		payload, _ := json.Marshal(req)
+		if err := jobType.Validate(payload); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestOptimizeGenetic(t *testing.T) {
+	db := setupSweepTestDB(t)
+	simSvc, sweepSvc, _ := setupSweepServices(t, db)
+	sweepSvc.SetBatchPollInterval(10 * time.Millisecond)
+
+	// Generations are queued, so a worker runs them
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jobWorker := worker.NewJobWorker(simulations.New(db.SimulationsDB), simSvc, "worker-a", 10*time.Millisecond, 4, logger)
+	workerCtx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jobWorker.Start(workerCtx)
+	}()
+	defer func() {
+		stop()
+		<-done
+	}()
+
+	req := optimizationRequest(services.OptimizationGenetic, 1000)
+	req.SweepConfig.Parameters = []sweep.ParameterSweep{
+		{Name: "alpha", Type: "range", Values: sweep.RangeValues{Min: 0, Max: 1, Step: 0.1}},
+		{Name: "beta", Type: "range", Values: sweep.RangeValues{Min: 0, Max: 1, Step: 0.1}},
+	}
+	req.SweepConfig.Constraints = []sweep.Constraint{{Type: "max", Parameters: []string{"alpha"}, Value: 0.5}}
+	req.Population = 4
+	req.Generations = 2
+	req.Elite = 1
+	req.MutationRate = 0.5
+	result, err := sweepSvc.Optimize(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	// 3 generations of 4, the elite of the last 2 carried over without a run
+	if result.Stopped != services.OptimizationStopCompleted || len(result.Trials) != 12 {
+		t.Fatalf("Expected 12 completed trials, got %d (%s)", len(result.Trials), result.Stopped)
+	}
+	if result.ContestsUsed > 10*4 || result.Candidates > 10 {
+		t.Errorf("Expected at most 10 runs, got %d candidates using %d contests", result.Candidates, result.ContestsUsed)
+	}
+	for i, trial := range result.Trials {
+		if trial.Round != i/4 {
+			t.Errorf("Trial %d: expected generation %d, got %d", i, i/4, trial.Round)
+		}
+		if alpha := trial.Params["alpha"].(float64); alpha > 0.5 {
+			t.Errorf("Trial %d: alpha %f violates the constraint", i, alpha)
+		}
+		switch trial.Origin {
+		case services.OptimizationOriginRandom:
+			if trial.Round != 0 || len(trial.Parents) != 0 {
+				t.Errorf("Trial %d: unexpected random trial %+v", i, trial)
+			}
+		case services.OptimizationOriginElite:
+			if len(trial.Parents) != 1 || result.Trials[trial.Parents[0]].Candidate != trial.Candidate {
+				t.Errorf("Trial %d: expected an elite to descend from itself, got %+v", i, trial)
+			}
+		case services.OptimizationOriginCrossover, services.OptimizationOriginMutation:
+			if len(trial.Parents) != 2 {
+				t.Errorf("Trial %d: expected 2 parents, got %v", i, trial.Parents)
+			}
+			for _, parent := range trial.Parents {
+				if result.Trials[parent].Round != trial.Round-1 {
+					t.Errorf("Trial %d: parent %d is not from the last generation", i, parent)
+				}
+			}
+		default:
+			t.Errorf("Trial %d: unexpected origin %q", i, trial.Origin)
+		}
+	}
+	if result.Best == nil {
+		t.Fatal("Expected a best trial")
+	}
+
+	// Every run was claimed by the worker rather than run by the search
+	for i, trial := range result.Trials {
+		sim, err := simSvc.GetSimulation(context.Background(), trial.SimulationID)
+		if err != nil {
+			t.Fatalf("Trial %d: GetSimulation failed: %v", i, err)
+		}
+		if sim.Status != "completed" || sim.WorkerID.String != "worker-a" {
+			t.Errorf("Trial %d: expected a simulation completed by worker-a, got %s on %q", i, sim.Status, sim.WorkerID.String)
+		}
+	}
+}
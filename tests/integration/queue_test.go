@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+func TestClaimOrderPriorityAndFairness(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	ctx := context.Background()
+
+	create := func(createdBy string, priority int64) int64 {
+		t.Helper()
+		sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+			RecipeJson:   `{"version":"1.1","name":"test","parameters":{}}`,
+			Mode:         "simple",
+			StartContest: 1001,
+			EndContest:   1003,
+			CreatedBy:    sql.NullString{String: createdBy, Valid: true},
+			Priority:     priority,
+		})
+		if err != nil {
+			t.Fatalf("CreateSimulation failed: %v", err)
+		}
+		return sim.ID
+	}
+
+	alice1 := create("alice", 0)
+	alice2 := create("alice", 0)
+	alice3 := create("alice", 0)
+	bob := create("bob", 0)
+	carolSweep := create("carol", -10)
+	dave := create("dave", 0)
+	eve := create("eve", 5)
+
+	paused, err := q.UpdateQueuedSimulation(ctx, simulations.UpdateQueuedSimulationParams{
+		Paused: sql.NullInt64{Int64: 1, Valid: true},
+		ID:     dave,
+	})
+	if err != nil || paused.Paused != 1 || paused.Priority != 0 {
+		t.Fatalf("expected dave's simulation to be paused, got %+v, %v", paused, err)
+	}
+
+	start := time.Now().UTC()
+	claims := 0
+	claim := func() (simulations.Simulation, error) {
+		claims++
+		at := sql.NullString{String: start.Add(time.Duration(claims) * time.Second).Format(time.RFC3339), Valid: true}
+		return q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+			StartedAt:      at,
+			WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+			LeaseExpiresAt: at,
+		})
+	}
+
+	// Jobs run one at a time, so fairness comes from each user's last claim
+	for _, want := range []int64{eve, alice1, bob, alice2, alice3, carolSweep} {
+		sim, err := claim()
+		if err != nil {
+			t.Fatalf("ClaimPendingSimulation failed: %v", err)
+		}
+		if sim.ID != want {
+			t.Fatalf("expected simulation %d to be claimed, got %d", want, sim.ID)
+		}
+		if n, err := q.CompleteSimulation(ctx, simulations.CompleteSimulationParams{ID: sim.ID, WorkerID: sim.WorkerID}); err != nil || n != 1 {
+			t.Fatalf("CompleteSimulation failed: %d rows, %v", n, err)
+		}
+	}
+
+	if _, err := claim(); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected paused simulation to be skipped, got %v", err)
+	}
+
+	// Only queued simulations can be rescheduled
+	if _, err := q.UpdateQueuedSimulation(ctx, simulations.UpdateQueuedSimulationParams{
+		Priority: sql.NullInt64{Int64: 50, Valid: true},
+		ID:       eve,
+	}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected completed simulation to be left alone, got %v", err)
+	}
+
+	if _, err := q.UpdateQueuedSimulation(ctx, simulations.UpdateQueuedSimulationParams{
+		Paused: sql.NullInt64{Valid: true},
+		ID:     dave,
+	}); err != nil {
+		t.Fatalf("UpdateQueuedSimulation failed: %v", err)
+	}
+	sim, err := claim()
+	if err != nil || sim.ID != dave {
+		t.Fatalf("expected resumed simulation to be claimed, got %d, %v", sim.ID, err)
+	}
+}
+
+func TestClaimOrderPrefersUsersWithFewerRunningJobs(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	_, err := db.SimulationsDB.Exec(`
+INSERT INTO simulations (id, created_at, started_at, status, recipe_json, mode, start_contest, end_contest, created_by) VALUES
+(1, '2025-01-01 09:00:00', '2025-01-01T09:00:00Z', 'running', '{}', 'simple', 1, 2, 'alice'),
+(2, '2025-01-01 09:01:00', NULL, 'pending', '{}', 'simple', 1, 2, 'alice'),
+(3, '2025-01-01 09:02:00', '2025-01-01T10:00:00Z', 'completed', '{}', 'simple', 1, 2, 'bob'),
+(4, '2025-01-01 09:03:00', NULL, 'pending', '{}', 'simple', 1, 2, 'bob');
+`)
+	if err != nil {
+		t.Fatalf("Failed to insert simulations: %v", err)
+	}
+
+	// Bob claimed more recently, but alice already has a job running
+	sim, err := simulations.New(db.SimulationsDB).ClaimPendingSimulation(context.Background(), simulations.ClaimPendingSimulationParams{
+		StartedAt: sql.NullString{String: "2025-01-01T11:00:00Z", Valid: true},
+		WorkerID:  sql.NullString{String: "worker-b", Valid: true},
+	})
+	if err != nil || sim.ID != 4 {
+		t.Fatalf("expected bob's simulation to be claimed, got %d, %v", sim.ID, err)
+	}
+}
@@ -0,0 +1,232 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/garnizeh/luckyfive/internal/handlers"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+	"github.com/garnizeh/luckyfive/internal/worker"
+)
+
+// newLeaseServer serves the job-lease API of remote workers like cmd/api.
+func newLeaseServer(leaseSvc services.LeaseServicer) *httptest.Server {
+	r := chi.NewRouter()
+	r.Put("/api/v1/workers/{worker}", handlers.RegisterWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/heartbeat", handlers.HeartbeatWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/stop", handlers.StopWorker(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/reap", handlers.ReapExpiredSimulations(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/claim", handlers.ClaimSimulation(leaseSvc))
+	r.Get("/api/v1/workers/{worker}/draws", handlers.ListLeaseDraws(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/heartbeat", handlers.HeartbeatSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/release", handlers.ReleaseSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/complete", handlers.CompleteSimulation(leaseSvc))
+	r.Post("/api/v1/workers/{worker}/simulations/{id}/fail", handlers.FailSimulation(leaseSvc))
+	return httptest.NewServer(r)
+}
+
+func TestRemoteWorker(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	simulationsQueries := simulations.New(db.SimulationsDB)
+	if err := simSvc.SetLimitPolicy(services.LimitPolicy{Ceiling: services.ExecutionLimits{MaxCandidateEvaluations: 1000000}}); err != nil {
+		t.Fatalf("SetLimitPolicy failed: %v", err)
+	}
+	simSvc.SetRetryPolicy(services.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour})
+	leaseSvc := services.NewLeaseService(simSvc, simulationsQueries, results.New(db.ResultsDB), logger)
+
+	server := newLeaseServer(leaseSvc)
+	defer server.Close()
+
+	ctx := context.Background()
+	recipe := services.Recipe{Version: "1.2", Name: "remote", Parameters: services.RecipeParameters{SimPrevMax: 10, SimPreds: 5}}
+	create := func(limits *services.RecipeLimits) int64 {
+		t.Helper()
+		r := recipe
+		r.Limits = limits
+		sim, err := simSvc.CreateSimulation(ctx, services.CreateSimulationRequest{
+			Mode:         "simple",
+			RecipeName:   "test_preset",
+			Recipe:       r,
+			StartContest: 1001,
+			EndContest:   1003,
+			Async:        true,
+		})
+		if err != nil {
+			t.Fatalf("CreateSimulation failed: %v", err)
+		}
+		return sim.ID
+	}
+	completed := create(nil)
+	// Runs out of its budget on the worker; the API fails it without a retry
+	limited := create(&services.RecipeLimits{MaxCandidateEvaluations: 1})
+
+	client := worker.NewRemoteClient(server.URL, "remote-a", logger)
+	jobWorker := worker.NewRemoteJobWorker(client, 10*time.Millisecond, 2, logger)
+	jobWorker.SetVersion("v-remote")
+
+	workerCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jobWorker.Start(workerCtx)
+	}()
+
+	finished := func(id int64) simulations.Simulation {
+		t.Helper()
+		deadline := time.After(10 * time.Second)
+		for {
+			sim, err := simulationsQueries.GetSimulation(ctx, id)
+			if err != nil {
+				t.Fatalf("GetSimulation failed: %v", err)
+			}
+			if sim.Status == "completed" || sim.Status == "failed" {
+				return sim
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("simulation %d still %s", id, sim.Status)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+
+	sim := finished(completed)
+	if sim.Status != "completed" || sim.WorkerID.String != "remote-a" || !sim.SummaryJson.Valid || len(sim.LogBlob) == 0 {
+		t.Errorf("unexpected completed simulation: status %s, worker %q, summary %v, log %d bytes", sim.Status, sim.WorkerID.String, sim.SummaryJson.Valid, len(sim.LogBlob))
+	}
+	contests, err := simSvc.GetContestResults(ctx, completed, 10, 0)
+	if err != nil || len(contests) != 3 {
+		t.Errorf("expected 3 contest results, got %d: %v", len(contests), err)
+	}
+
+	sim = finished(limited)
+	if sim.Status != "failed" || !strings.Contains(sim.ErrorMessage.String, "limit exceeded") {
+		t.Errorf("expected simulation to fail on its limit, got %s: %q", sim.Status, sim.ErrorMessage.String)
+	}
+	attempts, err := simSvc.ListSimulationAttempts(ctx, limited)
+	if err != nil || len(attempts) != 1 || attempts[0].Outcome != "failed" || attempts[0].Transient != 0 {
+		t.Errorf("expected one non-transient failed attempt, got %+v: %v", attempts, err)
+	}
+
+	stop()
+	<-done
+
+	workers, err := services.NewWorkerService(simulationsQueries, logger).ListWorkers(ctx)
+	if err != nil || len(workers) != 1 {
+		t.Fatalf("expected the remote worker in the registry, got %+v: %v", workers, err)
+	}
+	if w := workers[0]; w.ID != "remote-a" || w.Version != "v-remote" || w.Status != services.WorkerStatusStopped {
+		t.Errorf("unexpected registry entry %+v", w)
+	}
+}
+
+func TestRemoteWorkerPanicRecordsFailedAttempt(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	simSvc.SetRetryPolicy(services.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour})
+	leaseSvc := services.NewLeaseService(simSvc, simulations.New(db.SimulationsDB), results.New(db.ResultsDB), logger)
+
+	ctx := context.Background()
+	sim, err := simSvc.CreateSimulation(ctx, services.CreateSimulationRequest{
+		Mode:         "simple",
+		RecipeName:   "test_preset",
+		Recipe:       services.Recipe{Version: "1.2", Name: "remote", Parameters: services.RecipeParameters{SimPrevMax: 10, SimPreds: 5}},
+		StartContest: 1001,
+		EndContest:   1003,
+		Async:        true,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+
+	lease, err := leaseSvc.ClaimSimulation(ctx, "remote-a", time.Minute)
+	if err != nil || lease.SimulationID != sim.ID {
+		t.Fatalf("expected to claim simulation %d, got %+v: %v", sim.ID, lease, err)
+	}
+
+	// Panics are failed at once even though the retry policy allows more
+	retried, err := leaseSvc.FailSimulation(ctx, "remote-a", sim.ID, services.LeaseReport{
+		Error:      "simulation panicked: boom",
+		ErrorStack: "goroutine 1 [running]:",
+	})
+	if err != nil || retried {
+		t.Fatalf("expected the panic to fail without a retry, got %v: %v", retried, err)
+	}
+
+	attempts, err := simSvc.ListSimulationAttempts(ctx, sim.ID)
+	if err != nil || len(attempts) != 1 {
+		t.Fatalf("expected one attempt, got %+v: %v", attempts, err)
+	}
+	if a := attempts[0]; a.Outcome != "failed" || a.WorkerID.String != "remote-a" || !strings.Contains(a.ErrorMessage.String, "boom") {
+		t.Errorf("expected a failed attempt with the panic, got %+v", a)
+	}
+}
+
+func TestReapUsesAPIRetryPolicy(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	q := simulations.New(db.SimulationsDB)
+	simSvc.SetRetryPolicy(services.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour})
+	server := newLeaseServer(services.NewLeaseService(simSvc, q, results.New(db.ResultsDB), logger))
+	defer server.Close()
+
+	ctx := context.Background()
+	sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+		RecipeJson:   `{"version":"1.2","name":"test","parameters":{}}`,
+		Mode:         "simple",
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+	past := sql.NullString{String: time.Now().UTC().Add(-time.Minute).Format(time.RFC3339), Valid: true}
+	if _, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt: past, WorkerID: sql.NullString{String: "crashed", Valid: true}, LeaseExpiresAt: past,
+	}); err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+
+	// A remote worker asking for a single attempt cannot fail the first run
+	resp, err := http.Post(server.URL+"/api/v1/workers/remote-a/reap", "application/json", strings.NewReader(`{"max_attempts":1}`))
+	if err != nil {
+		t.Fatalf("reap request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result services.ReapResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode reap result: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(result.Failed) != 0 || len(result.Requeued) != 1 || result.Requeued[0] != sim.ID {
+		t.Errorf("expected simulation %d requeued, got status %d and %+v", sim.ID, resp.StatusCode, result)
+	}
+}
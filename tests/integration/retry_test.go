@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+// flakyEngine fails its first runs with a transient error.
+type flakyEngine struct {
+	failures int
+	calls    int
+}
+
+func (e *flakyEngine) RunSimulation(ctx context.Context, cfg services.SimulationConfig) (*services.SimulationResult, error) {
+	e.calls++
+	if e.calls <= e.failures {
+		return nil, fmt.Errorf("load draws: %w", services.ErrTransient)
+	}
+	return &services.SimulationResult{Summary: services.Summary{TotalContests: 1}, DurationMs: 1}, nil
+}
+
+func TestSimulationRetryAfterTransientFailure(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	engine := &flakyEngine{failures: 1}
+	simSvc := services.NewSimulationService(q, db.SimulationsDB, engine, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	simSvc.SetRetryPolicy(services.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour})
+
+	ctx := context.Background()
+	sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{"sim_prev_max":10,"sim_preds":5}}`,
+		Mode:         "simple",
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	claimAt := func(at time.Time) (simulations.Simulation, error) {
+		ts := sql.NullString{String: at.Format(time.RFC3339), Valid: true}
+		return q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+			StartedAt:      ts,
+			WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+			LeaseExpiresAt: sql.NullString{String: at.Add(time.Minute).Format(time.RFC3339), Valid: true},
+		})
+	}
+
+	if _, err := claimAt(now); err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+	if err := simSvc.ExecuteSimulation(ctx, sim.ID); !errors.Is(err, services.ErrRetryScheduled) {
+		t.Fatalf("expected a scheduled retry, got %v", err)
+	}
+
+	got, err := q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "pending" || got.WorkerID.Valid || !got.NextAttemptAt.Valid || !got.ErrorMessage.Valid {
+		t.Fatalf("unexpected simulation waiting for retry: %+v", got)
+	}
+
+	// The backoff keeps the simulation out of the queue
+	if _, err := claimAt(now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no claimable simulation during backoff, got %v", err)
+	}
+
+	claimed, err := claimAt(now.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+	if claimed.ID != sim.ID || claimed.Attempts != 2 || claimed.NextAttemptAt.Valid {
+		t.Fatalf("unexpected retried simulation: %+v", claimed)
+	}
+	if err := simSvc.ExecuteSimulation(ctx, sim.ID); err != nil {
+		t.Fatalf("ExecuteSimulation failed: %v", err)
+	}
+
+	attempts, err := simSvc.ListSimulationAttempts(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("ListSimulationAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %+v", attempts)
+	}
+	if a := attempts[0]; a.Attempt != 1 || a.Outcome != "retrying" || a.Transient != 1 || !a.RetryAt.Valid || a.WorkerID.String != "worker-a" {
+		t.Errorf("unexpected first attempt: %+v", a)
+	}
+	if a := attempts[1]; a.Attempt != 2 || a.Outcome != "completed" || a.ErrorMessage.Valid {
+		t.Errorf("unexpected second attempt: %+v", a)
+	}
+
+	if _, err := simSvc.ListSimulationAttempts(ctx, sim.ID+1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing simulation, got %v", err)
+	}
+}
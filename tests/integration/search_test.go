@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+)
+
+func TestSimulationSearch(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	ctx := context.Background()
+
+	_, err := db.SimulationsDB.Exec(`
+INSERT INTO simulations (id, created_at, status, recipe_name, recipe_json, mode, start_contest, end_contest, summary_json, created_by) VALUES
+(1, '2025-01-10 09:00:00', 'completed', 'freq_a', '{}', 'simple', 100, 200, '{"HitRateQuina":0.02,"AverageHits":1.5}', 'alice'),
+(2, '2025-01-20 09:00:00', 'completed', 'freq_b', '{}', 'advanced', 150, 250, '{"HitRateQuina":0,"AverageHits":2.5}', 'bob'),
+(3, '2025-02-01 09:00:00', 'completed', 'hot', '{}', 'simple', 300, 400, '{"HitRateQuina":0.05,"AverageHits":0.5}', 'alice'),
+(4, '2025-02-02 09:00:00', 'pending', 'freq_c', '{}', 'simple', 100, 120, NULL, 'alice');
+`)
+	if err != nil {
+		t.Fatalf("Failed to insert simulations: %v", err)
+	}
+	if _, err := simSvc.SetSimulationTags(ctx, 1, []string{"baseline", "v2"}); err != nil {
+		t.Fatalf("SetSimulationTags failed: %v", err)
+	}
+	if _, err := simSvc.SetSimulationTags(ctx, 3, []string{"baseline"}); err != nil {
+		t.Fatalf("SetSimulationTags failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		search services.SimulationSearch
+		want   []int64
+	}{
+		{"newest first", services.SimulationSearch{}, []int64{4, 3, 2, 1}},
+		{"status", services.SimulationSearch{Status: "pending"}, []int64{4}},
+		{"mode", services.SimulationSearch{Mode: "advanced"}, []int64{2}},
+		{"recipe prefix", services.SimulationSearch{RecipeName: "freq"}, []int64{4, 2, 1}},
+		{"created by", services.SimulationSearch{CreatedBy: "bob"}, []int64{2}},
+		{"contest overlap", services.SimulationSearch{ContestFrom: 210, ContestTo: 320}, []int64{3, 2}},
+		{"created range", services.SimulationSearch{CreatedFrom: "2025-01-10", CreatedTo: "2025-02-01"}, []int64{3, 2, 1}},
+		{"metric threshold", services.SimulationSearch{Metric: "quina_rate>0"}, []int64{3, 1}},
+		{"all tags", services.SimulationSearch{Tags: []string{"baseline", "v2"}}, []int64{1}},
+		{"one tag", services.SimulationSearch{Tags: []string{"baseline"}}, []int64{3, 1}},
+		{"sort desc", services.SimulationSearch{SortBy: "avg_hits"}, []int64{2, 1, 3, 4}},
+		{"sort asc keeps missing last", services.SimulationSearch{SortBy: "avg_hits", SortOrder: "asc"}, []int64{3, 1, 2, 4}},
+		{"page", services.SimulationSearch{SortBy: "avg_hits", Limit: 2, Offset: 1}, []int64{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := simSvc.SearchSimulations(ctx, tt.search)
+			if err != nil {
+				t.Fatalf("SearchSimulations failed: %v", err)
+			}
+
+			var got []int64
+			for _, sim := range result.Simulations {
+				got = append(got, sim.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	result, err := simSvc.SearchSimulations(ctx, services.SimulationSearch{RecipeName: "freq", Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchSimulations failed: %v", err)
+	}
+	if result.Total != 3 || len(result.Simulations) != 1 {
+		t.Errorf("expected 1 of 3 simulations, got %d of %d", len(result.Simulations), result.Total)
+	}
+
+	tags, err := simSvc.GetSimulationTags(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetSimulationTags failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "baseline" || tags[1] != "v2" {
+		t.Errorf("expected [baseline v2], got %v", tags)
+	}
+}
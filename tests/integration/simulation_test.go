@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -109,6 +111,10 @@ func setupSchemas(t *testing.T, db *store.DB) error {
 		}
 	}
 
+	if err := applySimulationsMigrations(db.SimulationsDB); err != nil {
+		return err
+	}
+
 	// Insert sample data
 	if err := insertSampleData(t, db); err != nil {
 		return fmt.Errorf("insert sample data: %w", err)
@@ -117,6 +123,30 @@ func setupSchemas(t *testing.T, db *store.DB) error {
 	return nil
 }
 
+// simulationsMigrations change the simulations schema created by 002.
+var simulationsMigrations = []string{
+	"011_add_simulation_search.sql",
+	"012_add_simulation_leases.sql",
+	"013_add_simulation_priority.sql",
+	"014_add_simulation_retries.sql",
+	"015_create_workers.sql",
+	"017_extend_analysis_jobs.sql",
+	"018_unique_contest_results.sql",
+}
+
+func applySimulationsMigrations(sqlDB *sql.DB) error {
+	for _, migrationFile := range simulationsMigrations {
+		content, err := migrations.Files.ReadFile(migrationFile)
+		if err != nil {
+			return fmt.Errorf("read migration file %s: %w", migrationFile, err)
+		}
+		if _, err := sqlDB.Exec(string(content)); err != nil {
+			return fmt.Errorf("execute migration %s: %w", migrationFile, err)
+		}
+	}
+	return nil
+}
+
 // insertSampleData inserts test data into the databases
 func insertSampleData(t *testing.T, db *store.DB) error {
 	t.Helper()
@@ -233,6 +263,53 @@ func TestFullSimulationFlow(t *testing.T) {
 			t.Errorf("Result %d: missing predictions JSON", i)
 		}
 	}
+
+	// Verify filtered browsing scores every prediction against the draw
+	page, err := simSvc.ListContestResults(ctx, sim.ID, services.ContestResultFilter{
+		ContestFrom:        1002,
+		IncludePredictions: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list contest results: %v", err)
+	}
+	if page.Total != 2 || len(page.Results) != 2 || page.Results[0].Contest != 1002 {
+		t.Errorf("Expected contests 1002 and 1003, got %+v", page)
+	}
+	for _, result := range page.Results {
+		best := 0
+		for _, p := range result.Predictions {
+			if p.Hits != len(p.Matched) {
+				t.Errorf("Contest %d: prediction %d has %d hits but %v matched", result.Contest, p.Index, p.Hits, p.Matched)
+			}
+			best = max(best, p.Hits)
+		}
+		if best != result.BestHits {
+			t.Errorf("Contest %d: expected best hits %d, got %d", result.Contest, result.BestHits, best)
+		}
+	}
+
+	var export strings.Builder
+	if err := simSvc.ExportContestResults(ctx, sim.ID, services.ContestResultFilter{}, &export); err != nil {
+		t.Fatalf("Failed to export contest results: %v", err)
+	}
+	if lines := strings.Count(export.String(), "\n"); lines != expectedContests+1 {
+		t.Errorf("Expected header and %d rows in export, got %q", expectedContests, export.String())
+	}
+
+	// Verify the run log was captured
+	logs, err := simSvc.GetSimulationLog(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("Failed to get simulation log: %v", err)
+	}
+	defer logs.Close()
+
+	logData, err := io.ReadAll(logs)
+	if err != nil {
+		t.Fatalf("Failed to read simulation log: %v", err)
+	}
+	if !strings.Contains(string(logData), `"msg":"simulation completed"`) {
+		t.Errorf("Expected completion record in simulation log, got %q", logData)
+	}
 }
 
 func TestWorkerJobProcessing(t *testing.T) {
@@ -3,6 +3,8 @@ package integration
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -114,6 +116,10 @@ func setupSweepSchemas(t *testing.T, db *store.DB) error {
 		}
 	}
 
+	if err := applySimulationsMigrations(db.SimulationsDB); err != nil {
+		return err
+	}
+
 	// Create sweep tables manually (since they might not be in migrations yet)
 	sweepTables := `
 CREATE TABLE sweep_jobs (
@@ -130,7 +136,9 @@ CREATE TABLE sweep_jobs (
     started_at TEXT,
     finished_at TEXT,
     run_duration_ms INTEGER,
-    created_by TEXT
+    created_by TEXT,
+    best_metric TEXT,
+    best_json TEXT
 );
 
 CREATE TABLE sweep_simulations (
@@ -232,6 +240,24 @@ func setupSweepServices(t *testing.T, db *store.DB) (*services.SimulationService
 	return simulationService, sweepService, comparisonService
 }
 
+// executeSweepSimulation runs a queued simulation like a worker that claimed
+// it.
+func executeSweepSimulation(t *testing.T, db *store.DB, simSvc *services.SimulationService, id int64) error {
+	t.Helper()
+
+	ctx := context.Background()
+	err := simulations.New(db.SimulationsDB).UpdateSimulationStatus(ctx, simulations.UpdateSimulationStatusParams{
+		Status:    "running",
+		StartedAt: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		WorkerID:  sql.NullString{String: "worker-a", Valid: true},
+		ID:        id,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start simulation %d: %v", id, err)
+	}
+	return simSvc.ExecuteSimulation(ctx, id)
+}
+
 func TestFullSweepFlow(t *testing.T) {
 	db := setupSweepTestDB(t)
 
@@ -327,7 +353,7 @@ func TestFullSweepFlow(t *testing.T) {
 
 		// If not completed, run it synchronously
 		if sim.Status != "completed" {
-			err := simSvc.ExecuteSimulation(ctx, sweepSim.SimulationID)
+			err := executeSweepSimulation(t, db, simSvc, sweepSim.SimulationID)
 			if err != nil {
 				t.Fatalf("Failed to execute simulation %d synchronously: %v", sweepSim.SimulationID, err)
 			}
@@ -395,6 +421,323 @@ func TestFullSweepFlow(t *testing.T) {
 	}
 }
 
+func TestSweepLifecycleFromSimulationEvents(t *testing.T) {
+	db := setupSweepTestDB(t)
+
+	simSvc, sweepSvc, _ := setupSweepServices(t, db)
+	if err := sweepSvc.SetBestMetric("quina_rate"); err != nil {
+		t.Fatalf("Failed to set best metric: %v", err)
+	}
+	simSvc.AddSimulationObserver(sweepSvc)
+
+	ctx := context.Background()
+
+	sweepJob, err := sweepSvc.CreateSweep(ctx, services.CreateSweepRequest{
+		Name: "lifecycle_sweep",
+		SweepConfig: sweep.SweepConfig{
+			Name: "lifecycle_sweep",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.0",
+				Name:       "base",
+				Parameters: map[string]any{"sim_prev_max": 10, "sim_preds": 5, "gamma": 0.5, "delta": 0.5},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "alpha", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{0.1, 0.2}}},
+			},
+		},
+		StartContest: 1001,
+		EndContest:   1002,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sweep: %v", err)
+	}
+
+	status, err := sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if len(status.Simulations) != 2 {
+		t.Fatalf("Expected 2 simulations, got %d", len(status.Simulations))
+	}
+
+	// The first simulation moves the sweep to running
+	if err := executeSweepSimulation(t, db, simSvc, status.Simulations[0].SimulationID); err != nil {
+		t.Fatalf("Failed to execute simulation: %v", err)
+	}
+	status, err = sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if status.Sweep.Status != "running" || !status.Sweep.StartedAt.Valid {
+		t.Errorf("Expected running sweep with start time, got %s (started %v)", status.Sweep.Status, status.Sweep.StartedAt)
+	}
+	if status.Sweep.CompletedSimulations.Int64 != 1 {
+		t.Errorf("Expected 1 completed simulation, got %d", status.Sweep.CompletedSimulations.Int64)
+	}
+
+	// Cancelling the last one finishes the sweep
+	if err := simSvc.CancelSimulation(ctx, status.Simulations[1].SimulationID); err != nil {
+		t.Fatalf("Failed to cancel simulation: %v", err)
+	}
+	status, err = sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if status.Sweep.Status != "completed" || !status.Sweep.FinishedAt.Valid {
+		t.Errorf("Expected finished completed sweep, got %s (finished %v)", status.Sweep.Status, status.Sweep.FinishedAt)
+	}
+	if status.Completed != 1 || status.Cancelled != 1 {
+		t.Errorf("Expected 1 completed and 1 cancelled simulation, got %d and %d", status.Completed, status.Cancelled)
+	}
+	if status.Sweep.BestMetric.String != "quina_rate" || !status.Sweep.BestJson.Valid {
+		t.Errorf("Expected cached best for quina_rate, got %v", status.Sweep.BestMetric)
+	}
+
+	best, err := sweepSvc.FindBest(ctx, sweepJob.ID, "quina_rate")
+	if err != nil {
+		t.Fatalf("Failed to find best configuration: %v", err)
+	}
+	if best.SimulationID != status.Simulations[0].SimulationID {
+		t.Errorf("Expected best simulation %d, got %d", status.Simulations[0].SimulationID, best.SimulationID)
+	}
+}
+
+func TestSampledSweepRecordsSeed(t *testing.T) {
+	db := setupSweepTestDB(t)
+
+	_, sweepSvc, _ := setupSweepServices(t, db)
+	ctx := context.Background()
+
+	sweepJob, err := sweepSvc.CreateSweep(ctx, services.CreateSweepRequest{
+		Name: "sampled_sweep",
+		SweepConfig: sweep.SweepConfig{
+			Name: "sampled_sweep",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.0",
+				Name:       "base",
+				Parameters: map[string]any{"sim_prev_max": 10, "sim_preds": 5},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "alpha", Type: "range", Values: sweep.RangeValues{Min: 0, Max: 1, Step: 0.01}},
+				{Name: "beta", Type: "range", Values: sweep.RangeValues{Min: 0, Max: 1, Step: 0.01}},
+			},
+			Sampling: &sweep.Sampling{Strategy: sweep.SamplingLatinHypercube, Samples: 4},
+		},
+		StartContest: 1001,
+		EndContest:   1002,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sweep: %v", err)
+	}
+	if sweepJob.TotalCombinations != 4 {
+		t.Errorf("Expected 4 sampled simulations, got %d", sweepJob.TotalCombinations)
+	}
+
+	// The stored config reproduces the sample
+	var stored sweep.SweepConfig
+	if err := json.Unmarshal([]byte(sweepJob.SweepConfigJson), &stored); err != nil {
+		t.Fatalf("Failed to unmarshal stored config: %v", err)
+	}
+	if stored.Sampling == nil || stored.Sampling.Seed == 0 {
+		t.Fatalf("Expected the stored config to record a seed, got %+v", stored.Sampling)
+	}
+	first, err := sweep.NewGenerator().Generate(stored)
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	status, err := sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	for i, sim := range status.Simulations {
+		var params map[string]any
+		if err := json.Unmarshal([]byte(sim.VariationParams), &params); err != nil {
+			t.Fatalf("Failed to unmarshal variation params: %v", err)
+		}
+		if params["alpha"] != first[i].Parameters["alpha"] {
+			t.Errorf("Simulation %d: expected alpha %v, got %v", i, first[i].Parameters["alpha"], params["alpha"])
+		}
+	}
+}
+
+func TestCancelSweep(t *testing.T) {
+	db := setupSweepTestDB(t)
+
+	simSvc, sweepSvc, _ := setupSweepServices(t, db)
+	simSvc.AddSimulationObserver(sweepSvc)
+	simulationsQueries := simulations.New(db.SimulationsDB)
+
+	ctx := context.Background()
+
+	sweepJob, err := sweepSvc.CreateSweep(ctx, services.CreateSweepRequest{
+		Name: "cancel_sweep",
+		SweepConfig: sweep.SweepConfig{
+			Name: "cancel_sweep",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.0",
+				Name:       "base",
+				Parameters: map[string]any{"sim_prev_max": 10, "sim_preds": 5, "gamma": 0.5, "delta": 0.5},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "alpha", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{0.1, 0.2, 0.3}}},
+			},
+		},
+		StartContest: 1001,
+		EndContest:   1002,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sweep: %v", err)
+	}
+
+	status, err := sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if len(status.Simulations) != 3 {
+		t.Fatalf("Expected 3 simulations, got %d", len(status.Simulations))
+	}
+	completedID := status.Simulations[0].SimulationID
+
+	// One simulation completes and a worker claims another
+	if err := executeSweepSimulation(t, db, simSvc, completedID); err != nil {
+		t.Fatalf("Failed to execute simulation: %v", err)
+	}
+	now := time.Now().UTC()
+	running, err := simulationsQueries.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to claim simulation: %v", err)
+	}
+
+	cancellation, err := sweepSvc.CancelSweep(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to cancel sweep: %v", err)
+	}
+	if cancellation.Cancelled != 1 || cancellation.Stopped != 1 || cancellation.AlreadyCancelled {
+		t.Errorf("Expected 1 cancelled and 1 stopped simulation, got %+v", cancellation)
+	}
+
+	status, err = sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if status.Sweep.Status != "cancelled" || !status.Sweep.FinishedAt.Valid {
+		t.Errorf("Expected finished cancelled sweep, got %s (finished %v)", status.Sweep.Status, status.Sweep.FinishedAt)
+	}
+	if status.Completed != 1 || status.Cancelled != 2 {
+		t.Errorf("Expected 1 completed and 2 cancelled simulations, got %d and %d", status.Completed, status.Cancelled)
+	}
+
+	// The worker of the running simulation loses its lease
+	n, err := simulationsQueries.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+		ID:             running.ID,
+		WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+	})
+	if err != nil || n != 0 {
+		t.Errorf("Expected heartbeat of cancelled simulation to affect no rows, got %d: %v", n, err)
+	}
+
+	// The run in flight finishes after the cancellation and is discarded
+	if err := simSvc.ExecuteSimulation(ctx, running.ID); !errors.Is(err, services.ErrLeaseLost) {
+		t.Fatalf("Expected ErrLeaseLost for the cancelled run, got %v", err)
+	}
+	child, err := simulationsQueries.GetSimulation(ctx, running.ID)
+	if err != nil {
+		t.Fatalf("Failed to get simulation: %v", err)
+	}
+	if child.Status != "cancelled" || child.SummaryJson.Valid {
+		t.Errorf("Expected cancelled simulation without results, got %s (summary %v)", child.Status, child.SummaryJson.Valid)
+	}
+	status, err = sweepSvc.GetSweepStatus(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if status.Completed != 1 || status.Cancelled != 2 || status.Sweep.CompletedSimulations.Int64 != 1 {
+		t.Errorf("Expected the cancelled run to be counted once, got %d completed (%d recorded) and %d cancelled",
+			status.Completed, status.Sweep.CompletedSimulations.Int64, status.Cancelled)
+	}
+
+	// Cancelling again changes nothing
+	cancellation, err = sweepSvc.CancelSweep(ctx, sweepJob.ID)
+	if err != nil {
+		t.Fatalf("Failed to cancel sweep again: %v", err)
+	}
+	if !cancellation.AlreadyCancelled || cancellation.Cancelled != 0 || cancellation.Stopped != 0 {
+		t.Errorf("Expected no simulations affected, got %+v", cancellation)
+	}
+
+	if _, err := sweepSvc.CancelSweep(ctx, sweepJob.ID+100); !errors.Is(err, services.ErrSweepNotFound) {
+		t.Errorf("Expected ErrSweepNotFound, got %v", err)
+	}
+}
+
+func TestInterruptedSweepCreationCancelsChildren(t *testing.T) {
+	db := setupSweepTestDB(t)
+
+	simSvc, sweepSvc, _ := setupSweepServices(t, db)
+	simSvc.AddSimulationObserver(sweepSvc)
+
+	payload, err := json.Marshal(services.CreateSweepRequest{
+		Name: "interrupted_sweep",
+		SweepConfig: sweep.SweepConfig{
+			Name: "interrupted_sweep",
+			BaseRecipe: sweep.Recipe{
+				Version:    "1.0",
+				Name:       "base",
+				Parameters: map[string]any{"sim_prev_max": 10, "sim_preds": 5, "gamma": 0.5, "delta": 0.5},
+			},
+			Parameters: []sweep.ParameterSweep{
+				{Name: "alpha", Type: "discrete", Values: sweep.DiscreteValues{Values: []float64{0.1, 0.2, 0.3, 0.4}}},
+			},
+		},
+		StartContest: 1001,
+		EndContest:   1002,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+
+	// The job is cancelled after its second child is queued
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	progress := func(done, total int64) {
+		if done == 2 {
+			cancel()
+		}
+	}
+	if _, err := sweepSvc.SweepJobType().Run(ctx, payload, progress); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the interrupted job to fail with context.Canceled, got %v", err)
+	}
+
+	bg := context.Background()
+	sweeps, err := sweep_execution.New(db.SimulationsDB).ListSweepJobs(bg, sweep_execution.ListSweepJobsParams{Limit: 10})
+	if err != nil || len(sweeps) != 1 {
+		t.Fatalf("Expected one sweep, got %d: %v", len(sweeps), err)
+	}
+	status, err := sweepSvc.GetSweepStatus(bg, sweeps[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get sweep status: %v", err)
+	}
+	if status.Sweep.Status != "cancelled" || len(status.Simulations) != 2 || status.Cancelled != 2 {
+		t.Errorf("Expected a cancelled sweep with 2 cancelled children, got %s with %d children (%d cancelled)",
+			status.Sweep.Status, len(status.Simulations), status.Cancelled)
+	}
+
+	// No child is left in the queue, linked or not
+	var pending int
+	if err := db.SimulationsDB.QueryRow(`SELECT COUNT(*) FROM simulations WHERE status = 'pending'`).Scan(&pending); err != nil {
+		t.Fatalf("Failed to count pending simulations: %v", err)
+	}
+	if pending != 0 {
+		t.Errorf("Expected no pending simulations, got %d", pending)
+	}
+}
+
 func TestComparisonFlow(t *testing.T) {
 	db := setupSweepTestDB(t)
 
@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/models"
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store"
+	"github.com/garnizeh/luckyfive/internal/store/finances"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/migrations"
+)
+
+// setupTicketServices creates the tickets schema and a ticket service that
+// checks the draws of the returned import service.
+func setupTicketServices(t *testing.T, db *store.DB) (*services.TicketService, *services.ImportService) {
+	t.Helper()
+
+	content, err := migrations.Files.ReadFile("010_create_tickets.sql")
+	if err != nil {
+		t.Fatalf("Failed to read tickets migration: %v", err)
+	}
+	if _, err := db.FinancesDB.Exec(string(content)); err != nil {
+		t.Fatalf("Failed to apply tickets migration: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	ticketSvc := services.NewTicketService(finances.New(db.FinancesDB), db.FinancesDB, results.New(db.ResultsDB), logger)
+	importSvc := services.NewImportService(db, logger)
+	importSvc.AddDrawObserver(ticketSvc)
+	return ticketSvc, importSvc
+}
+
+func TestTicketFlow_CheckedOnCreateAndImport(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	ticketSvc, importSvc := setupTicketServices(t, db)
+	ctx := context.Background()
+
+	// Contest 1004 (21-25) is already drawn, 1005 and 1006 are not
+	ticket, err := ticketSvc.CreateTicket(ctx, services.CreateTicketRequest{
+		Owner:        "alice",
+		Numbers:      []int{30, 21, 22, 23, 24, 25},
+		FirstContest: 1004,
+		LastContest:  1006,
+	})
+	if err != nil {
+		t.Fatalf("CreateTicket failed: %v", err)
+	}
+
+	// Six numbers play six combinations at 250 cents over three contests
+	if ticket.CostCents != 4500 {
+		t.Errorf("expected cost 4500, got %d", ticket.CostCents)
+	}
+	// One quina plus five quadras with the default prizes
+	if ticket.TotalPrizeCents != 52500000 {
+		t.Errorf("expected prize 52500000 after first contest, got %d", ticket.TotalPrizeCents)
+	}
+	if ticket.Status != "pending" {
+		t.Errorf("expected pending ticket, got %s", ticket.Status)
+	}
+
+	err = importSvc.ImportDraws(ctx, []models.Draw{
+		{Contest: 1005, DrawDate: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Bola1: 30, Bola2: 31, Bola3: 32, Bola4: 33, Bola5: 34},
+		{Contest: 1006, DrawDate: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), Bola1: 21, Bola2: 22, Bola3: 23, Bola4: 40, Bola5: 50},
+	})
+	if err != nil {
+		t.Fatalf("ImportDraws failed: %v", err)
+	}
+
+	detail, err := ticketSvc.GetTicket(ctx, ticket.ID)
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+
+	if detail.Ticket.Status != "settled" {
+		t.Errorf("expected settled ticket, got %s", detail.Ticket.Status)
+	}
+	// Three hits out of six numbers win three ternos
+	if detail.Ticket.TotalPrizeCents != 52530000 {
+		t.Errorf("expected total prize 52530000, got %d", detail.Ticket.TotalPrizeCents)
+	}
+	if len(detail.Checks) != 3 {
+		t.Fatalf("expected 3 checks, got %d", len(detail.Checks))
+	}
+	if detail.Checks[1].Hits != 1 || detail.Checks[1].PrizeType.Valid {
+		t.Errorf("unexpected check for contest 1005: %+v", detail.Checks[1])
+	}
+	if detail.Checks[2].PrizeType.String != "terno" || detail.Checks[2].MatchedNumbers != "[21,22,23]" {
+		t.Errorf("unexpected check for contest 1006: %+v", detail.Checks[2])
+	}
+
+	var bets, prizes, balance int64
+	rows, err := db.FinancesDB.Query(`SELECT transaction_type, amount_cents FROM ledger_entries`)
+	if err != nil {
+		t.Fatalf("Failed to query ledger: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var typ string
+		var amount int64
+		if err := rows.Scan(&typ, &amount); err != nil {
+			t.Fatalf("Failed to scan ledger entry: %v", err)
+		}
+		switch typ {
+		case "bet":
+			bets++
+		case "prize":
+			prizes++
+		}
+		balance += amount
+	}
+
+	if bets != 3 || prizes != 2 {
+		t.Errorf("expected 3 bet and 2 prize entries, got %d and %d", bets, prizes)
+	}
+	if balance != 52530000-4500 {
+		t.Errorf("expected ledger balance %d, got %d", 52530000-4500, balance)
+	}
+
+	// Importing the same contest again must not check the ticket twice
+	if n, err := ticketSvc.CheckContest(ctx, 1006); err != nil || n != 0 {
+		t.Errorf("expected no pending tickets, got %d (%v)", n, err)
+	}
+}
+
+func TestTicketFlow_SettledOnceEveryContestIsChecked(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	ticketSvc, importSvc := setupTicketServices(t, db)
+	ctx := context.Background()
+
+	ticket, err := ticketSvc.CreateTicket(ctx, services.CreateTicketRequest{
+		Owner:        "bob",
+		Numbers:      []int{21, 22, 23, 24, 25},
+		FirstContest: 1005,
+		LastContest:  1007,
+	})
+	if err != nil {
+		t.Fatalf("CreateTicket failed: %v", err)
+	}
+
+	status := func() string {
+		t.Helper()
+		detail, err := ticketSvc.GetTicket(ctx, ticket.ID)
+		if err != nil {
+			t.Fatalf("GetTicket failed: %v", err)
+		}
+		return detail.Ticket.Status
+	}
+
+	// The last contest is imported before the earlier ones
+	err = importSvc.ImportDraws(ctx, []models.Draw{
+		{Contest: 1007, DrawDate: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 4, Bola5: 5},
+	})
+	if err != nil {
+		t.Fatalf("ImportDraws failed: %v", err)
+	}
+	if got := status(); got != "pending" {
+		t.Errorf("expected pending ticket with unchecked contests, got %s", got)
+	}
+
+	err = importSvc.ImportDraws(ctx, []models.Draw{
+		{Contest: 1005, DrawDate: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 4, Bola5: 5},
+		{Contest: 1006, DrawDate: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), Bola1: 1, Bola2: 2, Bola3: 3, Bola4: 4, Bola5: 5},
+	})
+	if err != nil {
+		t.Fatalf("ImportDraws failed: %v", err)
+	}
+	if got := status(); got != "settled" {
+		t.Errorf("expected settled ticket, got %s", got)
+	}
+}
@@ -0,0 +1,290 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/results"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+func TestWorkerLeaseRecovery(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	ctx := context.Background()
+
+	sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{}}`,
+		Mode:         "simple",
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+	if sim.Attempts != 0 {
+		t.Errorf("expected 0 attempts, got %d", sim.Attempts)
+	}
+
+	now := time.Now().UTC()
+	ts := func(d time.Duration) sql.NullString {
+		return sql.NullString{String: now.Add(d).Format(time.RFC3339), Valid: true}
+	}
+	worker := sql.NullString{String: "worker-a", Valid: true}
+	maxAttempts := int64(2)
+
+	claim := func() simulations.Simulation {
+		t.Helper()
+		claimed, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+			StartedAt:      ts(-time.Hour),
+			WorkerID:       worker,
+			LeaseExpiresAt: ts(-time.Minute), // Already expired
+		})
+		if err != nil {
+			t.Fatalf("ClaimPendingSimulation failed: %v", err)
+		}
+		return claimed
+	}
+
+	claimed := claim()
+	if claimed.ID != sim.ID || claimed.Status != "running" || claimed.Attempts != 1 || claimed.HeartbeatAt != claimed.StartedAt {
+		t.Errorf("unexpected claimed simulation: %+v", claimed)
+	}
+
+	// Only the owning worker can extend the lease
+	n, err := q.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    ts(0),
+		LeaseExpiresAt: ts(time.Minute),
+		ID:             sim.ID,
+		WorkerID:       sql.NullString{String: "worker-b", Valid: true},
+	})
+	if err != nil || n != 0 {
+		t.Errorf("expected no rows for another worker, got %d, %v", n, err)
+	}
+
+	// Leases that have not expired are left alone
+	if _, err := q.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    ts(-2 * time.Minute),
+		LeaseExpiresAt: ts(time.Minute),
+		ID:             sim.ID,
+		WorkerID:       worker,
+	}); err != nil {
+		t.Fatalf("HeartbeatSimulation failed: %v", err)
+	}
+	requeued, err := q.RequeueExpiredSimulations(ctx, simulations.RequeueExpiredSimulationsParams{Now: ts(0), MaxAttempts: maxAttempts})
+	if err != nil || len(requeued) != 0 {
+		t.Errorf("expected nothing to requeue, got %v, %v", requeued, err)
+	}
+
+	// The worker stops sending heartbeats
+	if _, err := q.HeartbeatSimulation(ctx, simulations.HeartbeatSimulationParams{
+		HeartbeatAt:    ts(-2 * time.Minute),
+		LeaseExpiresAt: ts(-time.Minute),
+		ID:             sim.ID,
+		WorkerID:       worker,
+	}); err != nil {
+		t.Fatalf("HeartbeatSimulation failed: %v", err)
+	}
+
+	failed, err := q.FailExpiredSimulations(ctx, simulations.FailExpiredSimulationsParams{Now: ts(0), MaxAttempts: maxAttempts})
+	if err != nil || len(failed) != 0 {
+		t.Errorf("expected nothing to fail after one attempt, got %v, %v", failed, err)
+	}
+	requeued, err = q.RequeueExpiredSimulations(ctx, simulations.RequeueExpiredSimulationsParams{Now: ts(0), MaxAttempts: maxAttempts})
+	if err != nil || len(requeued) != 1 || requeued[0] != sim.ID {
+		t.Fatalf("expected simulation to be requeued, got %v, %v", requeued, err)
+	}
+
+	got, err := q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "pending" || got.WorkerID.Valid || got.LeaseExpiresAt.Valid || got.Attempts != 1 || !got.HeartbeatAt.Valid {
+		t.Errorf("unexpected requeued simulation: %+v", got)
+	}
+
+	// The second claim also expires and uses the last attempt
+	if claimed := claim(); claimed.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", claimed.Attempts)
+	}
+	failed, err = q.FailExpiredSimulations(ctx, simulations.FailExpiredSimulationsParams{Now: ts(0), MaxAttempts: maxAttempts})
+	if err != nil || len(failed) != 1 {
+		t.Fatalf("expected simulation to be failed, got %v, %v", failed, err)
+	}
+
+	got, err = q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "failed" || got.ErrorMessage.String != "worker lease expired after 2 attempts" || !got.FinishedAt.Valid {
+		t.Errorf("unexpected failed simulation: %+v", got)
+	}
+}
+
+func TestReleaseSimulationOnShutdown(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	ctx := context.Background()
+
+	sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{}}`,
+		Mode:         "simple",
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	worker := sql.NullString{String: "worker-a", Valid: true}
+	if _, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       worker,
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+	}); err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+
+	// Only the owning worker can release the simulation
+	n, err := q.ReleaseSimulation(ctx, simulations.ReleaseSimulationParams{
+		ID:       sim.ID,
+		WorkerID: sql.NullString{String: "worker-b", Valid: true},
+	})
+	if err != nil || n != 0 {
+		t.Errorf("expected no rows for another worker, got %d, %v", n, err)
+	}
+
+	n, err = q.ReleaseSimulation(ctx, simulations.ReleaseSimulationParams{ID: sim.ID, WorkerID: worker})
+	if err != nil || n != 1 {
+		t.Fatalf("expected simulation to be released, got %d, %v", n, err)
+	}
+
+	got, err := q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "pending" || got.StartedAt.Valid || got.WorkerID.Valid || got.LeaseExpiresAt.Valid || got.Attempts != 0 {
+		t.Errorf("unexpected released simulation: %+v", got)
+	}
+
+	// Released simulations are claimed again right away
+	claimed, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt:      sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		WorkerID:       sql.NullString{String: "worker-b", Valid: true},
+		LeaseExpiresAt: sql.NullString{String: now.Add(time.Minute).Format(time.RFC3339), Valid: true},
+	})
+	if err != nil || claimed.ID != sim.ID || claimed.Attempts != 1 {
+		t.Errorf("expected released simulation to be claimed with 1 attempt, got %+v, %v", claimed, err)
+	}
+}
+
+func TestStaleWorkerCannotFinishRecoveredSimulation(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	simSvc, _, _, svcCleanup := setupServices(t, db)
+	defer svcCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	leaseSvc := services.NewLeaseService(simSvc, q, results.New(db.ResultsDB), slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	sim, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+		RecipeJson:   `{"version":"1.1","name":"test","parameters":{}}`,
+		Mode:         "simple",
+		StartContest: 1001,
+		EndContest:   1003,
+	})
+	if err != nil {
+		t.Fatalf("CreateSimulation failed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	ts := func(d time.Duration) sql.NullString {
+		return sql.NullString{String: now.Add(d).Format(time.RFC3339), Valid: true}
+	}
+	workerA := sql.NullString{String: "worker-a", Valid: true}
+	workerB := sql.NullString{String: "worker-b", Valid: true}
+
+	// worker-a loses its lease and worker-b takes the simulation over
+	if _, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt: ts(-time.Hour), WorkerID: workerA, LeaseExpiresAt: ts(-time.Minute),
+	}); err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+	if _, err := q.RequeueExpiredSimulations(ctx, simulations.RequeueExpiredSimulationsParams{Now: ts(0), MaxAttempts: 3}); err != nil {
+		t.Fatalf("RequeueExpiredSimulations failed: %v", err)
+	}
+	if _, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+		StartedAt: ts(0), WorkerID: workerB, LeaseExpiresAt: ts(time.Minute),
+	}); err != nil {
+		t.Fatalf("ClaimPendingSimulation failed: %v", err)
+	}
+
+	// The late results of worker-a change nothing
+	n, err := q.CompleteSimulation(ctx, simulations.CompleteSimulationParams{ID: sim.ID, WorkerID: workerA, FinishedAt: ts(0)})
+	if err != nil || n != 0 {
+		t.Errorf("expected no rows completed for worker-a, got %d, %v", n, err)
+	}
+	n, err = q.FailSimulation(ctx, simulations.FailSimulationParams{ID: sim.ID, WorkerID: workerA, FinishedAt: ts(0)})
+	if err != nil || n != 0 {
+		t.Errorf("expected no rows failed for worker-a, got %d, %v", n, err)
+	}
+	// A transient failure of worker-a must not requeue worker-b's run
+	n, err = q.RetrySimulation(ctx, simulations.RetrySimulationParams{ID: sim.ID, WorkerID: workerA, NextAttemptAt: ts(time.Minute)})
+	if err != nil || n != 0 {
+		t.Errorf("expected no rows retried for worker-a, got %d, %v", n, err)
+	}
+	if got, err := q.GetSimulation(ctx, sim.ID); err != nil || got.Status != "running" || got.WorkerID != workerB || got.Attempts != 2 {
+		t.Errorf("expected simulation still running on worker-b at attempt 2, got %+v, %v", got, err)
+	}
+
+	result := &services.SimulationResult{
+		ContestResults: []services.ContestResult{{Contest: 1001, ActualNumbers: []int{1, 2, 3, 4, 5}}},
+		Summary:        services.Summary{TotalContests: 1},
+	}
+	if err := leaseSvc.CompleteSimulation(ctx, workerA.String, sim.ID, services.LeaseReport{Result: result}); !errors.Is(err, services.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost for worker-a, got %v", err)
+	}
+
+	// worker-b completes it once; a repeated report is rejected
+	if err := leaseSvc.CompleteSimulation(ctx, workerB.String, sim.ID, services.LeaseReport{Result: result}); err != nil {
+		t.Fatalf("CompleteSimulation failed: %v", err)
+	}
+	if err := leaseSvc.CompleteSimulation(ctx, workerB.String, sim.ID, services.LeaseReport{Result: result}); !errors.Is(err, services.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost for a repeated completion, got %v", err)
+	}
+
+	got, err := q.GetSimulation(ctx, sim.ID)
+	if err != nil {
+		t.Fatalf("GetSimulation failed: %v", err)
+	}
+	if got.Status != "completed" || got.WorkerID != workerB {
+		t.Errorf("expected simulation completed by worker-b, got %s by %v", got.Status, got.WorkerID)
+	}
+
+	// Each contest of a simulation is stored only once
+	err = q.InsertContestResult(ctx, simulations.InsertContestResultParams{
+		SimulationID:    sim.ID,
+		Contest:         1001,
+		ActualNumbers:   "[1,2,3,4,5]",
+		PredictionsJson: "[]",
+	})
+	if err == nil {
+		t.Error("expected duplicate contest result to be rejected")
+	}
+	count, err := q.CountContestResults(ctx, simulations.CountContestResultsParams{SimulationID: sim.ID})
+	if err != nil || count != 1 {
+		t.Errorf("expected 1 contest result, got %d, %v", count, err)
+	}
+}
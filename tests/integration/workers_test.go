@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/luckyfive/internal/services"
+	"github.com/garnizeh/luckyfive/internal/store/simulations"
+)
+
+func TestWorkerRegistryFleetStatus(t *testing.T) {
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	q := simulations.New(db.SimulationsDB)
+	svc := services.NewWorkerService(q, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	ts := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	register := func(id string, startedAt, heartbeatAt string) {
+		t.Helper()
+		if err := q.RegisterWorker(ctx, simulations.RegisterWorkerParams{
+			ID:          id,
+			Host:        "host-" + id,
+			Version:     "v1",
+			Concurrency: 2,
+			StartedAt:   startedAt,
+			HeartbeatAt: heartbeatAt,
+		}); err != nil {
+			t.Fatalf("RegisterWorker failed: %v", err)
+		}
+	}
+	register("worker-a", ts(-time.Hour), ts(-time.Hour))
+	register("worker-b", ts(-time.Hour), ts(-time.Hour))
+	register("worker-c", ts(-time.Hour), ts(-time.Hour))
+
+	// worker-a is alive and running a job, worker-b stopped, worker-c went silent
+	if n, err := q.HeartbeatWorker(ctx, simulations.HeartbeatWorkerParams{HeartbeatAt: ts(0), ActiveJobs: 1, ID: "worker-a"}); err != nil || n != 1 {
+		t.Fatalf("HeartbeatWorker failed: %d, %v", n, err)
+	}
+	if err := q.StopWorker(ctx, simulations.StopWorkerParams{StoppedAt: sql.NullString{String: ts(0), Valid: true}, ID: "worker-b"}); err != nil {
+		t.Fatalf("StopWorker failed: %v", err)
+	}
+
+	// worker-a finished a 3 contest simulation and is running another one
+	for range 2 {
+		if _, err := q.CreateSimulation(ctx, simulations.CreateSimulationParams{
+			RecipeJson:   `{"version":"1.1","name":"test","parameters":{}}`,
+			Mode:         "simple",
+			StartContest: 1001,
+			EndContest:   1003,
+		}); err != nil {
+			t.Fatalf("CreateSimulation failed: %v", err)
+		}
+	}
+	var claimed []simulations.Simulation
+	for range 2 {
+		sim, err := q.ClaimPendingSimulation(ctx, simulations.ClaimPendingSimulationParams{
+			StartedAt:      sql.NullString{String: ts(-time.Minute), Valid: true},
+			WorkerID:       sql.NullString{String: "worker-a", Valid: true},
+			LeaseExpiresAt: sql.NullString{String: ts(time.Minute), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("ClaimPendingSimulation failed: %v", err)
+		}
+		claimed = append(claimed, sim)
+	}
+	if n, err := q.CompleteSimulation(ctx, simulations.CompleteSimulationParams{
+		FinishedAt: sql.NullString{String: ts(0), Valid: true},
+		ID:         claimed[0].ID,
+		WorkerID:   claimed[0].WorkerID,
+	}); err != nil || n != 1 {
+		t.Fatalf("CompleteSimulation failed: %d rows, %v", n, err)
+	}
+	if err := q.RecordSimulationAttempt(ctx, simulations.RecordSimulationAttemptParams{
+		SimulationID: claimed[0].ID,
+		Attempt:      1,
+		WorkerID:     sql.NullString{String: "worker-a", Valid: true},
+		FinishedAt:   ts(0),
+		Outcome:      "completed",
+	}); err != nil {
+		t.Fatalf("RecordSimulationAttempt failed: %v", err)
+	}
+
+	workers, err := svc.ListWorkers(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkers failed: %v", err)
+	}
+	if len(workers) != 3 {
+		t.Fatalf("expected 3 workers, got %d", len(workers))
+	}
+
+	byID := make(map[string]services.WorkerStatus)
+	for _, w := range workers {
+		byID[w.ID] = w
+	}
+
+	a := byID["worker-a"]
+	if a.Status != services.WorkerStatusLive || a.ActiveJobs != 1 || len(a.CurrentJobs) != 1 || a.CurrentJobs[0] != claimed[1].ID {
+		t.Errorf("unexpected worker-a: %+v", a)
+	}
+	if a.Throughput.JobsCompleted != 1 || a.Throughput.ContestsCompleted != 3 || a.Throughput.JobsPerMinute <= 0 {
+		t.Errorf("unexpected worker-a throughput: %+v", a.Throughput)
+	}
+	if b := byID["worker-b"]; b.Status != services.WorkerStatusStopped || b.StoppedAt == nil {
+		t.Errorf("unexpected worker-b: %+v", b)
+	}
+	if c := byID["worker-c"]; c.Status != services.WorkerStatusDead {
+		t.Errorf("unexpected worker-c: %+v", c)
+	}
+	// Stopped workers are listed last
+	if workers[2].ID != "worker-b" {
+		t.Errorf("expected stopped worker last, got %s", workers[2].ID)
+	}
+
+	// Restarting a worker resets its entry
+	register("worker-b", ts(0), ts(0))
+	workers, err = svc.ListWorkers(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkers failed: %v", err)
+	}
+	for _, w := range workers {
+		if w.ID == "worker-b" && (w.Status != services.WorkerStatusLive || w.StoppedAt != nil) {
+			t.Errorf("expected restarted worker-b to be live, got %+v", w)
+		}
+	}
+}